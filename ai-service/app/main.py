@@ -5,7 +5,8 @@ import re
 from typing import List, Optional, Set
 
 import numpy as np
-from fastapi import FastAPI, HTTPException
+from fastapi import FastAPI, HTTPException, Request
+from fastapi.responses import JSONResponse
 from fastapi.middleware.cors import CORSMiddleware
 from pydantic import BaseModel, Field
 from PyPDF2 import PdfReader
@@ -29,6 +30,26 @@ app.add_middleware(
     max_age=86400,  # 24 hours cache for preflight
 )
 
+# Protocol version shared with the Go backend (services.AIAPIVersion). Bump it
+# whenever a request or response schema changes incompatibly.
+API_VERSION = "1"
+API_VERSION_HEADER = "X-AI-API-Version"
+
+
+@app.middleware("http")
+async def api_version(request: Request, call_next):
+    requested = request.headers.get(API_VERSION_HEADER)
+    if requested and requested != API_VERSION:
+        response = JSONResponse(
+            status_code=400,
+            content={"detail": f"unsupported API version {requested}, server speaks {API_VERSION}"},
+        )
+    else:
+        response = await call_next(request)
+    response.headers[API_VERSION_HEADER] = API_VERSION
+    return response
+
+
 # CRITICAL: Request size limit to prevent memory issues
 # FastAPI handles this via Starlette's request size limits
 # We'll add validation in endpoints instead
@@ -195,6 +216,37 @@ class MatchRequest(BaseModel):
 
 class MatchResponse(BaseModel):
     score: float
+    # Components of the hybrid score (0-100) so callers can explain it.
+    semantic_score: Optional[float] = None  # effective semantic similarity after mismatch penalties
+    skill_score: Optional[float] = None  # required skill coverage incl. breadth bonus
+
+
+class MatchBatchItem(BaseModel):
+    id: str
+    text: str = ""
+    skills: Optional[List[str]] = None
+
+
+class MatchBatchRequest(BaseModel):
+    # Every job is scored against every candidate; callers send one job with
+    # many candidates or one candidate with many jobs.
+    jobs: List[MatchBatchItem]
+    candidates: List[MatchBatchItem]
+
+
+class MatchBatchResult(BaseModel):
+    job_id: str
+    candidate_id: str
+    score: float
+    semantic_score: Optional[float] = None
+    skill_score: Optional[float] = None
+
+
+class MatchBatchResponse(BaseModel):
+    results: List[MatchBatchResult]
+
+
+MAX_BATCH_PAIRS = 500
 
 
 class RecommendationRequest(BaseModel):
@@ -811,6 +863,59 @@ def _required_skill_coverage(required_skills: List[str], candidate_skills: List[
     return max(0.0, min(1.0, skill_score))
 
 
+def _hybrid_score(cos: float, job_skills: List[str], candidate_skills: List[str]) -> dict:
+    """Combine cosine similarity and required skill coverage into the /match payload."""
+    embed_score = (cos + 1) / 2  # Normalize to 0..1
+
+    # Calculate skill score using REQUIRED SKILL COVERAGE (not Jaccard)
+    skill_score = 0.0
+    if job_skills and candidate_skills:
+        try:
+            skill_score = _required_skill_coverage(job_skills, candidate_skills)
+        except Exception as e:
+            # If skill coverage calculation fails, log but continue with 0.0
+            print(f"Warning: Skill coverage calculation failed: {e}")
+            skill_score = 0.0
+
+    # CRITICAL: If candidate has ALL required skills, score MUST be ≥ 95%
+    # This ensures perfect skill matches are always highly ranked
+    if skill_score >= 1.0:
+        # All required skills match - guarantee minimum 95% score
+        # Use max of calculated score and 95% to preserve higher scores
+        hybrid = max(0.95, 0.7 * embed_score + 0.3 * skill_score)
+    else:
+        # Apply semantic threshold penalty for completely unrelated profiles
+        # Penalties help identify domain mismatches and unrelated profiles
+        if embed_score < 0.35:
+            # Very low semantic (< 0.35): aggressive penalty for completely unrelated
+            embed_score = embed_score * 0.15  # Reduce by 85%
+        elif embed_score < 0.45:
+            # Low semantic (0.35-0.45): strong penalty for domain mismatches
+            embed_score = embed_score * 0.5  # Reduce by 50%
+        elif embed_score < 0.55 and skill_score < 0.3:
+            # Medium-low semantic with low skill coverage: likely domain mismatch
+            embed_score = embed_score * 0.75  # Reduce by 25%
+
+        # Hybrid weight: 70% semantic similarity, 30% skill coverage
+        # This ensures semantic understanding dominates, while skill coverage provides precision
+        hybrid = 0.7 * embed_score + 0.3 * skill_score
+    
+    # Convert to percentage and clamp to 0-100
+    normalized = round(hybrid * 100, 2)
+    normalized = max(0.0, min(100.0, normalized))
+    
+    # Defensive check: Ensure no NaN or infinity values
+    if not (0.0 <= normalized <= 100.0) or normalized != normalized:  # NaN check
+        print(f"Warning: Invalid score calculated: {normalized}, using 0.0")
+        normalized = 0.0
+    
+    return {
+        "score": normalized,
+        "semantic_score": round(max(0.0, min(1.0, embed_score)) * 100, 2),
+        "skill_score": round(skill_score * 100, 2),
+    }
+
+
 @app.post("/match", response_model=MatchResponse)
 def match(req: MatchRequest):
     """
@@ -883,51 +988,7 @@ def match(req: MatchRequest):
             print(f"Error: Failed to calculate cosine similarity: {e}")
             return {"score": 0.0}  # Return 0 instead of 500
         
-        embed_score = (cos + 1) / 2  # Normalize to 0..1
-
-        # Calculate skill score using REQUIRED SKILL COVERAGE (not Jaccard)
-        skill_score = 0.0
-        if job_skills and candidate_skills:
-            try:
-                skill_score = _required_skill_coverage(job_skills, candidate_skills)
-            except Exception as e:
-                # If skill coverage calculation fails, log but continue with 0.0
-                print(f"Warning: Skill coverage calculation failed: {e}")
-                skill_score = 0.0
-
-        # CRITICAL: If candidate has ALL required skills, score MUST be ≥ 95%
-        # This ensures perfect skill matches are always highly ranked
-        if skill_score >= 1.0:
-            # All required skills match - guarantee minimum 95% score
-            # Use max of calculated score and 95% to preserve higher scores
-            hybrid = max(0.95, 0.7 * embed_score + 0.3 * skill_score)
-        else:
-            # Apply semantic threshold penalty for completely unrelated profiles
-            # Penalties help identify domain mismatches and unrelated profiles
-            if embed_score < 0.35:
-                # Very low semantic (< 0.35): aggressive penalty for completely unrelated
-                embed_score = embed_score * 0.15  # Reduce by 85%
-            elif embed_score < 0.45:
-                # Low semantic (0.35-0.45): strong penalty for domain mismatches
-                embed_score = embed_score * 0.5  # Reduce by 50%
-            elif embed_score < 0.55 and skill_score < 0.3:
-                # Medium-low semantic with low skill coverage: likely domain mismatch
-                embed_score = embed_score * 0.75  # Reduce by 25%
-
-            # Hybrid weight: 70% semantic similarity, 30% skill coverage
-            # This ensures semantic understanding dominates, while skill coverage provides precision
-            hybrid = 0.7 * embed_score + 0.3 * skill_score
-        
-        # Convert to percentage and clamp to 0-100
-        normalized = round(hybrid * 100, 2)
-        normalized = max(0.0, min(100.0, normalized))
-        
-        # Defensive check: Ensure no NaN or infinity values
-        if not (0.0 <= normalized <= 100.0) or normalized != normalized:  # NaN check
-            print(f"Warning: Invalid score calculated: {normalized}, using 0.0")
-            normalized = 0.0
-        
-        return {"score": normalized}
+        return _hybrid_score(cos, job_skills, candidate_skills)
     
     except Exception as e:
         # CRITICAL: Never throw 500 error - always return valid score
@@ -937,6 +998,56 @@ def match(req: MatchRequest):
         return {"score": 0.0}  # Return 0 instead of 500
 
 
+@app.post("/match/batch", response_model=MatchBatchResponse)
+def match_batch(req: MatchBatchRequest):
+    """
+    Score every job against every candidate with the same hybrid formula as
+    /match, encoding all texts in a single embedder call.
+
+    Pairs with an empty description or bio score 0. Like /match, never returns
+    500: if the embedder fails every pair scores 0.
+    """
+    if len(req.jobs) * len(req.candidates) > MAX_BATCH_PAIRS:
+        raise HTTPException(status_code=413, detail=f"batch exceeds {MAX_BATCH_PAIRS} pairs")
+
+    job_texts = [(j.text or "").strip() for j in req.jobs]
+    cand_texts = [(c.text or "").strip() for c in req.candidates]
+    texts = [t for t in job_texts + cand_texts if t]
+
+    vectors = {}
+    if texts:
+        try:
+            embedder = get_embedder()
+            embeddings = embedder.encode(
+                texts,
+                convert_to_numpy=True,
+                show_progress_bar=False,
+                normalize_embeddings=False
+            )
+            vectors = dict(zip(texts, embeddings))
+        except Exception:
+            import traceback
+            print(f"Batch encoding error: {traceback.format_exc()}")
+            vectors = {}
+
+    results = []
+    for job, job_text in zip(req.jobs, job_texts):
+        for cand, cand_text in zip(req.candidates, cand_texts):
+            item = {"job_id": job.id, "candidate_id": cand.id, "score": 0.0}
+            job_vec = vectors.get(job_text) if job_text else None
+            cand_vec = vectors.get(cand_text) if cand_text else None
+            if job_vec is not None and cand_vec is not None:
+                try:
+                    job_norm = np.linalg.norm(job_vec)
+                    cand_norm = np.linalg.norm(cand_vec)
+                    cos = 0.0 if job_norm == 0 or cand_norm == 0 else float(np.dot(job_vec, cand_vec) / (job_norm * cand_norm))
+                    item.update(_hybrid_score(cos, job.skills or [], cand.skills or []))
+                except Exception as e:
+                    print(f"Warning: batch pair {job.id}/{cand.id} failed: {e}")
+            results.append(item)
+    return {"results": results}
+
+
 @app.post("/recommendations/recruiter")
 def recruiter_reco(req: RecommendationRequest):
     top_skills = req.skills[:5]
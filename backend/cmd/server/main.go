@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"rizeos/backend/internal/config"
 	"rizeos/backend/internal/database"
+	"rizeos/backend/internal/models"
 	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
 )
 
 func main() {
@@ -20,7 +25,91 @@ func main() {
 	}
 	defer client.Disconnect(database.Ctx())
 
-	router := routes.SetupRouter(cfg, db)
+	// Convert legacy free-form experience/education fields before serving traffic.
+	migrateCtx, cancel := context.WithTimeout(database.Ctx(), 2*time.Minute)
+	migrated, err := services.NewUserService(db).MigrateLegacyProfiles(migrateCtx)
+	cancel()
+	if err != nil {
+		log.Printf("legacy profile migration failed: %v", err)
+	} else if migrated > 0 {
+		log.Printf("migrated %d legacy user profiles", migrated)
+	}
+
+	deps := routes.DefaultDeps(cfg, db)
+	// Group messages sent before conversations existed into threads.
+	convCtx, cancel := context.WithTimeout(database.Ctx(), 2*time.Minute)
+	if err := deps.MessageSvc.EnsureIndexes(convCtx); err != nil {
+		log.Printf("conversation index creation failed: %v", err)
+	}
+	if migrated, err := deps.MessageSvc.MigrateConversations(convCtx); err != nil {
+		log.Printf("conversation migration failed: %v", err)
+	} else if migrated > 0 {
+		log.Printf("filed %d legacy messages into conversations", migrated)
+	}
+	if admins, err := deps.UserSvc.Search(convCtx, models.RoleAdmin, "", nil); err != nil {
+		log.Printf("admin read state migration failed: %v", err)
+	} else {
+		ids := make([]primitive.ObjectID, len(admins))
+		for i, a := range admins {
+			ids[i] = a.ID
+		}
+		if migrated, err := deps.MessageSvc.MigrateAdminReads(convCtx, ids); err != nil {
+			log.Printf("admin read state migration failed: %v", err)
+		} else if migrated > 0 {
+			log.Printf("kept %d messages to admins read for every admin", migrated)
+		}
+	}
+	cancel()
+	// Schedule announcements created before audiences existed for delivery,
+	// replacing the inbox messages they were sent as.
+	annCtx, cancel := context.WithTimeout(database.Ctx(), 2*time.Minute)
+	if err := deps.AnnouncementSvc.EnsureIndexes(annCtx); err != nil {
+		log.Printf("announcement index creation failed: %v", err)
+	}
+	if migrated, err := deps.AnnouncementSvc.MigrateLegacy(annCtx); err != nil {
+		log.Printf("announcement migration failed: %v", err)
+	} else if migrated > 0 {
+		log.Printf("migrated %d legacy announcements and announcement messages", migrated)
+	}
+	cancel()
+	// Funnel rollups: recompute application counters so history recorded
+	// before tracking (or missed by failed writes) is reflected.
+	rollupCtx, cancel := context.WithTimeout(database.Ctx(), 2*time.Minute)
+	if err := deps.AnalyticsSvc.EnsureIndexes(rollupCtx); err != nil {
+		log.Printf("analytics index creation failed: %v", err)
+	}
+	if err := deps.JobViewSvc.EnsureIndexes(rollupCtx); err != nil {
+		log.Printf("job view index creation failed: %v", err)
+	}
+	if err := deps.NotificationSvc.EnsureIndexes(rollupCtx); err != nil {
+		log.Printf("notification index creation failed: %v", err)
+	}
+	if err := deps.PostSvc.EnsureIndexes(rollupCtx); err != nil {
+		log.Printf("post index creation failed: %v", err)
+	}
+	if migrated, err := deps.PostSvc.MigrateJobClosing(rollupCtx); err != nil {
+		log.Printf("job post migration failed: %v", err)
+	} else if migrated > 0 {
+		log.Printf("recorded the closing date on %d job posts", migrated)
+	}
+	if err := deps.SkillSvc.EnsureIndexes(rollupCtx); err != nil {
+		log.Printf("skill index creation failed: %v", err)
+	}
+	if err := deps.AnalyticsSvc.RebuildApplicationRollups(rollupCtx); err != nil {
+		log.Printf("analytics rollup rebuild failed: %v", err)
+	}
+	cancel()
+	// Background workers recompute match scores invalidated by job/profile edits.
+	deps.MatchScoreSvc.Start(database.Ctx(), 4)
+	// Notification digests and job expiry notices.
+	deps.NotificationSvc.Start(database.Ctx(), deps.JobSvc, 5*time.Minute)
+	// Announcement fan-out: scheduled, interrupted and retried deliveries.
+	deps.AnnouncementSvc.Start(database.Ctx(), time.Minute)
+	// Real-time hub: deliver WebSocket events published by any instance.
+	if err := deps.Hub.Start(database.Ctx()); err != nil {
+		log.Fatalf("failed to start realtime hub (%s backend): %v", cfg.RealtimeBackend, err)
+	}
+	router := routes.SetupRouterWithDeps(cfg, deps)
 
 	if err := router.Run(":" + cfg.Port); err != nil {
 		log.Fatalf("server error: %v", err)
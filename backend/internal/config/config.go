@@ -9,15 +9,35 @@ import (
 
 // Config holds runtime configuration for the backend.
 type Config struct {
-	Port              string
-	MongoURI          string
-	JWTSecret         string
-	AdminWallet       string
-	AIServiceURL      string
+	Port         string
+	MongoURI     string
+	JWTSecret    string
+	AdminWallet  string
+	AIServiceURL string
+	// AIEngine selects the scoring backend: "http" (default), "local" or "fake".
+	AIEngine          string
 	PolygonRPCURL     string
 	PlatformFeeMatic  float64
 	AllowedOriginsCSV string
 	AdminSignupCode   string
+	// MatchScoreTTLHours bounds how long cached AI match scores are reused.
+	MatchScoreTTLHours int
+	// SuggestionTemplatesPath is an optional JSON file with job suggestion
+	// title/description templates; empty uses the built-in templates.
+	SuggestionTemplatesPath string
+	// RealtimeBackend fans WebSocket events out across instances: "memory"
+	// (default, single instance) or "mongo" (change streams; needs a replica set).
+	RealtimeBackend string
+	// AttachmentsDir is where message attachments are stored on local disk.
+	AttachmentsDir string
+	// AttachmentMaxMB caps the size of one message attachment.
+	AttachmentMaxMB int
+	// SMTPAddr (host:port) sends notification email; empty logs it instead.
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	// MailFrom is the sender address of notification email.
+	MailFrom string
 }
 
 // Load reads environment variables and returns a Config.
@@ -25,15 +45,25 @@ func Load() (Config, error) {
 	_ = godotenv.Load()
 
 	return Config{
-		Port:              getEnv("PORT", "8080"),
-		MongoURI:          getEnv("MONGO_URI", "mongodb://localhost:27017/rizeos"),
-		JWTSecret:         getEnv("JWT_SECRET", "change_me"),
-		AdminWallet:       getEnv("ADMIN_WALLET_ADDRESS", ""),
-		AIServiceURL:      getEnv("AI_SERVICE_URL", "http://localhost:8000"),
-		PolygonRPCURL:     getEnv("POLYGON_RPC_URL", ""),
-		PlatformFeeMatic:  getEnvAsFloat("PLATFORM_FEE_MATIC", 0.1),
-		AllowedOriginsCSV: getEnv("CORS_ALLOWED_ORIGINS", "*"),
-		AdminSignupCode:   getEnv("ADMIN_SIGNUP_CODE", "owner-secret"),
+		Port:                    getEnv("PORT", "8080"),
+		MongoURI:                getEnv("MONGO_URI", "mongodb://localhost:27017/rizeos"),
+		JWTSecret:               getEnv("JWT_SECRET", "change_me"),
+		AdminWallet:             getEnv("ADMIN_WALLET_ADDRESS", ""),
+		AIServiceURL:            getEnv("AI_SERVICE_URL", "http://localhost:8000"),
+		AIEngine:                getEnv("AI_ENGINE", "http"),
+		PolygonRPCURL:           getEnv("POLYGON_RPC_URL", ""),
+		PlatformFeeMatic:        getEnvAsFloat("PLATFORM_FEE_MATIC", 0.1),
+		AllowedOriginsCSV:       getEnv("CORS_ALLOWED_ORIGINS", "*"),
+		AdminSignupCode:         getEnv("ADMIN_SIGNUP_CODE", "owner-secret"),
+		MatchScoreTTLHours:      getEnvAsInt("MATCH_SCORE_TTL_HOURS", 24),
+		SuggestionTemplatesPath: getEnv("SUGGESTION_TEMPLATES_PATH", ""),
+		RealtimeBackend:         getEnv("REALTIME_BACKEND", "memory"),
+		AttachmentsDir:          getEnv("ATTACHMENTS_DIR", "data/attachments"),
+		AttachmentMaxMB:         getEnvAsInt("ATTACHMENT_MAX_MB", 10),
+		SMTPAddr:                getEnv("SMTP_ADDR", ""),
+		SMTPUsername:            getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:            getEnv("SMTP_PASSWORD", ""),
+		MailFrom:                getEnv("MAIL_FROM", "no-reply@rizeos.local"),
 	}, nil
 }
 
@@ -53,6 +83,15 @@ func getEnvAsFloat(key string, fallback float64) float64 {
 	return fallback
 }
 
+func getEnvAsInt(key string, fallback int) int {
+	if val := os.Getenv(key); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			return n
+		}
+	}
+	return fallback
+}
+
 func parseFloat(val string) (float64, error) {
 	return strconv.ParseFloat(val, 64)
 }
@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -19,56 +20,89 @@ type AdminController struct {
 	PaymentService *services.PaymentService
 	UserService    *services.UserService
 	JobService     *services.JobService
-	UserCol        *mongo.Collection
-	JobCol         *mongo.Collection
+	Analytics      *services.PlatformAnalyticsService
 }
 
-// Dashboard returns payment totals and counts.
+// Admin list pagination.
+const (
+	defaultAdminPageSize = 50
+	maxAdminPageSize     = 200
+)
+
+// Dashboard returns platform metrics computed by aggregation: revenue by
+// period and payment type, signups by role, job and application activity,
+// premium conversion, top recruiters by spend and message volume. Accepts
+// ?from=&to= (see analyticsRange) and ?interval=day|week|month (default day,
+// see analyticsInterval).
+// Raw users, jobs and payments are served by the paginated list endpoints.
 func (a *AdminController) Dashboard(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	from, to, ok := analyticsRange(c)
+	if !ok {
+		return
+	}
+	interval, ok := analyticsInterval(c, from, to)
+	if !ok {
+		return
+	}
+	if a.Analytics == nil {
+		utils.JSONError(c, http.StatusServiceUnavailable, "analytics are not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
 	defer cancel()
 
-	payments, err := a.PaymentService.List(ctx, bson.M{"status": "verified"})
+	metrics, err := a.Analytics.Metrics(ctx, services.PlatformRange{From: from, To: to, Interval: interval})
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
-	total := 0.0
-	for _, p := range payments {
-		total += p.Amount
-	}
+	utils.JSON(c, http.StatusOK, metrics)
+}
 
-	var users []map[string]interface{}
-	cursor, err := a.UserCol.Find(ctx, bson.M{})
-	if err == nil {
-		defer cursor.Close(ctx)
-		for cursor.Next(ctx) {
-			var u bson.M
-			if err := cursor.Decode(&u); err == nil {
-				delete(u, "password_hash")
-				users = append(users, u)
-			}
-		}
-	}
+// ListUsers returns a page of users, newest first, without password hashes.
+func (a *AdminController) ListUsers(c *gin.Context) {
+	a.listPage(c, func(ctx context.Context, page, limit int) (interface{}, int64, error) {
+		return a.UserService.ListPage(ctx, page, limit)
+	})
+}
 
-	var jobs []bson.M
-	jobCur, err := a.JobCol.Find(ctx, bson.M{})
-	if err == nil {
-		defer jobCur.Close(ctx)
-		_ = jobCur.All(ctx, &jobs)
+// ListJobs returns a page of jobs, newest first.
+func (a *AdminController) ListJobs(c *gin.Context) {
+	a.listPage(c, func(ctx context.Context, page, limit int) (interface{}, int64, error) {
+		return a.JobService.ListPage(ctx, page, limit)
+	})
+}
+
+// ListPayments returns a page of payments, newest first.
+func (a *AdminController) ListPayments(c *gin.Context) {
+	a.listPage(c, func(ctx context.Context, page, limit int) (interface{}, int64, error) {
+		return a.PaymentService.ListPage(ctx, page, limit)
+	})
+}
+
+// listPage serves ?page= (from 1) and ?limit= (default 50, max 200) of list.
+func (a *AdminController) listPage(c *gin.Context, list func(ctx context.Context, page, limit int) (interface{}, int64, error)) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "page must be a positive integer")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAdminPageSize)))
+	if err != nil || limit < 1 || limit > maxAdminPageSize {
+		utils.JSONError(c, http.StatusBadRequest, "limit must be between 1 and 200")
+		return
 	}
 
-	userCount, _ := a.UserCol.CountDocuments(ctx, bson.M{})
-	jobCount, _ := a.JobCol.CountDocuments(ctx, bson.M{})
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
 
-	utils.JSON(c, http.StatusOK, gin.H{
-		"total_payments_matic": total,
-		"payments":             payments,
-		"users":                userCount,
-		"jobs":                 jobCount,
-		"user_list":            users,
-		"job_list":             jobs,
-	})
+	items, total, err := list(ctx, page, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"items": items, "page": page, "limit": limit, "total": total})
 }
 
 // GetUserProfile returns detailed user information with role-specific stats.
@@ -161,9 +195,8 @@ func (a *AdminController) GetUserProfile(c *gin.Context) {
 		"phone_number":  user.PhoneNumber,
 		"summary":       user.Summary,
 		"education":     user.Education,
-		"tenth_marks":   user.TenthMarks,
-		"twelfth_marks": user.TwelfthMarks,
 		"experience":    user.Experience,
+		"experience_years": user.ExperienceYears,
 		"is_active":     user.IsActive,
 		"is_premium":    user.IsPremium,
 	}
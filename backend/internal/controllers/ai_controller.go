@@ -3,11 +3,13 @@ package controllers
 import (
 	"context"
 	"net/http"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/ranking"
+	"rizeos/backend/internal/recommend"
 	"rizeos/backend/internal/services"
 	"rizeos/backend/internal/utils"
 )
@@ -16,7 +18,11 @@ import (
 type AIController struct {
 	JobService  *services.JobService
 	UserService *services.UserService
-	AIService   *services.AIService
+	Matcher     services.Matcher
+	SkillExtractor services.SkillExtractor
+	SkillService *services.SkillService
+	MatchScoreService *services.MatchScoreService
+	Recommender       *services.RecommendationService
 }
 
 // MatchScoreResponse is the payload for match score.
@@ -25,6 +31,8 @@ type MatchScoreResponse struct {
 	CandidateID  string   `json:"candidateId"`
 	MatchScore   float64  `json:"matchScore"`
 	MatchedSkills []string `json:"matchedSkills"`
+	Explanation  *ranking.Explanation `json:"explanation,omitempty"`
+	Engine       string   `json:"engine,omitempty"` // "ai-service" or "local" fallback
 }
 
 // MatchScore returns similarity between a job and a candidate.
@@ -60,38 +68,21 @@ func (a *AIController) MatchScore(c *gin.Context) {
 		return
 	}
 
-	// CRITICAL: Validate inputs before calling AI service to prevent 500 errors
-	jobDesc := strings.TrimSpace(job.Description)
-	candidateBio := strings.TrimSpace(user.Bio)
-	
-	// If required fields are empty, return 0 score (not error)
-	var score float64
-	if jobDesc == "" || candidateBio == "" {
-		score = 0.0
-	} else {
-		var err error
-		score, err = a.AIService.MatchScore(ctx, jobDesc, candidateBio)
-		if err != nil {
-			// Log error but return 0 score instead of 500
-			score = 0.0
-		}
-	}
-	
-	// Ensure score is valid (0-100)
-	if score < 0 {
-		score = 0
-	}
-	if score > 100 {
-		score = 100
+	// Empty descriptions score 0 and AI failures return 0 instead of a 500.
+	breakdown, err := matchBreakdown(ctx, a.MatchScoreService, a.Matcher, job, user)
+	if err != nil {
+		breakdown = services.MatchBreakdown{}
 	}
-	
-	matched := intersect(job.Skills, user.Skills)
+
+	explanation := explainMatch(ctx, a.SkillService, job, user, breakdown)
 
 	utils.JSON(c, http.StatusOK, MatchScoreResponse{
 		JobID:         jobID,
 		CandidateID:   candID,
-		MatchScore:    score,
-		MatchedSkills: matched,
+		MatchScore:    breakdown.Score,
+		MatchedSkills: explanation.MatchedSkills,
+		Explanation:   &explanation,
+		Engine:        breakdown.Engine,
 	})
 }
 
@@ -106,14 +97,22 @@ func (a *AIController) ExtractSkills(c *gin.Context) {
 	}
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
 	defer cancel()
-	skills, _ := a.AIService.ExtractSkills(ctx, req.ResumeText)
+	skills, engine, _ := a.SkillExtractor.ExtractSkillsDetailed(ctx, req.ResumeText)
+	if skills == nil {
+		skills = []string{}
+	}
 	utils.JSON(c, http.StatusOK, gin.H{
 		"extractedSkills": skills,
 		"confidenceScore": 1.0,
+		"engine":          engine,
 	})
 }
 
-// RecommendJobs recommends jobs to a seeker.
+// maxRecommendations caps the limit query parameter of recommendation endpoints.
+const maxRecommendations = 100
+
+// RecommendJobs recommends jobs the seeker has not applied to yet, with the
+// reasons each job was picked.
 func (a *AIController) RecommendJobs(c *gin.Context) {
 	userID := c.Query("userId")
 	if userID == "" {
@@ -125,6 +124,10 @@ func (a *AIController) RecommendJobs(c *gin.Context) {
 		utils.JSONError(c, http.StatusBadRequest, "invalid userId")
 		return
 	}
+	limit, ok := recommendationLimit(c)
+	if !ok {
+		return
+	}
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
 	defer cancel()
 
@@ -133,33 +136,39 @@ func (a *AIController) RecommendJobs(c *gin.Context) {
 		utils.JSONError(c, http.StatusNotFound, "user not found")
 		return
 	}
-	jobs, err := a.JobService.List(ctx, map[string]interface{}{})
+	recs, err := a.Recommender.RecommendJobs(ctx, user, recommend.Options{Limit: limit, Diversity: recommend.DefaultDiversity})
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 	type rec struct {
-		JobID string  `json:"jobId"`
-		Title string  `json:"title"`
-		Score float64 `json:"score"`
-	}
-	var recs []rec
-	for _, jb := range jobs {
-		score, _ := a.AIService.MatchScore(ctx, jb.Description, user.Bio)
-		recs = append(recs, rec{JobID: jb.ID.Hex(), Title: jb.Title, Score: score})
-	}
-	// Simple sort descending
-	for i := 0; i < len(recs); i++ {
-		for j := i + 1; j < len(recs); j++ {
-			if recs[j].Score > recs[i].Score {
-				recs[i], recs[j] = recs[j], recs[i]
-			}
+		JobID         string            `json:"jobId"`
+		Title         string            `json:"title"`
+		Location      string            `json:"location"`
+		Skills        []string          `json:"skills"`
+		Score         float64           `json:"score"`
+		MatchedSkills []string          `json:"matchedSkills"`
+		Reasons       []string          `json:"reasons"`
+		Signals       recommend.Signals `json:"signals"`
+	}
+	out := make([]rec, len(recs))
+	for i, r := range recs {
+		out[i] = rec{
+			JobID:         r.ID,
+			Title:         r.Job.Title,
+			Location:      r.Job.Location,
+			Skills:        r.Job.Skills,
+			Score:         r.Score,
+			MatchedSkills: r.MatchedSkills,
+			Reasons:       r.Reasons,
+			Signals:       r.Signals,
 		}
 	}
-	utils.JSON(c, http.StatusOK, recs)
+	utils.JSON(c, http.StatusOK, out)
 }
 
-// RecommendCandidates recommends candidates for a job.
+// RecommendCandidates recommends seekers who have not applied to a job yet,
+// with the reasons each candidate was picked.
 func (a *AIController) RecommendCandidates(c *gin.Context) {
 	jobID := c.Query("jobId")
 	if jobID == "" {
@@ -171,6 +180,10 @@ func (a *AIController) RecommendCandidates(c *gin.Context) {
 		utils.JSONError(c, http.StatusBadRequest, "invalid jobId")
 		return
 	}
+	limit, ok := recommendationLimit(c)
+	if !ok {
+		return
+	}
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
 	defer cancel()
 
@@ -179,76 +192,51 @@ func (a *AIController) RecommendCandidates(c *gin.Context) {
 		utils.JSONError(c, http.StatusNotFound, "job not found")
 		return
 	}
-
-	// get seekers
-	seekers, err := a.UserService.Search(ctx, "seeker", "", nil)
+	recs, err := a.Recommender.RecommendCandidates(ctx, job, recommend.Options{Limit: limit, Diversity: recommend.DefaultDiversity})
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
-
 	type rec struct {
-		CandidateID string   `json:"candidateId"`
-		Name        string   `json:"name"`
-		Email       string   `json:"email"`
-		Skills      []string `json:"skills"`
-		Score       float64  `json:"score"`
-	}
-	var recs []rec
-	for _, u := range seekers {
-		// CRITICAL: Validate inputs before calling AI service
-		jobDesc := strings.TrimSpace(job.Description)
-		candidateBio := strings.TrimSpace(u.Bio)
-		
-		var score float64
-		if jobDesc == "" || candidateBio == "" {
-			score = 0.0
-		} else {
-			var err error
-			score, err = a.AIService.MatchScore(ctx, jobDesc, candidateBio)
-			if err != nil {
-				score = 0.0 // Return 0 instead of failing
-			}
-		}
-		
-		// Ensure score is valid (0-100)
-		if score < 0 {
-			score = 0
-		}
-		if score > 100 {
-			score = 100
-		}
-		
-		recs = append(recs, rec{
-			CandidateID: u.ID.Hex(),
-			Name:        u.Name,
-			Email:       u.Email,
-			Skills:      u.Skills,
-			Score:       score,
-		})
-	}
-	// sort descending
-	for i := 0; i < len(recs); i++ {
-		for j := i + 1; j < len(recs); j++ {
-			if recs[j].Score > recs[i].Score {
-				recs[i], recs[j] = recs[j], recs[i]
-			}
+		CandidateID   string            `json:"candidateId"`
+		Name          string            `json:"name"`
+		Email         string            `json:"email"`
+		Skills        []string          `json:"skills"`
+		Score         float64           `json:"score"`
+		MatchedSkills []string          `json:"matchedSkills"`
+		Reasons       []string          `json:"reasons"`
+		Signals       recommend.Signals `json:"signals"`
+	}
+	out := make([]rec, len(recs))
+	for i, r := range recs {
+		out[i] = rec{
+			CandidateID:   r.ID,
+			Name:          r.Seeker.Name,
+			Email:         r.Seeker.Email,
+			Skills:        r.Seeker.Skills,
+			Score:         r.Score,
+			MatchedSkills: r.MatchedSkills,
+			Reasons:       r.Reasons,
+			Signals:       r.Signals,
 		}
 	}
-	utils.JSON(c, http.StatusOK, recs)
+	utils.JSON(c, http.StatusOK, out)
 }
 
-func intersect(a, b []string) []string {
-	m := map[string]struct{}{}
-	for _, s := range a {
-		m[strings.ToLower(s)] = struct{}{}
+// recommendationLimit parses the optional limit query parameter, writing a 400
+// and returning false when it is invalid.
+func recommendationLimit(c *gin.Context) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return recommend.DefaultLimit, true
 	}
-	var out []string
-	for _, s := range b {
-		if _, ok := m[strings.ToLower(s)]; ok {
-			out = append(out, s)
-		}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		utils.JSONError(c, http.StatusBadRequest, "limit must be a positive integer")
+		return 0, false
 	}
-	return out
+	if limit > maxRecommendations {
+		limit = maxRecommendations
+	}
+	return limit, true
 }
-
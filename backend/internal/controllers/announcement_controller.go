@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -11,142 +12,290 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// AnnouncementController manages announcement endpoints.
+// AnnouncementController manages announcement endpoints. Announcements are
+// delivered to their audience in the background by the AnnouncementService.
 type AnnouncementController struct {
 	AnnouncementService *services.AnnouncementService
-	UserService         *services.UserService
-	MessageService      *services.MessageService
+	SkillService        *services.SkillService // nil keeps audience skills as given
 }
 
 type createAnnouncementRequest struct {
-	Message string `json:"message" binding:"required"`
+	Message   string          `json:"message" binding:"required"`
+	Audience  models.Audience `json:"audience"`   // segment defaults to all
+	PublishAt *time.Time      `json:"publish_at"` // omitted publishes now
+	ExpiresAt *time.Time      `json:"expires_at"` // omitted never expires
 }
 
-// CreateAnnouncement handles announcement creation by admin.
-func (a *AnnouncementController) CreateAnnouncement(c *gin.Context) {
-	var req createAnnouncementRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.JSONError(c, http.StatusBadRequest, err.Error())
+// announcementFeedItem is an announcement as shown to one user.
+type announcementFeedItem struct {
+	ID        primitive.ObjectID `json:"id"`
+	FromRole  string             `json:"from_role"`
+	Message   string             `json:"message"`
+	PublishAt time.Time          `json:"publish_at"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	ReadAt    *time.Time         `json:"read_at,omitempty"`
+	IsRead    bool               `json:"is_read"`
+}
+
+type reviewAnnouncementRequest struct {
+	Decision string `json:"decision" binding:"required"` // approve or reject
+	Reason   string `json:"reason"`                      // required to reject
+}
+
+// create validates req and passes it to save as an announcement from the
+// current user in role.
+func (a *AnnouncementController) create(c *gin.Context, req createAnnouncementRequest, role string, save func(context.Context, models.Announcement) (models.Announcement, error)) {
+	if req.PublishAt != nil && req.PublishAt.Before(time.Now().Add(-time.Minute)) {
+		utils.JSONError(c, http.StatusBadRequest, "publish_at must not be in the past")
 		return
 	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	// Create announcement
+	if a.SkillService != nil && len(req.Audience.Skills) > 0 {
+		req.Audience.Skills = a.SkillService.Normalize(ctx, req.Audience.Skills)
+	}
 	announcement := models.Announcement{
-		FromRole: models.RoleAdmin,
-		Message:  req.Message,
+		FromRole:  role,
+		CreatedBy: &userOID,
+		Message:   req.Message,
+		Audience:  req.Audience,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if req.PublishAt != nil {
+		announcement.PublishAt = *req.PublishAt
 	}
 
-	created, err := a.AnnouncementService.Create(ctx, announcement)
+	created, err := save(ctx, announcement)
 	if err != nil {
-		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		switch {
+		case errors.Is(err, services.ErrInvalidAudience) || errors.Is(err, services.ErrInvalidSchedule):
+			utils.JSONError(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrAnnouncementRateLimited):
+			utils.JSONError(c, http.StatusTooManyRequests, err.Error())
+		default:
+			utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
+	utils.JSON(c, http.StatusCreated, created)
+}
 
-	// Send announcement as messages to all recruiters and job seekers
-	// Get all recruiters
-	recruiters, err := a.UserService.Search(ctx, models.RoleRecruiter, "", nil)
-	if err == nil {
-		for _, recruiter := range recruiters {
-			message := models.Message{
-				FromUserID: primitive.NilObjectID, // No specific sender for announcements
-				FromRole:   models.RoleAdmin,
-				ToUserID:   recruiter.ID,
-				ToRole:     models.RoleRecruiter,
-				Message:    "[ANNOUNCEMENT] " + req.Message,
-			}
-			_, _ = a.MessageService.Create(ctx, message) // Ignore errors for individual sends
-		}
+// CreateAnnouncement schedules an announcement by admin for any audience.
+func (a *AnnouncementController) CreateAnnouncement(c *gin.Context) {
+	var req createAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
 	}
+	a.create(c, req, models.RoleAdmin, a.AnnouncementService.Create)
+}
 
-	// Get all job seekers
-	seekers, err := a.UserService.Search(ctx, models.RoleSeeker, "", nil)
-	if err == nil {
-		for _, seeker := range seekers {
-			// For job seekers, announcements are sent as messages with ToRole = "recruiter"
-			// but ToUserID = seeker.ID (this is a special case for announcements)
-			// Actually, we need to check the message model - it seems ToRole should match the recipient
-			// Let's use a special approach: send to seeker with ToRole matching their role
-			message := models.Message{
-				FromUserID: primitive.NilObjectID, // No specific sender for announcements
-				FromRole:   models.RoleAdmin,
-				ToUserID:   seeker.ID,
-				ToRole:     models.RoleSeeker, // This should work if the model supports it
-				Message:    "[ANNOUNCEMENT] " + req.Message,
-			}
-			_, _ = a.MessageService.Create(ctx, message) // Ignore errors for individual sends
-		}
+// CreateRecruiterAnnouncement submits an announcement by a recruiter for
+// moderation. Recruiter announcements are only delivered to other recruiters,
+// once approved.
+func (a *AnnouncementController) CreateRecruiterAnnouncement(c *gin.Context) {
+	var req createAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
 	}
+	req.Audience.Segment = models.AudienceRecruiters
+	a.create(c, req, models.RoleRecruiter, a.AnnouncementService.Submit)
+}
 
-	utils.JSON(c, http.StatusCreated, created)
+// ListMyAnnouncements returns the current user's announcements with their
+// moderation status and any rejection reason.
+func (a *AnnouncementController) ListMyAnnouncements(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	announcements, err := a.AnnouncementService.ListByAuthor(ctx, userOID)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, announcements)
 }
 
-// ListAnnouncements returns all announcements for recruiters and job seekers.
+// ListAnnouncements returns every announcement with its delivery progress to
+// admins, and the current user's announcement feed to everyone else.
 func (a *AnnouncementController) ListAnnouncements(c *gin.Context) {
+	if role, _ := c.Get("role"); role == models.RoleAdmin {
+		a.AdminList(c)
+		return
+	}
+	a.feed(c)
+}
+
+// ListRecruiterAnnouncements returns the announcements delivered to the
+// current recruiter, from admins and other recruiters.
+func (a *AnnouncementController) ListRecruiterAnnouncements(c *gin.Context) {
+	a.feed(c)
+}
+
+// feed returns the live, undismissed announcements delivered to the current
+// user, newest first.
+func (a *AnnouncementController) feed(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	announcements, err := a.AnnouncementService.List(ctx)
+	items, err := a.AnnouncementService.Feed(ctx, userOID, time.Now())
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
+	feed := make([]announcementFeedItem, len(items))
+	for i, item := range items {
+		feed[i] = announcementFeedItem{
+			ID:        item.Announcement.ID,
+			FromRole:  item.Announcement.FromRole,
+			Message:   item.Announcement.Message,
+			PublishAt: item.Announcement.PublishAt,
+			ExpiresAt: item.Announcement.ExpiresAt,
+			CreatedAt: item.Announcement.CreatedAt,
+			ReadAt:    item.Receipt.ReadAt,
+			IsRead:    item.Receipt.ReadAt != nil,
+		}
+	}
+	utils.JSON(c, http.StatusOK, feed)
+}
 
-	utils.JSON(c, http.StatusOK, announcements)
+// MarkRead marks an announcement delivered to the current user as read.
+func (a *AnnouncementController) MarkRead(c *gin.Context) {
+	a.updateReceipt(c, a.AnnouncementService.MarkRead, "announcement marked as read")
 }
 
-// CreateRecruiterAnnouncement handles announcement creation by recruiters.
-// Recruiter announcements are only visible to other recruiters.
-func (a *AnnouncementController) CreateRecruiterAnnouncement(c *gin.Context) {
-	var req createAnnouncementRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.JSONError(c, http.StatusBadRequest, err.Error())
+// Dismiss hides an announcement from the current user's feed.
+func (a *AnnouncementController) Dismiss(c *gin.Context) {
+	a.updateReceipt(c, a.AnnouncementService.Dismiss, "announcement dismissed")
+}
+
+func (a *AnnouncementController) updateReceipt(c *gin.Context, update func(ctx context.Context, announcementID, userID primitive.ObjectID) error, done string) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid announcement id")
 		return
 	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	// Create announcement with recruiter role
-	announcement := models.Announcement{
-		FromRole: models.RoleRecruiter,
-		Message:  req.Message,
+	if err := update(ctx, id, userOID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.JSONError(c, http.StatusNotFound, "announcement not found")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
 	}
+	utils.JSON(c, http.StatusOK, gin.H{"message": done})
+}
 
-	created, err := a.AnnouncementService.Create(ctx, announcement)
+// AdminList returns all announcements, latest first, with their status and
+// delivery progress. ?status=PENDING returns the moderation queue.
+func (a *AnnouncementController) AdminList(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	announcements, err := a.AnnouncementService.List(ctx, c.Query("status"))
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
-
-	utils.JSON(c, http.StatusCreated, created)
+	utils.JSON(c, http.StatusOK, announcements)
 }
 
-// ListRecruiterAnnouncements returns all announcements visible to recruiters.
-// This includes both admin announcements and recruiter announcements.
-// This endpoint ensures only recruiters can access announcements.
-func (a *AnnouncementController) ListRecruiterAnnouncements(c *gin.Context) {
+// Get returns one announcement with its delivery progress.
+func (a *AnnouncementController) Get(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid announcement id")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	// Get all announcements (both admin and recruiter)
-	allAnnouncements, err := a.AnnouncementService.List(ctx)
+	announcement, err := a.AnnouncementService.FindByID(ctx, id)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.JSONError(c, http.StatusNotFound, "announcement not found")
+			return
+		}
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
+	utils.JSON(c, http.StatusOK, announcement)
+}
 
-	// Filter to only include admin and recruiter announcements (exclude any other roles)
-	var recruiterAnnouncements []models.Announcement
-	for _, ann := range allAnnouncements {
-		if ann.FromRole == models.RoleAdmin || ann.FromRole == models.RoleRecruiter {
-			recruiterAnnouncements = append(recruiterAnnouncements, ann)
-		}
+// Cancel stops delivery of an announcement and hides it from every feed.
+func (a *AnnouncementController) Cancel(c *gin.Context) {
+	a.transition(c, a.AnnouncementService.Cancel)
+}
+
+// Retry reschedules delivery of a FAILED announcement.
+func (a *AnnouncementController) Retry(c *gin.Context) {
+	a.transition(c, a.AnnouncementService.Retry)
+}
+
+// Review approves or rejects a PENDING recruiter announcement.
+func (a *AnnouncementController) Review(c *gin.Context) {
+	var req reviewAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Decision != "approve" && req.Decision != "reject" {
+		utils.JSONError(c, http.StatusBadRequest, services.ErrInvalidDecision.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	a.transition(c, func(ctx context.Context, id primitive.ObjectID) (models.Announcement, error) {
+		return a.AnnouncementService.Review(ctx, id, adminOID, req.Decision == "approve", req.Reason)
+	})
+}
+
+func (a *AnnouncementController) transition(c *gin.Context, apply func(ctx context.Context, id primitive.ObjectID) (models.Announcement, error)) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid announcement id")
+		return
 	}
 
-	utils.JSON(c, http.StatusOK, recruiterAnnouncements)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	announcement, err := apply(ctx, id)
+	if err != nil {
+		switch {
+		case err == mongo.ErrNoDocuments:
+			utils.JSONError(c, http.StatusNotFound, "announcement not found")
+		case errors.Is(err, services.ErrInvalidDecision):
+			utils.JSONError(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrAnnouncementState):
+			utils.JSONError(c, http.StatusConflict, err.Error())
+		default:
+			utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	utils.JSON(c, http.StatusOK, announcement)
 }
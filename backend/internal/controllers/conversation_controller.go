@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/utils"
+)
+
+const (
+	defaultConversationPageSize = 20
+	maxConversationPageSize     = 100
+	defaultThreadPageSize       = 30
+	maxThreadPageSize           = 100
+)
+
+// ConversationController serves message threads to their participants.
+type ConversationController struct {
+	MessageService    *services.MessageService
+	UserService       *services.UserService
+	JobService        *services.JobService
+	AttachmentService *services.AttachmentService // nil disables attachments
+}
+
+// replyRequest is sent as JSON, or as a multipart form when files are
+// attached under "attachments".
+type replyRequest struct {
+	Message string `json:"message" form:"message" binding:"required"`
+}
+
+// List returns a page of the current user's conversations, most recently
+// active first, with the other participant, job title, last message and the
+// user's unread count. The user's thread with the admins is marked support.
+func (cc *ConversationController) List(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "page must be a positive integer")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultConversationPageSize)))
+	if err != nil || limit < 1 || limit > maxConversationPageSize {
+		utils.JSONError(c, http.StatusBadRequest, "limit must be between 1 and 100")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	convs, total, err := cc.MessageService.ListConversations(ctx, userOID, page, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	items := make([]map[string]interface{}, 0, len(convs))
+	for _, conv := range convs {
+		item := map[string]interface{}{
+			"id":              conv.ID,
+			"last_message":    conv.LastMessage,
+			"last_sender_id":  conv.LastSenderID,
+			"last_message_at": conv.LastMessageAt,
+			"unread_count":    conv.Unread[userOID.Hex()],
+		}
+		if conv.Support != nil {
+			item["support"] = true
+			item["status"] = conv.Support.Status
+			item["participant"] = map[string]interface{}{"role": models.RoleAdmin, "name": "Support"}
+		} else if other, ok := conv.Other(userOID); ok {
+			participant := map[string]interface{}{"id": other.UserID, "role": other.Role}
+			if user, err := cc.UserService.FindByID(ctx, other.UserID); err == nil {
+				participant["name"] = user.Name
+				participant["is_premium"] = user.IsPremium
+			}
+			item["participant"] = participant
+		}
+		if conv.JobID != nil {
+			item["job_id"] = conv.JobID
+			if cc.JobService != nil {
+				if job, err := cc.JobService.FindByID(ctx, *conv.JobID); err == nil {
+					item["job_title"] = job.Title
+				}
+			}
+		}
+		items = append(items, item)
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"items": items, "page": page, "limit": limit, "total": total})
+}
+
+// Messages returns a conversation's history, oldest first, a page at a
+// time: ?before=<message id> returns the messages older than that one.
+func (cc *ConversationController) Messages(c *gin.Context) {
+	conv, ok := cc.participantConversation(c)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultThreadPageSize)))
+	if err != nil || limit < 1 || limit > maxThreadPageSize {
+		utils.JSONError(c, http.StatusBadRequest, "limit must be between 1 and 100")
+		return
+	}
+	var before primitive.ObjectID
+	if raw := c.Query("before"); raw != "" {
+		if before, err = primitive.ObjectIDFromHex(raw); err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "invalid before message id")
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	messages, hasMore, err := cc.MessageService.ConversationMessages(ctx, conv.ID, userOID, before, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := gin.H{"conversation": conv, "messages": messages, "has_more": hasMore}
+	if hasMore && len(messages) > 0 {
+		resp["next_before"] = messages[0].ID
+	}
+	utils.JSON(c, http.StatusOK, resp)
+}
+
+// Reply sends a message to the other participant of a conversation, keeping
+// its job context. Replies in a support thread go back to the support queue.
+func (cc *ConversationController) Reply(c *gin.Context) {
+	limitMessageBody(c, cc.AttachmentService)
+	var req replyRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	conv, ok := cc.participantConversation(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	other, ok := conv.Other(userOID)
+	if conv.Support != nil {
+		other, ok = models.ConversationParticipant{Role: models.RoleAdmin}, true
+	}
+	if !ok || !canMessage(role.(string), other.Role) {
+		utils.JSONError(c, http.StatusForbidden, "you cannot reply to this conversation")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	message := models.Message{
+		FromUserID: userOID,
+		FromRole:   role.(string),
+		ToUserID:   other.UserID,
+		ToRole:     other.Role,
+		Message:    req.Message,
+	}
+	if conv.JobID != nil {
+		message.JobID = *conv.JobID
+	}
+	attachments, ok := readAttachments(c, cc.AttachmentService)
+	if !ok {
+		return
+	}
+	message.Attachments = attachments
+	created, err := cc.MessageService.Create(ctx, message)
+	if err != nil {
+		if len(attachments) > 0 {
+			cc.AttachmentService.Delete(ctx, attachments)
+		}
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusCreated, created)
+}
+
+// MarkRead marks every message to the current user in a conversation as read.
+func (cc *ConversationController) MarkRead(c *gin.Context) {
+	conv, ok := cc.participantConversation(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	n, err := cc.MessageService.MarkConversationRead(ctx, conv.ID, userOID)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"marked_read": n})
+}
+
+// participantConversation loads the :id conversation, answering 404 unless
+// the current user takes part in it.
+func (cc *ConversationController) participantConversation(c *gin.Context) (models.Conversation, bool) {
+	convOID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid conversation id")
+		return models.Conversation{}, false
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	conv, err := cc.MessageService.FindConversation(ctx, convOID)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "conversation not found")
+		return models.Conversation{}, false
+	}
+	if _, ok := conv.Participant(userOID); !ok {
+		utils.JSONError(c, http.StatusNotFound, "conversation not found")
+		return models.Conversation{}, false
+	}
+	return conv, true
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/insights"
+	"rizeos/backend/internal/ranking"
+	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/utils"
+)
+
+// Seeker insight defaults: qualification threshold (skills-only ranking score)
+// and the trend window of six 30-day periods.
+const (
+	defaultInsightThreshold = 60.0
+	insightTrendPeriod      = 30 * 24 * time.Hour
+	insightTrendPeriods     = 6
+	insightListLimit        = 10
+	insightPathSteps        = 3
+)
+
+// InsightsController serves skill-gap and career-path insights to job seekers.
+type InsightsController struct {
+	UserService  *services.UserService
+	JobService   *services.JobService
+	SkillService *services.SkillService
+}
+
+// GetSeekerInsights compares the seeker's skills with demand across active
+// jobs: the jobs they already qualify for, missing skills ranked by how many
+// more jobs each would unlock, a suggested learning path, and trending skills.
+// A job counts as qualified when the skills-only ranking score reaches the
+// threshold query parameter (default 60).
+func (ic *InsightsController) GetSeekerInsights(c *gin.Context) {
+	threshold := defaultInsightThreshold
+	if raw := c.Query("threshold"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v <= 0 || v > 100 {
+			utils.JSONError(c, http.StatusBadRequest, "threshold must be between 0 and 100")
+			return
+		}
+		threshold = v
+	}
+	userID, _ := c.Get("user_id")
+	seekerOID, err := primitive.ObjectIDFromHex(userID.(string))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	seeker, err := ic.UserService.FindByID(ctx, seekerOID)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "user not found")
+		return
+	}
+	jobs, err := ic.JobService.List(ctx, bson.M{})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "failed to fetch jobs: "+err.Error())
+		return
+	}
+
+	skills := seeker.Skills
+	if ic.SkillService != nil {
+		skills = ic.SkillService.Expand(ctx, skills)
+	}
+	engine := ranking.NewEngine(ranking.SkillScorer{})
+	active := make([]insights.Job, 0, len(jobs))
+	demandSets := make([][]string, 0, len(jobs))
+	for _, job := range jobs {
+		rj := rankingJobFor(ctx, ic.SkillService, job)
+		jobSkills := make([]string, 0, len(rj.Profile.Skills))
+		for _, req := range rj.Profile.Skills {
+			jobSkills = append(jobSkills, req.Skill)
+		}
+		demandSets = append(demandSets, jobSkills)
+		active = append(active, insights.Job{
+			Skills:   jobSkills,
+			PostedAt: job.CreatedAt,
+			Score: func(s []string) float64 {
+				return engine.Score(rj, ranking.Candidate{Skills: s}).Score
+			},
+		})
+	}
+
+	qualified, gaps := insights.SkillGaps(skills, active, threshold, insightListLimit)
+	demand := insights.CountSkills(demandSets)
+	if len(demand) > insightListLimit {
+		demand = demand[:insightListLimit]
+	}
+	utils.JSON(c, http.StatusOK, gin.H{
+		"threshold":     threshold,
+		"totalJobs":     len(active),
+		"qualifiedJobs": qualified,
+		"topDemand":     demand,
+		"skillGaps":     gaps,
+		"careerPath":    insights.CareerPath(skills, active, threshold, insightPathSteps),
+		"trending":      insights.Trending(active, time.Now(), insightTrendPeriod, insightTrendPeriods, insightListLimit),
+	})
+}
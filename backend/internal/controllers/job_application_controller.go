@@ -2,13 +2,18 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/ranking"
 	"rizeos/backend/internal/services"
 	"rizeos/backend/internal/utils"
 )
@@ -18,7 +23,11 @@ type JobApplicationController struct {
 	JobApplicationService *services.JobApplicationService
 	JobService            *services.JobService
 	UserService           *services.UserService
-	AIService             *services.AIService
+	Matcher               services.Matcher
+	SkillService          *services.SkillService
+	MatchScoreService     *services.MatchScoreService
+	AnalyticsService      *services.AnalyticsService // nil skips funnel tracking
+	NotificationService   *services.NotificationService // nil skips notifications
 }
 
 type applyJobRequest struct {
@@ -59,13 +68,17 @@ func (j *JobApplicationController) Apply(c *gin.Context) {
 		utils.JSONError(c, http.StatusNotFound, "job not found")
 		return
 	}
+	if job.Closed(time.Now()) {
+		utils.JSONError(c, http.StatusBadRequest, "this job is no longer accepting applications")
+		return
+	}
 
 	// Create application
 	application := models.JobApplication{
 		JobID:             jobOID,
 		JobSeekerID:       jobSeekerOID,
 		RecruiterID:       job.RecruiterID,
-		ApplicationStatus: "APPLIED",
+		ApplicationStatus: models.ApplicationApplied,
 		AppliedAt:         time.Now(),
 	}
 
@@ -92,9 +105,98 @@ func (j *JobApplicationController) Apply(c *gin.Context) {
 		_ = j.JobService.SetCandidates(ctx, jobOID, job.Candidates)
 	}
 
+	j.recordEvent(ctx, created, services.EventApply, created.AppliedAt)
+	applicant := "A job seeker"
+	if seeker, err := j.UserService.FindByID(ctx, jobSeekerOID); err == nil && seeker.Name != "" {
+		applicant = seeker.Name
+	}
+	notify(ctx, j.NotificationService, models.Notification{
+		UserID: job.RecruiterID,
+		Type:   models.NotifyNewApplicant,
+		Title:  "New applicant for " + job.Title,
+		Body:   applicant + " applied to " + job.Title + ".",
+		Data:   map[string]string{"job_id": job.ID.Hex(), "application_id": created.ID.Hex()},
+	})
 	utils.JSON(c, http.StatusCreated, created)
 }
 
+type updateApplicationStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// UpdateStatus moves an application through the hiring funnel (recruiter only):
+// APPLIED -> SHORTLISTED -> HIRED, or to REJECTED from either of the first two.
+func (j *JobApplicationController) UpdateStatus(c *gin.Context) {
+	appOID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid application id")
+		return
+	}
+	var req updateApplicationStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := strings.ToUpper(strings.TrimSpace(req.Status))
+
+	userID, _ := c.Get("user_id")
+	recruiterOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	app, err := j.JobApplicationService.FindByID(ctx, appOID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.JSONError(c, http.StatusNotFound, "application not found")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if app.RecruiterID != recruiterOID {
+		utils.JSONError(c, http.StatusForbidden, "you do not own this job")
+		return
+	}
+
+	now := time.Now()
+	updated, err := j.JobApplicationService.UpdateStatus(ctx, appOID, status, now)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStatusTransition) {
+			utils.JSONError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if event, ok := services.StatusEvent(status); ok {
+		j.recordEvent(ctx, updated, event, now)
+	}
+	jobTitle := "a job"
+	if job, err := j.JobService.FindByID(ctx, updated.JobID); err == nil {
+		jobTitle = job.Title
+	}
+	notify(ctx, j.NotificationService, models.Notification{
+		UserID: updated.JobSeekerID,
+		Type:   models.NotifyApplicationStatus,
+		Title:  "Application update: " + jobTitle,
+		Body:   "Your application for " + jobTitle + " is now " + strings.ToLower(updated.ApplicationStatus) + ".",
+		Data:   map[string]string{"job_id": updated.JobID.Hex(), "application_id": updated.ID.Hex(), "status": updated.ApplicationStatus},
+	})
+	utils.JSON(c, http.StatusOK, updated)
+}
+
+// recordEvent counts a funnel event for the application's job. Failures are
+// logged rather than failing the request; rollups can be rebuilt at startup.
+func (j *JobApplicationController) recordEvent(ctx context.Context, app models.JobApplication, event string, at time.Time) {
+	if j.AnalyticsService == nil {
+		return
+	}
+	if err := j.AnalyticsService.Record(ctx, app.JobID, app.RecruiterID, event, at); err != nil {
+		log.Printf("analytics: recording %s for job %s failed: %v", event, app.JobID.Hex(), err)
+	}
+}
+
 // GetApplicants returns all applicants for a specific job (recruiter only).
 func (j *JobApplicationController) GetApplicants(c *gin.Context) {
 	jobID := c.Param("jobId")
@@ -144,56 +246,48 @@ func (j *JobApplicationController) GetApplicants(c *gin.Context) {
 
 	// Enrich with job seeker details and fitment scores
 	type applicantDTO struct {
+		ApplicationID string   `json:"applicationId"`
+		Status        string   `json:"status"`
 		JobSeekerID   string   `json:"jobSeekerId"`
 		Name          string   `json:"name"`
 		Email         string   `json:"email"`
 		PremiumUser   bool     `json:"premiumUser"`
 		ProfileStatus string   `json:"profileStatus"` // "ACTIVE" or "INACTIVE"
 		Skills        []string `json:"skills"`
-		Experience    interface{} `json:"experience"`
-		Education     string   `json:"education"`
+		Experience    []models.Position `json:"experience"`
+		ExperienceYears float64 `json:"experienceYears"`
+		Education     []models.Degree `json:"education"`
 		AppliedAt     string   `json:"appliedAt"`
 		FitmentScore  *float64 `json:"fitmentScore,omitempty"`
+		Explanation   *ranking.Explanation `json:"explanation,omitempty"`
 	}
 
 	applicants := make([]applicantDTO, 0, len(applications))
 
+	// Get job seeker details
+	seekers := make([]models.User, 0, len(applications))
+	applied := make([]models.JobApplication, 0, len(applications))
 	for _, app := range applications {
-		// Get job seeker details
 		seeker, err := j.UserService.FindByID(ctx, app.JobSeekerID)
 		if err != nil {
 			continue // Skip if user not found
 		}
+		seekers = append(seekers, seeker)
+		applied = append(applied, app)
+	}
 
-		// Calculate fitment score if possible
-		var fitmentScore *float64
-		candidateBio := seeker.Bio
-		if seeker.Summary != "" {
-			candidateBio = seeker.Summary
-		}
-		if candidateBio == "" {
-			candidateBio = seeker.Name
-		}
+	// Fitment scores for all applicants: cached, with misses scored in one batch
+	results := matchBreakdownsForJob(ctx, j.MatchScoreService, j.Matcher, job, seekers)
 
-		if j.AIService != nil && candidateBio != "" {
-			score, err := j.AIService.MatchScoreWithSkills(ctx, job.Description, candidateBio, job.Skills, seeker.Skills)
-			if err == nil {
-				// Clamp score to 0-100
-				if score < 0 {
-					score = 0
-				}
-				if score > 100 {
-					score = 100
-				}
-				fitmentScore = &score
-				
-				// Store calculated score in job.MatchScores for future use by GetRankedJobSeekers
-				// This ensures scores are available without recalculation
-				if job.MatchScores == nil {
-					job.MatchScores = make(map[string]float64)
-				}
-				job.MatchScores[app.JobSeekerID.Hex()] = score
-			}
+	for i, seeker := range seekers {
+		app := applied[i]
+		var fitmentScore *float64
+		var explanation *ranking.Explanation
+		if (j.Matcher != nil || j.MatchScoreService != nil) && services.CandidateText(seeker) != "" && results[i].Err == nil {
+			score := results[i].Score
+			fitmentScore = &score
+			exp := explainMatch(ctx, j.SkillService, job, seeker, results[i].MatchBreakdown)
+			explanation = &exp
 		}
 
 		// Determine profile status
@@ -203,6 +297,8 @@ func (j *JobApplicationController) GetApplicants(c *gin.Context) {
 		}
 
 		applicants = append(applicants, applicantDTO{
+			ApplicationID: app.ID.Hex(),
+			Status:        app.ApplicationStatus,
 			JobSeekerID:   app.JobSeekerID.Hex(),
 			Name:          seeker.Name,
 			Email:         seeker.Email,
@@ -210,9 +306,11 @@ func (j *JobApplicationController) GetApplicants(c *gin.Context) {
 			ProfileStatus: profileStatus,
 			Skills:        seeker.Skills,
 			Experience:    seeker.Experience,
+			ExperienceYears: utils.TotalExperienceYears(seeker.Experience, time.Now()),
 			Education:     seeker.Education,
 			AppliedAt:     app.AppliedAt.Format(time.RFC3339),
 			FitmentScore:  fitmentScore,
+			Explanation:   explanation,
 		})
 	}
 
@@ -221,13 +319,6 @@ func (j *JobApplicationController) GetApplicants(c *gin.Context) {
 		return applicants[i].AppliedAt > applicants[j].AppliedAt
 	})
 
-	// Store updated match scores back to job (async, non-blocking)
-	if job.MatchScores != nil && len(job.MatchScores) > 0 {
-		go func() {
-			_ = j.JobService.SetMatchScores(context.Background(), jobOID, job.MatchScores)
-		}()
-	}
-
 	utils.JSON(c, http.StatusOK, gin.H{
 		"jobId":     jobID,
 		"jobTitle":  job.Title,
@@ -2,8 +2,13 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,7 +16,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"rizeos/backend/internal/jobquality"
 	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/ranking"
 	"rizeos/backend/internal/services"
 	"rizeos/backend/internal/utils"
 )
@@ -20,8 +27,16 @@ import (
 type JobController struct {
 	JobService       *services.JobService
 	PaymentService   *services.PaymentService
-	AIService        *services.AIService
+	Matcher          services.Matcher
 	UserService      *services.UserService
+	SkillService     *services.SkillService
+	SkillExtractor   services.SkillExtractor // used by Analyze; nil skips the unlisted-skills check
+	RankingEngine    *ranking.Engine // nil uses ranking.Default()
+	MatchScoreService *services.MatchScoreService // nil calls the AI service on every request
+	JobViewService   *services.JobViewService   // nil skips view tracking
+	AnalyticsService *services.AnalyticsService // popularity for sort=trending; nil disables it
+	NotificationService *services.NotificationService // nil skips new matching job notifications
+	PostService      *services.PostService // nil skips job posts in the feed
 	PlatformFeeMatic float64
 }
 
@@ -33,6 +48,8 @@ type createJobRequest struct {
 	Tags        []string `json:"tags"`
 	Budget      float64  `json:"budget"`
 	PaymentID   string   `json:"payment_id" binding:"required"`
+	ScoringProfile *models.ScoringProfile `json:"scoring_profile"`
+	ExpiresAt   *time.Time `json:"expires_at"` // optional closing date for applications
 }
 
 // Create handles job creation after payment verification.
@@ -73,15 +90,34 @@ func (j *JobController) Create(c *gin.Context) {
 		_ = j.PaymentService.AttachRecruiter(ctx, paymentOID, recruiterOID)
 	}
 
+	skills := req.Skills
+	if j.SkillService != nil {
+		skills = j.SkillService.Normalize(ctx, skills)
+	}
+	if req.ScoringProfile != nil {
+		profile, err := j.cleanScoringProfile(ctx, *req.ScoringProfile)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.ScoringProfile = &profile
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		utils.JSONError(c, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
 	job := models.Job{
 		RecruiterID: recruiterOID,
 		Title:       req.Title,
 		Description: req.Description,
-		Skills:      req.Skills,
+		Skills:      skills,
 		Location:    req.Location,
 		Tags:        req.Tags,
 		Budget:      req.Budget,
 		PaymentID:   paymentOID,
+		ScoringProfile: req.ScoringProfile,
+		ExpiresAt:   req.ExpiresAt,
 	}
 
 	created, err := j.JobService.Create(ctx, job)
@@ -90,9 +126,181 @@ func (j *JobController) Create(c *gin.Context) {
 		return
 	}
 	_ = j.PaymentService.MarkConsumed(ctx, paymentOID, created.ID)
+	go j.notifyMatchingSeekers(created)
+	if j.PostService != nil {
+		if _, err := j.PostService.CreateForJob(ctx, created); err != nil {
+			log.Printf("feed: job post for job %s failed: %v", created.ID.Hex(), err)
+		}
+	}
 	utils.JSON(c, http.StatusCreated, created)
 }
 
+type analyzeJobRequest struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Skills      []string `json:"skills"`
+	Location    string   `json:"location"`
+	Budget      float64  `json:"budget"`
+}
+
+// Analyze reviews a draft job before the recruiter pays to post it: missing or
+// vague fields, description length, skills mentioned but not listed, biased
+// wording, and how many current seekers match the listed skills.
+func (j *JobController) Analyze(c *gin.Context) {
+	var req analyzeJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	draft := jobquality.Draft{Title: req.Title, Description: req.Description, Skills: req.Skills, Location: req.Location, Budget: req.Budget}
+	var in jobquality.Inputs
+	if j.SkillService != nil {
+		draft.Skills = j.SkillService.Normalize(ctx, req.Skills)
+	}
+
+	engine := ""
+	if j.SkillExtractor != nil && strings.TrimSpace(req.Description) != "" {
+		extracted, used, err := j.SkillExtractor.ExtractSkillsDetailed(ctx, req.Description)
+		if err == nil {
+			engine = used
+			if j.SkillService != nil {
+				extracted = j.SkillService.Normalize(ctx, extracted)
+			}
+			// Non-nil even when empty: nil tells the analyzer extraction failed.
+			in.ExtractedSkills = append([]string{}, extracted...)
+		}
+	}
+
+	seekers, err := j.UserService.Search(ctx, models.RoleSeeker, "", nil)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, seeker := range seekers {
+		if seeker.IsActive != nil && !*seeker.IsActive {
+			continue
+		}
+		skills := seeker.Skills
+		if j.SkillService != nil {
+			skills = j.SkillService.Expand(ctx, skills)
+		}
+		in.SeekerSkills = append(in.SeekerSkills, skills)
+	}
+	jobs, err := j.JobService.List(ctx, bson.M{})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, job := range jobs {
+		in.Budgets = append(in.Budgets, job.Budget)
+	}
+
+	report := jobquality.Analyze(draft, in)
+	utils.JSON(c, http.StatusOK, gin.H{"report": report, "engine": engine})
+}
+
+type updateJobRequest struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Skills      *[]string `json:"skills"`
+	Location    *string   `json:"location"`
+	Tags        *[]string `json:"tags"`
+	Budget      *float64  `json:"budget"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// Update lets the owning recruiter edit a job's content. Cached match scores
+// for the job are invalidated by the JobService update listeners.
+func (j *JobController) Update(c *gin.Context) {
+	jobOID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid job id")
+		return
+	}
+	var req updateJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	recruiterOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	job, err := j.JobService.FindByID(ctx, jobOID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.JSONError(c, http.StatusNotFound, "job not found")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if job.RecruiterID != recruiterOID {
+		utils.JSONError(c, http.StatusForbidden, "you do not own this job")
+		return
+	}
+
+	update := bson.M{}
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			utils.JSONError(c, http.StatusBadRequest, "title cannot be empty")
+			return
+		}
+		update["title"] = *req.Title
+	}
+	if req.Description != nil {
+		if strings.TrimSpace(*req.Description) == "" {
+			utils.JSONError(c, http.StatusBadRequest, "description cannot be empty")
+			return
+		}
+		update["description"] = *req.Description
+	}
+	if req.Skills != nil {
+		skills := *req.Skills
+		if j.SkillService != nil {
+			skills = j.SkillService.Normalize(ctx, skills)
+		}
+		update["skills"] = skills
+	}
+	if req.Location != nil {
+		update["location"] = *req.Location
+	}
+	if req.Tags != nil {
+		update["tags"] = *req.Tags
+	}
+	if req.Budget != nil {
+		if *req.Budget < 0 {
+			utils.JSONError(c, http.StatusBadRequest, "budget cannot be negative")
+			return
+		}
+		update["budget"] = *req.Budget
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			utils.JSONError(c, http.StatusBadRequest, "expires_at must be in the future")
+			return
+		}
+		update["expires_at"] = *req.ExpiresAt
+	}
+	if len(update) == 0 {
+		utils.JSON(c, http.StatusOK, job)
+		return
+	}
+
+	updated, err := j.JobService.Update(ctx, jobOID, update)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, updated)
+}
+
 // List returns job listings with optional filters and AI match scores.
 func (j *JobController) List(c *gin.Context) {
 	filters := bson.M{}
@@ -114,6 +322,23 @@ func (j *JobController) List(c *gin.Context) {
 		return
 	}
 
+	// sort=trending orders by recent views and applications, newest first on ties
+	var popularity map[primitive.ObjectID]float64
+	if c.Query("sort") == "trending" && j.AnalyticsService != nil {
+		popularity, err = j.AnalyticsService.Popularity(ctx, time.Now())
+		if err != nil {
+			utils.JSONError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		sort.SliceStable(jobs, func(a, b int) bool {
+			pa, pb := popularity[jobs[a].ID], popularity[jobs[b].ID]
+			if pa != pb {
+				return pa > pb
+			}
+			return jobs[a].CreatedAt.After(jobs[b].CreatedAt)
+		})
+	}
+
 	// Enrich jobs with recruiter info and match scores
 	enrichedJobs := make([]map[string]interface{}, 0, len(jobs))
 	for _, jb := range jobs {
@@ -129,12 +354,10 @@ func (j *JobController) List(c *gin.Context) {
 			"updated_at":  jb.UpdatedAt,
 			"candidates":  jb.Candidates,
 			"recruiter_id": jb.RecruiterID,
+			"match_scores": map[string]float64{},
 		}
-		// Initialize match_scores as empty map if nil
-		if jb.MatchScores == nil {
-			enriched["match_scores"] = map[string]float64{}
-		} else {
-			enriched["match_scores"] = jb.MatchScores
+		if popularity != nil {
+			enriched["popularity"] = math.Round(popularity[jb.ID]*100) / 100
 		}
 		// Add recruiter name
 		recruiter, err := j.UserService.FindByID(ctx, jb.RecruiterID)
@@ -147,20 +370,17 @@ func (j *JobController) List(c *gin.Context) {
 	// Attach match score if seeker logged in.
 	userID, ok := c.Get("user_id")
 	role, roleOk := c.Get("role")
-	if ok && roleOk && role.(string) == models.RoleSeeker && j.AIService != nil {
+	if ok && roleOk && role.(string) == models.RoleSeeker && (j.Matcher != nil || j.MatchScoreService != nil) {
 		oid, _ := primitive.ObjectIDFromHex(userID.(string))
 		user, err := j.UserService.FindByID(ctx, oid)
-		if err == nil && user.Bio != "" {
+		if err == nil && services.CandidateText(user) != "" {
+			// Served from the score store; new or changed pairs are scored in one batch.
+			results := matchBreakdownsForSeeker(ctx, j.MatchScoreService, j.Matcher, user, jobs)
 			for idx, enriched := range enrichedJobs {
-				jb := jobs[idx]
-				score, err := j.AIService.MatchScore(ctx, jb.Description, user.Bio)
-				if err == nil {
-					scores, ok := enriched["match_scores"].(map[string]float64)
-					if !ok {
-						scores = map[string]float64{}
-					}
-					scores[user.ID.Hex()] = score
-					enriched["match_scores"] = scores
+				res := results[idx]
+				if res.Err == nil {
+					enriched["match_scores"] = map[string]float64{user.ID.Hex(): res.Score}
+					enriched["match_explanation"] = explainMatch(ctx, j.SkillService, jobs[idx], user, res.MatchBreakdown)
 					enrichedJobs[idx] = enriched
 				}
 			}
@@ -196,6 +416,22 @@ func (j *JobController) GetJobProfile(c *gin.Context) {
 		return
 	}
 
+	// Track the view, once per viewer per day; the owner's own views are not counted
+	userID, _ := c.Get("user_id")
+	viewerID, _ := userID.(string)
+	isOwner := viewerID != "" && viewerID == job.RecruiterID.Hex()
+	if j.JobViewService != nil && !isOwner {
+		var err error
+		if oid, parseErr := primitive.ObjectIDFromHex(viewerID); parseErr == nil {
+			_, err = j.JobViewService.Track(ctx, job, services.UserViewerKey(oid), &oid, time.Now())
+		} else {
+			_, err = j.JobViewService.TrackAnonymous(ctx, job, c.ClientIP(), c.Request.UserAgent(), time.Now())
+		}
+		if err != nil {
+			log.Printf("job view tracking failed for job %s: %v", job.ID.Hex(), err)
+		}
+	}
+
 	// Get recruiter information
 	var recruiter map[string]interface{}
 	recruiterUser, err := j.UserService.FindByID(ctx, job.RecruiterID)
@@ -222,6 +458,20 @@ func (j *JobController) GetJobProfile(c *gin.Context) {
 	stats := map[string]interface{}{
 		"applications": applicationsCount,
 	}
+	// View counts are only shown to the recruiter who owns the job
+	if isOwner && j.JobViewService != nil {
+		if counts, err := j.JobViewService.Counts(ctx, []primitive.ObjectID{job.ID}); err == nil {
+			stats["views"] = counts[job.ID].Views
+			stats["viewers"] = counts[job.ID].Viewers
+		}
+	}
+
+	matchScores := map[string]float64{}
+	if j.MatchScoreService != nil {
+		if scores, err := j.MatchScoreService.ScoresForJob(ctx, jobOID); err == nil {
+			matchScores = scores
+		}
+	}
 
 	response := gin.H{
 		"id":          job.ID,
@@ -236,7 +486,7 @@ func (j *JobController) GetJobProfile(c *gin.Context) {
 		"updated_at":  job.UpdatedAt,
 		"recruiter":   recruiter,
 		"stats":       stats,
-		"match_scores": job.MatchScores,
+		"match_scores": matchScores,
 	}
 
 	utils.JSON(c, http.StatusOK, response)
@@ -266,6 +516,10 @@ func (j *JobController) Apply(c *gin.Context) {
 		utils.JSONError(c, http.StatusNotFound, "job not found")
 		return
 	}
+	if job.Closed(time.Now()) {
+		utils.JSONError(c, http.StatusBadRequest, "this job is no longer accepting applications")
+		return
+	}
 
 	// Add candidate if not already present.
 	exists := false
@@ -325,21 +579,32 @@ func (j *JobController) GetRankedJobSeekers(c *gin.Context) {
 		return
 	}
 
-	// Use SAME service as Job Seeker dashboard: AIService.MatchScoreWithSkills
-	// Strategy: Try stored scores first, calculate if missing, then store for future use
+	// Same scores as the Job Seeker dashboard, served from the match score store
 	type rankedSeeker struct {
 		JobSeekerID  string   `json:"jobSeekerId"`
 		Name         string   `json:"name"`
 		Email        string   `json:"email"`
 		Skills       []string `json:"skills"`
-		FitmentScore float64  `json:"fitmentScore"`
+		FitmentScore float64  `json:"fitmentScore"` // raw AI similarity
+		RankScore    float64  `json:"rankScore"`    // engine score combining AI similarity with profile factors
+		ExperienceYears float64 `json:"experienceYears"`
 		IsPremium    bool     `json:"isPremium"`
+		Breakdown    ranking.Result `json:"breakdown"`
+		Explanation  ranking.Explanation `json:"explanation"`
 	}
 
+	minExperience, err := parseMinExperience(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	now := time.Now()
+	rankJob := j.rankingJob(ctx, job)
+	engine := j.rankingEngine()
+
 	var ranked []rankedSeeker
 	jobDesc := strings.TrimSpace(job.Description)
-	updatedScores := make(map[string]float64) // Track scores to store back
-	
+
 	// If job description is empty, return empty list
 	if jobDesc == "" {
 		utils.JSON(c, http.StatusOK, gin.H{
@@ -350,89 +615,70 @@ func (j *JobController) GetRankedJobSeekers(c *gin.Context) {
 		return
 	}
 
-	// For each seeker, get fitment score (stored first, then calculate if needed)
+	// Filter first so only eligible seekers are scored
+	eligible := make([]models.User, 0, len(seekers))
+	candidates := make([]ranking.Candidate, 0, len(seekers))
 	for _, seeker := range seekers {
-		seekerIDHex := seeker.ID.Hex()
-		
-		// Prepare candidate bio (same logic as GetApplicants)
-		candidateBio := seeker.Bio
-		if seeker.Summary != "" {
-			candidateBio = seeker.Summary
-		}
-		if candidateBio == "" {
-			candidateBio = seeker.Name
-		}
-		candidateBioTrimmed := strings.TrimSpace(candidateBio)
-		
-		// Skip if candidate bio is empty
-		if candidateBioTrimmed == "" {
+		candidate := j.rankingCandidate(ctx, seeker, now)
+		if candidate.ExperienceYears < minExperience {
 			continue
 		}
-		
-		var score float64
-		scoreFound := false
-		
-		// STEP 1: Try to use stored score from job.MatchScores (if exists)
-		if job.MatchScores != nil {
-			if storedScore, exists := job.MatchScores[seekerIDHex]; exists && storedScore > 0 {
-				score = storedScore
-				scoreFound = true
-				updatedScores[seekerIDHex] = score // Preserve stored score
-			}
+
+		// Skip if candidate bio is empty
+		if services.CandidateText(seeker) == "" {
+			continue
 		}
-		
-		// STEP 2: If no stored score, calculate using SAME service as Job Seeker
-		if !scoreFound && j.AIService != nil {
-			calculatedScore, err := j.AIService.MatchScoreWithSkills(ctx, jobDesc, candidateBioTrimmed, job.Skills, seeker.Skills)
-			if err != nil {
-				// If AI service fails, skip this seeker (no default 0)
-				continue
-			}
-			// Clamp score to 0-100
-			if calculatedScore < 0 {
-				calculatedScore = 0
-			}
-			if calculatedScore > 100 {
-				calculatedScore = 100
-			}
-			score = calculatedScore
-			updatedScores[seekerIDHex] = score // Store calculated score
+		eligible = append(eligible, seeker)
+		candidates = append(candidates, candidate)
+	}
+
+	// Fitment scores: cached, with misses scored in one batch
+	results := matchBreakdownsForJob(ctx, j.MatchScoreService, j.Matcher, job, eligible)
+	for i, seeker := range eligible {
+		candidate := candidates[i]
+		if results[i].Err != nil {
+			// If AI service fails, skip this seeker (no default 0)
+			continue
 		}
-		
+		breakdown := results[i].MatchBreakdown
+		score := breakdown.Score
+
 		// Only include seekers with valid scores (> 0)
 		// NO DEFAULT 0 - if score is 0, skip user (as per requirements)
 		if score <= 0 {
 			continue
 		}
 
+		// The engine weighs skills itself, so it gets the semantic component
+		// of the hybrid score, estimated when the AI did not report it.
+		explanation := explainMatch(ctx, j.SkillService, job, seeker, breakdown)
+		semantic := explanation.SemanticScore
+		candidate.SemanticScore = &semantic
+		result := engine.Score(rankJob, candidate)
+
 		ranked = append(ranked, rankedSeeker{
-			JobSeekerID:  seekerIDHex,
+			JobSeekerID:  seeker.ID.Hex(),
 			Name:         seeker.Name,
 			Email:        seeker.Email,
 			Skills:       seeker.Skills,
 			FitmentScore: score,
+			RankScore:    result.Score,
+			ExperienceYears: candidate.ExperienceYears,
 			IsPremium:    seeker.IsPremium,
+			Breakdown:    result,
+			Explanation:  explanation,
 		})
 	}
-	
-	// Store calculated scores back to job for future use (async, non-blocking)
-	if len(updatedScores) > 0 {
-		go func() {
-			// Merge with existing scores
-			if job.MatchScores == nil {
-				job.MatchScores = make(map[string]float64)
-			}
-			for k, v := range updatedScores {
-				job.MatchScores[k] = v
-			}
-			// Update job with new scores (non-blocking)
-			_ = j.JobService.SetMatchScores(context.Background(), jobOID, job.MatchScores)
-		}()
-	}
 
-	// Sort by fitment score descending (efficient sort)
+	// Sort by engine score descending, then raw fitment and experience on ties
 	sort.Slice(ranked, func(i, j int) bool {
-		return ranked[i].FitmentScore > ranked[j].FitmentScore
+		if ranked[i].RankScore != ranked[j].RankScore {
+			return ranked[i].RankScore > ranked[j].RankScore
+		}
+		if ranked[i].FitmentScore != ranked[j].FitmentScore {
+			return ranked[i].FitmentScore > ranked[j].FitmentScore
+		}
+		return ranked[i].ExperienceYears > ranked[j].ExperienceYears
 	})
 
 	response := gin.H{
@@ -444,9 +690,9 @@ func (j *JobController) GetRankedJobSeekers(c *gin.Context) {
 	utils.JSON(c, http.StatusOK, response)
 }
 
-// GetRecruiterJobRanking returns job seekers ranked by rule-based skill match score.
-// This is SEPARATE from AI-based fitment scores and uses simple skill overlap calculation.
-// DO NOT modify AI service or existing fitment score logic.
+// GetRecruiterJobRanking returns job seekers ranked by the rule-based ranking engine.
+// This is SEPARATE from AI-based fitment scores: skills, experience, location and
+// premium factors are weighted by the job's scoring profile (NO AI calls).
 func (j *JobController) GetRecruiterJobRanking(c *gin.Context) {
 	jobID := c.Param("jobId")
 	if jobID == "" {
@@ -489,26 +735,40 @@ func (j *JobController) GetRecruiterJobRanking(c *gin.Context) {
 		return
 	}
 
-	// Rule-based ranking using skill overlap (NO AI calls)
+	// Rule-based ranking using the weighted engine (NO AI calls)
 	type rankedSeeker struct {
 		UserID            string   `json:"userId"`
 		Name              string   `json:"name"`
 		Email             string   `json:"email"`
 		Skills            []string `json:"skills"`
 		RecruiterRankScore float64 `json:"recruiterRankScore"`
+		ExperienceYears   float64  `json:"experienceYears"`
 		IsPremium         bool     `json:"isPremium"`
+		Breakdown         ranking.Result `json:"breakdown"`
 	}
 
+	minExperience, err := parseMinExperience(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	now := time.Now()
+
 	var ranked []rankedSeeker
-	requiredSkills := job.Skills
+	rankJob := j.rankingJob(ctx, job)
+	engine := j.rankingEngine()
 
 	// Calculate rule-based score for each seeker
 	for _, seeker := range seekers {
-		// Calculate skill overlap score (rule-based, deterministic)
-		score := utils.CalculateRecruiterRankScore(requiredSkills, seeker.Skills)
+		candidate := j.rankingCandidate(ctx, seeker, now)
+		if candidate.ExperienceYears < minExperience {
+			continue
+		}
 
-		// Only include seekers with score > 0
-		if score <= 0 {
+		result := engine.Score(rankJob, candidate)
+
+		// Only include seekers matching at least one skill
+		if len(result.MatchedSkills) == 0 {
 			continue
 		}
 
@@ -517,14 +777,19 @@ func (j *JobController) GetRecruiterJobRanking(c *gin.Context) {
 			Name:              seeker.Name,
 			Email:             seeker.Email,
 			Skills:            seeker.Skills,
-			RecruiterRankScore: score,
+			RecruiterRankScore: result.Score,
+			ExperienceYears:   candidate.ExperienceYears,
 			IsPremium:         seeker.IsPremium,
+			Breakdown:         result,
 		})
 	}
 
-	// Sort by recruiterRankScore descending (highest first)
+	// Sort by recruiterRankScore descending (highest first), then by experience
 	sort.Slice(ranked, func(i, j int) bool {
-		return ranked[i].RecruiterRankScore > ranked[j].RecruiterRankScore
+		if ranked[i].RecruiterRankScore != ranked[j].RecruiterRankScore {
+			return ranked[i].RecruiterRankScore > ranked[j].RecruiterRankScore
+		}
+		return ranked[i].ExperienceYears > ranked[j].ExperienceYears
 	})
 
 	response := gin.H{
@@ -535,3 +800,188 @@ func (j *JobController) GetRecruiterJobRanking(c *gin.Context) {
 
 	utils.JSON(c, http.StatusOK, response)
 }
+
+// parseMinExperience reads the optional minExperience (years) query filter.
+func parseMinExperience(c *gin.Context) (float64, error) {
+	raw := c.Query("minExperience")
+	if raw == "" {
+		return 0, nil
+	}
+	years, err := strconv.ParseFloat(raw, 64)
+	if err != nil || years < 0 {
+		return 0, errors.New("invalid minExperience")
+	}
+	return years, nil
+}
+
+// UpdateScoringProfile lets the owning recruiter configure must-have/nice-to-have
+// skill weights, minimum experience and factor weights for ranking.
+func (j *JobController) UpdateScoringProfile(c *gin.Context) {
+	jobOID, err := primitive.ObjectIDFromHex(c.Param("jobId"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid job id")
+		return
+	}
+	var req models.ScoringProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	recruiterOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	job, err := j.JobService.FindByID(ctx, jobOID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.JSONError(c, http.StatusNotFound, "job not found")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if job.RecruiterID != recruiterOID {
+		utils.JSONError(c, http.StatusForbidden, "you do not own this job")
+		return
+	}
+
+	profile, err := j.cleanScoringProfile(ctx, req)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := j.JobService.SetScoringProfile(ctx, jobOID, profile); err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, profile)
+}
+
+// cleanScoringProfile validates weights and canonicalizes skill names.
+func (j *JobController) cleanScoringProfile(ctx context.Context, profile models.ScoringProfile) (models.ScoringProfile, error) {
+	if profile.MinExperienceYears < 0 {
+		return profile, errors.New("min_experience_years cannot be negative")
+	}
+	for name, w := range profile.FactorWeights {
+		if !ranking.KnownFactor(name) {
+			return profile, errors.New("unknown ranking factor: " + name)
+		}
+		if w < 0 || w > 10 {
+			return profile, errors.New("factor weights must be between 0 and 10")
+		}
+	}
+	seen := map[string]bool{}
+	skills := make([]models.SkillRequirement, 0, len(profile.Skills))
+	for _, req := range profile.Skills {
+		if req.Weight < 0 || req.Weight > 10 {
+			return profile, errors.New("skill weights must be between 0 and 10")
+		}
+		if req.Weight == 0 {
+			req.Weight = 1
+		}
+		names := []string{strings.TrimSpace(req.Skill)}
+		if j.SkillService != nil {
+			names = j.SkillService.Normalize(ctx, names)
+		}
+		if len(names) == 0 || names[0] == "" {
+			continue
+		}
+		req.Skill = names[0]
+		if seen[strings.ToLower(req.Skill)] {
+			return profile, errors.New("duplicate skill in scoring profile: " + req.Skill)
+		}
+		seen[strings.ToLower(req.Skill)] = true
+		skills = append(skills, req)
+	}
+	profile.Skills = skills
+	return profile, nil
+}
+
+func (j *JobController) rankingEngine() *ranking.Engine {
+	if j.RankingEngine != nil {
+		return j.RankingEngine
+	}
+	return ranking.Default()
+}
+
+// rankingJob builds the ranking engine's view of a job with canonical skill names.
+func (j *JobController) rankingJob(ctx context.Context, job models.Job) ranking.Job {
+	return rankingJobFor(ctx, j.SkillService, job)
+}
+
+// rankingJobFor canonicalizes the job's scoring profile skills via the
+// taxonomy when available.
+func rankingJobFor(ctx context.Context, skills *services.SkillService, job models.Job) ranking.Job {
+	profile := ranking.ProfileFor(job)
+	if skills != nil {
+		for i, req := range profile.Skills {
+			if names := skills.Normalize(ctx, []string{req.Skill}); len(names) > 0 {
+				profile.Skills[i].Skill = names[0]
+			}
+		}
+	}
+	return ranking.Job{Location: job.Location, Profile: profile}
+}
+
+// rankingCandidate builds the ranking engine's view of a job seeker. Skills are
+// canonicalized and expanded with taxonomy parents (React implies JavaScript).
+func (j *JobController) rankingCandidate(ctx context.Context, seeker models.User, now time.Time) ranking.Candidate {
+	skills := seeker.Skills
+	if j.SkillService != nil {
+		skills = j.SkillService.Expand(ctx, skills)
+	}
+	return ranking.Candidate{
+		Skills:          skills,
+		ExperienceYears: utils.TotalExperienceYears(seeker.Experience, now),
+		Location:        seeker.Location,
+		IsPremium:       seeker.IsPremium,
+	}
+}
+
+// notifyMatchingSeekers tells active seekers who have at least half of a new
+// job's skills about it. It scans every seeker, so it runs in the background
+// rather than on the request that created the job.
+func (j *JobController) notifyMatchingSeekers(job models.Job) {
+	if j.NotificationService == nil || len(job.Skills) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	seekers, err := j.UserService.Search(ctx, models.RoleSeeker, "", nil)
+	if err != nil {
+		log.Printf("notifications: loading seekers for job %s failed: %v", job.ID.Hex(), err)
+		return
+	}
+	for _, seeker := range seekers {
+		if seeker.IsActive != nil && !*seeker.IsActive {
+			continue
+		}
+		skills := seeker.Skills
+		if j.SkillService != nil {
+			skills = j.SkillService.Expand(ctx, skills)
+		}
+		has := map[string]bool{}
+		for _, s := range skills {
+			has[strings.ToLower(s)] = true
+		}
+		matched := 0
+		for _, s := range job.Skills {
+			if has[strings.ToLower(s)] {
+				matched++
+			}
+		}
+		if matched == 0 || 2*matched < len(job.Skills) {
+			continue
+		}
+		notify(ctx, j.NotificationService, models.Notification{
+			UserID: seeker.ID,
+			Type:   models.NotifyNewMatchingJob,
+			Title:  "New job matching your skills: " + job.Title,
+			Body:   fmt.Sprintf("%s matches %d of its %d listed skills.", job.Title, matched, len(job.Skills)),
+			Data:   map[string]string{"job_id": job.ID.Hex()},
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/ranking"
+	"rizeos/backend/internal/services"
+)
+
+// explainMatch builds the explanation for a scored job/candidate pair, using
+// the skill taxonomy (when available) for canonical names and related skills.
+func explainMatch(ctx context.Context, skills *services.SkillService, job models.Job, seeker models.User, breakdown services.MatchBreakdown) ranking.Explanation {
+	in := ranking.ExplainInput{
+		Score:           breakdown.Score,
+		SemanticScore:   breakdown.SemanticScore,
+		SkillScore:      breakdown.SkillScore,
+		Engine:          breakdown.Engine,
+		JobSkills:       job.Skills,
+		CandidateSkills: seeker.Skills,
+	}
+	if skills != nil {
+		in.JobSkills = skills.Normalize(ctx, job.Skills)
+		in.CandidateSkills = skills.Expand(ctx, seeker.Skills)
+		in.CategoryOf = func(skill string) string { return skills.Category(ctx, skill) }
+	}
+	return ranking.Explain(in)
+}
+
+// matchBreakdown returns the match score for a job/seeker pair from the score
+// store, computing it on a miss. Without a store it calls the AI service directly.
+func matchBreakdown(ctx context.Context, store *services.MatchScoreService, ai services.Matcher, job models.Job, seeker models.User) (services.MatchBreakdown, error) {
+	if store != nil {
+		return store.GetOrCompute(ctx, job, seeker)
+	}
+	text := services.CandidateText(seeker)
+	if ai == nil || strings.TrimSpace(job.Description) == "" || text == "" {
+		return services.MatchBreakdown{}, nil
+	}
+	return ai.MatchScoreDetailed(ctx, job.Description, text, job.Skills, seeker.Skills)
+}
+
+// matchBreakdownsForJob scores many seekers against one job in seeker order,
+// using the score store when available and one batch AI request for misses.
+func matchBreakdownsForJob(ctx context.Context, store *services.MatchScoreService, ai services.Matcher, job models.Job, seekers []models.User) []services.MatchResult {
+	if store != nil {
+		return store.GetOrComputeForJob(ctx, job, seekers)
+	}
+	if ai == nil {
+		return make([]services.MatchResult, len(seekers))
+	}
+	items := make([]services.MatchItem, len(seekers))
+	for i, seeker := range seekers {
+		items[i] = services.SeekerMatchItem(seeker)
+	}
+	return ai.MatchJobAgainstCandidates(ctx, services.JobMatchItem(job), items)
+}
+
+// matchBreakdownsForSeeker scores one seeker against many jobs in job order.
+func matchBreakdownsForSeeker(ctx context.Context, store *services.MatchScoreService, ai services.Matcher, seeker models.User, jobs []models.Job) []services.MatchResult {
+	if store != nil {
+		return store.GetOrComputeForSeeker(ctx, seeker, jobs)
+	}
+	if ai == nil {
+		return make([]services.MatchResult, len(jobs))
+	}
+	items := make([]services.MatchItem, len(jobs))
+	for i, job := range jobs {
+		items[i] = services.JobMatchItem(job)
+	}
+	return ai.MatchCandidateAgainstJobs(ctx, services.SeekerMatchItem(seeker), items)
+}
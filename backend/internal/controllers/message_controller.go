@@ -2,35 +2,62 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"time"
 
 	"rizeos/backend/internal/models"
 	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/storage"
 	"rizeos/backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // MessageController manages message endpoints.
 type MessageController struct {
-	MessageService *services.MessageService
-	UserService    *services.UserService
-	JobService     *services.JobService
+	MessageService    *services.MessageService
+	UserService       *services.UserService
+	JobService        *services.JobService
+	AttachmentService *services.AttachmentService // nil disables attachments
 }
 
+// messageRecipientRoles lists the roles each role may message.
+var messageRecipientRoles = map[string][]string{
+	models.RoleRecruiter: {models.RoleAdmin, models.RoleSeeker}, // Recruiter can message admin and seekers
+	models.RoleSeeker:    {models.RoleRecruiter},
+	models.RoleAdmin:     {models.RoleRecruiter, models.RoleSeeker}, // Admin can message recruiters and seekers
+}
+
+// canMessage reports whether fromRole may message toRole.
+func canMessage(fromRole, toRole string) bool {
+	for _, r := range messageRecipientRoles[fromRole] {
+		if r == toRole {
+			return true
+		}
+	}
+	return false
+}
+
+// sendMessageRequest is sent as JSON, or as a multipart form when files are
+// attached under "attachments".
 type sendMessageRequest struct {
-	ToUserID string `json:"toUserId" binding:"required"`
-	ToRole   string `json:"toRole" binding:"required"`
-	Message  string `json:"message" binding:"required"`
-	JobID    string `json:"jobId,omitempty"` // Optional: for job-context messages
+	ToUserID string `json:"toUserId" form:"toUserId"` // ignored for admins: messages to admins go to the support queue
+	ToRole   string `json:"toRole" form:"toRole" binding:"required"`
+	Message  string `json:"message" form:"message" binding:"required"`
+	JobID    string `json:"jobId,omitempty" form:"jobId"` // Optional: for job-context messages
 }
 
 // Send handles message creation from any role to another role.
 func (m *MessageController) Send(c *gin.Context) {
+	limitMessageBody(c, m.AttachmentService)
 	var req sendMessageRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBind(&req); err != nil {
 		utils.JSONError(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -40,60 +67,40 @@ func (m *MessageController) Send(c *gin.Context) {
 	fromRole := role.(string)
 
 	// Validate role combinations
-	validCombinations := map[string][]string{
-		models.RoleRecruiter: {models.RoleAdmin, models.RoleSeeker}, // Recruiter can message admin and seekers
-		models.RoleSeeker:    {models.RoleRecruiter},
-		models.RoleAdmin:     {models.RoleRecruiter, models.RoleSeeker}, // Admin can message recruiters and seekers
-	}
-
-	allowedRoles, ok := validCombinations[fromRole]
-	if !ok {
+	if _, ok := messageRecipientRoles[fromRole]; !ok {
 		utils.JSONError(c, http.StatusForbidden, "your role cannot send messages")
 		return
 	}
-
-	validToRole := false
-	for _, r := range allowedRoles {
-		if r == req.ToRole {
-			validToRole = true
-			break
-		}
-	}
-	if !validToRole {
+	if !canMessage(fromRole, req.ToRole) {
 		utils.JSONError(c, http.StatusBadRequest, "invalid recipient role for your role")
 		return
 	}
 
 	fromUserOID, _ := primitive.ObjectIDFromHex(userID.(string))
-	toUserOID, err := primitive.ObjectIDFromHex(req.ToUserID)
-	if err != nil {
-		utils.JSONError(c, http.StatusBadRequest, "invalid recipient user id")
-		return
-	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	// Verify recipient exists and has correct role
-	recipient, err := m.UserService.FindByID(ctx, toUserOID)
-	if err != nil {
-		utils.JSONError(c, http.StatusNotFound, "recipient user not found")
-		return
-	}
-	if recipient.Role != req.ToRole {
-		utils.JSONError(c, http.StatusBadRequest, "recipient role mismatch")
-		return
-	}
-
-	// Special case: If recruiter sends to admin, find any admin (backward compatibility)
-	if fromRole == models.RoleRecruiter && req.ToRole == models.RoleAdmin {
-		adminUsers, err := m.UserService.Search(ctx, models.RoleAdmin, "", nil)
-		if err != nil || len(adminUsers) == 0 {
-			utils.JSONError(c, http.StatusInternalServerError, "admin user not found")
+	// Messages to admins go to the sender's support thread in the shared
+	// queue (addressed to its assignee, if any) rather than to one admin.
+	var toUserOID primitive.ObjectID
+	if req.ToRole != models.RoleAdmin {
+		var err error
+		toUserOID, err = primitive.ObjectIDFromHex(req.ToUserID)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "invalid recipient user id")
+			return
+		}
+		// Verify recipient exists and has correct role
+		recipient, err := m.UserService.FindByID(ctx, toUserOID)
+		if err != nil {
+			utils.JSONError(c, http.StatusNotFound, "recipient user not found")
+			return
+		}
+		if recipient.Role != req.ToRole {
+			utils.JSONError(c, http.StatusBadRequest, "recipient role mismatch")
 			return
 		}
-		// Use first admin found
-		toUserOID = adminUsers[0].ID
 	}
 
 	// Validate job context for seeker → recruiter messages
@@ -131,9 +138,17 @@ func (m *MessageController) Send(c *gin.Context) {
 	if !jobOID.IsZero() {
 		message.JobID = jobOID
 	}
+	attachments, ok := readAttachments(c, m.AttachmentService)
+	if !ok {
+		return
+	}
+	message.Attachments = attachments
 
 	created, err := m.MessageService.Create(ctx, message)
 	if err != nil {
+		if len(attachments) > 0 {
+			m.AttachmentService.Delete(ctx, attachments)
+		}
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -141,12 +156,16 @@ func (m *MessageController) Send(c *gin.Context) {
 	utils.JSON(c, http.StatusCreated, created)
 }
 
-// AdminInbox returns all messages for admin.
+// AdminInbox returns the messages in the current admin's assigned support
+// threads and the shared queue; ?archived=true lists their archive instead.
 func (m *MessageController) AdminInbox(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	messages, err := m.MessageService.GetAdminInbox(ctx)
+	messages, err := m.MessageService.GetAdminInbox(ctx, adminOID, c.Query("archived") == "true")
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
@@ -162,7 +181,7 @@ func (m *MessageController) AdminInbox(c *gin.Context) {
 			"from_role":    msg.FromRole,
 			"to_role":      msg.ToRole,
 			"message":      msg.Message,
-			"is_read":      msg.IsRead,
+			"is_read":      msg.ReadFor(adminOID),
 			"created_at":   msg.CreatedAt,
 		}
 		if err == nil {
@@ -176,46 +195,47 @@ func (m *MessageController) AdminInbox(c *gin.Context) {
 	utils.JSON(c, http.StatusOK, enrichedMessages)
 }
 
-// MarkAsRead marks a message as read.
+// MarkAsRead marks a message to the current user as read.
 func (m *MessageController) MarkAsRead(c *gin.Context) {
-	messageID := c.Param("id")
-	if messageID == "" {
-		utils.JSONError(c, http.StatusBadRequest, "missing message id")
-		return
-	}
-
-	messageOID, err := primitive.ObjectIDFromHex(messageID)
-	if err != nil {
-		utils.JSONError(c, http.StatusBadRequest, "invalid message id")
+	messageOID, ok := messageParam(c)
+	if !ok {
 		return
 	}
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := m.MessageService.MarkAsRead(ctx, messageOID); err != nil {
-		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+	if err := m.MessageService.MarkAsRead(ctx, messageOID, userOID, role.(string)); err != nil {
+		messageError(c, err)
 		return
 	}
 
 	utils.JSON(c, http.StatusOK, gin.H{"status": "marked as read"})
 }
 
-// GetUnreadCount returns the count of unread messages for admin.
+// GetUnreadCount returns the current admin's unread support messages: those
+// in threads assigned to them plus those waiting in the shared queue.
 func (m *MessageController) GetUnreadCount(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	count, err := m.MessageService.GetUnreadCount(ctx)
+	assigned, queue, err := m.MessageService.SupportUnreadCounts(ctx, adminOID)
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	utils.JSON(c, http.StatusOK, gin.H{"unread_count": count})
+	utils.JSON(c, http.StatusOK, gin.H{"unread_count": assigned + queue, "assigned": assigned, "queue": queue})
 }
 
-// RecruiterInbox returns all messages for the current recruiter.
+// RecruiterInbox returns all messages for the current recruiter;
+// ?archived=true lists their archive instead.
 func (m *MessageController) RecruiterInbox(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	recruiterOID, _ := primitive.ObjectIDFromHex(userID.(string))
@@ -223,7 +243,7 @@ func (m *MessageController) RecruiterInbox(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	messages, err := m.MessageService.GetRecruiterInbox(ctx, recruiterOID)
+	messages, err := m.MessageService.GetRecruiterInbox(ctx, recruiterOID, c.Query("archived") == "true")
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
@@ -268,7 +288,8 @@ func (m *MessageController) RecruiterInbox(c *gin.Context) {
 	utils.JSON(c, http.StatusOK, enrichedMessages)
 }
 
-// SeekerInbox returns all messages for the current job seeker.
+// SeekerInbox returns all messages for the current job seeker;
+// ?archived=true lists their archive instead.
 func (m *MessageController) SeekerInbox(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	seekerOID, _ := primitive.ObjectIDFromHex(userID.(string))
@@ -276,7 +297,7 @@ func (m *MessageController) SeekerInbox(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	messages, err := m.MessageService.GetSeekerInbox(ctx, seekerOID)
+	messages, err := m.MessageService.GetSeekerInbox(ctx, seekerOID, c.Query("archived") == "true")
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, err.Error())
 		return
@@ -343,3 +364,307 @@ func (m *MessageController) GetRecruiterUnreadCount(c *gin.Context) {
 
 	utils.JSON(c, http.StatusOK, gin.H{"unread_count": count})
 }
+
+type reportMessageRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type reviewReportRequest struct {
+	Status string `json:"status" binding:"required"` // RESOLVED or DISMISSED
+	Note   string `json:"note"`
+}
+
+// MarkAllRead marks every unread message to the current user as read.
+func (m *MessageController) MarkAllRead(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	n, err := m.MessageService.MarkAllRead(ctx, userOID, role.(string))
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"marked_read": n})
+}
+
+// Archive moves a message the current user sent or received to their archive.
+func (m *MessageController) Archive(c *gin.Context) {
+	m.setArchived(c, true)
+}
+
+// Unarchive moves a message back from the current user's archive.
+func (m *MessageController) Unarchive(c *gin.Context) {
+	m.setArchived(c, false)
+}
+
+func (m *MessageController) setArchived(c *gin.Context, archived bool) {
+	messageOID, ok := messageParam(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := m.MessageService.SetArchived(ctx, messageOID, userOID, role.(string), archived); err != nil {
+		messageError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"archived": archived})
+}
+
+// Delete removes a message from the current user's view only; the other
+// party keeps their copy.
+func (m *MessageController) Delete(c *gin.Context) {
+	messageOID, ok := messageParam(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := m.MessageService.Delete(ctx, messageOID, userOID, role.(string)); err != nil {
+		messageError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// Report flags a message the current user received as abusive for admins.
+func (m *MessageController) Report(c *gin.Context) {
+	var req reportMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	messageOID, ok := messageParam(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	report, err := m.MessageService.Report(ctx, messageOID, userOID, role.(string), req.Reason)
+	if err != nil {
+		messageError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusCreated, report)
+}
+
+// ListReports returns message reports for admins, newest first, with the
+// reported message. ?status= filters by status (default OPEN, "all" for all).
+func (m *MessageController) ListReports(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ReportOpen)
+	if status == "all" {
+		status = ""
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	reports, err := m.MessageService.ListReports(ctx, status)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	items := make([]map[string]interface{}, 0, len(reports))
+	for _, r := range reports {
+		item := map[string]interface{}{"report": r}
+		if msg, err := m.MessageService.FindByID(ctx, r.MessageID); err == nil {
+			item["message"] = msg
+		}
+		if sender, err := m.UserService.FindByID(ctx, r.SenderID); err == nil {
+			item["sender_name"] = sender.Name
+			item["sender_email"] = sender.Email
+		}
+		items = append(items, item)
+	}
+	utils.JSON(c, http.StatusOK, items)
+}
+
+// ReviewReport closes an open report as RESOLVED or DISMISSED.
+func (m *MessageController) ReviewReport(c *gin.Context) {
+	var req reviewReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	reportOID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid report id")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	report, err := m.MessageService.ReviewReport(ctx, reportOID, adminOID, req.Status, req.Note)
+	switch {
+	case err == services.ErrInvalidReportStatus:
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+	case err == services.ErrReportReviewed:
+		utils.JSONError(c, http.StatusConflict, err.Error())
+	case err == mongo.ErrNoDocuments:
+		utils.JSONError(c, http.StatusNotFound, "report not found")
+	case err != nil:
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+	default:
+		utils.JSON(c, http.StatusOK, report)
+	}
+}
+
+// messageParam parses the :id message parameter, answering 400 if invalid.
+func messageParam(c *gin.Context) (primitive.ObjectID, bool) {
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid message id")
+		return primitive.NilObjectID, false
+	}
+	return oid, true
+}
+
+// messageError maps message service errors to responses. Messages the user
+// may not act on are reported as not found.
+func messageError(c *gin.Context, err error) {
+	switch err {
+	case mongo.ErrNoDocuments:
+		utils.JSONError(c, http.StatusNotFound, "message not found")
+	case services.ErrAlreadyReported:
+		utils.JSONError(c, http.StatusConflict, err.Error())
+	default:
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// DownloadAttachment streams a message attachment to the message's sender or
+// recipient, unless they deleted the message.
+func (m *MessageController) DownloadAttachment(c *gin.Context) {
+	messageOID, ok := messageParam(c)
+	if !ok {
+		return
+	}
+	attachmentOID, err := primitive.ObjectIDFromHex(c.Param("attachmentId"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid attachment id")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	role, _ := c.Get("role")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	msg, err := m.MessageService.FindByID(ctx, messageOID)
+	if err != nil || !services.CanAccessMessage(msg, userOID, role.(string)) || m.AttachmentService == nil {
+		utils.JSONError(c, http.StatusNotFound, "attachment not found")
+		return
+	}
+	for _, att := range msg.Attachments {
+		if att.ID != attachmentOID {
+			continue
+		}
+		// The stream outlives ctx's timeout for large files on slow links.
+		content, err := m.AttachmentService.Open(c.Request.Context(), att)
+		if err != nil {
+			utils.JSONError(c, http.StatusNotFound, "attachment not found")
+			return
+		}
+		defer content.Close()
+		c.DataFromReader(http.StatusOK, att.Size, att.ContentType, content, map[string]string{
+			"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": att.Name}),
+			"X-Content-Type-Options": "nosniff",
+		})
+		return
+	}
+	utils.JSONError(c, http.StatusNotFound, "attachment not found")
+}
+
+// limitMessageBody bounds a message request to one message with the maximum
+// attachments.
+func limitMessageBody(c *gin.Context, attachments *services.AttachmentService) {
+	limit := int64(1 << 20)
+	if attachments != nil {
+		limit = attachments.MaxRequestBytes()
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+}
+
+// readAttachments validates and stores the files of a multipart message
+// request, answering with an error (and removing what was stored) if any
+// file is rejected.
+func readAttachments(c *gin.Context, svc *services.AttachmentService) ([]models.Attachment, bool) {
+	if c.ContentType() != "multipart/form-data" {
+		return nil, true
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	files := form.File["attachments"]
+	if len(files) == 0 {
+		return nil, true
+	}
+	if svc == nil {
+		utils.JSONError(c, http.StatusBadRequest, "attachments are not enabled")
+		return nil, false
+	}
+	if len(files) > services.MaxAttachmentsPerMessage {
+		utils.JSONError(c, http.StatusBadRequest, fmt.Sprintf("at most %d attachments per message", services.MaxAttachmentsPerMessage))
+		return nil, false
+	}
+
+	stored := make([]models.Attachment, 0, len(files))
+	reject := func(code int, msg string) ([]models.Attachment, bool) {
+		svc.Delete(c.Request.Context(), stored)
+		utils.JSONError(c, code, msg)
+		return nil, false
+	}
+	for _, fh := range files {
+		if fh.Size > svc.MaxBytes() {
+			return reject(http.StatusRequestEntityTooLarge, fmt.Sprintf("%s: %v (max %d MB)", fh.Filename, services.ErrAttachmentTooLarge, svc.MaxBytes()>>20))
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return reject(http.StatusBadRequest, err.Error())
+		}
+		data, err := io.ReadAll(io.LimitReader(f, svc.MaxBytes()+1))
+		f.Close()
+		if err != nil {
+			return reject(http.StatusBadRequest, err.Error())
+		}
+		att, err := svc.Store(c.Request.Context(), fh.Filename, data)
+		switch {
+		case err == nil:
+			stored = append(stored, att)
+		case errors.Is(err, services.ErrAttachmentTooLarge):
+			return reject(http.StatusRequestEntityTooLarge, fmt.Sprintf("%s: %v (max %d MB)", fh.Filename, err, svc.MaxBytes()>>20))
+		case errors.Is(err, services.ErrAttachmentType):
+			return reject(http.StatusUnsupportedMediaType, fmt.Sprintf("%s: %v", fh.Filename, err))
+		case errors.Is(err, storage.ErrInfected):
+			return reject(http.StatusUnprocessableEntity, fmt.Sprintf("%s: %v", fh.Filename, err))
+		default:
+			return reject(http.StatusInternalServerError, err.Error())
+		}
+	}
+	return stored, true
+}
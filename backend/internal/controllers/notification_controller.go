@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/utils"
+)
+
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+)
+
+// NotificationController serves the current user's notification center and
+// notification preferences.
+type NotificationController struct {
+	NotificationService *services.NotificationService
+}
+
+type notificationPreferencesRequest struct {
+	Channels   map[string][]string `json:"channels"`    // type -> channels; omitted types are unchanged
+	WebhookURL *string             `json:"webhook_url"` // omitted keeps the current URL, "" removes it
+	Digest     string              `json:"digest"`      // off, hourly or daily; omitted is unchanged
+}
+
+// notify records a notification for a side effect of a request. Failures are
+// logged rather than failing the request, and a nil service skips it.
+func notify(ctx context.Context, svc *services.NotificationService, n models.Notification) {
+	if svc == nil {
+		return
+	}
+	if _, err := svc.Notify(ctx, n); err != nil {
+		log.Printf("notifications: %s for user %s failed: %v", n.Type, n.UserID.Hex(), err)
+	}
+}
+
+// List returns a page of the current user's notifications, newest first, and
+// their unread count. ?unread=true returns unread notifications only.
+func (n *NotificationController) List(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "page must be a positive integer")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultNotificationPageSize)))
+	if err != nil || limit < 1 || limit > maxNotificationPageSize {
+		utils.JSONError(c, http.StatusBadRequest, "limit must be between 1 and 100")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	items, total, err := n.NotificationService.List(ctx, userOID, c.Query("unread") == "true", page, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	unread, err := n.NotificationService.UnreadCount(ctx, userOID)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"items": items, "page": page, "limit": limit, "total": total, "unread_count": unread})
+}
+
+// MarkRead marks one of the current user's notifications as read.
+func (n *NotificationController) MarkRead(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := n.NotificationService.MarkRead(ctx, id, userOID); err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.JSONError(c, http.StatusNotFound, "notification not found")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"message": "notification marked as read"})
+}
+
+// MarkAllRead marks all of the current user's notifications as read.
+func (n *NotificationController) MarkAllRead(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	changed, err := n.NotificationService.MarkAllRead(ctx, userOID)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"marked_read": changed})
+}
+
+// GetPreferences returns the current user's notification preferences.
+func (n *NotificationController) GetPreferences(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	prefs, err := n.NotificationService.Preferences(ctx, userOID)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, prefs)
+}
+
+// UpdatePreferences changes the channels of some notification types, the
+// webhook URL or the digest frequency.
+func (n *NotificationController) UpdatePreferences(c *gin.Context) {
+	var req notificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	current, err := n.NotificationService.Preferences(ctx, userOID)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	update := models.NotificationPreferences{Channels: req.Channels, WebhookURL: current.WebhookURL, Digest: req.Digest}
+	if req.WebhookURL != nil {
+		update.WebhookURL = *req.WebhookURL
+	}
+	prefs, err := n.NotificationService.SetPreferences(ctx, userOID, update)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPreferences) {
+			utils.JSONError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, prefs)
+}
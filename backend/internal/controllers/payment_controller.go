@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 type PaymentController struct {
 	Service     *services.PaymentService
 	UserService *services.UserService
+	NotificationService *services.NotificationService // nil skips notifications
 	Cfg         config.Config
 }
 
@@ -47,6 +49,7 @@ func (p *PaymentController) Verify(c *gin.Context) {
 		return
 	}
 	_ = p.Service.AttachRecruiter(ctx, payment.ID, recruiterOID)
+	p.notifyVerified(ctx, recruiterOID, payment, "You can now post a job with this payment.")
 	utils.JSON(c, http.StatusCreated, payment)
 }
 
@@ -129,8 +132,20 @@ func (p *PaymentController) VerifyJobSeekerPremium(c *gin.Context) {
 		return
 	}
 	
+	p.notifyVerified(ctx2, jobSeekerOID, payment, "Your premium access is now active.")
 	utils.JSON(c, http.StatusCreated, gin.H{
 		"payment": payment,
 		"message": "premium access activated",
 	})
 }
+
+// notifyVerified tells the payer their payment was verified.
+func (p *PaymentController) notifyVerified(ctx context.Context, userID primitive.ObjectID, payment models.Payment, next string) {
+	notify(ctx, p.NotificationService, models.Notification{
+		UserID: userID,
+		Type:   models.NotifyPaymentVerified,
+		Title:  "Payment verified",
+		Body:   fmt.Sprintf("Your payment of %g MATIC (tx %s) was verified. %s", payment.Amount, payment.TxHash, next),
+		Data:   map[string]string{"payment_id": payment.ID.Hex()},
+	})
+}
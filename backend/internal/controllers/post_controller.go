@@ -0,0 +1,367 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/utils"
+)
+
+const (
+	defaultPostPageSize = 20
+	maxPostPageSize     = 100
+)
+
+// PostController serves the social feed: posts, likes, comments and their
+// moderation.
+type PostController struct {
+	PostService *services.PostService
+	UserService *services.UserService
+}
+
+type postRequest struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content" binding:"required"`
+	Tags    []string `json:"tags"`
+}
+
+type commentRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+type moderatePostRequest struct {
+	Hidden *bool  `json:"hidden" binding:"required"`
+	Reason string `json:"reason"` // required to hide
+}
+
+// postPage parses ?page= and ?limit=, answering 400 if invalid.
+func postPage(c *gin.Context) (int, int, bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "page must be a positive integer")
+		return 0, 0, false
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPostPageSize)))
+	if err != nil || limit < 1 || limit > maxPostPageSize {
+		utils.JSONError(c, http.StatusBadRequest, "limit must be between 1 and 100")
+		return 0, 0, false
+	}
+	return page, limit, true
+}
+
+// postParam parses the :id post parameter, answering 400 if invalid.
+func postParam(c *gin.Context) (primitive.ObjectID, bool) {
+	oid, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid post id")
+		return primitive.NilObjectID, false
+	}
+	return oid, true
+}
+
+// postError maps post service errors to responses. Posts the user may not see
+// or act on are reported as not found.
+func postError(c *gin.Context, err error) {
+	switch {
+	case err == mongo.ErrNoDocuments:
+		utils.JSONError(c, http.StatusNotFound, "post not found")
+	case errors.Is(err, services.ErrInvalidPost), errors.Is(err, services.ErrInvalidComment), errors.Is(err, services.ErrHideReason):
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPostNotEditable):
+		utils.JSONError(c, http.StatusConflict, err.Error())
+	default:
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// Feed returns a page of the current user's feed: recent posts ranked by
+// recency and by how many of their tags are among the user's skills.
+func (p *PostController) Feed(c *gin.Context) {
+	page, limit, ok := postPage(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var skills []string
+	if user, err := p.UserService.FindByID(ctx, userOID); err == nil {
+		skills = user.Skills
+	}
+	items, total, err := p.PostService.Feed(ctx, userOID, skills, time.Now(), page, limit)
+	if err != nil {
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"items": items, "page": page, "limit": limit, "total": total})
+}
+
+// Create publishes an update post by the current user.
+func (p *PostController) Create(c *gin.Context) {
+	var req postRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	post, err := p.PostService.Create(ctx, models.Post{UserID: userOID, Title: req.Title, Content: req.Content, Tags: req.Tags})
+	if err != nil {
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusCreated, post)
+}
+
+// Get returns a post. Hidden posts are only shown to their author and admins.
+func (p *PostController) Get(c *gin.Context) {
+	postOID, ok := postParam(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	role, _ := c.Get("role")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	post, err := p.PostService.Visible(ctx, postOID, userOID, role.(string))
+	if err != nil {
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, post)
+}
+
+// Update edits one of the current user's update posts.
+func (p *PostController) Update(c *gin.Context) {
+	postOID, ok := postParam(c)
+	if !ok {
+		return
+	}
+	var req postRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	post, err := p.PostService.Update(ctx, postOID, userOID, req.Title, req.Content, req.Tags)
+	if err != nil {
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, post)
+}
+
+// Delete removes a post of the current user, or any post for admins.
+func (p *PostController) Delete(c *gin.Context) {
+	postOID, ok := postParam(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	role, _ := c.Get("role")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := p.PostService.Delete(ctx, postOID, userOID, role == models.RoleAdmin); err != nil {
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"message": "post deleted"})
+}
+
+// Like adds the current user's like to a post.
+func (p *PostController) Like(c *gin.Context) {
+	p.setLike(c, true)
+}
+
+// Unlike removes the current user's like from a post.
+func (p *PostController) Unlike(c *gin.Context) {
+	p.setLike(c, false)
+}
+
+func (p *PostController) setLike(c *gin.Context, like bool) {
+	postOID, ok := postParam(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	role, _ := c.Get("role")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if _, err := p.PostService.Visible(ctx, postOID, userOID, role.(string)); err != nil {
+		postError(c, err)
+		return
+	}
+	set := p.PostService.Unlike
+	if like {
+		set = p.PostService.Like
+	}
+	count, err := set(ctx, postOID, userOID)
+	if err != nil {
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"liked": like, "like_count": count})
+}
+
+// Comments returns a page of a post's comments, oldest first.
+func (p *PostController) Comments(c *gin.Context) {
+	postOID, ok := postParam(c)
+	if !ok {
+		return
+	}
+	page, limit, ok := postPage(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	role, _ := c.Get("role")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if _, err := p.PostService.Visible(ctx, postOID, userOID, role.(string)); err != nil {
+		postError(c, err)
+		return
+	}
+	comments, total, err := p.PostService.Comments(ctx, postOID, page, limit)
+	if err != nil {
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"items": comments, "page": page, "limit": limit, "total": total})
+}
+
+// AddComment comments on a post as the current user.
+func (p *PostController) AddComment(c *gin.Context) {
+	postOID, ok := postParam(c)
+	if !ok {
+		return
+	}
+	var req commentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	role, _ := c.Get("role")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if _, err := p.PostService.Visible(ctx, postOID, userOID, role.(string)); err != nil {
+		postError(c, err)
+		return
+	}
+	comment, err := p.PostService.AddComment(ctx, postOID, userOID, req.Content)
+	if err != nil {
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusCreated, comment)
+}
+
+// DeleteComment removes a comment. Its author, the post's author and admins
+// may delete it.
+func (p *PostController) DeleteComment(c *gin.Context) {
+	postOID, ok := postParam(c)
+	if !ok {
+		return
+	}
+	commentOID, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid comment id")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	role, _ := c.Get("role")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := p.PostService.DeleteComment(ctx, postOID, commentOID, userOID, role == models.RoleAdmin); err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.JSONError(c, http.StatusNotFound, "comment not found")
+			return
+		}
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"message": "comment deleted"})
+}
+
+// AdminList returns a page of posts, latest first. ?status=HIDDEN lists the
+// posts taken down.
+func (p *PostController) AdminList(c *gin.Context) {
+	page, limit, ok := postPage(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	posts, total, err := p.PostService.List(ctx, c.Query("status"), page, limit)
+	if err != nil {
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"items": posts, "page": page, "limit": limit, "total": total})
+}
+
+// Moderate hides a post from the feed with a reason shown to its author, or
+// restores it.
+func (p *PostController) Moderate(c *gin.Context) {
+	postOID, ok := postParam(c)
+	if !ok {
+		return
+	}
+	var req moderatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	post, err := p.PostService.Moderate(ctx, postOID, adminOID, *req.Hidden, req.Reason)
+	if err != nil {
+		postError(c, err)
+		return
+	}
+	utils.JSON(c, http.StatusOK, post)
+}
@@ -2,20 +2,25 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
 	"rizeos/backend/internal/services"
 	"rizeos/backend/internal/utils"
 )
 
 // ProfileController manages profile updates.
 type ProfileController struct {
-	UserService *services.UserService
-	AIService   *services.AIService
+	UserService  *services.UserService
+	SkillExtractor services.SkillExtractor
+	SkillService *services.SkillService
 }
 
 type updateProfileRequest struct {
@@ -23,16 +28,18 @@ type updateProfileRequest struct {
 	Bio           string   `json:"bio"`
 	LinkedInURL   string   `json:"linkedin_url"`
 	Skills        []string `json:"skills"`
+	SkillLevels   []models.SkillProficiency `json:"skill_levels,omitempty"`
 	WalletAddress string   `json:"wallet_address"`
 	ExtractSkills bool     `json:"extract_skills"`
 	// New optional fields
-	PhoneNumber   string      `json:"phone_number,omitempty"`
-	Summary       string      `json:"summary,omitempty"`
-	Education     string      `json:"education,omitempty"`
-	TenthMarks    interface{} `json:"tenth_marks,omitempty"` // string or number
-	TwelfthMarks  interface{} `json:"twelfth_marks,omitempty"` // string or number
-	Experience    interface{} `json:"experience,omitempty"` // string or number
-	IsActive      *bool       `json:"is_active,omitempty"` // pointer to allow nil
+	PhoneNumber   string          `json:"phone_number,omitempty"`
+	Location      string          `json:"location,omitempty"`
+	Summary       string          `json:"summary,omitempty"`
+	Education     json.RawMessage `json:"education,omitempty"` // []models.Degree, or legacy string
+	Experience    json.RawMessage `json:"experience,omitempty"` // []models.Position, or legacy string/number
+	TenthMarks    interface{}     `json:"tenth_marks,omitempty"` // legacy: folded into Education
+	TwelfthMarks  interface{}     `json:"twelfth_marks,omitempty"` // legacy: folded into Education
+	IsActive      *bool           `json:"is_active,omitempty"` // pointer to allow nil
 }
 
 // Update updates user profile and optionally enriches skills via AI.
@@ -65,20 +72,37 @@ func (p *ProfileController) Update(c *gin.Context) {
 	if req.PhoneNumber != "" {
 		update["phone_number"] = req.PhoneNumber
 	}
+	if req.Location != "" {
+		update["location"] = strings.TrimSpace(req.Location)
+	}
 	if req.Summary != "" {
 		update["summary"] = req.Summary
 	}
-	if req.Education != "" {
-		update["education"] = req.Education
-	}
-	if req.TenthMarks != nil {
-		update["tenth_marks"] = req.TenthMarks
-	}
-	if req.TwelfthMarks != nil {
-		update["twelfth_marks"] = req.TwelfthMarks
+	if !blankJSON(req.Education) || req.TenthMarks != nil || req.TwelfthMarks != nil {
+		var current []models.Degree
+		if blankJSON(req.Education) {
+			// Marks alone update the stored history rather than replacing it
+			ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+			user, err := p.UserService.FindByID(ctx, oid)
+			cancel()
+			if err == nil {
+				current = user.Education
+			}
+		}
+		degrees, err := decodeDegrees(req.Education, current, req.TenthMarks, req.TwelfthMarks)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "invalid education: "+err.Error())
+			return
+		}
+		update["education"] = degrees
 	}
-	if req.Experience != nil {
-		update["experience"] = req.Experience
+	if !blankJSON(req.Experience) {
+		positions, err := decodePositions(req.Experience)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "invalid experience: "+err.Error())
+			return
+		}
+		update["experience"] = positions
 	}
 	// Handle is_active: default to true if not provided, but allow explicit false
 	if req.IsActive != nil {
@@ -89,10 +113,10 @@ func (p *ProfileController) Update(c *gin.Context) {
 	}
 
 	// Handle skill extraction and merging
-	if req.ExtractSkills && req.Bio != "" && p.AIService != nil {
+	if req.ExtractSkills && req.Bio != "" && p.SkillExtractor != nil {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
 		defer cancel()
-		skills, err := p.AIService.ExtractSkills(ctx, req.Bio)
+		skills, _, err := p.SkillExtractor.ExtractSkillsDetailed(ctx, req.Bio)
 		if err == nil && len(skills) > 0 {
 			// Merge extracted skills with existing skills (avoid duplicates)
 			existingSkills := make(map[string]bool)
@@ -111,6 +135,24 @@ func (p *ProfileController) Update(c *gin.Context) {
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
+
+	// Canonicalize skills against the taxonomy so "JS" and "javascript" are one skill
+	if p.SkillService != nil {
+		if req.SkillLevels != nil {
+			levels, err := p.SkillService.NormalizeProficiencies(ctx, req.SkillLevels)
+			if err != nil {
+				utils.JSONError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			update["skill_levels"] = levels
+			// Every skill with a proficiency must also be listed as a skill
+			for _, lvl := range levels {
+				req.Skills = append(req.Skills, lvl.Skill)
+			}
+		}
+		update["skills"] = p.SkillService.Normalize(ctx, req.Skills)
+	}
+
 	updated, err := p.UserService.UpdateProfile(ctx, oid, update)
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, err.Error())
@@ -119,3 +161,61 @@ func (p *ProfileController) Update(c *gin.Context) {
 	updated.PasswordHash = ""
 	utils.JSON(c, http.StatusOK, updated)
 }
+
+// decodePositions accepts a structured work history or a legacy scalar value.
+func decodePositions(raw json.RawMessage) ([]models.Position, error) {
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		var positions []models.Position
+		if err := json.Unmarshal(raw, &positions); err != nil {
+			return nil, err
+		}
+		for i, p := range positions {
+			if p.StartDate != nil && p.EndDate != nil && p.EndDate.Before(*p.StartDate) {
+				return nil, errors.New("end_date before start_date in position " + p.Title)
+			}
+			if p.Years < 0 {
+				return nil, errors.New("years cannot be negative")
+			}
+			positions[i].Company = strings.TrimSpace(p.Company)
+			positions[i].Title = strings.TrimSpace(p.Title)
+		}
+		return positions, nil
+	}
+	var legacy interface{}
+	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return nil, err
+	}
+	return utils.LegacyPositions(legacy), nil
+}
+
+// decodeDegrees accepts a structured education history or legacy education/marks values.
+// When raw is blank, marks are applied to current. Class 10/12 marks replace an
+// existing degree of the same name instead of being appended again.
+func decodeDegrees(raw json.RawMessage, current []models.Degree, tenthMarks, twelfthMarks interface{}) ([]models.Degree, error) {
+	marks := utils.LegacyDegrees(nil, tenthMarks, twelfthMarks)
+	if blankJSON(raw) {
+		return utils.UpsertDegrees(append([]models.Degree{}, current...), marks...), nil
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		var degrees []models.Degree
+		if err := json.Unmarshal(raw, &degrees); err != nil {
+			return nil, err
+		}
+		return utils.UpsertDegrees(degrees, marks...), nil
+	}
+	var legacy interface{}
+	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return nil, err
+	}
+	return utils.LegacyDegrees(legacy, tenthMarks, twelfthMarks), nil
+}
+
+// blankJSON reports whether an optional field was omitted, null or an empty
+// string, in which case the stored value is kept.
+func blankJSON(raw json.RawMessage) bool {
+	switch strings.TrimSpace(string(raw)) {
+	case "", "null", `""`:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/websocket"
+	"rizeos/backend/internal/config"
+	"rizeos/backend/internal/realtime"
+	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/utils"
+)
+
+// RealtimeController serves the WebSocket that pushes messages, receipts and
+// typing indicators to connected users.
+type RealtimeController struct {
+	Hub         *realtime.Hub
+	UserService *services.UserService
+	Cfg         config.Config
+}
+
+// clientFrame is a frame sent by the client. Only typing indicators are
+// accepted; messages are still sent with POST /api/messages/send.
+type clientFrame struct {
+	Type   string `json:"type"`
+	To     string `json:"to"`
+	Typing bool   `json:"typing"`
+}
+
+// typingPayload is the data of a typing event.
+type typingPayload struct {
+	From     string `json:"from"`
+	FromRole string `json:"fromRole"`
+	Typing   bool   `json:"typing"`
+}
+
+// Connect upgrades to a WebSocket authenticated with the usual JWT, passed as
+// ?token= (browsers cannot set headers on WebSocket requests) or a bearer
+// Authorization header. The server pushes JSON events {"type","data","at"}.
+func (r *RealtimeController) Connect(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		token = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	}
+	claims, err := utils.ParseToken(r.Cfg.JWTSecret, token)
+	if err != nil {
+		utils.JSONError(c, http.StatusUnauthorized, "invalid token")
+		return
+	}
+
+	server := websocket.Server{
+		Handshake: func(cfg *websocket.Config, req *http.Request) error {
+			if !r.originAllowed(req.Header.Get("Origin")) {
+				return fmt.Errorf("origin not allowed")
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			r.serve(ws, claims.UserID, claims.Role)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (r *RealtimeController) serve(ws *websocket.Conn, userID, role string) {
+	client := r.Hub.Register(userID, role)
+	defer ws.Close()
+
+	// Writer: push hub events until the client is unregistered or dropped.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range client.Events() {
+			if err := websocket.JSON.Send(ws, ev); err != nil {
+				ws.Close()
+				return
+			}
+		}
+		ws.Close()
+	}()
+
+	// Reader: typing indicators. Recipient roles are cached per connection.
+	recipientRoles := map[string]string{}
+	for {
+		var frame clientFrame
+		if err := websocket.JSON.Receive(ws, &frame); err != nil {
+			break
+		}
+		if frame.Type != realtime.EventTyping || frame.To == "" || frame.To == userID {
+			continue
+		}
+		toRole, ok := recipientRoles[frame.To]
+		if !ok {
+			toRole = r.recipientRole(frame.To)
+			recipientRoles[frame.To] = toRole
+		}
+		if toRole == "" || !canMessage(role, toRole) {
+			continue
+		}
+		ev, err := realtime.NewEvent(realtime.EventTyping, typingPayload{From: userID, FromRole: role, Typing: frame.Typing}, frame.To)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_ = r.Hub.Publish(ctx, ev)
+			cancel()
+		}
+	}
+	r.Hub.Unregister(client)
+	<-done
+}
+
+// recipientRole returns the role of a user, or "" if they do not exist.
+func (r *RealtimeController) recipientRole(userID string) string {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	user, err := r.UserService.FindByID(ctx, oid)
+	if err != nil {
+		return ""
+	}
+	return user.Role
+}
+
+// originAllowed applies the CORS origin list to WebSocket handshakes.
+func (r *RealtimeController) originAllowed(origin string) bool {
+	if r.Cfg.AllowedOriginsCSV == "*" || origin == "" {
+		return true
+	}
+	for _, allowed := range strings.Split(r.Cfg.AllowedOriginsCSV, ",") {
+		if strings.TrimSuffix(strings.TrimSpace(allowed), "/") == strings.TrimSuffix(origin, "/") {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"math"
 	"net/http"
 	"sort"
 	"strings"
@@ -10,8 +11,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/insights"
 	"rizeos/backend/internal/models"
 	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/suggest"
 	"rizeos/backend/internal/utils"
 )
 
@@ -19,27 +22,24 @@ import (
 type RecruiterController struct {
 	UserService *services.UserService
 	JobService  *services.JobService
-	AIService   *services.AIService
+	Matcher     services.Matcher
+	SkillService *services.SkillService
+	MatchScoreService *services.MatchScoreService
+	SuggestionTemplates *suggest.Templates // nil uses the built-in templates
+	AnalyticsService *services.AnalyticsService
+	JobViewService   *services.JobViewService // nil omits view counts from GetJobsAnalytics
 }
 
 // SkillCount represents a skill with its frequency count.
-type SkillCount struct {
-	Skill string `json:"skill"`
-	Count int    `json:"count"`
-}
+type SkillCount = insights.SkillCount
 
-// JobCandidateCount represents a job with matching candidate count.
+// JobCandidateCount represents a job with matching candidate count, plus its
+// lifetime views and the share of viewers who applied.
 type JobCandidateCount struct {
-	JobTitle string `json:"jobTitle"`
-	Count    int    `json:"count"`
-}
-
-// JobSuggestion represents an AI-generated job suggestion.
-type JobSuggestion struct {
-	Title       string   `json:"title"`
-	Skills      []string `json:"skills"`
-	Description string   `json:"description"`
-	Reason      string   `json:"reason"`
+	JobTitle    string   `json:"jobTitle"`
+	Count       int      `json:"count"`
+	Views       int      `json:"views"`
+	ViewToApply *float64 `json:"viewToApply"` // percent; nil without views
 }
 
 // GetSkillsAnalytics returns aggregated skill frequency across all job seekers.
@@ -54,39 +54,12 @@ func (r *RecruiterController) GetSkillsAnalytics(c *gin.Context) {
 		return
 	}
 
-	// Aggregate skills
-	skillMap := make(map[string]int)
+	// Aggregate skills, most common first
+	sets := make([][]string, 0, len(seekers))
 	for _, seeker := range seekers {
-		for _, skill := range seeker.Skills {
-			if skill != "" {
-				skill = strings.TrimSpace(skill)
-				// Normalize skill name (capitalize first letter)
-				if len(skill) > 0 {
-					// Handle multi-word skills (e.g., "Spring Boot")
-					words := strings.Fields(skill)
-					normalizedWords := make([]string, len(words))
-					for i, word := range words {
-						if len(word) > 0 {
-							normalizedWords[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
-						}
-					}
-					skill = strings.Join(normalizedWords, " ")
-				}
-				skillMap[skill]++
-			}
-		}
-	}
-
-	// Convert to slice and sort by count (descending)
-	var results []SkillCount
-	for skill, count := range skillMap {
-		results = append(results, SkillCount{Skill: skill, Count: count})
+		sets = append(sets, r.normalizeSkills(ctx, seeker.Skills))
 	}
-
-	// Sort by count descending
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Count > results[j].Count
-	})
+	results := insights.CountSkills(sets)
 
 	// Limit to top 20
 	if len(results) > 20 {
@@ -118,6 +91,18 @@ func (r *RecruiterController) GetJobsAnalytics(c *gin.Context) {
 		return
 	}
 
+	views := map[primitive.ObjectID]services.ViewCount{}
+	if r.JobViewService != nil {
+		ids := make([]primitive.ObjectID, len(jobs))
+		for i, job := range jobs {
+			ids[i] = job.ID
+		}
+		if views, err = r.JobViewService.Counts(ctx, ids); err != nil {
+			utils.JSONError(c, http.StatusInternalServerError, "failed to count views: "+err.Error())
+			return
+		}
+	}
+
 	// For each job, count matching candidates (fitment score >= 50%)
 	threshold := 50.0
 	var results []JobCandidateCount
@@ -125,40 +110,24 @@ func (r *RecruiterController) GetJobsAnalytics(c *gin.Context) {
 	for _, job := range jobs {
 		matchCount := 0
 
-		// Calculate fitment score for each seeker
-		for _, seeker := range seekers {
-			// Use bio or summary for candidate bio
-			candidateBio := seeker.Bio
-			if seeker.Summary != "" {
-				candidateBio = seeker.Summary
-			}
-			if candidateBio == "" {
-				candidateBio = seeker.Name // Fallback
-			}
-
-			// Calculate match score with skills
-			score, err := r.AIService.MatchScoreWithSkills(ctx, job.Description, candidateBio, job.Skills, seeker.Skills)
-			if err != nil {
+		// Fitment scores for every seeker: cached, with misses scored in one batch
+		for _, res := range matchBreakdownsForJob(ctx, r.MatchScoreService, r.Matcher, job, seekers) {
+			if res.Err != nil {
 				continue
 			}
 
-			// Clamp score to 0-100
-			if score < 0 {
-				score = 0
-			}
-			if score > 100 {
-				score = 100
-			}
-
 			// Count if score >= threshold
-			if score >= threshold {
+			if res.Score >= threshold {
 				matchCount++
 			}
 		}
 
+		viewCount := views[job.ID]
 		results = append(results, JobCandidateCount{
-			JobTitle: job.Title,
-			Count:    matchCount,
+			JobTitle:    job.Title,
+			Count:       matchCount,
+			Views:       viewCount.Views,
+			ViewToApply: services.FunnelCounts{Views: viewCount.Viewers, Applies: len(job.Candidates)}.Rates().ViewToApply,
 		})
 	}
 
@@ -170,188 +139,268 @@ func (r *RecruiterController) GetJobsAnalytics(c *gin.Context) {
 	utils.JSON(c, http.StatusOK, results)
 }
 
-// GetAISuggestions returns AI-powered job posting suggestions.
-func (r *RecruiterController) GetAISuggestions(c *gin.Context) {
-	userID, _ := c.Get("user_id")
-	recruiterOID, _ := primitive.ObjectIDFromHex(userID.(string))
+// defaultAnalyticsRange is the funnel window when no from date is given.
+const defaultAnalyticsRange = 30 * 24 * time.Hour
+
+// jobFunnel is one job's funnel over the requested range.
+type jobFunnel struct {
+	JobID string `json:"jobId"`
+	Title string `json:"title"`
+	services.FunnelCounts
+	Rates services.FunnelRates `json:"rates"`
+	// Hours from posting to the first application, over the job's lifetime.
+	TimeToFirstApplicantHours *float64 `json:"timeToFirstApplicantHours"`
+	// Mean hours from application to hire, for hires within the range.
+	AvgTimeToHireHours *float64 `json:"avgTimeToHireHours"`
+}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+// GetFunnelAnalytics returns per-job hiring funnel metrics for the recruiter's
+// jobs over a date range (?from=&to=, YYYY-MM-DD or RFC3339; default the last
+// 30 days), optionally limited to one job (?jobId=).
+func (r *RecruiterController) GetFunnelAnalytics(c *gin.Context) {
+	filter, ok := r.analyticsFilter(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
 	defer cancel()
 
-	// Fetch all job seekers to analyze skill frequency
-	seekers, err := r.UserService.Search(ctx, models.RoleSeeker, "", nil)
+	query := bson.M{"recruiter_id": filter.RecruiterID}
+	if filter.JobID != nil {
+		query["_id"] = *filter.JobID
+	}
+	jobs, err := r.JobService.List(ctx, query)
 	if err != nil {
-		utils.JSONError(c, http.StatusInternalServerError, "failed to fetch job seekers: "+err.Error())
+		utils.JSONError(c, http.StatusInternalServerError, "failed to fetch jobs: "+err.Error())
+		return
+	}
+	if filter.JobID != nil && len(jobs) == 0 {
+		utils.JSONError(c, http.StatusNotFound, "job not found")
+		return
+	}
+	counts, err := r.AnalyticsService.FunnelByJob(ctx, filter)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "failed to aggregate funnel: "+err.Error())
+		return
+	}
+	times, err := r.AnalyticsService.HiringTimesByJob(ctx, filter)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "failed to aggregate hiring times: "+err.Error())
 		return
 	}
 
-	// Aggregate skills with frequency
-	skillMap := make(map[string]int)
-	for _, seeker := range seekers {
-		for _, skill := range seeker.Skills {
-			if skill != "" {
-				skill = strings.TrimSpace(skill)
-				skillMap[strings.ToLower(skill)]++
-			}
+	var totals services.FunnelCounts
+	results := make([]jobFunnel, 0, len(jobs))
+	for _, job := range jobs {
+		fc := counts[job.ID]
+		totals.Views += fc.Views
+		totals.Applies += fc.Applies
+		totals.Shortlisted += fc.Shortlisted
+		totals.Hired += fc.Hired
+		totals.Rejected += fc.Rejected
+
+		jf := jobFunnel{JobID: job.ID.Hex(), Title: job.Title, FunnelCounts: fc, Rates: fc.Rates()}
+		ht := times[job.ID]
+		if ht.FirstAppliedAt != nil && !job.CreatedAt.IsZero() {
+			jf.TimeToFirstApplicantHours = hours(ht.FirstAppliedAt.Sub(job.CreatedAt))
+		}
+		if ht.AvgTimeToHire != nil {
+			jf.AvgTimeToHireHours = hours(*ht.AvgTimeToHire)
 		}
+		results = append(results, jf)
 	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Applies > results[j].Applies })
 
-	// Get top skills
-	type skillFreq struct {
-		skill string
-		count int
-	}
-	var skillFreqs []skillFreq
-	for skill, count := range skillMap {
-		skillFreqs = append(skillFreqs, skillFreq{skill: skill, count: count})
-	}
-	sort.Slice(skillFreqs, func(i, j int) bool {
-		return skillFreqs[i].count > skillFreqs[j].count
+	utils.JSON(c, http.StatusOK, gin.H{
+		"from":   filter.From,
+		"to":     filter.To,
+		"totals": gin.H{"counts": totals, "rates": totals.Rates()},
+		"jobs":   results,
 	})
+}
 
-	// Fetch recruiter's existing jobs to avoid duplicates
-	existingJobs, _ := r.JobService.List(ctx, bson.M{"recruiter_id": recruiterOID})
-	existingSkillsSet := make(map[string]bool)
-	for _, job := range existingJobs {
-		for _, skill := range job.Skills {
-			existingSkillsSet[strings.ToLower(skill)] = true
-		}
+// GetFunnelTrends returns the recruiter's funnel per day, week or month
+// (?interval=, default day) over the same date range and job filter as
+// GetFunnelAnalytics. Periods without activity are included as zeros.
+func (r *RecruiterController) GetFunnelTrends(c *gin.Context) {
+	filter, ok := r.analyticsFilter(c)
+	if !ok {
+		return
+	}
+	interval, ok := analyticsInterval(c, filter.From, filter.To)
+	if !ok {
+		return
 	}
 
-	// Generate suggestions based on top skill combinations
-	var suggestions []JobSuggestion
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
 
-	// Helper function to capitalize first letter
-	capitalize := func(s string) string {
-		if len(s) == 0 {
-			return s
-		}
-		return strings.ToUpper(s[:1]) + strings.ToLower(s[1:])
+	points, err := r.AnalyticsService.Trend(ctx, filter, interval)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "failed to aggregate trends: "+err.Error())
+		return
+	}
+	type trendPoint struct {
+		services.TrendPoint
+		Rates services.FunnelRates `json:"rates"`
 	}
+	out := make([]trendPoint, len(points))
+	for i, p := range points {
+		out[i] = trendPoint{TrendPoint: p, Rates: p.Rates()}
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"from": filter.From, "to": filter.To, "interval": interval, "points": out})
+}
 
-	// Suggestion 1: Top 3 skills combination
-	if len(skillFreqs) >= 3 {
-		topSkills := []string{
-			capitalize(skillFreqs[0].skill),
-			capitalize(skillFreqs[1].skill),
-			capitalize(skillFreqs[2].skill),
-		}
-		
-		// Determine job title based on skills
-		title := r.inferJobTitle(topSkills)
-		
-		suggestions = append(suggestions, JobSuggestion{
-			Title:       title,
-			Skills:      topSkills,
-			Description: r.generateJobDescription(title, topSkills),
-			Reason:      "High availability of matching candidates",
-		})
+// analyticsFilter parses the analytics query parameters (see analyticsRange),
+// writing an error and returning false when they are invalid.
+func (r *RecruiterController) analyticsFilter(c *gin.Context) (services.AnalyticsFilter, bool) {
+	if r.AnalyticsService == nil {
+		utils.JSONError(c, http.StatusServiceUnavailable, "analytics are not available")
+		return services.AnalyticsFilter{}, false
 	}
+	userID, _ := c.Get("user_id")
+	recruiterOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	filter := services.AnalyticsFilter{RecruiterID: recruiterOID}
 
-	// Suggestion 2: Next top 3 skills (if available)
-	if len(skillFreqs) >= 6 {
-		nextSkills := []string{
-			capitalize(skillFreqs[3].skill),
-			capitalize(skillFreqs[4].skill),
-			capitalize(skillFreqs[5].skill),
+	var ok bool
+	if filter.From, filter.To, ok = analyticsRange(c); !ok {
+		return filter, false
+	}
+	if raw := c.Query("jobId"); raw != "" {
+		jobOID, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "invalid job id")
+			return filter, false
 		}
-		title := r.inferJobTitle(nextSkills)
-		
-		suggestions = append(suggestions, JobSuggestion{
-			Title:       title,
-			Skills:      nextSkills,
-			Description: r.generateJobDescription(title, nextSkills),
-			Reason:      "Strong candidate pool with these skills",
-		})
+		filter.JobID = &jobOID
 	}
+	return filter, true
+}
 
-	// Suggestion 3: Popular skill combination (if different from above)
-	if len(skillFreqs) >= 4 {
-		// Try to find a combination that's not already suggested
-		comboSkills := []string{
-			capitalize(skillFreqs[0].skill),
-			capitalize(skillFreqs[2].skill),
-			capitalize(skillFreqs[3].skill),
+// analyticsRange parses ?from= and ?to= (default: the 30 days up to now),
+// writing a 400 and returning false when they are invalid. A date-only "to"
+// covers that whole day.
+func analyticsRange(c *gin.Context) (time.Time, time.Time, bool) {
+	to := time.Now().UTC()
+	if raw := c.Query("to"); raw != "" {
+		parsed, dateOnly, err := parseAnalyticsTime(raw)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "invalid to date")
+			return time.Time{}, time.Time{}, false
 		}
-		title := r.inferJobTitle(comboSkills)
-		
-		// Only add if different from previous suggestions
-		if len(suggestions) < 2 || suggestions[1].Title != title {
-			suggestions = append(suggestions, JobSuggestion{
-				Title:       title,
-				Skills:      comboSkills,
-				Description: r.generateJobDescription(title, comboSkills),
-				Reason:      "Optimal skill combination for market demand",
-			})
+		if dateOnly {
+			parsed = parsed.Add(24*time.Hour - time.Nanosecond)
 		}
+		to = parsed
 	}
-
-	// Limit to 3 suggestions
-	if len(suggestions) > 3 {
-		suggestions = suggestions[:3]
+	from := to.Add(-defaultAnalyticsRange)
+	if raw := c.Query("from"); raw != "" {
+		parsed, _, err := parseAnalyticsTime(raw)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "invalid from date")
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
 	}
+	if from.After(to) {
+		utils.JSONError(c, http.StatusBadRequest, "from must not be after to")
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
 
-	utils.JSON(c, http.StatusOK, gin.H{"suggestions": suggestions})
+// analyticsInterval parses ?interval= (default day), writing a 400 and
+// returning false when it is invalid or from..to is too long for it (see
+// services.CheckTrendRange).
+func analyticsInterval(c *gin.Context, from, to time.Time) (string, bool) {
+	interval := c.DefaultQuery("interval", services.IntervalDay)
+	if interval != services.IntervalDay && interval != services.IntervalWeek && interval != services.IntervalMonth {
+		utils.JSONError(c, http.StatusBadRequest, "interval must be day, week or month")
+		return "", false
+	}
+	if err := services.CheckTrendRange(from, to, interval); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return "", false
+	}
+	return interval, true
 }
 
-// inferJobTitle determines job title based on skills.
-func (r *RecruiterController) inferJobTitle(skills []string) string {
-	skillsLower := make([]string, len(skills))
-	for i, s := range skills {
-		skillsLower[i] = strings.ToLower(s)
+// parseAnalyticsTime accepts YYYY-MM-DD (UTC midnight) or RFC3339.
+func parseAnalyticsTime(raw string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, true, nil
 	}
+	t, err := time.Parse(time.RFC3339, raw)
+	return t.UTC(), false, err
+}
 
-	// Check for specific patterns
-	hasReact := contains(skillsLower, "react")
-	hasNode := contains(skillsLower, "node") || contains(skillsLower, "node.js")
-	hasPython := contains(skillsLower, "python")
-	hasJava := contains(skillsLower, "java")
-	hasML := contains(skillsLower, "machine learning") || contains(skillsLower, "ml")
-	hasSecurity := contains(skillsLower, "cyber security") || contains(skillsLower, "cybersecurity")
+func hours(d time.Duration) *float64 {
+	h := math.Round(d.Hours()*10) / 10
+	return &h
+}
 
-	if hasReact && hasNode {
-		return "Full Stack Developer"
-	}
-	if hasReact {
-		return "Frontend Developer"
-	}
-	if hasNode && !hasReact {
-		return "Backend Developer"
-	}
-	if hasPython && hasML {
-		return "AI/ML Engineer"
+// GetAISuggestions returns job posting suggestions computed from platform
+// supply and demand: skill clusters many seekers share but few jobs ask for,
+// with candidate pool size, budget benchmarks and a confidence per suggestion.
+func (r *RecruiterController) GetAISuggestions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	recruiterOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	seekers, err := r.UserService.Search(ctx, models.RoleSeeker, "", nil)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "failed to fetch job seekers: "+err.Error())
+		return
 	}
-	if hasJava {
-		return "Java Developer"
+	jobs, err := r.JobService.List(ctx, bson.M{})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "failed to fetch jobs: "+err.Error())
+		return
 	}
-	if hasSecurity {
-		return "Cyber Security Analyst"
+
+	in := suggest.Input{SeekerSkills: make([][]string, 0, len(seekers))}
+	for _, seeker := range seekers {
+		in.SeekerSkills = append(in.SeekerSkills, r.normalizeSkills(ctx, seeker.Skills))
 	}
-	if hasPython {
-		return "Python Developer"
+	for _, job := range jobs {
+		skills := r.normalizeSkills(ctx, job.Skills)
+		in.Jobs = append(in.Jobs, suggest.Job{Title: job.Title, Skills: skills, Budget: job.Budget})
+		if job.RecruiterID == recruiterOID {
+			in.Existing = append(in.Existing, skills)
+		}
 	}
-
-	// Default based on first skill
-	if len(skills) > 0 {
-		return skills[0] + " Developer"
+	if r.SkillService != nil {
+		in.Category = func(skill string) string { return r.SkillService.Category(ctx, skill) }
 	}
-	return "Software Developer"
-}
 
-// generateJobDescription creates a short job description.
-func (r *RecruiterController) generateJobDescription(title string, skills []string) string {
-	skillsStr := strings.Join(skills, ", ")
-	return "We are looking for a " + title + " with expertise in " + skillsStr + ". Join our team and work on exciting projects."
+	suggestions := suggest.Analyze(in, suggest.Options{Templates: r.SuggestionTemplates})
+	utils.JSON(c, http.StatusOK, gin.H{"suggestions": suggestions})
 }
 
-// contains checks if a slice contains a string (case-insensitive).
-func contains(slice []string, item string) bool {
-	itemLower := strings.ToLower(item)
-	for _, s := range slice {
-		if strings.ToLower(s) == itemLower {
-			return true
+// normalizeSkills canonicalizes skills via the taxonomy, falling back to
+// title-casing (e.g. "spring boot" -> "Spring Boot") when it is unavailable.
+func (r *RecruiterController) normalizeSkills(ctx context.Context, skills []string) []string {
+	if r.SkillService != nil {
+		return r.SkillService.Normalize(ctx, skills)
+	}
+	seen := make(map[string]bool, len(skills))
+	out := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		words := strings.Fields(skill)
+		if len(words) == 0 {
+			continue
+		}
+		for i, word := range words {
+			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+		}
+		normalized := strings.Join(words, " ")
+		if !seen[normalized] {
+			seen[normalized] = true
+			out = append(out, normalized)
 		}
 	}
-	return false
+	return out
 }
-
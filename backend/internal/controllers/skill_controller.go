@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/utils"
+)
+
+// SkillController exposes the skill taxonomy and its admin curation API.
+type SkillController struct {
+	SkillService *services.SkillService
+}
+
+type skillRequest struct {
+	Slug       string   `json:"slug"`
+	Name       string   `json:"name" binding:"required"`
+	Aliases    []string `json:"aliases"`
+	Category   string   `json:"category"`
+	ParentSlug string   `json:"parent_slug"`
+}
+
+// List returns taxonomy entries, optionally filtered by category or a name/alias prefix.
+func (s *SkillController) List(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	skills, err := s.SkillService.List(ctx, c.Query("category"))
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if q := models.SkillKey(c.Query("q")); q != "" {
+		filtered := make([]models.Skill, 0, len(skills))
+		for _, sk := range skills {
+			if strings.HasPrefix(models.SkillKey(sk.Name), q) || strings.HasPrefix(sk.Slug, q) {
+				filtered = append(filtered, sk)
+				continue
+			}
+			for _, a := range sk.Aliases {
+				if strings.HasPrefix(a, q) {
+					filtered = append(filtered, sk)
+					break
+				}
+			}
+		}
+		skills = filtered
+	}
+
+	utils.JSON(c, http.StatusOK, skills)
+}
+
+// Normalize resolves free-form skills to their canonical names.
+func (s *SkillController) Normalize(c *gin.Context) {
+	var req struct {
+		Skills []string `json:"skills" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	utils.JSON(c, http.StatusOK, gin.H{"skills": s.SkillService.Normalize(ctx, req.Skills)})
+}
+
+// Create adds a taxonomy entry (admin only).
+func (s *SkillController) Create(c *gin.Context) {
+	var req skillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Slug == "" {
+		req.Slug = req.Name
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	created, err := s.SkillService.Create(ctx, req.toModel())
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusCreated, created)
+}
+
+// Update edits a taxonomy entry (admin only).
+func (s *SkillController) Update(c *gin.Context) {
+	var req skillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	updated, err := s.SkillService.Update(ctx, c.Param("slug"), req.toModel())
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.JSONError(c, http.StatusNotFound, "skill not found")
+			return
+		}
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, updated)
+}
+
+// Delete removes a taxonomy entry (admin only).
+func (s *SkillController) Delete(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := s.SkillService.Delete(ctx, c.Param("slug")); err != nil {
+		if err == mongo.ErrNoDocuments {
+			utils.JSONError(c, http.StatusNotFound, "skill not found")
+			return
+		}
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"status": "deleted"})
+}
+
+func (r skillRequest) toModel() models.Skill {
+	return models.Skill{
+		Slug:       r.Slug,
+		Name:       r.Name,
+		Aliases:    r.Aliases,
+		Category:   r.Category,
+		ParentSlug: r.ParentSlug,
+	}
+}
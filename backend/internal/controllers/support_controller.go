@@ -0,0 +1,350 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/utils"
+)
+
+// SupportController serves the shared admin support queue: one thread per
+// user who messaged the admins, assignable to an admin, with a status and
+// internal notes.
+type SupportController struct {
+	MessageService    *services.MessageService
+	UserService       *services.UserService
+	AttachmentService *services.AttachmentService // nil disables attachments
+}
+
+type assignSupportRequest struct {
+	AdminID string `json:"adminId"` // empty returns the thread to the queue
+}
+
+type supportStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type supportNoteRequest struct {
+	Note string `json:"note" binding:"required"`
+}
+
+// List returns a page of support threads, most recently active first.
+// ?status= filters by status and ?assignee= by "me", "unassigned" or an
+// admin id.
+func (sc *SupportController) List(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "page must be a positive integer")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultConversationPageSize)))
+	if err != nil || limit < 1 || limit > maxConversationPageSize {
+		utils.JSONError(c, http.StatusBadRequest, "limit must be between 1 and 100")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	filter := services.SupportFilter{Status: c.Query("status")}
+	switch assignee := c.Query("assignee"); assignee {
+	case "":
+	case "unassigned":
+		filter.Unassigned = true
+	case "me":
+		filter.Assignee = &adminOID
+	default:
+		assigneeOID, err := primitive.ObjectIDFromHex(assignee)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "assignee must be me, unassigned or an admin id")
+			return
+		}
+		filter.Assignee = &assigneeOID
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	threads, total, err := sc.MessageService.ListSupportThreads(ctx, filter, page, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ids := make([]primitive.ObjectID, len(threads))
+	for i, t := range threads {
+		ids[i] = t.ID
+	}
+	unread, err := sc.MessageService.SupportUnread(ctx, adminOID, ids)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	items := make([]map[string]interface{}, 0, len(threads))
+	for _, t := range threads {
+		item := sc.threadView(ctx, t, unread[t.ID])
+		delete(item, "notes")
+		items = append(items, item)
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"items": items, "page": page, "limit": limit, "total": total})
+}
+
+// Get returns a support thread with its notes and a page of its messages,
+// oldest first; ?before=<message id> pages back as for conversations.
+func (sc *SupportController) Get(c *gin.Context) {
+	thread, ok := sc.supportThread(c)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultThreadPageSize)))
+	if err != nil || limit < 1 || limit > maxThreadPageSize {
+		utils.JSONError(c, http.StatusBadRequest, "limit must be between 1 and 100")
+		return
+	}
+	var before primitive.ObjectID
+	if raw := c.Query("before"); raw != "" {
+		if before, err = primitive.ObjectIDFromHex(raw); err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "invalid before message id")
+			return
+		}
+	}
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	messages, hasMore, err := sc.MessageService.ConversationMessages(ctx, thread.ID, adminOID, before, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	unread, err := sc.MessageService.SupportUnread(ctx, adminOID, []primitive.ObjectID{thread.ID})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := gin.H{"thread": sc.threadView(ctx, thread, unread[thread.ID]), "messages": messages, "has_more": hasMore}
+	if hasMore && len(messages) > 0 {
+		resp["next_before"] = messages[0].ID
+	}
+	utils.JSON(c, http.StatusOK, resp)
+}
+
+// Reply sends a message from the current admin to the thread's requester.
+func (sc *SupportController) Reply(c *gin.Context) {
+	limitMessageBody(c, sc.AttachmentService)
+	var req replyRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	thread, ok := sc.supportThread(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	requester := thread.Participants[0]
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	attachments, ok := readAttachments(c, sc.AttachmentService)
+	if !ok {
+		return
+	}
+	created, err := sc.MessageService.Create(ctx, models.Message{
+		FromUserID:  adminOID,
+		FromRole:    models.RoleAdmin,
+		ToUserID:    requester.UserID,
+		ToRole:      requester.Role,
+		Message:     req.Message,
+		Attachments: attachments,
+	})
+	if err != nil {
+		if len(attachments) > 0 {
+			sc.AttachmentService.Delete(ctx, attachments)
+		}
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusCreated, created)
+}
+
+// Assign hands a thread to an admin, or back to the queue for an empty id.
+func (sc *SupportController) Assign(c *gin.Context) {
+	var req assignSupportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	thread, ok := sc.supportThread(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var assignee *primitive.ObjectID
+	if req.AdminID != "" {
+		adminOID, err := primitive.ObjectIDFromHex(req.AdminID)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "invalid admin id")
+			return
+		}
+		admin, err := sc.UserService.FindByID(ctx, adminOID)
+		if err != nil || admin.Role != models.RoleAdmin {
+			utils.JSONError(c, http.StatusBadRequest, "threads can only be assigned to admins")
+			return
+		}
+		assignee = &adminOID
+	}
+	updated, err := sc.MessageService.AssignSupport(ctx, thread.ID, assignee)
+	sc.respondThread(ctx, c, updated, err)
+}
+
+// SetStatus sets a thread's status to OPEN, PENDING or RESOLVED.
+func (sc *SupportController) SetStatus(c *gin.Context) {
+	var req supportStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	thread, ok := sc.supportThread(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	updated, err := sc.MessageService.SetSupportStatus(ctx, thread.ID, req.Status)
+	if err == services.ErrInvalidSupportStatus {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	sc.respondThread(ctx, c, updated, err)
+}
+
+// AddNote adds an internal note, visible to admins only.
+func (sc *SupportController) AddNote(c *gin.Context) {
+	var req supportNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	thread, ok := sc.supportThread(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	note, err := sc.MessageService.AddSupportNote(ctx, thread.ID, adminOID, req.Note)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusCreated, note)
+}
+
+// MarkRead marks the requester's messages in a thread as read by the current
+// admin.
+func (sc *SupportController) MarkRead(c *gin.Context) {
+	thread, ok := sc.supportThread(c)
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	n, err := sc.MessageService.MarkSupportRead(ctx, thread.ID, adminOID)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, gin.H{"marked_read": n})
+}
+
+func (sc *SupportController) respondThread(ctx context.Context, c *gin.Context, thread models.Conversation, err error) {
+	if err == mongo.ErrNoDocuments {
+		utils.JSONError(c, http.StatusNotFound, "support thread not found")
+		return
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	userID, _ := c.Get("user_id")
+	adminOID, _ := primitive.ObjectIDFromHex(userID.(string))
+	unread, err := sc.MessageService.SupportUnread(ctx, adminOID, []primitive.ObjectID{thread.ID})
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(c, http.StatusOK, sc.threadView(ctx, thread, unread[thread.ID]))
+}
+
+// threadView is the admin view of a support thread.
+func (sc *SupportController) threadView(ctx context.Context, thread models.Conversation, unread int) map[string]interface{} {
+	requester := thread.Participants[0]
+	notes := thread.Support.Notes
+	if notes == nil {
+		notes = []models.SupportNote{}
+	}
+	view := map[string]interface{}{
+		"id":              thread.ID,
+		"status":          thread.Support.Status,
+		"assignee_id":     thread.Support.AssigneeID,
+		"notes":           notes,
+		"last_message":    thread.LastMessage,
+		"last_sender_id":  thread.LastSenderID,
+		"last_message_at": thread.LastMessageAt,
+		"unread_count":    unread,
+		"created_at":      thread.CreatedAt,
+	}
+	requesterView := map[string]interface{}{"id": requester.UserID, "role": requester.Role}
+	if user, err := sc.UserService.FindByID(ctx, requester.UserID); err == nil {
+		requesterView["name"] = user.Name
+		requesterView["email"] = user.Email
+		requesterView["is_premium"] = user.IsPremium
+	}
+	view["requester"] = requesterView
+	if thread.Support.AssigneeID != nil {
+		if admin, err := sc.UserService.FindByID(ctx, *thread.Support.AssigneeID); err == nil {
+			view["assignee_name"] = admin.Name
+		}
+	}
+	return view
+}
+
+// supportThread loads the :id support thread, answering 404 if there is none.
+func (sc *SupportController) supportThread(c *gin.Context) (models.Conversation, bool) {
+	convOID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "invalid thread id")
+		return models.Conversation{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	thread, err := sc.MessageService.FindSupportThread(ctx, convOID)
+	if err != nil {
+		utils.JSONError(c, http.StatusNotFound, "support thread not found")
+		return models.Conversation{}, false
+	}
+	return thread, true
+}
@@ -67,9 +67,8 @@ func (u *UserController) GetUserProfilePublic(c *gin.Context) {
 			"phone_number":  user.PhoneNumber,
 			"summary":       user.Summary,
 			"education":     user.Education,
-			"tenth_marks":   user.TenthMarks,
-			"twelfth_marks": user.TwelfthMarks,
 			"experience":    user.Experience,
+			"experience_years": user.ExperienceYears,
 			"is_active":     user.IsActive,
 			"is_premium":    user.IsPremium,
 		}
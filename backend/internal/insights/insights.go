@@ -0,0 +1,220 @@
+// Package insights aggregates skill supply and demand across the platform and
+// turns it into per-seeker skill-gap and career-path guidance.
+package insights
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"rizeos/backend/internal/models"
+)
+
+// SkillCount is a skill with the number of profiles or jobs listing it.
+type SkillCount struct {
+	Skill string `json:"skill"`
+	Count int    `json:"count"`
+}
+
+// CountSkills counts how many skill sets list each skill, most common first
+// (ties alphabetical). Skills should already be canonicalized; each set counts
+// a skill once and the first spelling seen is reported.
+func CountSkills(sets [][]string) []SkillCount {
+	counts := map[string]int{}
+	names := map[string]string{}
+	for _, set := range sets {
+		for key, name := range keyNames(set) {
+			counts[key]++
+			if names[key] == "" {
+				names[key] = name
+			}
+		}
+	}
+	out := make([]SkillCount, 0, len(counts))
+	for key, n := range counts {
+		out = append(out, SkillCount{Skill: names[key], Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Skill < out[j].Skill
+	})
+	return out
+}
+
+// Job is an active job as seen by the gap analysis.
+type Job struct {
+	Skills   []string
+	PostedAt time.Time
+	// Score rates a candidate with the given skills for this job, 0-100.
+	Score func(skills []string) float64
+}
+
+// Gap is a skill the seeker lacks, with the jobs it would qualify them for.
+type Gap struct {
+	Skill   string `json:"skill"`
+	Demand  int    `json:"demand"`  // active jobs listing the skill
+	Unlocks int    `json:"unlocks"` // additional jobs reaching the threshold with this skill
+}
+
+// PathStep is one skill in a suggested learning sequence.
+type PathStep struct {
+	Skill     string `json:"skill"`
+	Unlocks   int    `json:"unlocks"`   // jobs unlocked by this step
+	Qualified int    `json:"qualified"` // jobs at or above the threshold after this step
+}
+
+// SkillGaps returns how many jobs the seeker already qualifies for (score at
+// or above threshold) and up to limit missing skills ranked by the jobs each
+// would unlock, then by demand.
+func SkillGaps(seeker []string, jobs []Job, threshold float64, limit int) (int, []Gap) {
+	qualified, gaps := gapsFor(seeker, jobs, threshold)
+	if limit > 0 && len(gaps) > limit {
+		gaps = gaps[:limit]
+	}
+	return qualified, gaps
+}
+
+// CareerPath greedily picks up to steps skills, each the one unlocking the
+// most jobs given the skills picked before it. Steps that unlock nothing end the path.
+func CareerPath(seeker []string, jobs []Job, threshold float64, steps int) []PathStep {
+	skills := append([]string{}, seeker...)
+	path := []PathStep{}
+	for len(path) < steps {
+		qualified, gaps := gapsFor(skills, jobs, threshold)
+		if len(gaps) == 0 || gaps[0].Unlocks == 0 {
+			break
+		}
+		next := gaps[0]
+		path = append(path, PathStep{Skill: next.Skill, Unlocks: next.Unlocks, Qualified: qualified + next.Unlocks})
+		skills = append(skills, next.Skill)
+	}
+	return path
+}
+
+func gapsFor(seeker []string, jobs []Job, threshold float64) (int, []Gap) {
+	have := keyNames(seeker)
+	qualifiedJob := make([]bool, len(jobs))
+	qualified := 0
+	for i, job := range jobs {
+		if job.Score != nil && job.Score(seeker) >= threshold {
+			qualifiedJob[i] = true
+			qualified++
+		}
+	}
+
+	demand := map[string]*Gap{}
+	var order []string
+	for _, job := range jobs {
+		for key, name := range keyNames(job.Skills) {
+			if _, ok := have[key]; ok {
+				continue
+			}
+			if demand[key] == nil {
+				demand[key] = &Gap{Skill: name}
+				order = append(order, key)
+			}
+			demand[key].Demand++
+		}
+	}
+	for _, key := range order {
+		g := demand[key]
+		with := append(append([]string{}, seeker...), g.Skill)
+		for i, job := range jobs {
+			if qualifiedJob[i] || job.Score == nil {
+				continue
+			}
+			if _, lists := keyNames(job.Skills)[key]; lists && job.Score(with) >= threshold {
+				g.Unlocks++
+			}
+		}
+	}
+
+	gaps := make([]Gap, 0, len(order))
+	for _, key := range order {
+		gaps = append(gaps, *demand[key])
+	}
+	sort.Slice(gaps, func(i, j int) bool {
+		if gaps[i].Unlocks != gaps[j].Unlocks {
+			return gaps[i].Unlocks > gaps[j].Unlocks
+		}
+		if gaps[i].Demand != gaps[j].Demand {
+			return gaps[i].Demand > gaps[j].Demand
+		}
+		return gaps[i].Skill < gaps[j].Skill
+	})
+	return qualified, gaps
+}
+
+// Trend is a skill's demand over consecutive periods.
+type Trend struct {
+	Skill    string  `json:"skill"`
+	Recent   int     `json:"recent"`   // jobs posted in the latest period
+	Previous int     `json:"previous"` // jobs posted in the period before
+	Growth   float64 `json:"growth"`   // percent change; previous 0 counts as 1
+	Series   []int   `json:"series"`   // jobs per period, oldest first
+}
+
+// Trending buckets job postings into periods ending at now and returns up to
+// limit skills posted in the latest period, fastest growing first.
+func Trending(jobs []Job, now time.Time, period time.Duration, periods, limit int) []Trend {
+	if period <= 0 || periods < 2 {
+		return []Trend{}
+	}
+	series := map[string][]int{}
+	names := map[string]string{}
+	for _, job := range jobs {
+		age := now.Sub(job.PostedAt)
+		if job.PostedAt.IsZero() || age < 0 {
+			continue
+		}
+		bucket := periods - 1 - int(age/period)
+		if bucket < 0 {
+			continue
+		}
+		for key, name := range keyNames(job.Skills) {
+			if series[key] == nil {
+				series[key] = make([]int, periods)
+				names[key] = name
+			}
+			series[key][bucket]++
+		}
+	}
+
+	out := []Trend{}
+	for key, s := range series {
+		recent, previous := s[periods-1], s[periods-2]
+		if recent == 0 {
+			continue
+		}
+		growth := float64(recent-previous) / math.Max(1, float64(previous)) * 100
+		out = append(out, Trend{Skill: names[key], Recent: recent, Previous: previous, Growth: math.Round(growth*10) / 10, Series: s})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Growth != out[j].Growth {
+			return out[i].Growth > out[j].Growth
+		}
+		if out[i].Recent != out[j].Recent {
+			return out[i].Recent > out[j].Recent
+		}
+		return out[i].Skill < out[j].Skill
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// keyNames maps normalized skill keys to their first spelling in skills.
+func keyNames(skills []string) map[string]string {
+	out := make(map[string]string, len(skills))
+	for _, s := range skills {
+		key := models.SkillKey(s)
+		if key != "" && out[key] == "" {
+			out[key] = strings.TrimSpace(s)
+		}
+	}
+	return out
+}
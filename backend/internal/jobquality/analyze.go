@@ -0,0 +1,238 @@
+// Package jobquality reviews draft job postings before they are paid for and
+// published: missing or vague fields, description length, skills mentioned in
+// the text but not listed, exclusionary wording, and the size of the current
+// candidate pool for the listed skills.
+package jobquality
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"rizeos/backend/internal/models"
+)
+
+// Severities, from blocking to advisory.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Description length bounds in words.
+const (
+	MinDescriptionWords = 50
+	MaxDescriptionWords = 1200
+	maxTitleChars       = 80
+)
+
+// Draft is a job posting under review.
+type Draft struct {
+	Title       string
+	Description string
+	Skills      []string
+	Location    string
+	Budget      float64
+}
+
+// Inputs are platform data the analysis compares the draft against.
+type Inputs struct {
+	// ExtractedSkills are skills found in the description (nil when extraction failed).
+	ExtractedSkills []string
+	// SeekerSkills are current seekers' skill sets, expanded with taxonomy parents.
+	SeekerSkills [][]string
+	// Budgets are budgets of existing jobs, for benchmarking.
+	Budgets []float64
+}
+
+// Issue is a single finding.
+type Issue struct {
+	Code       string `json:"code"`
+	Severity   string `json:"severity"`
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// CandidatePool estimates how many current seekers fit the listed skills.
+type CandidatePool struct {
+	Total   int `json:"total"`   // seekers considered
+	Full    int `json:"full"`    // seekers with every listed skill
+	Partial int `json:"partial"` // seekers with at least half (including Full)
+}
+
+// Report is the analysis result. Score starts at 100 and loses points per issue.
+type Report struct {
+	Score            int           `json:"score"`
+	Issues           []Issue       `json:"issues"`
+	UnlistedSkills   []string      `json:"unlistedSkills"` // in the description but not in Skills
+	DescriptionWords int           `json:"descriptionWords"`
+	CandidatePool    CandidatePool `json:"candidatePool"`
+}
+
+var penalty = map[string]int{SeverityError: 25, SeverityWarning: 10, SeverityInfo: 3}
+
+// Analyze reviews a draft.
+func Analyze(d Draft, in Inputs) Report {
+	r := Report{Issues: []Issue{}, UnlistedSkills: []string{}}
+	add := func(code, severity, field, message, suggestion string) {
+		r.Issues = append(r.Issues, Issue{Code: code, Severity: severity, Field: field, Message: message, Suggestion: suggestion})
+	}
+
+	title := strings.TrimSpace(d.Title)
+	switch {
+	case title == "":
+		add("missing_title", SeverityError, "title", "Title is required.", "")
+	case len(title) > maxTitleChars:
+		add("long_title", SeverityWarning, "title", fmt.Sprintf("Title is %d characters long.", len(title)), "Keep titles under 80 characters; put details in the description.")
+	case title == strings.ToUpper(title) && strings.ToLower(title) != title:
+		add("shouting_title", SeverityInfo, "title", "Title is in all caps.", "Use title case.")
+	}
+
+	r.DescriptionWords = len(strings.Fields(d.Description))
+	switch {
+	case r.DescriptionWords == 0:
+		add("missing_description", SeverityError, "description", "Description is required.", "")
+	case r.DescriptionWords < MinDescriptionWords:
+		add("short_description", SeverityWarning, "description", fmt.Sprintf("Description has only %d words.", r.DescriptionWords), fmt.Sprintf("Describe responsibilities, requirements and team in at least %d words.", MinDescriptionWords))
+	case r.DescriptionWords > MaxDescriptionWords:
+		add("long_description", SeverityWarning, "description", fmt.Sprintf("Description has %d words.", r.DescriptionWords), fmt.Sprintf("Trim it below %d words; long postings get fewer applications.", MaxDescriptionWords))
+	}
+
+	listed := models.SkillKeySet(d.Skills)
+	if len(listed) == 0 {
+		add("missing_skills", SeverityError, "skills", "No skills are listed.", "List the skills candidates need so they can be matched.")
+	}
+	if in.ExtractedSkills == nil && r.DescriptionWords > 0 {
+		add("skill_extraction_unavailable", SeverityInfo, "description", "Skills in the description could not be checked right now.", "")
+	}
+	seen := map[string]bool{}
+	for _, s := range in.ExtractedSkills {
+		key := models.SkillKey(s)
+		if key != "" && !listed[key] && !seen[key] {
+			seen[key] = true
+			r.UnlistedSkills = append(r.UnlistedSkills, strings.TrimSpace(s))
+		}
+	}
+	if len(r.UnlistedSkills) > 0 {
+		add("unlisted_skills", SeverityWarning, "skills", "The description mentions skills that are not listed: "+strings.Join(r.UnlistedSkills, ", ")+".", "Add them to Skills if they are required, or remove them from the description.")
+	}
+
+	for _, hit := range findBiasedTerms(title + "\n" + d.Description) {
+		add("biased_wording", SeverityWarning, "description", fmt.Sprintf("%q may discourage %s.", hit.match, hit.term.discourages), hit.term.suggestion)
+	}
+
+	location := strings.ToLower(strings.TrimSpace(d.Location))
+	switch {
+	case location == "":
+		add("missing_location", SeverityWarning, "location", "No location is given.", `Give a city, or "Remote".`)
+	case vagueLocations[location]:
+		add("vague_location", SeverityWarning, "location", fmt.Sprintf("Location %q is vague.", d.Location), `Give a city, a region, or "Remote".`)
+	}
+
+	if d.Budget <= 0 {
+		add("missing_budget", SeverityWarning, "budget", "No budget is given.", "Postings with a budget attract more qualified applicants.")
+	} else if median, n := medianOf(in.Budgets); n >= 5 && (d.Budget < median/3 || d.Budget > median*3) {
+		add("budget_outlier", SeverityInfo, "budget", fmt.Sprintf("Budget %.0f is far from the platform median of %.0f.", d.Budget, median), "Double-check the amount.")
+	}
+
+	r.CandidatePool = candidatePool(listed, in.SeekerSkills)
+	if len(listed) > 0 && r.CandidatePool.Total > 0 && r.CandidatePool.Full == 0 {
+		add("empty_candidate_pool", SeverityInfo, "skills", "No current job seeker has every listed skill.", "Consider marking some skills as nice-to-have in the scoring profile.")
+	}
+
+	sort.SliceStable(r.Issues, func(i, j int) bool { return penalty[r.Issues[i].Severity] > penalty[r.Issues[j].Severity] })
+	score := 100
+	for _, is := range r.Issues {
+		score -= penalty[is.Severity]
+	}
+	r.Score = int(math.Max(0, float64(score)))
+	return r
+}
+
+func candidatePool(required map[string]bool, seekers [][]string) CandidatePool {
+	pool := CandidatePool{Total: len(seekers)}
+	if len(required) == 0 {
+		return pool
+	}
+	half := (len(required) + 1) / 2
+	for _, skills := range seekers {
+		have := models.SkillKeySet(skills)
+		n := 0
+		for key := range required {
+			if have[key] {
+				n++
+			}
+		}
+		if n == len(required) {
+			pool.Full++
+		}
+		if n >= half {
+			pool.Partial++
+		}
+	}
+	return pool
+}
+
+var vagueLocations = map[string]bool{
+	"anywhere": true, "any": true, "tbd": true, "tba": true, "n/a": true, "na": true,
+	"various": true, "multiple": true, "flexible": true, "global": true, "worldwide": true,
+}
+
+type biasedTerm struct {
+	pattern     *regexp.Regexp
+	discourages string
+	suggestion  string
+}
+
+// biasedTerms lists wording research links to fewer or narrower applications.
+var biasedTerms = []biasedTerm{
+	{regexp.MustCompile(`(?i)\b(rock ?stars?|ninjas?|gurus?|wizards?|superstars?)\b`), "many qualified applicants, especially women", `Name the skill level instead, e.g. "experienced" or "senior".`},
+	{regexp.MustCompile(`(?i)\b(young|youthful|recent graduates? only|digital natives?)\b`), "older applicants", "Describe the experience needed rather than age."},
+	{regexp.MustCompile(`(?i)\bnative (english|hindi) speakers?\b`), "applicants based on national origin", `Ask for "fluent" or "professional proficiency" instead.`},
+	{regexp.MustCompile(`(?i)\b(salesman|salesmen|chairman|manpower|workmanship)\b`), "women", "Use gender-neutral wording such as salesperson, chair or workforce."},
+	{regexp.MustCompile(`(?i)\b(he|him|his) (will|must|should)\b`), "applicants who are not men", `Address the candidate as "you".`},
+	{regexp.MustCompile(`(?i)\b(aggressive|dominant|dominate)\b`), "many qualified applicants, especially women", `Prefer words like "proactive" or "driven".`},
+	{regexp.MustCompile(`(?i)\b(able[- ]bodied|must be able to stand)\b`), "applicants with disabilities", "Describe the essential task rather than a physical trait."},
+	{regexp.MustCompile(`(?i)\bculture fit\b`), "applicants from different backgrounds", `Say "values alignment" and list the values.`},
+}
+
+type biasedHit struct {
+	match string
+	term  biasedTerm
+}
+
+func findBiasedTerms(text string) []biasedHit {
+	var hits []biasedHit
+	seen := map[string]bool{}
+	for _, t := range biasedTerms {
+		for _, m := range t.pattern.FindAllString(text, -1) {
+			key := strings.ToLower(m)
+			if !seen[key] {
+				seen[key] = true
+				hits = append(hits, biasedHit{match: m, term: t})
+			}
+		}
+	}
+	return hits
+}
+
+func medianOf(values []float64) (float64, int) {
+	var v []float64
+	for _, x := range values {
+		if x > 0 {
+			v = append(v, x)
+		}
+	}
+	if len(v) == 0 {
+		return 0, 0
+	}
+	sort.Float64s(v)
+	mid := len(v) / 2
+	if len(v)%2 == 0 {
+		return (v[mid-1] + v[mid]) / 2, len(v)
+	}
+	return v[mid], len(v)
+}
@@ -0,0 +1,164 @@
+// Package localai is a pure-Go stand-in for the FastAPI AI microservice, used
+// when it is unreachable. Match scores use TF-IDF cosine similarity instead of
+// sentence embeddings, combined with skill coverage using the same 70/30
+// weighting as the service, so results are comparable but not identical.
+package localai
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"rizeos/backend/internal/models"
+)
+
+// Hybrid weights and full-coverage floor mirror the AI service's /match.
+const (
+	semanticWeight    = 0.7
+	skillWeight       = 0.3
+	fullCoverageFloor = 0.95
+	maxBreadthBonus   = 0.05
+)
+
+// Match is a locally computed match score; all values are 0-100.
+type Match struct {
+	Score         float64
+	SemanticScore float64
+	SkillScore    float64
+}
+
+// Score computes the hybrid match score for a job description and candidate bio.
+func Score(jobDesc, candidateBio string, jobSkills, candidateSkills []string) Match {
+	jobDesc, candidateBio = strings.TrimSpace(jobDesc), strings.TrimSpace(candidateBio)
+	if jobDesc == "" || candidateBio == "" {
+		return Match{}
+	}
+	semantic := tfidfCosine(tokenize(jobDesc), tokenize(candidateBio))
+	skill := skillCoverage(jobSkills, candidateSkills)
+	hybrid := semanticWeight*semantic + skillWeight*skill
+	if skill >= 1 {
+		hybrid = math.Max(fullCoverageFloor, hybrid)
+	}
+	return Match{
+		Score:         round2(clamp01(hybrid) * 100),
+		SemanticScore: round2(clamp01(semantic) * 100),
+		SkillScore:    round2(clamp01(skill) * 100),
+	}
+}
+
+// tfidfCosine returns the cosine similarity of two token lists weighted by
+// smoothed inverse document frequency over the pair.
+func tfidfCosine(a, b []string) float64 {
+	tfA, tfB := termFreq(a), termFreq(b)
+	if len(tfA) == 0 || len(tfB) == 0 {
+		return 0
+	}
+	idf := func(term string) float64 {
+		df := 0.0
+		if tfA[term] > 0 {
+			df++
+		}
+		if tfB[term] > 0 {
+			df++
+		}
+		// Smoothed IDF (as in scikit-learn) keeps shared terms non-zero.
+		return math.Log((1+2)/(1+df)) + 1
+	}
+	dot, normA, normB := 0.0, 0.0, 0.0
+	for term, f := range tfA {
+		w := f * idf(term)
+		normA += w * w
+		if g, ok := tfB[term]; ok {
+			dot += w * g * idf(term)
+		}
+	}
+	for term, g := range tfB {
+		w := g * idf(term)
+		normB += w * w
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// skillCoverage is the share of required skills the candidate has, plus a
+// small breadth bonus for extra skills, as computed by the AI service.
+func skillCoverage(required, candidate []string) float64 {
+	req, have := models.SkillKeySet(required), models.SkillKeySet(candidate)
+	if len(req) == 0 || len(have) == 0 {
+		return 0
+	}
+	matched := 0
+	for s := range req {
+		if have[s] {
+			matched++
+		}
+	}
+	score := float64(matched) / float64(len(req))
+	if extra := len(have) - matched; extra > 0 && score > 0 {
+		score += math.Min(float64(extra)*0.02, maxBreadthBonus)
+	}
+	return math.Min(score, 1)
+}
+
+func termFreq(tokens []string) map[string]float64 {
+	tf := make(map[string]float64, len(tokens))
+	for _, t := range tokens {
+		tf[t]++
+	}
+	for t, n := range tf {
+		tf[t] = n / float64(len(tokens))
+	}
+	return tf
+}
+
+// tokenize returns the words of text without stop words and one-letter noise
+// (except the C and R languages).
+func tokenize(text string) []string {
+	all := words(text)
+	tokens := all[:0]
+	for _, w := range all {
+		if (len(w) < 2 && w != "c" && w != "r") || stopWords[w] {
+			continue
+		}
+		tokens = append(tokens, w)
+	}
+	return tokens
+}
+
+// words lowercases text and splits it into words, keeping characters common
+// in technology names (c++, c#, node.js).
+func words(text string) []string {
+	return casedWords(strings.ToLower(text))
+}
+
+// casedWords splits text like words but keeps its case.
+func casedWords(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#' && r != '.'
+	})
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.Trim(f, "."); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "has": true, "have": true, "in": true, "is": true, "it": true, "its": true,
+	"of": true, "on": true, "or": true, "our": true, "that": true, "the": true, "their": true, "this": true,
+	"to": true, "we": true, "will": true, "with": true, "you": true, "your": true, "i": true, "my": true,
+	"am": true, "was": true, "were": true, "who": true, "looking": true, "experience": true, "years": true,
+}
@@ -0,0 +1,65 @@
+package localai
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultVocabulary is used by ExtractSkills when no taxonomy is supplied.
+var DefaultVocabulary = []string{
+	"JavaScript", "TypeScript", "React", "Next.js", "Vue", "Angular", "Node.js", "Express",
+	"Python", "Django", "Flask", "FastAPI", "Go", "Golang", "Java", "Spring Boot", "C#", "C++",
+	"Rust", "Ruby", "Rails", "PHP", "Kotlin", "Swift", "SQL", "PostgreSQL", "MySQL", "MongoDB",
+	"Redis", "GraphQL", "REST", "HTML", "CSS", "Tailwind CSS", "Docker", "Kubernetes", "AWS",
+	"Google Cloud", "Azure", "Terraform", "Git", "Linux", "Machine Learning", "Deep Learning",
+	"Data Science", "TensorFlow", "PyTorch", "Pandas", "Solidity", "Blockchain", "Web3",
+}
+
+// ambiguousSkills are skill names that are also everyday English words ("ready
+// to go", "take a rest"). They only match when written as the proper noun.
+var ambiguousSkills = map[string]string{
+	"go":      "Go",
+	"rest":    "REST",
+	"git":     "Git",
+	"swift":   "Swift",
+	"express": "Express",
+	"rust":    "Rust",
+	"rails":   "Rails",
+}
+
+// ExtractSkills returns the vocabulary terms that occur in text as whole words
+// or phrases, ordered by first appearance in the text. Matching ignores case
+// except for ambiguousSkills.
+func ExtractSkills(text string, vocabulary []string) []string {
+	if len(vocabulary) == 0 {
+		vocabulary = DefaultVocabulary
+	}
+	padded := " " + strings.Join(words(text), " ") + " "
+	cased := " " + strings.Join(casedWords(text), " ") + " "
+	type hit struct {
+		skill string
+		pos   int
+	}
+	var hits []hit
+	seen := map[string]bool{}
+	for _, term := range vocabulary {
+		phrase := strings.Join(words(term), " ")
+		if phrase == "" || seen[phrase] {
+			continue
+		}
+		pos := strings.Index(padded, " "+phrase+" ")
+		if proper, ok := ambiguousSkills[phrase]; ok {
+			pos = strings.Index(cased, " "+proper+" ")
+		}
+		if pos >= 0 {
+			seen[phrase] = true
+			hits = append(hits, hit{skill: term, pos: pos})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].pos < hits[j].pos })
+	skills := make([]string, len(hits))
+	for i, h := range hits {
+		skills[i] = h.skill
+	}
+	return skills
+}
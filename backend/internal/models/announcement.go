@@ -6,11 +6,91 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Audience segments.
+const (
+	AudienceAll        = "all" // recruiters and job seekers
+	AudienceRecruiters = "recruiters"
+	AudienceSeekers    = "seekers"
+	AudiencePremium    = "premium" // premium job seekers
+)
+
+// Announcement statuses. A SCHEDULED announcement is fanned out to its
+// audience once PublishAt passes (SENDING), then becomes PUBLISHED, or FAILED
+// after too many failed attempts. CANCELLED announcements are hidden.
+// Recruiter announcements start PENDING until an admin approves (SCHEDULED)
+// or rejects (REJECTED) them.
+const (
+	AnnouncementPending   = "PENDING"
+	AnnouncementRejected  = "REJECTED"
+	AnnouncementScheduled = "SCHEDULED"
+	AnnouncementSending   = "SENDING"
+	AnnouncementPublished = "PUBLISHED"
+	AnnouncementFailed    = "FAILED"
+	AnnouncementCancelled = "CANCELLED"
+)
+
 // Announcement represents a global announcement sent by admin or recruiter.
 type Announcement struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	FromRole  string             `bson:"from_role" json:"from_role"` // "admin" or "recruiter"
-	Message   string             `bson:"message" json:"message"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	FromRole  string              `bson:"from_role" json:"from_role"` // "admin" or "recruiter"
+	CreatedBy *primitive.ObjectID `bson:"created_by,omitempty" json:"created_by,omitempty"`
+	Message   string              `bson:"message" json:"message"`
+	Audience  Audience            `bson:"audience" json:"audience"`
+	PublishAt time.Time           `bson:"publish_at" json:"publish_at"`
+	ExpiresAt *time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // hidden from then on
+	Status    string              `bson:"status" json:"status"`                             // one of the Announcement* statuses
+	FanOut    FanOut              `bson:"fan_out" json:"fan_out"`
+	// Moderation is set for announcements that need review.
+	Moderation *Moderation `bson:"moderation,omitempty" json:"moderation,omitempty"`
+	CreatedAt  time.Time   `bson:"created_at" json:"created_at"`
 }
 
+// Audience selects the users an announcement is delivered to: a segment,
+// narrowed to users with any of Skills and whose location contains Location.
+type Audience struct {
+	Segment  string   `bson:"segment" json:"segment"` // one of the Audience* segments
+	Skills   []string `bson:"skills,omitempty" json:"skills,omitempty"`
+	Location string   `bson:"location,omitempty" json:"location,omitempty"`
+}
+
+// Automated moderation flags.
+const (
+	FlagLink      = "link"
+	FlagProfanity = "profanity"
+	FlagLanguage  = "language" // mild language, left for an admin to judge
+	FlagSpam      = "spam"
+)
+
+// Moderation records the review of an announcement. Flags come from the
+// automated pre-filter; ReviewedBy is nil when the pre-filter rejected it.
+type Moderation struct {
+	Flags      []string            `bson:"flags,omitempty" json:"flags,omitempty"`
+	Reason     string              `bson:"reason,omitempty" json:"reason,omitempty"` // why it was rejected
+	ReviewedBy *primitive.ObjectID `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time          `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+}
+
+// FanOut tracks delivery of an announcement to its audience. Users are
+// delivered in id order, so a retry resumes after Cursor.
+type FanOut struct {
+	Total         int64              `bson:"total" json:"total"`         // audience size when delivery started
+	Delivered     int64              `bson:"delivered" json:"delivered"` // users delivered so far
+	Cursor        primitive.ObjectID `bson:"cursor,omitempty" json:"-"`
+	Attempts      int                `bson:"attempts" json:"attempts"` // failed attempts
+	LastError     string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt *time.Time         `bson:"next_attempt_at,omitempty" json:"next_attempt_at,omitempty"`
+	LeaseUntil    *time.Time         `bson:"lease_until,omitempty" json:"-"` // a worker is delivering until then
+	StartedAt     *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	CompletedAt   *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// AnnouncementReceipt records an announcement delivered to a user and what
+// they did with it.
+type AnnouncementReceipt struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AnnouncementID primitive.ObjectID `bson:"announcement_id" json:"announcement_id"`
+	UserID         primitive.ObjectID `bson:"user_id" json:"user_id"`
+	DeliveredAt    time.Time          `bson:"delivered_at" json:"delivered_at"`
+	ReadAt         *time.Time         `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	DismissedAt    *time.Time         `bson:"dismissed_at,omitempty" json:"dismissed_at,omitempty"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Support thread statuses. A requester's message reopens the thread; an admin
+// reply leaves it pending on the requester.
+const (
+	SupportOpen     = "OPEN"
+	SupportPending  = "PENDING"
+	SupportResolved = "RESOLVED"
+)
+
+// ConversationParticipant is one side of a conversation.
+type ConversationParticipant struct {
+	UserID primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Role   string             `bson:"role" json:"role"`
+}
+
+// SupportNote is an internal note admins keep on a support thread. Notes are
+// never shown to the requester.
+type SupportNote struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	AuthorID  primitive.ObjectID `bson:"author_id" json:"author_id"`
+	Note      string             `bson:"note" json:"note"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// SupportThread is the admin-side state of a support conversation.
+type SupportThread struct {
+	Status     string              `bson:"status" json:"status"`                               // one of the Support* statuses
+	AssigneeID *primitive.ObjectID `bson:"assignee_id,omitempty" json:"assignee_id,omitempty"` // nil while in the shared queue
+	Notes      []SupportNote       `bson:"notes,omitempty" json:"notes"`
+}
+
+// Conversation groups the messages exchanged by two users, optionally about a
+// job. There is one conversation per participant pair and job, except that
+// everything between a user and the admins is one support conversation whose
+// only participant is that user; any admin may handle it.
+type Conversation struct {
+	ID            primitive.ObjectID        `bson:"_id,omitempty" json:"id"`
+	Key           string                    `bson:"key" json:"-"` // sorted participant ids and job id, unique
+	Participants  []ConversationParticipant `bson:"participants" json:"participants"`
+	JobID         *primitive.ObjectID       `bson:"job_id,omitempty" json:"job_id,omitempty"`
+	LastMessage   string                    `bson:"last_message" json:"last_message"`
+	LastSenderID  primitive.ObjectID        `bson:"last_sender_id" json:"last_sender_id"`
+	LastMessageAt time.Time                 `bson:"last_message_at" json:"last_message_at"`
+	Unread        map[string]int            `bson:"unread" json:"-"`            // unread messages per participant id
+	Support       *SupportThread            `bson:"support,omitempty" json:"-"` // set on support conversations; admin only
+	CreatedAt     time.Time                 `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time                 `bson:"updated_at" json:"updated_at"`
+}
+
+// Participant returns the participant with the given id.
+func (c Conversation) Participant(userID primitive.ObjectID) (ConversationParticipant, bool) {
+	for _, p := range c.Participants {
+		if p.UserID == userID {
+			return p, true
+		}
+	}
+	return ConversationParticipant{}, false
+}
+
+// Other returns the participant that is not userID.
+func (c Conversation) Other(userID primitive.ObjectID) (ConversationParticipant, bool) {
+	for _, p := range c.Participants {
+		if p.UserID != userID {
+			return p, true
+		}
+	}
+	return ConversationParticipant{}, false
+}
@@ -8,17 +8,38 @@ import (
 
 // Job represents a recruiter-created job listing.
 type Job struct {
-	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
-	RecruiterID primitive.ObjectID   `bson:"recruiter_id" json:"recruiter_id"`
-	Title       string               `bson:"title" json:"title"`
-	Description string               `bson:"description" json:"description"`
-	Skills      []string             `bson:"skills" json:"skills"`
-	Location    string               `bson:"location" json:"location"`
-	Tags        []string             `bson:"tags" json:"tags"`
-	Budget      float64              `bson:"budget" json:"budget"`
-	PaymentID   primitive.ObjectID   `bson:"payment_id" json:"payment_id"`
-	CreatedAt   time.Time            `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time            `bson:"updated_at" json:"updated_at"`
-	MatchScores map[string]float64   `bson:"match_scores,omitempty" json:"match_scores,omitempty"`
-	Candidates  []primitive.ObjectID `bson:"candidates,omitempty" json:"candidates,omitempty"`
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	RecruiterID    primitive.ObjectID   `bson:"recruiter_id" json:"recruiter_id"`
+	Title          string               `bson:"title" json:"title"`
+	Description    string               `bson:"description" json:"description"`
+	Skills         []string             `bson:"skills" json:"skills"`
+	Location       string               `bson:"location" json:"location"`
+	Tags           []string             `bson:"tags" json:"tags"`
+	Budget         float64              `bson:"budget" json:"budget"`
+	PaymentID      primitive.ObjectID   `bson:"payment_id" json:"payment_id"`
+	CreatedAt      time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time            `bson:"updated_at" json:"updated_at"`
+	Candidates     []primitive.ObjectID `bson:"candidates,omitempty" json:"candidates,omitempty"`
+	ScoringProfile *ScoringProfile      `bson:"scoring_profile,omitempty" json:"scoring_profile,omitempty"`
+	ExpiresAt      *time.Time           `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // closing date for applications; nil never closes
+}
+
+// Closed reports whether the job stopped taking applications at now.
+func (j Job) Closed(now time.Time) bool {
+	return j.ExpiresAt != nil && !now.Before(*j.ExpiresAt)
+}
+
+// SkillRequirement weights a single job skill for candidate ranking.
+type SkillRequirement struct {
+	Skill    string  `bson:"skill" json:"skill"`
+	MustHave bool    `bson:"must_have" json:"must_have"`
+	Weight   float64 `bson:"weight,omitempty" json:"weight,omitempty"` // defaults to 1
+}
+
+// ScoringProfile configures how the ranking engine scores candidates for a job.
+// A nil profile treats every job skill as an equally weighted nice-to-have.
+type ScoringProfile struct {
+	Skills             []SkillRequirement `bson:"skills,omitempty" json:"skills,omitempty"`
+	MinExperienceYears float64            `bson:"min_experience_years,omitempty" json:"min_experience_years,omitempty"`
+	FactorWeights      map[string]float64 `bson:"factor_weights,omitempty" json:"factor_weights,omitempty"` // scorer name -> weight
 }
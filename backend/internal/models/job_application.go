@@ -6,15 +6,27 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Application statuses. Recruiters move applications from APPLIED to
+// SHORTLISTED and then to HIRED; either of the first two may be REJECTED.
+// HIRED and REJECTED are final.
+const (
+	ApplicationApplied     = "APPLIED"
+	ApplicationShortlisted = "SHORTLISTED"
+	ApplicationHired       = "HIRED"
+	ApplicationRejected    = "REJECTED"
+)
+
 // JobApplication represents a job seeker's application to a job.
 type JobApplication struct {
 	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	JobID             primitive.ObjectID `bson:"job_id" json:"job_id"`
 	JobSeekerID       primitive.ObjectID `bson:"job_seeker_id" json:"job_seeker_id"`
 	RecruiterID       primitive.ObjectID `bson:"recruiter_id" json:"recruiter_id"`
-	ApplicationStatus string             `bson:"application_status" json:"application_status"` // "APPLIED", "SHORTLISTED", "REJECTED", etc.
+	ApplicationStatus string             `bson:"application_status" json:"application_status"` // one of the Application* statuses
 	AppliedAt         time.Time          `bson:"applied_at" json:"applied_at"`
+	ShortlistedAt     *time.Time         `bson:"shortlisted_at,omitempty" json:"shortlisted_at,omitempty"`
+	HiredAt           *time.Time         `bson:"hired_at,omitempty" json:"hired_at,omitempty"`
+	RejectedAt        *time.Time         `bson:"rejected_at,omitempty" json:"rejected_at,omitempty"`
 	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt         time.Time          `bson:"updated_at" json:"updated_at"`
 }
-
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JobDailyStats is a per-job, per-day rollup of hiring funnel events. Day is
+// midnight UTC; counters are incremented as events happen.
+type JobDailyStats struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	JobID       primitive.ObjectID `bson:"job_id" json:"job_id"`
+	RecruiterID primitive.ObjectID `bson:"recruiter_id" json:"recruiter_id"`
+	Day         time.Time          `bson:"day" json:"day"`
+	Views       int                `bson:"views" json:"views"`
+	Applies     int                `bson:"applies" json:"applies"`
+	Shortlisted int                `bson:"shortlisted" json:"shortlisted"`
+	Hired       int                `bson:"hired" json:"hired"`
+	Rejected    int                `bson:"rejected" json:"rejected"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JobView records that a viewer opened a job on a given day. There is at most
+// one per job, viewer and day, so repeat visits the same day are not counted.
+type JobView struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	JobID       primitive.ObjectID  `bson:"job_id" json:"job_id"`
+	RecruiterID primitive.ObjectID  `bson:"recruiter_id" json:"recruiter_id"`
+	ViewerKey   string              `bson:"viewer_key" json:"-"`              // "user:<id>" or "anon:<hash>"
+	UserID      *primitive.ObjectID `bson:"user_id,omitempty" json:"user_id"` // nil for anonymous viewers
+	Day         time.Time           `bson:"day" json:"day"`
+	ViewedAt    time.Time           `bson:"viewed_at" json:"viewed_at"` // first view that day
+}
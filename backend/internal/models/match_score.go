@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatchScore is a cached AI match score for a (job, seeker) pair.
+// Hashes capture the content it was computed from; a mismatch means stale.
+type MatchScore struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	JobID         primitive.ObjectID `bson:"job_id" json:"job_id"`
+	SeekerID      primitive.ObjectID `bson:"seeker_id" json:"seeker_id"`
+	JobHash       string             `bson:"job_hash" json:"job_hash"`
+	SeekerHash    string             `bson:"seeker_hash" json:"seeker_hash"`
+	Score         float64            `bson:"score" json:"score"`
+	SemanticScore *float64           `bson:"semantic_score,omitempty" json:"semantic_score,omitempty"`
+	SkillScore    *float64           `bson:"skill_score,omitempty" json:"skill_score,omitempty"`
+	Engine        string             `bson:"engine,omitempty" json:"engine,omitempty"`
+	ComputedAt    time.Time          `bson:"computed_at" json:"computed_at"`
+	ExpiresAt     time.Time          `bson:"expires_at" json:"expires_at"`
+}
@@ -8,13 +8,44 @@ import (
 
 // Message represents a message between users.
 type Message struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	FromUserID primitive.ObjectID `bson:"from_user_id" json:"from_user_id"`
-	FromRole   string             `bson:"from_role" json:"from_role"` // "recruiter" | "seeker" | "admin"
-	ToUserID   primitive.ObjectID `bson:"to_user_id" json:"to_user_id"`
-	ToRole     string             `bson:"to_role" json:"to_role"` // "admin" | "recruiter" | "seeker"
-	Message    string             `bson:"message" json:"message"`
-	JobID      primitive.ObjectID `bson:"job_id,omitempty" json:"job_id,omitempty"` // Optional: for job-context messages
-	IsRead     bool               `bson:"is_read" json:"is_read"`
-	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	FromUserID     primitive.ObjectID   `bson:"from_user_id" json:"from_user_id"`
+	FromRole       string               `bson:"from_role" json:"from_role"` // "recruiter" | "seeker" | "admin"
+	ToUserID       primitive.ObjectID   `bson:"to_user_id" json:"to_user_id"`
+	ToRole         string               `bson:"to_role" json:"to_role"` // "admin" | "recruiter" | "seeker"
+	Message        string               `bson:"message" json:"message"`
+	Attachments    []Attachment         `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	JobID          primitive.ObjectID   `bson:"job_id,omitempty" json:"job_id,omitempty"`                   // Optional: for job-context messages
+	ConversationID *primitive.ObjectID  `bson:"conversation_id,omitempty" json:"conversation_id,omitempty"` // nil for announcements
+	IsRead         bool                 `bson:"is_read" json:"is_read"`
+	DeliveredAt    *time.Time           `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"` // first pushed to a recipient connection
+	ReadAt         *time.Time           `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	ReadBy         []primitive.ObjectID `bson:"read_by,omitempty" json:"-"`     // admins who read a message to admins
+	ArchivedBy     []primitive.ObjectID `bson:"archived_by,omitempty" json:"-"` // participants who archived their copy
+	DeletedBy      []primitive.ObjectID `bson:"deleted_by,omitempty" json:"-"`  // participants who deleted their copy
+	CreatedAt      time.Time            `bson:"created_at" json:"created_at"`
+}
+
+// ReadFor reports whether userID has read m. Messages to admins are read by
+// each admin separately; IsRead only tells their sender an admin read it.
+func (m Message) ReadFor(userID primitive.ObjectID) bool {
+	if m.ToRole == RoleAdmin {
+		for _, id := range m.ReadBy {
+			if id == userID {
+				return true
+			}
+		}
+		return false
+	}
+	return m.IsRead
+}
+
+// Attachment is a file sent with a message. The content lives in the blob
+// store under Key.
+type Attachment struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	ContentType string             `bson:"content_type" json:"content_type"`
+	Size        int64              `bson:"size" json:"size"`
+	Key         string             `bson:"key" json:"-"`
 }
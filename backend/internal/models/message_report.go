@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Message report statuses.
+const (
+	ReportOpen      = "OPEN"
+	ReportResolved  = "RESOLVED"
+	ReportDismissed = "DISMISSED"
+)
+
+// MessageReport flags a message as abusive for admins to review. A recipient
+// can report a message once.
+type MessageReport struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	MessageID  primitive.ObjectID  `bson:"message_id" json:"message_id"`
+	ReporterID primitive.ObjectID  `bson:"reporter_id" json:"reporter_id"`
+	SenderID   primitive.ObjectID  `bson:"sender_id" json:"sender_id"`
+	Reason     string              `bson:"reason" json:"reason"`
+	Status     string              `bson:"status" json:"status"` // one of the Report* statuses
+	ReviewedBy *primitive.ObjectID `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewNote string              `bson:"review_note,omitempty" json:"review_note,omitempty"`
+	ReviewedAt *time.Time          `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+}
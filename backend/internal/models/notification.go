@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification types: system events users are told about.
+const (
+	NotifyApplicationStatus = "application_status" // seeker: a recruiter moved their application
+	NotifyNewApplicant      = "new_applicant"      // recruiter: a seeker applied to their job
+	NotifyPaymentVerified   = "payment_verified"   // payer: a fee payment was verified on chain
+	NotifyJobExpiring       = "job_expiring"       // recruiter: a job's closing date is near
+	NotifyNewMatchingJob    = "new_matching_job"   // seeker: a new job lists skills they have
+)
+
+// NotificationTypes lists every notification type.
+var NotificationTypes = []string{NotifyApplicationStatus, NotifyNewApplicant, NotifyPaymentVerified, NotifyJobExpiring, NotifyNewMatchingJob}
+
+// Notification channels.
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// Digest frequencies. With a digest, email and webhook notifications are
+// batched into one delivery per period instead of sent one by one.
+const (
+	DigestOff    = "off"
+	DigestHourly = "hourly"
+	DigestDaily  = "daily"
+)
+
+// Notification is a system event for one user.
+type Notification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Type      string             `bson:"type" json:"type"`
+	Title     string             `bson:"title" json:"title"`
+	Body      string             `bson:"body" json:"body"`
+	Data      map[string]string  `bson:"data,omitempty" json:"data,omitempty"` // ids of the job, application or payment concerned
+	Key       string             `bson:"key,omitempty" json:"-"`               // dedupes one-off events such as a job's expiry notice
+	InApp     bool               `bson:"in_app" json:"-"`                      // shown in the notification center
+	Pending   []string           `bson:"pending,omitempty" json:"-"`           // external channels awaiting delivery
+	IsRead    bool               `bson:"is_read" json:"is_read"`
+	ReadAt    *time.Time         `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// NotificationPreferences choose the channels of each notification type.
+type NotificationPreferences struct {
+	UserID       primitive.ObjectID  `bson:"_id" json:"-"`
+	Channels     map[string][]string `bson:"channels" json:"channels"` // type -> channels; an empty list mutes the type
+	WebhookURL   string              `bson:"webhook_url,omitempty" json:"webhook_url,omitempty"`
+	Digest       string              `bson:"digest" json:"digest"`
+	LastDigestAt *time.Time          `bson:"last_digest_at,omitempty" json:"last_digest_at,omitempty"`
+	UpdatedAt    time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// DefaultNotificationPreferences sends every type in-app and by email,
+// without a digest.
+func DefaultNotificationPreferences(userID primitive.ObjectID) NotificationPreferences {
+	channels := make(map[string][]string, len(NotificationTypes))
+	for _, t := range NotificationTypes {
+		channels[t] = []string{ChannelInApp, ChannelEmail}
+	}
+	return NotificationPreferences{UserID: userID, Channels: channels, Digest: DigestOff}
+}
+
+// Wants reports whether notifications of type t go to channel.
+func (p NotificationPreferences) Wants(t, channel string) bool {
+	for _, c := range p.Channels[t] {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
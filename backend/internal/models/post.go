@@ -6,13 +6,54 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Post types.
+const (
+	PostJob    = "job_post" // generated when a job is created
+	PostUpdate = "update"   // written by a user
+)
+
+// Post statuses. HIDDEN posts were taken down by an admin and are only
+// visible to their author and admins.
+const (
+	PostActive = "ACTIVE"
+	PostHidden = "HIDDEN"
+)
+
 // Post represents a feed item (job or update).
 type Post struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID       primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Type         string              `bson:"type" json:"type"` // job_post, update
+	JobID        *primitive.ObjectID `bson:"job_id,omitempty" json:"job_id,omitempty"`
+	Title        string              `bson:"title" json:"title"`
+	Content      string              `bson:"content" json:"content"`
+	Tags         []string            `bson:"tags" json:"tags"`
+	Status       string              `bson:"status" json:"status"` // one of the Post* statuses
+	LikeCount    int64               `bson:"like_count" json:"like_count"`
+	CommentCount int64               `bson:"comment_count" json:"comment_count"`
+	Moderation   *Moderation         `bson:"moderation,omitempty" json:"moderation,omitempty"` // set once an admin hides it
+	JobClosesAt  *time.Time          `bson:"job_closes_at,omitempty" json:"-"`                 // the job's expiry, for job posts
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// JobClosed reports whether the post announces a job that is closed at now.
+func (p Post) JobClosed(now time.Time) bool {
+	return p.JobClosesAt != nil && !now.Before(*p.JobClosesAt)
+}
+
+// PostComment is a comment on a post.
+type PostComment struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PostID    primitive.ObjectID `bson:"post_id" json:"post_id"`
 	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Type      string             `bson:"type" json:"type"` // job_post, update
-	Title     string             `bson:"title" json:"title"`
 	Content   string             `bson:"content" json:"content"`
-	Tags      []string           `bson:"tags" json:"tags"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// PostLike records that a user liked a post.
+type PostLike struct {
+	PostID    primitive.ObjectID `bson:"post_id" json:"post_id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 }
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Proficiency levels for seeker skills.
+const (
+	ProficiencyBeginner     = "beginner"
+	ProficiencyIntermediate = "intermediate"
+	ProficiencyAdvanced     = "advanced"
+	ProficiencyExpert       = "expert"
+)
+
+// Skill is a canonical entry in the skill taxonomy.
+type Skill struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Slug       string             `bson:"slug" json:"slug"` // canonical id, e.g. "javascript"
+	Name       string             `bson:"name" json:"name"` // display name, e.g. "JavaScript"
+	Aliases    []string           `bson:"aliases" json:"aliases"`
+	Category   string             `bson:"category" json:"category"`                           // e.g. "language", "framework", "cloud"
+	ParentSlug string             `bson:"parent_slug,omitempty" json:"parent_slug,omitempty"` // e.g. react -> javascript
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// SkillKey is the comparison key for a skill name: lowercased with whitespace
+// collapsed. Every package that matches skills by name uses it.
+func SkillKey(raw string) string {
+	return strings.Join(strings.Fields(strings.ToLower(raw)), " ")
+}
+
+// SkillKeySet returns the set of non-empty keys for skills.
+func SkillKeySet(skills []string) map[string]bool {
+	set := make(map[string]bool, len(skills))
+	for _, s := range skills {
+		if key := SkillKey(s); key != "" {
+			set[key] = true
+		}
+	}
+	return set
+}
+
+// SkillProficiency records how well a job seeker knows a skill.
+type SkillProficiency struct {
+	Skill string  `bson:"skill" json:"skill"`                     // canonical skill name
+	Level string  `bson:"level,omitempty" json:"level,omitempty"` // beginner, intermediate, advanced, expert
+	Years float64 `bson:"years,omitempty" json:"years,omitempty"`
+}
@@ -23,17 +23,38 @@ type User struct {
 	Bio           string             `bson:"bio" json:"bio"`
 	LinkedInURL   string             `bson:"linkedin_url" json:"linkedin_url"`
 	Skills        []string           `bson:"skills" json:"skills"`
+	SkillLevels   []SkillProficiency `bson:"skill_levels,omitempty" json:"skill_levels,omitempty"` // per-skill proficiency for job seekers
 	WalletAddress string             `bson:"wallet_address" json:"wallet_address"`
 	// New optional fields for job seekers
 	PhoneNumber   string             `bson:"phone_number,omitempty" json:"phone_number,omitempty"`
+	Location      string             `bson:"location,omitempty" json:"location,omitempty"`
 	Summary       string             `bson:"summary,omitempty" json:"summary,omitempty"`
-	Education     string             `bson:"education,omitempty" json:"education,omitempty"`
-	TenthMarks    interface{}        `bson:"tenth_marks,omitempty" json:"tenth_marks,omitempty"` // string or number
-	TwelfthMarks  interface{}        `bson:"twelfth_marks,omitempty" json:"twelfth_marks,omitempty"` // string or number
-	Experience    interface{}        `bson:"experience,omitempty" json:"experience,omitempty"` // string or number
+	Education     []Degree           `bson:"education,omitempty" json:"education,omitempty"`
+	Experience    []Position         `bson:"experience,omitempty" json:"experience,omitempty"`
+	ExperienceYears float64          `bson:"experience_years,omitempty" json:"experience_years,omitempty"` // computed from Experience on write
 	IsActive      *bool              `bson:"is_active,omitempty" json:"is_active,omitempty"` // pointer to allow nil (default true)
 	IsPremium     bool               `bson:"is_premium,omitempty" json:"is_premium,omitempty"` // premium status for job seekers
 	PremiumPaymentID *primitive.ObjectID `bson:"premium_payment_id,omitempty" json:"premium_payment_id,omitempty"` // reference to premium payment
 	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
 }
+
+// Position is a single entry in a job seeker's work history.
+type Position struct {
+	Company     string     `bson:"company" json:"company"`
+	Title       string     `bson:"title" json:"title"`
+	StartDate   *time.Time `bson:"start_date,omitempty" json:"start_date,omitempty"`
+	EndDate     *time.Time `bson:"end_date,omitempty" json:"end_date,omitempty"` // nil with a StartDate means current role
+	Years       float64    `bson:"years,omitempty" json:"years,omitempty"`       // explicit duration when dates are unknown (legacy imports)
+	Description string     `bson:"description,omitempty" json:"description,omitempty"`
+}
+
+// Degree is a single entry in a job seeker's education history.
+type Degree struct {
+	Institution string `bson:"institution" json:"institution"`
+	Degree      string `bson:"degree" json:"degree"` // e.g. "B.Tech", "Class 12"
+	Field       string `bson:"field,omitempty" json:"field,omitempty"`
+	Grade       string `bson:"grade,omitempty" json:"grade,omitempty"` // percentage, CGPA or letter grade
+	StartYear   int    `bson:"start_year,omitempty" json:"start_year,omitempty"`
+	EndYear     int    `bson:"end_year,omitempty" json:"end_year,omitempty"`
+}
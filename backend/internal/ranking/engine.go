@@ -0,0 +1,214 @@
+// Package ranking implements the weighted, rule-based candidate ranking engine.
+//
+// An Engine combines pluggable Scorers (skills, experience, location, premium,
+// semantic similarity). Each scorer returns a 0-100 factor; the final score is
+// the weighted average of applicable factors, scaled down by the share of
+// must-have skills the candidate is missing.
+//
+// Explain complements the engine by breaking the AI service's hybrid match
+// score into its semantic and skill-coverage components.
+package ranking
+
+import (
+	"math"
+
+	"rizeos/backend/internal/models"
+)
+
+// Factor names used as keys in models.ScoringProfile.FactorWeights.
+const (
+	FactorSkills     = "skills"
+	FactorSemantic   = "semantic"
+	FactorExperience = "experience"
+	FactorLocation   = "location"
+	FactorPremium    = "premium"
+)
+
+// DefaultWeights are used for factors a job's scoring profile does not override.
+// Premium is opt-in: with a non-zero weight every non-premium candidate scores
+// 0 on it, so it only applies when a job sets a weight for it.
+var DefaultWeights = map[string]float64{
+	FactorSkills:     0.6,
+	FactorSemantic:   0.4,
+	FactorExperience: 0.15,
+	FactorLocation:   0.1,
+	FactorPremium:    0,
+}
+
+// Job is the job side of a ranking request.
+type Job struct {
+	Location string
+	Profile  models.ScoringProfile
+}
+
+// Candidate is the seeker side of a ranking request. Skills should already be
+// canonicalized and expanded with taxonomy parents.
+type Candidate struct {
+	Skills          []string
+	ExperienceYears float64
+	Location        string
+	IsPremium       bool
+	SemanticScore   *float64 // AI similarity 0-100; nil when not computed
+}
+
+// Factor is one scorer's contribution to a candidate's score.
+type Factor struct {
+	Name         string  `json:"name"`
+	Score        float64 `json:"score"`        // 0-100
+	Weight       float64 `json:"weight"`       // normalized weight among applicable factors
+	Contribution float64 `json:"contribution"` // points added to the final score
+	Detail       string  `json:"detail,omitempty"`
+}
+
+// Result is a candidate's final score and its per-factor breakdown.
+type Result struct {
+	Score           float64  `json:"score"` // 0-100, rounded to 1 decimal place
+	Factors         []Factor `json:"factors"`
+	MatchedSkills   []string `json:"matchedSkills"`
+	MissingMustHave []string `json:"missingMustHave"`
+	MustHaveRatio   float64  `json:"mustHaveRatio"` // multiplier applied for missing must-haves
+}
+
+// Scorer computes a single ranking factor.
+type Scorer interface {
+	// Name is the factor key, also used to look up its weight.
+	Name() string
+	// Score returns a 0-100 value and a short explanation; ok is false when
+	// the factor does not apply (e.g. no AI score available).
+	Score(job Job, c Candidate) (score float64, detail string, ok bool)
+}
+
+// Engine ranks candidates with a fixed set of scorers.
+type Engine struct {
+	scorers []Scorer
+}
+
+// NewEngine builds an engine from scorers.
+func NewEngine(scorers ...Scorer) *Engine {
+	return &Engine{scorers: scorers}
+}
+
+// Default returns the engine with all built-in scorers.
+func Default() *Engine {
+	return NewEngine(SkillScorer{}, SemanticScorer{}, ExperienceScorer{}, LocationScorer{}, PremiumScorer{})
+}
+
+// KnownFactor reports whether name is a built-in factor.
+func KnownFactor(name string) bool {
+	_, ok := DefaultWeights[name]
+	return ok
+}
+
+// Score ranks a single candidate for a job.
+func (e *Engine) Score(job Job, c Candidate) Result {
+	type scored struct {
+		name, detail  string
+		score, weight float64
+	}
+	var applicable []scored
+	totalWeight := 0.0
+	for _, s := range e.scorers {
+		weight := weightFor(job.Profile, s.Name())
+		if weight <= 0 {
+			continue
+		}
+		score, detail, ok := s.Score(job, c)
+		if !ok {
+			continue
+		}
+		applicable = append(applicable, scored{name: s.Name(), detail: detail, score: clamp(score), weight: weight})
+		totalWeight += weight
+	}
+
+	matched, missing, mustHaveTotal := skillCoverage(job.Profile.Skills, c.Skills)
+	ratio := 1.0
+	if mustHaveTotal > 0 {
+		ratio = float64(mustHaveTotal-len(missing)) / float64(mustHaveTotal)
+	}
+
+	res := Result{
+		Factors:         make([]Factor, 0, len(applicable)),
+		MatchedSkills:   matched,
+		MissingMustHave: missing,
+		MustHaveRatio:   round1(ratio),
+	}
+	if totalWeight == 0 {
+		return res
+	}
+	total := 0.0
+	for _, a := range applicable {
+		w := a.weight / totalWeight
+		contribution := a.score * w * ratio
+		total += contribution
+		res.Factors = append(res.Factors, Factor{
+			Name:         a.name,
+			Score:        round1(a.score),
+			Weight:       math.Round(w*1000) / 1000,
+			Contribution: round1(contribution),
+			Detail:       a.detail,
+		})
+	}
+	res.Score = round1(clamp(total))
+	return res
+}
+
+// ProfileFor returns the job's scoring profile, defaulting to every job skill
+// being an equally weighted nice-to-have.
+func ProfileFor(job models.Job) models.ScoringProfile {
+	if job.ScoringProfile != nil && len(job.ScoringProfile.Skills) > 0 {
+		return *job.ScoringProfile
+	}
+	profile := models.ScoringProfile{}
+	if job.ScoringProfile != nil {
+		profile = *job.ScoringProfile
+	}
+	profile.Skills = make([]models.SkillRequirement, 0, len(job.Skills))
+	for _, s := range job.Skills {
+		profile.Skills = append(profile.Skills, models.SkillRequirement{Skill: s, Weight: 1})
+	}
+	return profile
+}
+
+func weightFor(profile models.ScoringProfile, name string) float64 {
+	if w, ok := profile.FactorWeights[name]; ok {
+		return w
+	}
+	return DefaultWeights[name]
+}
+
+// skillCoverage returns matched skills, missing must-haves and the must-have count.
+func skillCoverage(reqs []models.SkillRequirement, candidate []string) ([]string, []string, int) {
+	have := models.SkillKeySet(candidate)
+	matched := []string{}
+	missing := []string{}
+	mustHave := 0
+	for _, r := range reqs {
+		key := models.SkillKey(r.Skill)
+		if key == "" {
+			continue
+		}
+		if r.MustHave {
+			mustHave++
+		}
+		if have[key] {
+			matched = append(matched, r.Skill)
+		} else if r.MustHave {
+			missing = append(missing, r.Skill)
+		}
+	}
+	return matched, missing, mustHave
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 100 {
+		return 100
+	}
+	return v
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
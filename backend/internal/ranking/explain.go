@@ -0,0 +1,165 @@
+package ranking
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"rizeos/backend/internal/models"
+)
+
+// Weights and floor mirror the AI service's hybrid /match formula.
+const (
+	SemanticWeight     = 0.7
+	SkillWeight        = 0.3
+	fullCoverageFloor  = 95.0
+	maxSuggestions     = 3
+	lowSemanticWarning = 45.0
+)
+
+// Explanation describes why a job/candidate pair received its match score.
+type Explanation struct {
+	Score          float64  `json:"score"`
+	SemanticScore  float64  `json:"semanticScore"`    // description/bio similarity component (0-100)
+	SkillCoverage  float64  `json:"skillCoverage"`    // share of required skills matched (0-100)
+	SemanticWeight float64  `json:"semanticWeight"`   // weight of SemanticScore in Score
+	SkillWeight    float64  `json:"skillWeight"`      // weight of SkillCoverage in Score
+	Estimated      bool     `json:"estimated"`        // true when the semantic component was inferred from Score
+	Engine         string   `json:"engine,omitempty"` // scoring engine: "ai-service" or "local" fallback
+	MatchedSkills  []string `json:"matchedSkills"`
+	MissingSkills  []string `json:"missingSkills"`
+	ExtraSkills    []string `json:"extraSkills"` // candidate skills beyond the requirements in a related category
+	Suggestions    []string `json:"suggestions"`
+}
+
+// ExplainInput is everything needed to explain a match score.
+type ExplainInput struct {
+	Score           float64
+	SemanticScore   *float64 // as reported by the AI service; estimated from Score when nil
+	SkillScore      *float64 // skill coverage as reported by the scoring engine; computed from the skill lists when nil
+	Engine          string   // engine that produced Score, passed through to the explanation
+	JobSkills       []string
+	CandidateSkills []string                  // canonicalized and expanded with taxonomy parents
+	CategoryOf      func(skill string) string // optional taxonomy category lookup
+}
+
+// Explain breaks a hybrid match score into its components and suggests the
+// missing skills that would raise it the most.
+func Explain(in ExplainInput) Explanation {
+	have := models.SkillKeySet(in.CandidateSkills)
+	required := map[string]bool{}
+	matched := []string{}
+	missing := []string{}
+	for _, s := range in.JobSkills {
+		key := models.SkillKey(s)
+		if key == "" || required[key] {
+			continue
+		}
+		required[key] = true
+		if have[key] {
+			matched = append(matched, s)
+		} else {
+			missing = append(missing, s)
+		}
+	}
+	coverage := 0.0
+	if in.SkillScore != nil {
+		// The engine's own coverage is what went into Score.
+		coverage = clamp(*in.SkillScore)
+	} else if len(required) > 0 {
+		coverage = float64(len(matched)) / float64(len(required)) * 100
+	}
+
+	exp := Explanation{
+		Score:          round1(in.Score),
+		SkillCoverage:  round1(coverage),
+		SemanticWeight: SemanticWeight,
+		SkillWeight:    SkillWeight,
+		Engine:         in.Engine,
+		MatchedSkills:  matched,
+		MissingSkills:  missing,
+		ExtraSkills:    extraSkills(in, required),
+		Suggestions:    []string{},
+	}
+	if in.SemanticScore != nil {
+		exp.SemanticScore = round1(clamp(*in.SemanticScore))
+	} else {
+		// Invert the hybrid formula; exact unless the full-coverage floor applied.
+		exp.SemanticScore = round1(clamp((in.Score - SkillWeight*coverage) / SemanticWeight))
+		exp.Estimated = true
+	}
+
+	exp.Suggestions = suggestions(exp, len(required))
+	return exp
+}
+
+// projectedScore estimates the hybrid score at a given skill coverage.
+func projectedScore(semantic, coverage float64) float64 {
+	score := SemanticWeight*semantic + SkillWeight*coverage
+	if coverage >= 100 {
+		score = math.Max(fullCoverageFloor, score)
+	}
+	return clamp(score)
+}
+
+func suggestions(exp Explanation, requiredCount int) []string {
+	out := []string{}
+	if requiredCount > 0 && len(exp.MissingSkills) > 0 {
+		// Each missing skill raises coverage by the same step, so suggest them in
+		// order and show the cumulative score for adding them one by one.
+		step := 100 / float64(requiredCount)
+		coverage := exp.SkillCoverage
+		for i, skill := range exp.MissingSkills {
+			if i >= maxSuggestions {
+				break
+			}
+			coverage = math.Min(100, coverage+step)
+			target := math.Round(projectedScore(exp.SemanticScore, coverage))
+			if target <= math.Round(exp.Score) {
+				continue
+			}
+			if i == 0 {
+				out = append(out, fmt.Sprintf("Add %s to reach %.0f%%", skill, target))
+			} else {
+				out = append(out, fmt.Sprintf("Then add %s to reach %.0f%%", skill, target))
+			}
+		}
+		if len(exp.MissingSkills) > 1 {
+			all := math.Round(projectedScore(exp.SemanticScore, 100))
+			out = append(out, fmt.Sprintf("Covering all %d required skills would reach %.0f%%", requiredCount, all))
+		}
+	}
+	if exp.SemanticScore < lowSemanticWarning {
+		out = append(out, "Describe relevant experience in the bio or summary to improve similarity with the job description")
+	}
+	return out
+}
+
+// extraSkills returns candidate skills that are not required but share a
+// taxonomy category with a required skill (all extras when no taxonomy).
+func extraSkills(in ExplainInput, required map[string]bool) []string {
+	categories := map[string]bool{}
+	if in.CategoryOf != nil {
+		for _, s := range in.JobSkills {
+			if cat := in.CategoryOf(s); cat != "" {
+				categories[cat] = true
+			}
+		}
+	}
+	seen := map[string]bool{}
+	extras := []string{}
+	for _, s := range in.CandidateSkills {
+		key := models.SkillKey(s)
+		if key == "" || required[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if in.CategoryOf != nil && !categories[in.CategoryOf(s)] {
+			continue
+		}
+		extras = append(extras, s)
+	}
+	sort.Slice(extras, func(i, j int) bool { return strings.ToLower(extras[i]) < strings.ToLower(extras[j]) })
+	return extras
+}
@@ -0,0 +1,112 @@
+package ranking
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"rizeos/backend/internal/models"
+)
+
+// experienceSaturationYears is where experience stops adding points when a
+// job sets no minimum.
+const experienceSaturationYears = 5.0
+
+// SkillScorer scores weighted coverage of the job's required skills.
+type SkillScorer struct{}
+
+// Name implements Scorer.
+func (SkillScorer) Name() string { return FactorSkills }
+
+// Score implements Scorer.
+func (SkillScorer) Score(job Job, c Candidate) (float64, string, bool) {
+	if len(job.Profile.Skills) == 0 {
+		return 0, "", false
+	}
+	have := models.SkillKeySet(c.Skills)
+	matchedWeight, totalWeight := 0.0, 0.0
+	matched := 0
+	for _, r := range job.Profile.Skills {
+		w := r.Weight
+		if w <= 0 {
+			w = 1
+		}
+		totalWeight += w
+		if have[models.SkillKey(r.Skill)] {
+			matchedWeight += w
+			matched++
+		}
+	}
+	if totalWeight == 0 {
+		return 0, "", false
+	}
+	return matchedWeight / totalWeight * 100, fmt.Sprintf("%d of %d skills matched", matched, len(job.Profile.Skills)), true
+}
+
+// SemanticScorer uses a precomputed AI similarity score when available.
+type SemanticScorer struct{}
+
+// Name implements Scorer.
+func (SemanticScorer) Name() string { return FactorSemantic }
+
+// Score implements Scorer.
+func (SemanticScorer) Score(_ Job, c Candidate) (float64, string, bool) {
+	if c.SemanticScore == nil {
+		return 0, "", false
+	}
+	return *c.SemanticScore, "AI description similarity", true
+}
+
+// ExperienceScorer compares total experience against the job's minimum.
+type ExperienceScorer struct{}
+
+// Name implements Scorer.
+func (ExperienceScorer) Name() string { return FactorExperience }
+
+// Score implements Scorer.
+func (ExperienceScorer) Score(job Job, c Candidate) (float64, string, bool) {
+	target := job.Profile.MinExperienceYears
+	if target <= 0 {
+		target = experienceSaturationYears
+	}
+	score := math.Min(c.ExperienceYears/target, 1) * 100
+	return score, fmt.Sprintf("%.1f of %.1f years", c.ExperienceYears, target), true
+}
+
+// LocationScorer rewards candidates located where the job is; remote or
+// unspecified job locations match everyone.
+type LocationScorer struct{}
+
+// Name implements Scorer.
+func (LocationScorer) Name() string { return FactorLocation }
+
+// Score implements Scorer.
+func (LocationScorer) Score(job Job, c Candidate) (float64, string, bool) {
+	jobLoc := strings.ToLower(strings.TrimSpace(job.Location))
+	if jobLoc == "" || strings.Contains(jobLoc, "remote") {
+		return 100, "remote or unspecified location", true
+	}
+	candLoc := strings.ToLower(strings.TrimSpace(c.Location))
+	if candLoc == "" {
+		return 0, "candidate location unknown", true
+	}
+	if strings.Contains(jobLoc, candLoc) || strings.Contains(candLoc, jobLoc) {
+		return 100, "same location", true
+	}
+	return 0, "different location", true
+}
+
+// PremiumScorer gives premium job seekers a boost when a job opts in with a
+// premium factor weight.
+type PremiumScorer struct{}
+
+// Name implements Scorer.
+func (PremiumScorer) Name() string { return FactorPremium }
+
+// Score implements Scorer.
+func (PremiumScorer) Score(_ Job, c Candidate) (float64, string, bool) {
+	if c.IsPremium {
+		return 100, "premium member", true
+	}
+	return 0, "", true
+}
@@ -0,0 +1,132 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Broker fans events out to every instance's hub.
+type Broker interface {
+	// Publish sends ev to all subscribers, on any instance.
+	Publish(ctx context.Context, ev Event) error
+	// Subscribe starts calling fn for every published event until ctx is
+	// done. It returns once the subscription is live.
+	Subscribe(ctx context.Context, fn func(Event)) error
+}
+
+// MemoryBroker delivers events within a single process.
+type MemoryBroker struct {
+	mu   sync.Mutex
+	subs map[int]func(Event)
+	next int
+}
+
+// NewMemoryBroker creates a MemoryBroker.
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{subs: map[int]func(Event){}}
+}
+
+// Publish calls every subscriber synchronously.
+func (b *MemoryBroker) Publish(ctx context.Context, ev Event) error {
+	b.mu.Lock()
+	fns := make([]func(Event), 0, len(b.subs))
+	for _, fn := range b.subs {
+		fns = append(fns, fn)
+	}
+	b.mu.Unlock()
+	for _, fn := range fns {
+		fn(ev)
+	}
+	return nil
+}
+
+// Subscribe registers fn until ctx is done.
+func (b *MemoryBroker) Subscribe(ctx context.Context, fn func(Event)) error {
+	b.mu.Lock()
+	id := b.next
+	b.next++
+	b.subs[id] = fn
+	b.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, id)
+		b.mu.Unlock()
+	}()
+	return nil
+}
+
+// eventTTL is how long published events stay in the MongoBroker collection.
+const eventTTL = 5 * time.Minute
+
+// MongoBroker fans events out across instances by inserting them into a
+// collection every instance watches with a change stream. Change streams
+// require a replica set or sharded cluster.
+type MongoBroker struct {
+	col *mongo.Collection
+}
+
+// NewMongoBroker creates a MongoBroker on the realtime_events collection.
+func NewMongoBroker(db *mongo.Database) *MongoBroker {
+	return &MongoBroker{col: db.Collection("realtime_events")}
+}
+
+// Publish stores ev for the watchers.
+func (b *MongoBroker) Publish(ctx context.Context, ev Event) error {
+	_, err := b.col.InsertOne(ctx, ev)
+	return err
+}
+
+// Subscribe opens a change stream on inserted events and delivers them to fn
+// until ctx is done, reopening the stream from its resume token on errors.
+func (b *MongoBroker) Subscribe(ctx context.Context, fn func(Event)) error {
+	if _, err := b.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(eventTTL.Seconds())),
+	}); err != nil {
+		return err
+	}
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+	stream, err := b.col.Watch(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			for stream.Next(ctx) {
+				var change struct {
+					FullDocument Event `bson:"fullDocument"`
+				}
+				if err := stream.Decode(&change); err == nil {
+					fn(change.FullDocument)
+				}
+			}
+			resume := stream.ResumeToken()
+			stream.Close(context.Background())
+			if ctx.Err() != nil {
+				return
+			}
+			// Reopen after transient errors, resuming where the stream stopped.
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+				opts := options.ChangeStream()
+				if resume != nil {
+					opts.SetResumeAfter(resume)
+				}
+				if stream, err = b.col.Watch(ctx, pipeline, opts); err == nil {
+					break
+				}
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,41 @@
+// Package realtime pushes events such as new messages, receipts and typing
+// indicators to connected users.
+//
+// Each server instance runs a Hub holding its own WebSocket connections.
+// Events are published through a Broker that fans them out to the hubs of
+// every instance: MemoryBroker for a single instance, MongoBroker (change
+// streams) when several instances share a database.
+package realtime
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types.
+const (
+	EventMessageNew       = "message.new"
+	EventMessageDelivered = "message.delivered"
+	EventMessageRead      = "message.read"
+	EventTyping           = "typing"
+	EventNotification     = "notification.new"
+)
+
+// Event is pushed to the users in To and to every connected user with a role
+// in ToRoles.
+type Event struct {
+	Type    string          `json:"type" bson:"type"`
+	To      []string        `json:"-" bson:"to"`                 // recipient user IDs
+	ToRoles []string        `json:"-" bson:"to_roles,omitempty"` // recipient roles
+	Data    json.RawMessage `json:"data" bson:"data"`
+	At      time.Time       `json:"at" bson:"at"`
+}
+
+// NewEvent builds an event with data encoded as JSON.
+func NewEvent(kind string, data interface{}, to ...string) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{Type: kind, To: to, Data: raw, At: time.Now().UTC()}, nil
+}
@@ -0,0 +1,162 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// clientBuffer is how many events a connection may lag behind before it is
+// dropped; the client reconnects and refetches its inbox.
+const clientBuffer = 64
+
+// Client is one connection of a user.
+type Client struct {
+	UserID string
+	Role   string
+	send   chan Event
+	once   sync.Once
+}
+
+// Events yields events for the connection; it is closed when the client is
+// unregistered or falls too far behind.
+func (c *Client) Events() <-chan Event {
+	return c.send
+}
+
+func (c *Client) close() {
+	c.once.Do(func() { close(c.send) })
+}
+
+// Hub tracks this instance's connections and delivers broker events to them.
+type Hub struct {
+	broker Broker
+
+	mu      sync.RWMutex
+	clients map[string]map[*Client]struct{}
+
+	onDelivered []func(userID string, ev Event)
+}
+
+// NewHub creates a Hub publishing through broker (NewMemoryBroker when nil).
+func NewHub(broker Broker) *Hub {
+	if broker == nil {
+		broker = NewMemoryBroker()
+	}
+	return &Hub{broker: broker, clients: map[string]map[*Client]struct{}{}}
+}
+
+// Start subscribes the hub to its broker until ctx is done.
+func (h *Hub) Start(ctx context.Context) error {
+	return h.broker.Subscribe(ctx, h.deliver)
+}
+
+// Publish sends ev to its recipients on every instance.
+func (h *Hub) Publish(ctx context.Context, ev Event) error {
+	if len(ev.To) == 0 && len(ev.ToRoles) == 0 {
+		return nil
+	}
+	return h.broker.Publish(ctx, ev)
+}
+
+// OnDelivered registers fn to run, in its own goroutine, when an event reaches
+// at least one of a recipient's connections on this instance.
+func (h *Hub) OnDelivered(fn func(userID string, ev Event)) {
+	h.onDelivered = append(h.onDelivered, fn)
+}
+
+// Register adds a connection for a user.
+func (h *Hub) Register(userID, role string) *Client {
+	c := &Client{UserID: userID, Role: role, send: make(chan Event, clientBuffer)}
+	h.mu.Lock()
+	if h.clients[userID] == nil {
+		h.clients[userID] = map[*Client]struct{}{}
+	}
+	h.clients[userID][c] = struct{}{}
+	h.mu.Unlock()
+	return c
+}
+
+// Unregister removes a connection and closes its event channel.
+func (h *Hub) Unregister(c *Client) {
+	h.mu.Lock()
+	if set := h.clients[c.UserID]; set != nil {
+		delete(set, c)
+		if len(set) == 0 {
+			delete(h.clients, c.UserID)
+		}
+	}
+	h.mu.Unlock()
+	c.close()
+}
+
+// Online reports whether the user has a connection on this instance.
+func (h *Hub) Online(userID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients[userID]) > 0
+}
+
+// recipients returns the users in ev.To and the users connected to this
+// instance with a role in ev.ToRoles, without duplicates.
+func (h *Hub) recipients(ev Event) []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, userID := range ev.To {
+		if !seen[userID] {
+			seen[userID] = true
+			ids = append(ids, userID)
+		}
+	}
+	if len(ev.ToRoles) == 0 {
+		return ids
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for userID, set := range h.clients {
+		if seen[userID] {
+			continue
+		}
+		for c := range set {
+			if containsRole(ev.ToRoles, c.Role) {
+				seen[userID] = true
+				ids = append(ids, userID)
+				break
+			}
+		}
+	}
+	return ids
+}
+
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hub) deliver(ev Event) {
+	for _, userID := range h.recipients(ev) {
+		delivered := false
+		var slow []*Client
+		h.mu.RLock()
+		for c := range h.clients[userID] {
+			select {
+			case c.send <- ev:
+				delivered = true
+			default:
+				slow = append(slow, c)
+			}
+		}
+		h.mu.RUnlock()
+		for _, c := range slow {
+			h.Unregister(c)
+		}
+		if delivered {
+			for _, fn := range h.onDelivered {
+				go fn(userID, ev)
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package recommend
+
+import "time"
+
+// Application is one historical seeker→job application.
+type Application struct {
+	SeekerID  string
+	JobID     string
+	AppliedAt time.Time
+}
+
+// Interactions indexes applications for collaborative signals.
+type Interactions struct {
+	bySeeker map[string]map[string]bool
+	byJob    map[string]map[string]bool
+}
+
+// NewInteractions indexes apps; duplicate applications count once.
+func NewInteractions(apps []Application) *Interactions {
+	in := &Interactions{bySeeker: map[string]map[string]bool{}, byJob: map[string]map[string]bool{}}
+	for _, a := range apps {
+		if a.SeekerID == "" || a.JobID == "" {
+			continue
+		}
+		if in.bySeeker[a.SeekerID] == nil {
+			in.bySeeker[a.SeekerID] = map[string]bool{}
+		}
+		if in.byJob[a.JobID] == nil {
+			in.byJob[a.JobID] = map[string]bool{}
+		}
+		in.bySeeker[a.SeekerID][a.JobID] = true
+		in.byJob[a.JobID][a.SeekerID] = true
+	}
+	return in
+}
+
+// Applied reports whether the seeker has applied to the job.
+func (in *Interactions) Applied(seekerID, jobID string) bool {
+	return in.bySeeker[seekerID][jobID]
+}
+
+// JobsForSeeker scores jobs the seeker has not applied to by how often
+// seekers who share an application with them applied there ("seekers who
+// applied to X also applied to Y"). Scores are normalized so the best is 1.
+func (in *Interactions) JobsForSeeker(seekerID string) map[string]float64 {
+	counts := map[string]float64{}
+	for job := range in.bySeeker[seekerID] {
+		for peer := range in.byJob[job] {
+			if peer == seekerID {
+				continue
+			}
+			for other := range in.bySeeker[peer] {
+				if !in.bySeeker[seekerID][other] {
+					counts[other]++
+				}
+			}
+		}
+	}
+	return normalize(counts)
+}
+
+// CandidatesForJob scores seekers who have not applied to the job by how many
+// of their applications went to jobs co-applied with it. Scores are normalized
+// so the best is 1.
+func (in *Interactions) CandidatesForJob(jobID string) map[string]float64 {
+	related := map[string]float64{}
+	for applicant := range in.byJob[jobID] {
+		for other := range in.bySeeker[applicant] {
+			if other != jobID {
+				related[other]++
+			}
+		}
+	}
+	counts := map[string]float64{}
+	for job, weight := range related {
+		for seeker := range in.byJob[job] {
+			if !in.byJob[jobID][seeker] {
+				counts[seeker] += weight
+			}
+		}
+	}
+	return normalize(counts)
+}
+
+// Popularity returns each job's applicant count normalized so the most
+// applied-to job is 1.
+func (in *Interactions) Popularity() map[string]float64 {
+	counts := make(map[string]float64, len(in.byJob))
+	for job, seekers := range in.byJob {
+		counts[job] = float64(len(seekers))
+	}
+	return normalize(counts)
+}
+
+func normalize(counts map[string]float64) map[string]float64 {
+	max := 0.0
+	for _, c := range counts {
+		if c > max {
+			max = c
+		}
+	}
+	if max == 0 {
+		return map[string]float64{}
+	}
+	for k, c := range counts {
+		counts[k] = c / max
+	}
+	return counts
+}
@@ -0,0 +1,96 @@
+package recommend
+
+import (
+	"math"
+	"sort"
+)
+
+// RankFunc returns up to k job IDs recommended to a seeker, best first, given
+// interactions that exclude the held-out application.
+type RankFunc func(train *Interactions, seekerID string, k int) []string
+
+// Metrics summarize an offline evaluation at cutoff K.
+type Metrics struct {
+	K         int     `json:"k"`
+	Evaluated int     `json:"evaluated"` // seekers with a held-out application
+	HitRate   float64 `json:"hitRate"`   // share whose held-out job was in the top K
+	MRR       float64 `json:"mrr"`       // mean reciprocal rank of the held-out job
+	NDCG      float64 `json:"ndcg"`      // mean normalized discounted cumulative gain
+	Coverage  float64 `json:"coverage"`  // share of applied-to jobs ever recommended
+}
+
+// Evaluate runs leave-last-out evaluation over historical applications: for
+// every seeker with at least two applications, the most recent one is hidden
+// and rank is asked for k jobs using the rest of the history.
+func Evaluate(apps []Application, k int, rank RankFunc) Metrics {
+	if k <= 0 {
+		k = DefaultLimit
+	}
+	bySeeker := map[string][]Application{}
+	catalog := map[string]bool{}
+	for _, a := range apps {
+		bySeeker[a.SeekerID] = append(bySeeker[a.SeekerID], a)
+		catalog[a.JobID] = true
+	}
+	seekers := make([]string, 0, len(bySeeker))
+	for id, list := range bySeeker {
+		if len(list) >= 2 {
+			seekers = append(seekers, id)
+		}
+	}
+	sort.Strings(seekers)
+
+	m := Metrics{K: k}
+	recommended := map[string]bool{}
+	for _, seeker := range seekers {
+		held := latest(bySeeker[seeker])
+		train := make([]Application, 0, len(apps)-1)
+		for _, a := range apps {
+			if a.SeekerID != seeker || a.JobID != held.JobID {
+				train = append(train, a)
+			}
+		}
+		ranked := rank(NewInteractions(train), seeker, k)
+		if len(ranked) > k {
+			ranked = ranked[:k]
+		}
+		m.Evaluated++
+		for i, id := range ranked {
+			recommended[id] = true
+			if id == held.JobID {
+				m.HitRate++
+				m.MRR += 1 / float64(i+1)
+				m.NDCG += 1 / math.Log2(float64(i+2))
+			}
+		}
+	}
+	if m.Evaluated > 0 {
+		n := float64(m.Evaluated)
+		m.HitRate, m.MRR, m.NDCG = round3(m.HitRate/n), round3(m.MRR/n), round3(m.NDCG/n)
+	}
+	if len(catalog) > 0 {
+		hits := 0
+		for id := range recommended {
+			if catalog[id] {
+				hits++
+			}
+		}
+		m.Coverage = round3(float64(hits) / float64(len(catalog)))
+	}
+	return m
+}
+
+// latest returns the most recent application, ties broken by job ID.
+func latest(apps []Application) Application {
+	best := apps[0]
+	for _, a := range apps[1:] {
+		if a.AppliedAt.After(best.AppliedAt) || (a.AppliedAt.Equal(best.AppliedAt) && a.JobID > best.JobID) {
+			best = a
+		}
+	}
+	return best
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
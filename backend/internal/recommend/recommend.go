@@ -0,0 +1,275 @@
+// Package recommend ranks jobs for seekers and candidates for jobs.
+//
+// Each item gets four signals in 0-1: content similarity (the AI match score),
+// skill overlap with the query, collaborative affinity from co-applications
+// and recency. The relevance score is their weighted sum; the top-N list is
+// then re-ranked with maximal marginal relevance so near-duplicates (same
+// recruiter or same skill set) do not crowd out everything else.
+package recommend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"rizeos/backend/internal/models"
+)
+
+// Weights are the relative weights of each signal.
+type Weights struct {
+	Content       float64
+	Skills        float64
+	Collaborative float64
+	Recency       float64
+}
+
+// DefaultWeights favour content and skills; collaborative and recency break ties.
+var DefaultWeights = Weights{Content: 0.45, Skills: 0.3, Collaborative: 0.15, Recency: 0.1}
+
+// Defaults for Options fields left zero.
+const (
+	DefaultLimit    = 20
+	DefaultHalfLife = 14 * 24 * time.Hour
+)
+
+// DefaultDiversity is the re-ranking strength used by the API. Options leave
+// it to the caller since zero legitimately disables re-ranking.
+const DefaultDiversity = 0.2
+
+// Options tune a ranking request.
+type Options struct {
+	Limit     int
+	Weights   Weights       // zero value uses DefaultWeights
+	HalfLife  time.Duration // age at which the recency signal halves
+	Diversity float64       // 0 disables re-ranking; 1 ranks by novelty only
+	Now       time.Time
+}
+
+// Query is the side being recommended to: a seeker's skills, or a job's skills.
+// Required marks the query's skills as the requirement set (a job looking for
+// candidates); otherwise each item's skills are (jobs recommended to a seeker).
+type Query struct {
+	Skills   []string
+	Required bool
+}
+
+// Item is a recommendable job or candidate.
+type Item struct {
+	ID        string
+	Skills    []string
+	Content   *float64  // AI match score 0-100; nil when unavailable
+	CreatedAt time.Time // job posting or profile update time, for recency
+	Group     string    // items sharing a group are treated as redundant (e.g. same recruiter)
+}
+
+// Signals are an item's per-signal values, each 0-1.
+type Signals struct {
+	Content       float64 `json:"content"`
+	Skills        float64 `json:"skills"`
+	Collaborative float64 `json:"collaborative"`
+	Recency       float64 `json:"recency"`
+}
+
+// Recommendation is one ranked item.
+type Recommendation struct {
+	ID            string   `json:"id"`
+	Score         float64  `json:"score"` // relevance 0-100, rounded to 1 decimal place
+	Signals       Signals  `json:"signals"`
+	MatchedSkills []string `json:"matchedSkills"`
+	Reasons       []string `json:"reasons"`
+}
+
+// Rank scores items for the query and returns the top opts.Limit after
+// diversity re-ranking. collaborative maps item IDs to 0-1 affinity and may be nil.
+func Rank(q Query, items []Item, collaborative map[string]float64, opts Options) []Recommendation {
+	opts = withDefaults(opts)
+	w := opts.Weights
+	total := w.Content + w.Skills + w.Collaborative + w.Recency
+	if total <= 0 || len(items) == 0 {
+		return []Recommendation{}
+	}
+
+	type scored struct {
+		rec    Recommendation
+		skills map[string]bool
+		group  string
+	}
+	queryKeys := models.SkillKeySet(q.Skills)
+	pool := make([]scored, 0, len(items))
+	for _, it := range items {
+		matched := matchedSkills(queryKeys, it.Skills)
+		sig := Signals{Collaborative: clamp01(collaborative[it.ID])}
+		if it.Content != nil {
+			sig.Content = clamp01(*it.Content / 100)
+		}
+		required := len(queryKeys)
+		if !q.Required {
+			required = len(models.SkillKeySet(it.Skills))
+		}
+		if required > 0 {
+			sig.Skills = math.Min(1, float64(len(matched))/float64(required))
+		}
+		if !it.CreatedAt.IsZero() {
+			age := opts.Now.Sub(it.CreatedAt)
+			if age < 0 {
+				age = 0
+			}
+			sig.Recency = math.Pow(0.5, float64(age)/float64(opts.HalfLife))
+		}
+		relevance := (w.Content*sig.Content + w.Skills*sig.Skills + w.Collaborative*sig.Collaborative + w.Recency*sig.Recency) / total
+		pool = append(pool, scored{
+			rec: Recommendation{
+				ID:            it.ID,
+				Score:         round1(relevance * 100),
+				Signals:       roundSignals(sig),
+				MatchedSkills: matched,
+				Reasons:       reasons(q, sig, matched, required, it, opts.Now),
+			},
+			skills: models.SkillKeySet(it.Skills),
+			group:  strings.ToLower(strings.TrimSpace(it.Group)),
+		})
+	}
+	sort.SliceStable(pool, func(i, j int) bool { return pool[i].rec.Score > pool[j].rec.Score })
+
+	limit := opts.Limit
+	if limit > len(pool) {
+		limit = len(pool)
+	}
+	out := make([]Recommendation, 0, limit)
+	picked := make([]scored, 0, limit)
+	used := make([]bool, len(pool))
+	for len(out) < limit {
+		best, bestValue := -1, math.Inf(-1)
+		for i, cand := range pool {
+			if used[i] {
+				continue
+			}
+			redundancy := 0.0
+			for _, p := range picked {
+				r := jaccard(cand.skills, p.skills)
+				if cand.group != "" && cand.group == p.group {
+					r = 1
+				}
+				redundancy = math.Max(redundancy, r)
+			}
+			value := (1-opts.Diversity)*cand.rec.Score/100 - opts.Diversity*redundancy
+			if value > bestValue {
+				best, bestValue = i, value
+			}
+		}
+		used[best] = true
+		picked = append(picked, pool[best])
+		out = append(out, pool[best].rec)
+	}
+	return out
+}
+
+func withDefaults(opts Options) Options {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLimit
+	}
+	if opts.Weights == (Weights{}) {
+		opts.Weights = DefaultWeights
+	}
+	if opts.HalfLife <= 0 {
+		opts.HalfLife = DefaultHalfLife
+	}
+	if opts.Diversity < 0 {
+		opts.Diversity = 0
+	}
+	if opts.Diversity > 1 {
+		opts.Diversity = 1
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	return opts
+}
+
+// reasons explains the strongest signals in plain language.
+func reasons(q Query, sig Signals, matched []string, required int, it Item, now time.Time) []string {
+	out := []string{}
+	if it.Content != nil && sig.Content >= 0.6 {
+		out = append(out, fmt.Sprintf("Strong profile similarity (%.0f%%)", sig.Content*100))
+	}
+	if len(matched) > 0 {
+		out = append(out, fmt.Sprintf("Matches %d of %d skills: %s", len(matched), required, strings.Join(headOf(matched, 3), ", ")))
+	}
+	if sig.Collaborative >= 0.3 {
+		if q.Required {
+			out = append(out, "Applied to jobs that this job's applicants also applied to")
+		} else {
+			out = append(out, "Popular with seekers who applied to the same jobs as you")
+		}
+	}
+	if !it.CreatedAt.IsZero() && sig.Recency >= 0.5 {
+		verb := "Posted"
+		if q.Required {
+			verb = "Profile updated"
+		}
+		days := int(now.Sub(it.CreatedAt).Hours() / 24)
+		switch {
+		case days <= 0:
+			out = append(out, verb+" today")
+		case days == 1:
+			out = append(out, verb+" yesterday")
+		default:
+			out = append(out, fmt.Sprintf("%s %d days ago", verb, days))
+		}
+	}
+	return out
+}
+
+func matchedSkills(query map[string]bool, skills []string) []string {
+	matched := []string{}
+	seen := map[string]bool{}
+	for _, s := range skills {
+		key := models.SkillKey(s)
+		if query[key] && !seen[key] {
+			seen[key] = true
+			matched = append(matched, strings.TrimSpace(s))
+		}
+	}
+	return matched
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	inter := 0
+	for k := range a {
+		if b[k] {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
+
+func headOf(s []string, n int) []string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+func roundSignals(s Signals) Signals {
+	r := func(v float64) float64 { return math.Round(v*1000) / 1000 }
+	return Signals{Content: r(s.Content), Skills: r(s.Skills), Collaborative: r(s.Collaborative), Recency: r(s.Recency)}
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 || math.IsNaN(v) {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,106 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"rizeos/backend/internal/config"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/realtime"
+	"rizeos/backend/internal/services"
+)
+
+// receiptPayload is the data of delivery and read receipt events.
+type receiptPayload struct {
+	MessageID string    `json:"messageId"`
+	At        time.Time `json:"at"`
+}
+
+// newHub creates the realtime hub with the configured fan-out backend.
+func newHub(cfg config.Config, db *mongo.Database) *realtime.Hub {
+	switch cfg.RealtimeBackend {
+	case "mongo":
+		return realtime.NewHub(realtime.NewMongoBroker(db))
+	case "", "memory":
+	default:
+		log.Printf("unknown REALTIME_BACKEND %q; using memory", cfg.RealtimeBackend)
+	}
+	return realtime.NewHub(realtime.NewMemoryBroker())
+}
+
+// wireRealtime pushes new messages to both parties and receipts to senders.
+// Messages to an unassigned support thread go to every connected admin. A
+// message counts as delivered once it reaches one of the recipient's
+// connections (any admin's, for an unassigned support thread) on any instance.
+func wireRealtime(hub *realtime.Hub, messages *services.MessageService) {
+	publish := func(ctx context.Context, kind string, data interface{}, roles []string, to ...primitive.ObjectID) {
+		ids := make([]string, len(to))
+		for i, id := range to {
+			ids[i] = id.Hex()
+		}
+		ev, err := realtime.NewEvent(kind, data, ids...)
+		if err == nil {
+			ev.ToRoles = roles
+			err = hub.Publish(ctx, ev)
+		}
+		if err != nil {
+			log.Printf("realtime: publishing %s failed: %v", kind, err)
+		}
+	}
+
+	messages.OnSend(func(ctx context.Context, msg models.Message) {
+		if unassignedSupport(msg) {
+			publish(ctx, realtime.EventMessageNew, msg, []string{models.RoleAdmin}, msg.FromUserID)
+			return
+		}
+		publish(ctx, realtime.EventMessageNew, msg, nil, msg.ToUserID, msg.FromUserID)
+	})
+	messages.OnRead(func(ctx context.Context, msg models.Message) {
+		publish(ctx, realtime.EventMessageRead, receiptPayload{MessageID: msg.ID.Hex(), At: *msg.ReadAt}, nil, msg.FromUserID)
+	})
+	hub.OnDelivered(func(userID string, ev realtime.Event) {
+		if ev.Type != realtime.EventMessageNew {
+			return
+		}
+		var msg models.Message
+		if err := json.Unmarshal(ev.Data, &msg); err != nil {
+			return
+		}
+		if msg.ToUserID.Hex() != userID && !(unassignedSupport(msg) && msg.FromUserID.Hex() != userID) {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		delivered, changed, err := messages.MarkDelivered(ctx, msg.ID, time.Now())
+		if err != nil {
+			log.Printf("realtime: marking message %s delivered failed: %v", msg.ID.Hex(), err)
+			return
+		}
+		if changed {
+			publish(ctx, realtime.EventMessageDelivered, receiptPayload{MessageID: msg.ID.Hex(), At: *delivered.DeliveredAt}, nil, msg.FromUserID)
+		}
+	})
+}
+
+// unassignedSupport reports whether msg went to admins in a support thread
+// no admin has been assigned to yet.
+func unassignedSupport(msg models.Message) bool {
+	return msg.ToRole == models.RoleAdmin && msg.ToUserID.IsZero()
+}
+
+// wireNotifications pushes new notification center entries to their user.
+func wireNotifications(hub *realtime.Hub, notifications *services.NotificationService) {
+	notifications.OnCreate(func(ctx context.Context, n models.Notification) {
+		ev, err := realtime.NewEvent(realtime.EventNotification, n, n.UserID.Hex())
+		if err == nil {
+			err = hub.Publish(ctx, ev)
+		}
+		if err != nil {
+			log.Printf("realtime: publishing %s failed: %v", realtime.EventNotification, err)
+		}
+	})
+}
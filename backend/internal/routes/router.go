@@ -1,13 +1,21 @@
 package routes
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"rizeos/backend/internal/config"
 	"rizeos/backend/internal/controllers"
 	"rizeos/backend/internal/middleware"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/ranking"
+	"rizeos/backend/internal/realtime"
 	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/storage"
+	"rizeos/backend/internal/suggest"
 	"rizeos/backend/internal/utils"
 
 	"github.com/gin-contrib/cors"
@@ -20,26 +28,74 @@ type Deps struct {
 	UserSvc           *services.UserService
 	JobSvc            *services.JobService
 	PaymentSvc        *services.PaymentService
-	AISvc             *services.AIService
+	Matcher           services.Matcher
+	SkillExtractor    services.SkillExtractor
 	MessageSvc        *services.MessageService
+	AttachmentSvc     *services.AttachmentService   // nil disables message attachments
+	NotificationSvc   *services.NotificationService // nil disables notifications
 	AnnouncementSvc   *services.AnnouncementService
+	PostSvc           *services.PostService // nil disables the social feed
 	JobApplicationSvc *services.JobApplicationService
-	UserCol           *mongo.Collection
-	JobCol            *mongo.Collection
+	SkillSvc          *services.SkillService
+	MatchScoreSvc     *services.MatchScoreService
+	AnalyticsSvc      *services.AnalyticsService
+	JobViewSvc        *services.JobViewService
+	PlatformAnalytics *services.PlatformAnalyticsService
+	Hub               *realtime.Hub // nil disables the WebSocket endpoint
 }
 
-// DefaultDeps builds services from a mongo database.
+// DefaultDeps builds services from a mongo database. Profile and job edits
+// invalidate cached match scores for the changed side.
 func DefaultDeps(cfg config.Config, db *mongo.Database) Deps {
+	userSvc := services.NewUserService(db)
+	jobSvc := services.NewJobService(db)
+	skillSvc := services.NewSkillService(db)
+	ai, err := services.NewAIBackend(cfg.AIEngine, cfg.AIServiceURL, skillSvc.Vocabulary)
+	if err != nil {
+		log.Printf("%v; using %s", err, services.BackendHTTP)
+		ai, _ = services.NewAIBackend(services.BackendHTTP, cfg.AIServiceURL, skillSvc.Vocabulary)
+	}
+	analyticsSvc := services.NewAnalyticsService(db)
+	hub := newHub(cfg, db)
+	matchScoreSvc := services.NewMatchScoreService(db, ai, jobSvc, userSvc, time.Duration(cfg.MatchScoreTTLHours)*time.Hour)
+	userSvc.OnProfileUpdate(func(ctx context.Context, u models.User) {
+		if err := matchScoreSvc.InvalidateSeeker(ctx, u.ID); err != nil {
+			log.Printf("match score invalidation failed for user %s: %v", u.ID.Hex(), err)
+		}
+	})
+	jobSvc.OnJobUpdate(func(ctx context.Context, job models.Job) {
+		if err := matchScoreSvc.InvalidateJob(ctx, job.ID); err != nil {
+			log.Printf("match score invalidation failed for job %s: %v", job.ID.Hex(), err)
+		}
+	})
+	var mailer services.Mailer = services.LogMailer{}
+	if cfg.SMTPAddr != "" {
+		mailer = services.SMTPMailer{Addr: cfg.SMTPAddr, Username: cfg.SMTPUsername, Password: cfg.SMTPPassword, From: cfg.MailFrom}
+	}
+	var attachmentSvc *services.AttachmentService
+	if store, err := storage.NewLocalStore(cfg.AttachmentsDir); err != nil {
+		log.Printf("attachment store unavailable, attachments disabled: %v", err)
+	} else {
+		attachmentSvc = services.NewAttachmentService(store, storage.NopScanner{}, int64(cfg.AttachmentMaxMB)<<20)
+	}
 	return Deps{
-		UserSvc:           services.NewUserService(db),
-		JobSvc:            services.NewJobService(db),
+		UserSvc:           userSvc,
+		JobSvc:            jobSvc,
 		PaymentSvc:        services.NewPaymentService(db),
-		AISvc:             services.NewAIService(cfg.AIServiceURL),
+		Matcher:           ai,
+		SkillExtractor:    ai,
 		MessageSvc:        services.NewMessageService(db),
-		AnnouncementSvc:   services.NewAnnouncementService(db),
+		AttachmentSvc:     attachmentSvc,
+		NotificationSvc:   services.NewNotificationService(db, userSvc, mailer, nil),
+		AnnouncementSvc:   services.NewAnnouncementService(db, userSvc),
+		PostSvc:           services.NewPostService(db),
 		JobApplicationSvc: services.NewJobApplicationService(db),
-		UserCol:           db.Collection("users"),
-		JobCol:            db.Collection("jobs"),
+		SkillSvc:          skillSvc,
+		MatchScoreSvc:     matchScoreSvc,
+		AnalyticsSvc:      analyticsSvc,
+		JobViewSvc:        services.NewJobViewService(db, analyticsSvc),
+		PlatformAnalytics: services.NewPlatformAnalyticsService(db),
+		Hub:               hub,
 	}
 }
 
@@ -79,22 +135,38 @@ func SetupRouterWithDeps(cfg config.Config, deps Deps) *gin.Engine {
 	router.Use(cors.New(corsCfg))
 
 	authCtrl := &controllers.AuthController{UserService: deps.UserSvc, Cfg: cfg}
-	profileCtrl := &controllers.ProfileController{UserService: deps.UserSvc, AIService: deps.AISvc}
-	jobCtrl := &controllers.JobController{JobService: deps.JobSvc, PaymentService: deps.PaymentSvc, AIService: deps.AISvc, UserService: deps.UserSvc, PlatformFeeMatic: cfg.PlatformFeeMatic}
-	paymentCtrl := &controllers.PaymentController{Service: deps.PaymentSvc, UserService: deps.UserSvc, Cfg: cfg}
-	adminCtrl := &controllers.AdminController{PaymentService: deps.PaymentSvc, UserService: deps.UserSvc, JobService: deps.JobSvc, UserCol: deps.UserCol, JobCol: deps.JobCol}
+	profileCtrl := &controllers.ProfileController{UserService: deps.UserSvc, SkillExtractor: deps.SkillExtractor, SkillService: deps.SkillSvc}
+	jobCtrl := &controllers.JobController{JobService: deps.JobSvc, PaymentService: deps.PaymentSvc, Matcher: deps.Matcher, UserService: deps.UserSvc, SkillService: deps.SkillSvc, SkillExtractor: deps.SkillExtractor, RankingEngine: ranking.Default(), MatchScoreService: deps.MatchScoreSvc, JobViewService: deps.JobViewSvc, AnalyticsService: deps.AnalyticsSvc, NotificationService: deps.NotificationSvc, PostService: deps.PostSvc, PlatformFeeMatic: cfg.PlatformFeeMatic}
+	paymentCtrl := &controllers.PaymentController{Service: deps.PaymentSvc, UserService: deps.UserSvc, NotificationService: deps.NotificationSvc, Cfg: cfg}
+	adminCtrl := &controllers.AdminController{PaymentService: deps.PaymentSvc, UserService: deps.UserSvc, JobService: deps.JobSvc, Analytics: deps.PlatformAnalytics}
 	configCtrl := &controllers.ConfigController{Cfg: cfg}
 	userCtrl := &controllers.UserController{UserService: deps.UserSvc}
-	aiCtrl := &controllers.AIController{JobService: deps.JobSvc, UserService: deps.UserSvc, AIService: deps.AISvc}
-	messageCtrl := &controllers.MessageController{MessageService: deps.MessageSvc, UserService: deps.UserSvc, JobService: deps.JobSvc}
-	announcementCtrl := &controllers.AnnouncementController{AnnouncementService: deps.AnnouncementSvc, UserService: deps.UserSvc, MessageService: deps.MessageSvc}
-	recruiterCtrl := &controllers.RecruiterController{UserService: deps.UserSvc, JobService: deps.JobSvc, AIService: deps.AISvc}
+	recommender := services.NewRecommendationService(deps.JobSvc, deps.UserSvc, deps.JobApplicationSvc, deps.MatchScoreSvc, deps.Matcher, deps.SkillSvc)
+	aiCtrl := &controllers.AIController{JobService: deps.JobSvc, UserService: deps.UserSvc, Matcher: deps.Matcher, SkillExtractor: deps.SkillExtractor, SkillService: deps.SkillSvc, MatchScoreService: deps.MatchScoreSvc, Recommender: recommender}
+	messageCtrl := &controllers.MessageController{MessageService: deps.MessageSvc, UserService: deps.UserSvc, JobService: deps.JobSvc, AttachmentService: deps.AttachmentSvc}
+	supportCtrl := &controllers.SupportController{MessageService: deps.MessageSvc, UserService: deps.UserSvc, AttachmentService: deps.AttachmentSvc}
+	conversationCtrl := &controllers.ConversationController{MessageService: deps.MessageSvc, UserService: deps.UserSvc, JobService: deps.JobSvc, AttachmentService: deps.AttachmentSvc}
+	announcementCtrl := &controllers.AnnouncementController{AnnouncementService: deps.AnnouncementSvc, SkillService: deps.SkillSvc}
+	suggestionTemplates, err := suggest.LoadTemplates(cfg.SuggestionTemplatesPath)
+	if err != nil {
+		log.Printf("job suggestion templates: %v; using built-in templates", err)
+		suggestionTemplates = suggest.DefaultTemplates()
+	}
+	recruiterCtrl := &controllers.RecruiterController{UserService: deps.UserSvc, JobService: deps.JobSvc, Matcher: deps.Matcher, SkillService: deps.SkillSvc, MatchScoreService: deps.MatchScoreSvc, SuggestionTemplates: suggestionTemplates, AnalyticsService: deps.AnalyticsSvc, JobViewService: deps.JobViewSvc}
+	skillCtrl := &controllers.SkillController{SkillService: deps.SkillSvc}
+	insightsCtrl := &controllers.InsightsController{UserService: deps.UserSvc, JobService: deps.JobSvc, SkillService: deps.SkillSvc}
 	jobApplicationCtrl := &controllers.JobApplicationController{
 		JobApplicationService: deps.JobApplicationSvc,
 		JobService:            deps.JobSvc,
 		UserService:           deps.UserSvc,
-		AIService:             deps.AISvc,
+		Matcher:               deps.Matcher,
+		SkillService:          deps.SkillSvc,
+		MatchScoreService:     deps.MatchScoreSvc,
+		AnalyticsService:      deps.AnalyticsSvc,
+		NotificationService:   deps.NotificationSvc,
 	}
+	notificationCtrl := &controllers.NotificationController{NotificationService: deps.NotificationSvc}
+	postCtrl := &controllers.PostController{PostService: deps.PostSvc, UserService: deps.UserSvc}
 
 	router.GET("/api/health", func(c *gin.Context) { utils.JSON(c, http.StatusOK, gin.H{"status": "ok"}) })
 	router.GET("/api/config/public", configCtrl.Public)
@@ -113,6 +185,8 @@ func SetupRouterWithDeps(cfg config.Config, deps Deps) *gin.Engine {
 		auth.GET("/payments", paymentCtrl.List)
 
 		auth.POST("/jobs", middleware.RecruiterOnly(), jobCtrl.Create)
+		auth.PUT("/jobs/:id", middleware.RecruiterOnly(), jobCtrl.Update)
+		auth.POST("/jobs/analyze", middleware.RecruiterOnly(), jobCtrl.Analyze)
 		auth.POST("/jobs/:id/apply", middleware.SeekerOnly(), jobCtrl.Apply) // Keep for backward compatibility
 		auth.POST("/job-applications/apply", middleware.SeekerOnly(), jobApplicationCtrl.Apply)
 		auth.GET("/ai/match-score", aiCtrl.MatchScore)
@@ -122,6 +196,57 @@ func SetupRouterWithDeps(cfg config.Config, deps Deps) *gin.Engine {
 
 		// Messages: Any role can send messages (with role validation)
 		auth.POST("/messages/send", messageCtrl.Send)
+
+		// Notification center: system events for the current user, any role
+		if deps.NotificationSvc != nil {
+			auth.GET("/notifications", notificationCtrl.List)
+			auth.PUT("/notifications/read-all", notificationCtrl.MarkAllRead)
+			auth.PUT("/notifications/:id/read", notificationCtrl.MarkRead)
+			auth.GET("/notifications/preferences", notificationCtrl.GetPreferences)
+			auth.PUT("/notifications/preferences", notificationCtrl.UpdatePreferences)
+		}
+
+		// Social feed: posts ranked for the current user, any role
+		if deps.PostSvc != nil {
+			auth.GET("/posts", postCtrl.Feed)
+			auth.POST("/posts", postCtrl.Create)
+			auth.GET("/posts/:id", postCtrl.Get)
+			auth.PUT("/posts/:id", postCtrl.Update)
+			auth.DELETE("/posts/:id", postCtrl.Delete) // author, or any post for admins
+			auth.POST("/posts/:id/like", postCtrl.Like)
+			auth.DELETE("/posts/:id/like", postCtrl.Unlike)
+			auth.GET("/posts/:id/comments", postCtrl.Comments)
+			auth.POST("/posts/:id/comments", postCtrl.AddComment)
+			auth.DELETE("/posts/:id/comments/:commentId", postCtrl.DeleteComment)
+		}
+	}
+
+	// Job posts in the feed follow edits to their job
+	if deps.JobSvc != nil && deps.PostSvc != nil {
+		deps.JobSvc.OnJobUpdate(func(ctx context.Context, job models.Job) {
+			if err := deps.PostSvc.SyncJob(ctx, job); err != nil {
+				log.Printf("feed: syncing job post for job %s failed: %v", job.ID.Hex(), err)
+			}
+		})
+	}
+
+	// Attachment files go once neither side of their message keeps it
+	if deps.MessageSvc != nil && deps.AttachmentSvc != nil {
+		deps.MessageSvc.OnDelete(func(ctx context.Context, msg models.Message) {
+			deps.AttachmentSvc.Delete(ctx, msg.Attachments)
+		})
+	}
+
+	// WebSocket authenticates from ?token= itself; browsers cannot send headers
+	if deps.Hub != nil {
+		if deps.MessageSvc != nil {
+			wireRealtime(deps.Hub, deps.MessageSvc)
+		}
+		if deps.NotificationSvc != nil {
+			wireNotifications(deps.Hub, deps.NotificationSvc)
+		}
+		realtimeCtrl := &controllers.RealtimeController{Hub: deps.Hub, UserService: deps.UserSvc, Cfg: cfg}
+		router.GET("/api/ws", realtimeCtrl.Connect)
 	}
 
 	router.GET("/api/jobs", middleware.OptionalAuth(cfg), jobCtrl.List)
@@ -130,12 +255,36 @@ func SetupRouterWithDeps(cfg config.Config, deps Deps) *gin.Engine {
 	admin := router.Group("/api/admin")
 	admin.Use(middleware.AuthMiddleware(cfg), middleware.AdminOnly())
 	admin.GET("/dashboard", adminCtrl.Dashboard)
+	admin.GET("/users", adminCtrl.ListUsers)
 	admin.GET("/users/:userId", adminCtrl.GetUserProfile)
+	admin.GET("/jobs", adminCtrl.ListJobs)
+	admin.GET("/payments", adminCtrl.ListPayments)
 	admin.GET("/jobs/:jobId", adminCtrl.GetJobProfile)
 	admin.GET("/messages/inbox", messageCtrl.AdminInbox)
 	admin.GET("/messages/unread-count", messageCtrl.GetUnreadCount)
 	admin.PUT("/messages/:id/read", messageCtrl.MarkAsRead)
+	admin.GET("/messages/reports", messageCtrl.ListReports)
+	admin.PUT("/messages/reports/:id", messageCtrl.ReviewReport)
+	admin.GET("/support/threads", supportCtrl.List)
+	admin.GET("/support/threads/:id", supportCtrl.Get)
+	admin.POST("/support/threads/:id/messages", supportCtrl.Reply)
+	admin.PUT("/support/threads/:id/assign", supportCtrl.Assign)
+	admin.PUT("/support/threads/:id/status", supportCtrl.SetStatus)
+	admin.POST("/support/threads/:id/notes", supportCtrl.AddNote)
+	admin.PUT("/support/threads/:id/read", supportCtrl.MarkRead)
 	admin.POST("/announcements", announcementCtrl.CreateAnnouncement)
+	admin.GET("/announcements", announcementCtrl.AdminList) // ?status=PENDING is the moderation queue
+	admin.GET("/announcements/:id", announcementCtrl.Get)   // status and delivery progress
+	admin.POST("/announcements/:id/cancel", announcementCtrl.Cancel)
+	admin.POST("/announcements/:id/retry", announcementCtrl.Retry)
+	admin.PUT("/announcements/:id/review", announcementCtrl.Review) // approve or reject a PENDING recruiter announcement
+	admin.POST("/skills", skillCtrl.Create)
+	admin.PUT("/skills/:slug", skillCtrl.Update)
+	admin.DELETE("/skills/:slug", skillCtrl.Delete)
+	if deps.PostSvc != nil {
+		admin.GET("/posts", postCtrl.AdminList) // ?status=HIDDEN lists posts taken down
+		admin.PUT("/posts/:id/moderation", postCtrl.Moderate)
+	}
 
 	api := router.Group("/api")
 	api.Use(middleware.AuthMiddleware(cfg))
@@ -143,19 +292,27 @@ func SetupRouterWithDeps(cfg config.Config, deps Deps) *gin.Engine {
 		api.GET("/users", userCtrl.List)                         // filtered user list (e.g., seekers)
 		api.GET("/users/:userId", userCtrl.GetUserProfilePublic) // public user profile (for job seekers viewing recruiters)
 
+		// Skill taxonomy lookup (autocomplete and canonicalization)
+		api.GET("/skills", skillCtrl.List)
+		api.POST("/skills/normalize", skillCtrl.Normalize)
+
 		// Recruiter job ranking
 		api.GET("/recruiter/jobs/:jobId/ranked-jobseekers", middleware.RecruiterOnly(), jobCtrl.GetRankedJobSeekers)
 		api.GET("/recruiter/job-ranking/:jobId", middleware.RecruiterOnly(), jobCtrl.GetRecruiterJobRanking)
+		api.PUT("/recruiter/jobs/:jobId/scoring-profile", middleware.RecruiterOnly(), jobCtrl.UpdateScoringProfile)
 
 		// Recruiter analytics
 		api.GET("/recruiter/analytics/skills", middleware.RecruiterOnly(), recruiterCtrl.GetSkillsAnalytics)
 		api.GET("/recruiter/analytics/jobs", middleware.RecruiterOnly(), recruiterCtrl.GetJobsAnalytics)
+		api.GET("/recruiter/analytics/funnel", middleware.RecruiterOnly(), recruiterCtrl.GetFunnelAnalytics)
+		api.GET("/recruiter/analytics/trends", middleware.RecruiterOnly(), recruiterCtrl.GetFunnelTrends)
 
 		// Recruiter AI suggestions
 		api.GET("/recruiter/jobs/ai-suggestions", middleware.RecruiterOnly(), recruiterCtrl.GetAISuggestions)
 
 		// Recruiter job applicants
 		api.GET("/recruiter/jobs/:jobId/applicants", middleware.RecruiterOnly(), jobApplicationCtrl.GetApplicants)
+		api.PUT("/recruiter/applications/:id/status", middleware.RecruiterOnly(), jobApplicationCtrl.UpdateStatus)
 
 		// Messages: Recruiter inbox for seeker messages
 		api.GET("/messages/recruiter/inbox", middleware.RecruiterOnly(), messageCtrl.RecruiterInbox)
@@ -165,17 +322,35 @@ func SetupRouterWithDeps(cfg config.Config, deps Deps) *gin.Engine {
 		api.GET("/messages/seeker/inbox", middleware.SeekerOnly(), messageCtrl.SeekerInbox)
 		api.GET("/messages/seeker/unread-count", middleware.SeekerOnly(), messageCtrl.GetSeekerUnreadCount)
 
-		api.PUT("/messages/:id/read", messageCtrl.MarkAsRead) // Shared endpoint for all roles
+		api.PUT("/messages/:id/read", messageCtrl.MarkAsRead) // Shared endpoint for all roles; recipient only
+		api.PUT("/messages/read-all", messageCtrl.MarkAllRead)
+		api.POST("/messages/:id/archive", messageCtrl.Archive)
+		api.DELETE("/messages/:id/archive", messageCtrl.Unarchive)
+		api.DELETE("/messages/:id", messageCtrl.Delete) // soft: the other party keeps their copy
+		api.POST("/messages/:id/report", messageCtrl.Report)
+		api.GET("/messages/:id/attachments/:attachmentId", messageCtrl.DownloadAttachment) // sender and recipient only
+
+		// Conversations: threads of the current user, any role
+		api.GET("/conversations", conversationCtrl.List)
+		api.GET("/conversations/:id/messages", conversationCtrl.Messages)
+		api.POST("/conversations/:id/messages", conversationCtrl.Reply)
+		api.PUT("/conversations/:id/read", conversationCtrl.MarkRead)
 
 		// Announcements: Available to recruiters and job seekers
 		api.GET("/announcements", announcementCtrl.ListAnnouncements)
+		api.PUT("/announcements/:id/read", announcementCtrl.MarkRead)
+		api.PUT("/announcements/:id/dismiss", announcementCtrl.Dismiss)
 
 		// Recruiter-only announcements
 		api.GET("/recruiter/announcements", middleware.RecruiterOnly(), announcementCtrl.ListRecruiterAnnouncements)
-		api.POST("/recruiter/announcements", middleware.RecruiterOnly(), announcementCtrl.CreateRecruiterAnnouncement)
+		api.POST("/recruiter/announcements", middleware.RecruiterOnly(), announcementCtrl.CreateRecruiterAnnouncement) // queued for moderation
+		api.GET("/recruiter/announcements/mine", middleware.RecruiterOnly(), announcementCtrl.ListMyAnnouncements)
 
 		// Job seeker premium status
 		api.GET("/jobseeker/premium-status", middleware.SeekerOnly(), userCtrl.GetPremiumStatus)
+
+		// Job seeker skill-gap and career-path insights
+		api.GET("/jobseeker/insights", middleware.SeekerOnly(), insightsCtrl.GetSeekerInsights)
 	}
 
 	return router
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxBatchPairs mirrors the AI service's MAX_BATCH_PAIRS limit.
+	maxBatchPairs = 500
+	// defaultBatchConcurrency bounds parallel /match calls when /match/batch is unavailable.
+	defaultBatchConcurrency = 8
+)
+
+// ErrMissingBatchResult is reported for items the AI service left out of a batch response.
+var ErrMissingBatchResult = errors.New("ai service returned no result for item")
+
+// MatchItem is one job or candidate in a batch match request. Text is the job
+// description or candidate bio.
+type MatchItem struct {
+	ID     string
+	Text   string
+	Skills []string
+}
+
+// MatchResult is the outcome for one job/candidate pair of a batch request.
+// Err is set when that pair could not be scored; other pairs are unaffected.
+type MatchResult struct {
+	JobID       string
+	CandidateID string
+	MatchBreakdown
+	Err error
+}
+
+// MatchJobAgainstCandidates scores one job against many candidates. Results
+// are in candidate order.
+func (s *AIService) MatchJobAgainstCandidates(ctx context.Context, job MatchItem, candidates []MatchItem) []MatchResult {
+	results := make([]MatchResult, len(candidates))
+	for i, c := range candidates {
+		results[i] = MatchResult{JobID: job.ID, CandidateID: c.ID}
+	}
+	s.matchBatch(ctx, results, func(i int) (MatchItem, MatchItem) { return job, candidates[i] })
+	return results
+}
+
+// MatchCandidateAgainstJobs scores one candidate against many jobs. Results
+// are in job order.
+func (s *AIService) MatchCandidateAgainstJobs(ctx context.Context, candidate MatchItem, jobs []MatchItem) []MatchResult {
+	results := make([]MatchResult, len(jobs))
+	for i, j := range jobs {
+		results[i] = MatchResult{JobID: j.ID, CandidateID: candidate.ID}
+	}
+	s.matchBatch(ctx, results, func(i int) (MatchItem, MatchItem) { return jobs[i], candidate })
+	return results
+}
+
+// matchBatch fills results in chunks via /match/batch, falling back to
+// concurrent single /match calls (and from there to local scoring) when a
+// batch request fails. Pairs with empty text score 0 without a call, as /match does.
+func (s *AIService) matchBatch(ctx context.Context, results []MatchResult, pair func(i int) (job, candidate MatchItem)) {
+	pending := make([]int, 0, len(results))
+	for i := range results {
+		job, cand := pair(i)
+		if strings.TrimSpace(job.Text) != "" && strings.TrimSpace(cand.Text) != "" {
+			pending = append(pending, i)
+		}
+	}
+	for start := 0; start < len(pending); start += maxBatchPairs {
+		end := start + maxBatchPairs
+		if end > len(pending) {
+			end = len(pending)
+		}
+		chunk := pending[start:end]
+		if err := s.postBatch(ctx, results, chunk, pair); err != nil {
+			s.matchEach(ctx, results, chunk, pair)
+		}
+	}
+}
+
+// postBatch sends one /match/batch request for the given result indexes. All
+// pairs share either the job or the candidate, so one side has a single item.
+func (s *AIService) postBatch(ctx context.Context, results []MatchResult, idx []int, pair func(i int) (MatchItem, MatchItem)) error {
+	req := MatchBatchRequest{Jobs: []MatchBatchItem{}, Candidates: []MatchBatchItem{}}
+	seenJobs, seenCands := map[string]bool{}, map[string]bool{}
+	for _, i := range idx {
+		job, cand := pair(i)
+		if !seenJobs[job.ID] {
+			seenJobs[job.ID] = true
+			req.Jobs = append(req.Jobs, MatchBatchItem{ID: job.ID, Text: job.Text, Skills: job.Skills})
+		}
+		if !seenCands[cand.ID] {
+			seenCands[cand.ID] = true
+			req.Candidates = append(req.Candidates, MatchBatchItem{ID: cand.ID, Text: cand.Text, Skills: cand.Skills})
+		}
+	}
+
+	var out MatchBatchResponse
+	if err := s.do(ctx, "/match/batch", req, &out); err != nil {
+		return err
+	}
+	byPair := make(map[string]MatchBreakdown, len(out.Results))
+	for _, r := range out.Results {
+		byPair[r.JobID+"\x00"+r.CandidateID] = MatchBreakdown{Score: *r.Score, SemanticScore: r.SemanticScore, SkillScore: r.SkillScore, Engine: EngineAIService}
+	}
+	for _, i := range idx {
+		if res, ok := byPair[results[i].JobID+"\x00"+results[i].CandidateID]; ok {
+			results[i].MatchBreakdown = res
+		} else {
+			results[i].Err = ErrMissingBatchResult
+		}
+	}
+	return nil
+}
+
+// matchEach scores pairs with single /match calls on a bounded worker pool.
+// Pairs not started before ctx is done get ctx.Err().
+func (s *AIService) matchEach(ctx context.Context, results []MatchResult, idx []int, pair func(i int) (MatchItem, MatchItem)) {
+	workers := s.BatchConcurrency
+	if workers <= 0 {
+		workers = defaultBatchConcurrency
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(idx); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				job, cand := pair(i)
+				res, err := s.MatchScoreDetailed(ctx, job.Text, cand.Text, job.Skills, cand.Skills)
+				results[i].MatchBreakdown, results[i].Err = res, err
+			}
+		}()
+	}
+	for n, i := range idx {
+		select {
+		case jobs <- i:
+			continue
+		case <-ctx.Done():
+		}
+		for _, rest := range idx[n:] {
+			results[rest].Err = ctx.Err()
+		}
+		break
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// AIAPIVersion is the AI microservice protocol version this client speaks. It
+// is sent in AIVersionHeader on every request; a response carrying a different
+// version is rejected as a schema error.
+const (
+	AIAPIVersion    = "1"
+	AIVersionHeader = "X-AI-API-Version"
+)
+
+// SchemaError reports an AI service response that does not match the
+// protocol contract. It is not retried and does not fall back to local scoring.
+type SchemaError struct {
+	Path   string
+	Reason string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("ai service %s: schema mismatch: %s", e.Path, e.Reason)
+}
+
+// IsSchemaError reports whether err is a SchemaError.
+func IsSchemaError(err error) bool {
+	var se *SchemaError
+	return errors.As(err, &se)
+}
+
+// contractResponse is implemented by response types that validate themselves
+// after strict decoding.
+type contractResponse interface {
+	validate() error
+}
+
+// MatchRequest is the body of POST /match.
+type MatchRequest struct {
+	JobDescription  string   `json:"job_description"`
+	CandidateBio    string   `json:"candidate_bio"`
+	JobSkills       []string `json:"job_skills,omitempty"`
+	CandidateSkills []string `json:"candidate_skills,omitempty"`
+}
+
+// MatchResponse is the body returned by POST /match. Component scores are
+// omitted when the service short-circuits (e.g. empty input).
+type MatchResponse struct {
+	Score         *float64 `json:"score"`
+	SemanticScore *float64 `json:"semantic_score,omitempty"`
+	SkillScore    *float64 `json:"skill_score,omitempty"`
+}
+
+func (r *MatchResponse) validate() error {
+	if r.Score == nil {
+		return errors.New("missing score")
+	}
+	return validatePercents(map[string]*float64{"score": r.Score, "semantic_score": r.SemanticScore, "skill_score": r.SkillScore})
+}
+
+func (r *MatchResponse) breakdown() MatchBreakdown {
+	return MatchBreakdown{Score: *r.Score, SemanticScore: r.SemanticScore, SkillScore: r.SkillScore, Engine: EngineAIService}
+}
+
+// MatchBatchItem is a job or candidate in POST /match/batch.
+type MatchBatchItem struct {
+	ID     string   `json:"id"`
+	Text   string   `json:"text"`
+	Skills []string `json:"skills,omitempty"`
+}
+
+// MatchBatchRequest is the body of POST /match/batch.
+type MatchBatchRequest struct {
+	Jobs       []MatchBatchItem `json:"jobs"`
+	Candidates []MatchBatchItem `json:"candidates"`
+}
+
+// MatchBatchResult is one scored pair in a /match/batch response.
+type MatchBatchResult struct {
+	JobID         string   `json:"job_id"`
+	CandidateID   string   `json:"candidate_id"`
+	Score         *float64 `json:"score"`
+	SemanticScore *float64 `json:"semantic_score,omitempty"`
+	SkillScore    *float64 `json:"skill_score,omitempty"`
+}
+
+// MatchBatchResponse is the body returned by POST /match/batch.
+type MatchBatchResponse struct {
+	Results []MatchBatchResult `json:"results"`
+}
+
+func (r *MatchBatchResponse) validate() error {
+	if r.Results == nil {
+		return errors.New("missing results")
+	}
+	for i, res := range r.Results {
+		if res.JobID == "" || res.CandidateID == "" {
+			return fmt.Errorf("results[%d]: missing job_id or candidate_id", i)
+		}
+		if res.Score == nil {
+			return fmt.Errorf("results[%d]: missing score", i)
+		}
+		if err := validatePercents(map[string]*float64{"score": res.Score, "semantic_score": res.SemanticScore, "skill_score": res.SkillScore}); err != nil {
+			return fmt.Errorf("results[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// SkillExtractRequest is the body of POST /skills/extract.
+type SkillExtractRequest struct {
+	Text string `json:"text"`
+}
+
+// SkillExtractResponse is the body returned by POST /skills/extract.
+type SkillExtractResponse struct {
+	Skills []string `json:"skills"`
+}
+
+func (r *SkillExtractResponse) validate() error {
+	if r.Skills == nil {
+		return errors.New("missing skills")
+	}
+	for i, s := range r.Skills {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("skills[%d]: empty skill", i)
+		}
+	}
+	return nil
+}
+
+// Recommendation roles accepted by POST /recommendations/{role}.
+const (
+	RecommendationRecruiter = "recruiter"
+	RecommendationSeeker    = "seeker"
+)
+
+// RecommendationRequest is the body of POST /recommendations/{role}.
+type RecommendationRequest struct {
+	Bio    string   `json:"bio"`
+	Skills []string `json:"skills"`
+	Jobs   []string `json:"jobs"`
+}
+
+// Recommendations is the data returned by POST /recommendations/{role};
+// TopSkills is set for recruiters and Skills for seekers.
+type Recommendations struct {
+	Suggestions []string `json:"suggestions"`
+	TopSkills   []string `json:"top_skills,omitempty"`
+	Skills      []string `json:"skills,omitempty"`
+}
+
+// RecommendationResponse is the envelope returned by POST /recommendations/{role}.
+type RecommendationResponse struct {
+	Data *Recommendations `json:"data"`
+}
+
+func (r *RecommendationResponse) validate() error {
+	if r.Data == nil {
+		return errors.New("missing data")
+	}
+	if r.Data.Suggestions == nil {
+		return errors.New("missing data.suggestions")
+	}
+	return nil
+}
+
+func validatePercents(fields map[string]*float64) error {
+	for name, v := range fields {
+		if v == nil {
+			continue
+		}
+		if math.IsNaN(*v) || *v < 0 || *v > 100 {
+			return fmt.Errorf("%s out of range: %v", name, *v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the AI service while the
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("ai service circuit open")
+
+// statusError is a non-2xx response from the AI service.
+type statusError struct {
+	path string
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("ai service %s returned %d %s", e.path, e.code, http.StatusText(e.code))
+}
+
+// isServiceFailure reports whether err means the AI service is unhealthy:
+// transport errors, 5xx and 429. Other 4xx responses, schema errors and caller
+// cancellation are not retried and do not trip the circuit breaker.
+func isServiceFailure(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil || IsSchemaError(err) {
+		return false
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code >= http.StatusInternalServerError || se.code == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// retryBackoff returns the wait before retry attempt n (0-based): base*2^n
+// plus up to 50% jitter.
+func retryBackoff(base time.Duration, n int) time.Duration {
+	d := base << uint(n)
+	return d + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// circuitBreaker opens after threshold consecutive failures and rejects calls
+// for cooldown; then a single probe call decides whether it closes again.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a call may proceed. A nil breaker always allows.
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// release ends a call without recording an outcome, e.g. when the caller
+// gave up before the service answered.
+func (b *circuitBreaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+// record updates the breaker with a call outcome.
+func (b *circuitBreaker) record(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
@@ -4,14 +4,37 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
+
+	"rizeos/backend/internal/localai"
+)
+
+// Engines that can produce match scores and skill extractions.
+const (
+	EngineAIService = "ai-service" // FastAPI sentence-embedding service
+	EngineLocal     = "local"      // in-process TF-IDF fallback (localai)
+	EngineFake      = "fake"       // deterministic FakeAI
 )
 
-// AIService communicates with the FastAPI AI microservice.
+// AIService communicates with the FastAPI AI microservice. Failed calls are
+// retried with backoff; after repeated failures a circuit breaker skips the
+// service for a cooldown, and scoring/extraction fall back to localai.
 type AIService struct {
 	BaseURL string
 	Client  *http.Client
+	// BatchConcurrency limits parallel /match calls when batch scoring falls
+	// back to single requests; 0 uses defaultBatchConcurrency.
+	BatchConcurrency int
+	// MaxRetries is how many times a failed call is retried; RetryBackoff is
+	// the initial wait, doubled on each retry.
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// Vocabulary supplies skill names for local extraction; nil uses
+	// localai.DefaultVocabulary.
+	Vocabulary func(ctx context.Context) []string
+	breaker    *circuitBreaker
 }
 
 // NewAIService creates an AIService.
@@ -21,99 +44,125 @@ func NewAIService(baseURL string) *AIService {
 		Client: &http.Client{
 			Timeout: 30 * time.Second, // CRITICAL: Add timeout to prevent hanging requests
 		},
+		MaxRetries:   2,
+		RetryBackoff: 200 * time.Millisecond,
+		breaker:      newCircuitBreaker(5, 30*time.Second),
 	}
 }
 
-// ExtractSkills calls the AI microservice to extract skills from text.
+// ExtractSkills extracts skills from text, falling back to local keyword
+// matching when the AI service is unavailable.
 func (s *AIService) ExtractSkills(ctx context.Context, text string) ([]string, error) {
-	payload := map[string]string{"text": text}
-	var out map[string]interface{}
-	if err := s.do(ctx, "/skills/extract", payload, &out); err != nil {
-		return nil, err
-	}
-	if skills, ok := out["skills"].([]interface{}); ok {
-		return toStringSlice(skills), nil
-	}
-	if data, ok := out["data"].(map[string]interface{}); ok {
-		if skills, ok := data["skills"].([]interface{}); ok {
-			return toStringSlice(skills), nil
+	skills, _, err := s.ExtractSkillsDetailed(ctx, text)
+	return skills, err
+}
+
+// ExtractSkillsDetailed is ExtractSkills that also reports the engine used.
+// Schema errors are returned as-is rather than falling back.
+func (s *AIService) ExtractSkillsDetailed(ctx context.Context, text string) ([]string, string, error) {
+	var out SkillExtractResponse
+	if err := s.do(ctx, "/skills/extract", SkillExtractRequest{Text: text}, &out); err != nil {
+		if ctx.Err() != nil || IsSchemaError(err) {
+			return nil, "", err
 		}
+		var vocab []string
+		if s.Vocabulary != nil {
+			vocab = s.Vocabulary(ctx)
+		}
+		return localai.ExtractSkills(text, vocab), EngineLocal, nil
 	}
-	return []string{}, nil
+	return out.Skills, EngineAIService, nil
 }
 
 // MatchScore returns similarity percentage.
 func (s *AIService) MatchScore(ctx context.Context, jobDesc, candidateBio string) (float64, error) {
-	payload := map[string]string{"job_description": jobDesc, "candidate_bio": candidateBio}
-	var out map[string]interface{}
-	if err := s.do(ctx, "/match", payload, &out); err != nil {
+	res, err := s.MatchScoreDetailed(ctx, jobDesc, candidateBio, nil, nil)
+	if err != nil {
 		return 0, err
 	}
-	var score float64
-	if s, ok := out["score"].(float64); ok {
-		score = s
-	} else if data, ok := out["data"].(map[string]interface{}); ok {
-		if s, ok := data["score"].(float64); ok {
-			score = s
-		}
-	}
-	// Hard clamp to guarantee 0-100 bounds (AI service already returns percentage)
-	if score < 0 {
-		score = 0
-	}
-	if score > 100 {
-		score = 100
-	}
-	return score, nil
+	return res.Score, nil
+}
+
+// MatchBreakdown is a match score with the components the AI service combined.
+type MatchBreakdown struct {
+	Score         float64  // hybrid score 0-100
+	SemanticScore *float64 // effective description/bio similarity 0-100; nil if not reported
+	SkillScore    *float64 // required skill coverage 0-100; nil if not reported
+	Engine        string   // EngineAIService or EngineLocal
 }
 
 // MatchScoreWithSkills returns similarity percentage with skill overlap consideration.
 func (s *AIService) MatchScoreWithSkills(ctx context.Context, jobDesc, candidateBio string, jobSkills, candidateSkills []string) (float64, error) {
-	payload := map[string]interface{}{
-		"job_description": jobDesc,
-		"candidate_bio":    candidateBio,
-	}
-	if len(jobSkills) > 0 {
-		payload["job_skills"] = jobSkills
-	}
-	if len(candidateSkills) > 0 {
-		payload["candidate_skills"] = candidateSkills
-	}
-	var out map[string]interface{}
-	if err := s.do(ctx, "/match", payload, &out); err != nil {
+	res, err := s.MatchScoreDetailed(ctx, jobDesc, candidateBio, jobSkills, candidateSkills)
+	if err != nil {
 		return 0, err
 	}
-	var score float64
-	if s, ok := out["score"].(float64); ok {
-		score = s
-	} else if data, ok := out["data"].(map[string]interface{}); ok {
-		if s, ok := data["score"].(float64); ok {
-			score = s
+	return res.Score, nil
+}
+
+// MatchScoreDetailed returns the hybrid match score together with its semantic
+// and skill-coverage components when the AI service reports them. When the
+// service is unavailable the score is computed locally (Engine is EngineLocal).
+func (s *AIService) MatchScoreDetailed(ctx context.Context, jobDesc, candidateBio string, jobSkills, candidateSkills []string) (MatchBreakdown, error) {
+	req := MatchRequest{JobDescription: jobDesc, CandidateBio: candidateBio, JobSkills: jobSkills, CandidateSkills: candidateSkills}
+	var out MatchResponse
+	if err := s.do(ctx, "/match", req, &out); err != nil {
+		if ctx.Err() != nil || IsSchemaError(err) {
+			return MatchBreakdown{}, err
 		}
+		return localMatch(jobDesc, candidateBio, jobSkills, candidateSkills), nil
 	}
-	// Hard clamp to guarantee 0-100 bounds (AI service already returns percentage)
-	if score < 0 {
-		score = 0
+	return out.breakdown(), nil
+}
+
+// localMatch scores a pair with the in-process fallback engine.
+func localMatch(jobDesc, candidateBio string, jobSkills, candidateSkills []string) MatchBreakdown {
+	m := localai.Score(jobDesc, candidateBio, jobSkills, candidateSkills)
+	return MatchBreakdown{Score: m.Score, SemanticScore: &m.SemanticScore, SkillScore: &m.SkillScore, Engine: EngineLocal}
+}
+
+// Recommendations returns smart suggestions for a recruiter or seeker.
+func (s *AIService) Recommendations(ctx context.Context, role string, req RecommendationRequest) (Recommendations, error) {
+	if role != RecommendationRecruiter && role != RecommendationSeeker {
+		return Recommendations{}, fmt.Errorf("unknown recommendation role %q", role)
 	}
-	if score > 100 {
-		score = 100
+	var out RecommendationResponse
+	if err := s.do(ctx, "/recommendations/"+role, req, &out); err != nil {
+		return Recommendations{}, err
 	}
-	return score, nil
+	return *out.Data, nil
 }
 
-// Recommendations returns smart suggestions.
-func (s *AIService) Recommendations(ctx context.Context, role string, payload map[string]interface{}) (map[string]interface{}, error) {
-	var out map[string]interface{}
-	if err := s.do(ctx, "/recommendations/"+role, payload, &out); err != nil {
-		return nil, err
+// do posts body to the AI service, retrying service failures with backoff.
+// All AI endpoints are pure computations, so retries are safe.
+func (s *AIService) do(ctx context.Context, path string, body interface{}, out contractResponse) error {
+	if !s.breaker.allow() {
+		return ErrCircuitOpen
+	}
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = s.post(ctx, path, body, out)
+		if !isServiceFailure(ctx, err) || attempt >= s.MaxRetries {
+			break
+		}
+		select {
+		case <-time.After(retryBackoff(s.RetryBackoff, attempt)):
+		case <-ctx.Done():
+		}
 	}
-	if data, ok := out["data"].(map[string]interface{}); ok {
-		return data, nil
+	if err != nil && ctx.Err() != nil {
+		// The caller's cancellation or deadline says nothing about the service.
+		s.breaker.release()
+		return err
 	}
-	return out, nil
+	s.breaker.record(isServiceFailure(ctx, err))
+	return err
 }
 
-func (s *AIService) do(ctx context.Context, path string, body interface{}, out interface{}) error {
+// post sends one versioned request and strictly decodes the response into
+// out: unknown fields, a different protocol version or a response failing
+// out's own validation are reported as *SchemaError.
+func (s *AIService) post(ctx context.Context, path string, body interface{}, out contractResponse) error {
 	b, err := json.Marshal(body)
 	if err != nil {
 		return err
@@ -123,20 +172,25 @@ func (s *AIService) do(ctx context.Context, path string, body interface{}, out i
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(AIVersionHeader, AIAPIVersion)
 	resp, err := s.Client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
-	return json.NewDecoder(resp.Body).Decode(out)
-}
-
-func toStringSlice(in []interface{}) []string {
-	res := make([]string, 0, len(in))
-	for _, v := range in {
-		if str, ok := v.(string); ok {
-			res = append(res, str)
-		}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return &statusError{path: path, code: resp.StatusCode}
+	}
+	if v := resp.Header.Get(AIVersionHeader); v != "" && v != AIAPIVersion {
+		return &SchemaError{Path: path, Reason: fmt.Sprintf("protocol version %s, want %s", v, AIAPIVersion)}
+	}
+	dec := json.NewDecoder(resp.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		return &SchemaError{Path: path, Reason: err.Error()}
+	}
+	if err := out.validate(); err != nil {
+		return &SchemaError{Path: path, Reason: err.Error()}
 	}
-	return res
+	return nil
 }
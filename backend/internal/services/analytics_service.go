@@ -0,0 +1,591 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+// Hiring funnel events. Each is also the JobDailyStats counter it increments.
+const (
+	EventView      = "views"
+	EventApply     = "applies"
+	EventShortlist = "shortlisted"
+	EventHire      = "hired"
+	EventReject    = "rejected"
+)
+
+// Trend intervals accepted by AnalyticsService.Trend.
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// maxTrendSpan is the longest range each interval covers, so a trend has at
+// most a few hundred periods.
+var maxTrendSpan = map[string]int{ // days
+	IntervalDay:   366,
+	IntervalWeek:  2 * 366,
+	IntervalMonth: 10 * 366,
+}
+
+// ErrRangeTooLong is returned for a range longer than its interval allows.
+var ErrRangeTooLong = errors.New("date range too long")
+
+// CheckTrendRange validates interval and that from..to is not longer than it
+// allows (366 days by day, about two years by week, ten years by month).
+func CheckTrendRange(from, to time.Time, interval string) error {
+	days, ok := maxTrendSpan[interval]
+	if !ok {
+		return fmt.Errorf("unknown interval %q", interval)
+	}
+	if to.Sub(from) > time.Duration(days)*24*time.Hour {
+		return fmt.Errorf("%w: a %s interval covers at most %d days", ErrRangeTooLong, interval, days)
+	}
+	return nil
+}
+
+// AnalyticsService maintains daily per-job funnel rollups and answers funnel
+// and trend queries from them with aggregation pipelines, so recruiter
+// analytics never load raw applications or users.
+type AnalyticsService struct {
+	stats *mongo.Collection
+	apps  *mongo.Collection
+}
+
+var jobStatsMemory = struct {
+	sync.Mutex
+	data map[string]models.JobDailyStats
+}{data: map[string]models.JobDailyStats{}}
+
+// NewAnalyticsService creates an AnalyticsService.
+func NewAnalyticsService(db *mongo.Database) *AnalyticsService {
+	if db == nil {
+		return &AnalyticsService{}
+	}
+	return &AnalyticsService{stats: db.Collection("job_daily_stats"), apps: db.Collection("job_applications")}
+}
+
+// FunnelCounts are summed funnel events.
+type FunnelCounts struct {
+	Views       int `bson:"views" json:"views"`
+	Applies     int `bson:"applies" json:"applies"`
+	Shortlisted int `bson:"shortlisted" json:"shortlisted"`
+	Hired       int `bson:"hired" json:"hired"`
+	Rejected    int `bson:"rejected" json:"rejected"`
+}
+
+// FunnelRates are stage-to-stage conversion rates in percent; nil when the
+// earlier stage is empty.
+type FunnelRates struct {
+	ViewToApply      *float64 `json:"viewToApply"`
+	ApplyToShortlist *float64 `json:"applyToShortlist"`
+	ShortlistToHire  *float64 `json:"shortlistToHire"`
+	ApplyToHire      *float64 `json:"applyToHire"`
+}
+
+// Rates computes conversion rates between funnel stages.
+func (c FunnelCounts) Rates() FunnelRates {
+	rate := func(num, den int) *float64 {
+		if den == 0 {
+			return nil
+		}
+		v := float64(int(float64(num)/float64(den)*1000+0.5)) / 10
+		return &v
+	}
+	return FunnelRates{
+		ViewToApply:      rate(c.Applies, c.Views),
+		ApplyToShortlist: rate(c.Shortlisted, c.Applies),
+		ShortlistToHire:  rate(c.Hired, c.Shortlisted),
+		ApplyToHire:      rate(c.Hired, c.Applies),
+	}
+}
+
+func (c *FunnelCounts) add(o FunnelCounts) {
+	c.Views += o.Views
+	c.Applies += o.Applies
+	c.Shortlisted += o.Shortlisted
+	c.Hired += o.Hired
+	c.Rejected += o.Rejected
+}
+
+// AnalyticsFilter scopes analytics queries to a recruiter, optionally one of
+// their jobs, and an inclusive time range.
+type AnalyticsFilter struct {
+	RecruiterID primitive.ObjectID
+	JobID       *primitive.ObjectID
+	From, To    time.Time
+}
+
+// TrendPoint is the funnel for one period starting at Period.
+type TrendPoint struct {
+	Period time.Time `json:"period"`
+	FunnelCounts
+}
+
+// HiringTimes summarizes application timing for a job.
+type HiringTimes struct {
+	FirstAppliedAt *time.Time     // earliest application ever, regardless of range
+	Hires          int            // hires within the range
+	AvgTimeToHire  *time.Duration // mean applied→hired time of those hires
+}
+
+// StatusEvent returns the funnel event for an application status.
+func StatusEvent(status string) (string, bool) {
+	switch status {
+	case models.ApplicationApplied:
+		return EventApply, true
+	case models.ApplicationShortlisted:
+		return EventShortlist, true
+	case models.ApplicationHired:
+		return EventHire, true
+	case models.ApplicationRejected:
+		return EventReject, true
+	}
+	return "", false
+}
+
+// Record increments the job's counter for event on the day of at.
+func (s *AnalyticsService) Record(ctx context.Context, jobID, recruiterID primitive.ObjectID, event string, at time.Time) error {
+	if !validEvent(event) {
+		return fmt.Errorf("unknown analytics event %q", event)
+	}
+	day := dayOf(at)
+	if s.stats == nil {
+		jobStatsMemory.Lock()
+		defer jobStatsMemory.Unlock()
+		key := statsKey(jobID, day)
+		st, ok := jobStatsMemory.data[key]
+		if !ok {
+			st = models.JobDailyStats{ID: primitive.NewObjectID(), JobID: jobID, RecruiterID: recruiterID, Day: day}
+		}
+		incrementStat(&st, event, 1)
+		jobStatsMemory.data[key] = st
+		return nil
+	}
+	_, err := s.stats.UpdateOne(ctx,
+		bson.M{"job_id": jobID, "day": day},
+		bson.M{"$inc": bson.M{event: 1}, "$setOnInsert": bson.M{"recruiter_id": recruiterID}},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+// FunnelByJob sums funnel counters per job over the filter's range.
+func (s *AnalyticsService) FunnelByJob(ctx context.Context, f AnalyticsFilter) (map[primitive.ObjectID]FunnelCounts, error) {
+	out := map[primitive.ObjectID]FunnelCounts{}
+	if s.stats == nil {
+		for _, st := range s.memoryStats(f) {
+			c := out[st.JobID]
+			c.add(statCounts(st))
+			out[st.JobID] = c
+		}
+		return out, nil
+	}
+	cursor, err := s.stats.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: statsMatch(f)}},
+		{{Key: "$group", Value: countersGroup("$job_id")}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var row struct {
+			ID           primitive.ObjectID `bson:"_id"`
+			FunnelCounts `bson:",inline"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		out[row.ID] = row.FunnelCounts
+	}
+	return out, cursor.Err()
+}
+
+// Trend returns funnel counters per interval over the filter's range, oldest
+// first, with empty periods included as zeros. Weeks start on Monday. Ranges
+// CheckTrendRange rejects return its error.
+func (s *AnalyticsService) Trend(ctx context.Context, f AnalyticsFilter, interval string) ([]TrendPoint, error) {
+	if err := CheckTrendRange(f.From, f.To, interval); err != nil {
+		return nil, err
+	}
+	byPeriod := map[time.Time]FunnelCounts{}
+	if s.stats == nil {
+		for _, st := range s.memoryStats(f) {
+			p := periodStart(st.Day, interval)
+			c := byPeriod[p]
+			c.add(statCounts(st))
+			byPeriod[p] = c
+		}
+	} else {
+		cursor, err := s.stats.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: statsMatch(f)}},
+			{{Key: "$group", Value: countersGroup(bson.M{"$dateTrunc": bson.M{"date": "$day", "unit": interval, "startOfWeek": "monday"}})}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+		for cursor.Next(ctx) {
+			var row struct {
+				ID           time.Time `bson:"_id"`
+				FunnelCounts `bson:",inline"`
+			}
+			if err := cursor.Decode(&row); err != nil {
+				return nil, err
+			}
+			byPeriod[row.ID.UTC()] = row.FunnelCounts
+		}
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	points := []TrendPoint{}
+	for p := periodStart(f.From, interval); !p.After(f.To); p = nextPeriod(p, interval) {
+		points = append(points, TrendPoint{Period: p, FunnelCounts: byPeriod[p]})
+	}
+	return points, nil
+}
+
+// Trending popularity: recent views and applications, halving in weight every
+// trendingHalfLife, with an application worth trendingApplyWeight views.
+const (
+	trendingWindow      = 14 * 24 * time.Hour
+	trendingHalfLife    = 3 * 24 * time.Hour
+	trendingApplyWeight = 5
+)
+
+// Popularity scores every job with recent activity by decayed views and
+// applications; jobs without activity in the last two weeks are absent.
+func (s *AnalyticsService) Popularity(ctx context.Context, now time.Time) (map[primitive.ObjectID]float64, error) {
+	since := dayOf(now.Add(-trendingWindow))
+	var stats []models.JobDailyStats
+	if s.stats == nil {
+		jobStatsMemory.Lock()
+		for _, st := range jobStatsMemory.data {
+			if !st.Day.Before(since) {
+				stats = append(stats, st)
+			}
+		}
+		jobStatsMemory.Unlock()
+	} else {
+		cursor, err := s.stats.Find(ctx, bson.M{"day": bson.M{"$gte": since}},
+			options.Find().SetProjection(bson.M{"job_id": 1, "day": 1, EventView: 1, EventApply: 1}))
+		if err != nil {
+			return nil, err
+		}
+		if err := cursor.All(ctx, &stats); err != nil {
+			return nil, err
+		}
+	}
+	out := map[primitive.ObjectID]float64{}
+	for _, st := range stats {
+		activity := float64(st.Views + trendingApplyWeight*st.Applies)
+		if activity == 0 {
+			continue
+		}
+		// Measure age from the middle of the day so today's activity is not overweighted.
+		age := now.Sub(st.Day.Add(12 * time.Hour))
+		if age < 0 {
+			age = 0
+		}
+		out[st.JobID] += activity * math.Pow(0.5, float64(age)/float64(trendingHalfLife))
+	}
+	return out, nil
+}
+
+// HiringTimesByJob returns first-applicant and time-to-hire data per job from
+// the applications collection.
+func (s *AnalyticsService) HiringTimesByJob(ctx context.Context, f AnalyticsFilter) (map[primitive.ObjectID]HiringTimes, error) {
+	out := map[primitive.ObjectID]HiringTimes{}
+	type acc struct {
+		first  time.Time
+		hires  int
+		hireMS int64
+	}
+	accs := map[primitive.ObjectID]*acc{}
+	if s.apps == nil {
+		jobApplicationMemory.Lock()
+		for _, app := range jobApplicationMemory.data {
+			if app.RecruiterID != f.RecruiterID || (f.JobID != nil && app.JobID != *f.JobID) {
+				continue
+			}
+			a := accs[app.JobID]
+			if a == nil {
+				a = &acc{first: app.AppliedAt}
+				accs[app.JobID] = a
+			}
+			if app.AppliedAt.Before(a.first) {
+				a.first = app.AppliedAt
+			}
+			if app.HiredAt != nil && !app.HiredAt.Before(f.From) && !app.HiredAt.After(f.To) {
+				a.hires++
+				a.hireMS += app.HiredAt.Sub(app.AppliedAt).Milliseconds()
+			}
+		}
+		jobApplicationMemory.Unlock()
+	} else {
+		match := bson.M{"recruiter_id": f.RecruiterID}
+		if f.JobID != nil {
+			match["job_id"] = *f.JobID
+		}
+		hiredInRange := bson.M{"$and": bson.A{
+			bson.M{"$gte": bson.A{"$hired_at", f.From}},
+			bson.M{"$lte": bson.A{"$hired_at", f.To}},
+		}}
+		cursor, err := s.apps.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: match}},
+			{{Key: "$group", Value: bson.M{
+				"_id":     "$job_id",
+				"first":   bson.M{"$min": "$applied_at"},
+				"hires":   bson.M{"$sum": bson.M{"$cond": bson.A{hiredInRange, 1, 0}}},
+				"hire_ms": bson.M{"$sum": bson.M{"$cond": bson.A{hiredInRange, bson.M{"$subtract": bson.A{"$hired_at", "$applied_at"}}, 0}}},
+			}}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+		for cursor.Next(ctx) {
+			var row struct {
+				ID     primitive.ObjectID `bson:"_id"`
+				First  time.Time          `bson:"first"`
+				Hires  int                `bson:"hires"`
+				HireMS int64              `bson:"hire_ms"`
+			}
+			if err := cursor.Decode(&row); err != nil {
+				return nil, err
+			}
+			accs[row.ID] = &acc{first: row.First, hires: row.Hires, hireMS: row.HireMS}
+		}
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+	}
+	for id, a := range accs {
+		ht := HiringTimes{Hires: a.hires}
+		if !a.first.IsZero() {
+			first := a.first
+			ht.FirstAppliedAt = &first
+		}
+		if a.hires > 0 {
+			avg := time.Duration(a.hireMS/int64(a.hires)) * time.Millisecond
+			ht.AvgTimeToHire = &avg
+		}
+		out[id] = ht
+	}
+	return out, nil
+}
+
+// RebuildApplicationRollups recomputes the apply, shortlist, hire and reject
+// counters from job_applications, leaving view counts untouched. It is
+// idempotent and meant to run at startup, before events are recorded.
+func (s *AnalyticsService) RebuildApplicationRollups(ctx context.Context) error {
+	type row struct {
+		jobID, recruiterID primitive.ObjectID
+		day                time.Time
+		event              string
+	}
+	var rows []row
+	stamps := func(app models.JobApplication) map[string]*time.Time {
+		applied := app.AppliedAt
+		return map[string]*time.Time{EventApply: &applied, EventShortlist: app.ShortlistedAt, EventHire: app.HiredAt, EventReject: app.RejectedAt}
+	}
+	if s.apps == nil {
+		jobApplicationMemory.Lock()
+		for _, app := range jobApplicationMemory.data {
+			for event, at := range stamps(app) {
+				if at != nil && !at.IsZero() {
+					rows = append(rows, row{app.JobID, app.RecruiterID, dayOf(*at), event})
+				}
+			}
+		}
+		jobApplicationMemory.Unlock()
+
+		jobStatsMemory.Lock()
+		defer jobStatsMemory.Unlock()
+		for key, st := range jobStatsMemory.data {
+			st.Applies, st.Shortlisted, st.Hired, st.Rejected = 0, 0, 0, 0
+			jobStatsMemory.data[key] = st
+		}
+		for _, r := range rows {
+			key := statsKey(r.jobID, r.day)
+			st, ok := jobStatsMemory.data[key]
+			if !ok {
+				st = models.JobDailyStats{ID: primitive.NewObjectID(), JobID: r.jobID, RecruiterID: r.recruiterID, Day: r.day}
+			}
+			incrementStat(&st, r.event, 1)
+			jobStatsMemory.data[key] = st
+		}
+		return nil
+	}
+
+	if _, err := s.stats.UpdateMany(ctx, bson.M{}, bson.M{"$set": bson.M{EventApply: 0, EventShortlist: 0, EventHire: 0, EventReject: 0}}); err != nil {
+		return err
+	}
+	fields := map[string]string{EventApply: "applied_at", EventShortlist: "shortlisted_at", EventHire: "hired_at", EventReject: "rejected_at"}
+	for event, field := range fields {
+		cursor, err := s.apps.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{field: bson.M{"$type": "date"}}}},
+			{{Key: "$group", Value: bson.M{
+				"_id":          bson.M{"job_id": "$job_id", "day": bson.M{"$dateTrunc": bson.M{"date": "$" + field, "unit": "day"}}},
+				"recruiter_id": bson.M{"$first": "$recruiter_id"},
+				"count":        bson.M{"$sum": 1},
+			}}},
+		})
+		if err != nil {
+			return err
+		}
+		var writes []mongo.WriteModel
+		for cursor.Next(ctx) {
+			var r struct {
+				ID struct {
+					JobID primitive.ObjectID `bson:"job_id"`
+					Day   time.Time          `bson:"day"`
+				} `bson:"_id"`
+				RecruiterID primitive.ObjectID `bson:"recruiter_id"`
+				Count       int                `bson:"count"`
+			}
+			if err := cursor.Decode(&r); err != nil {
+				cursor.Close(ctx)
+				return err
+			}
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"job_id": r.ID.JobID, "day": r.ID.Day.UTC()}).
+				SetUpdate(bson.M{"$set": bson.M{event: r.Count}, "$setOnInsert": bson.M{"recruiter_id": r.RecruiterID}}).
+				SetUpsert(true))
+		}
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return err
+		}
+		if len(writes) > 0 {
+			if _, err := s.stats.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EnsureIndexes creates the rollup indexes; a no-op in memory mode.
+func (s *AnalyticsService) EnsureIndexes(ctx context.Context) error {
+	if s.stats == nil {
+		return nil
+	}
+	_, err := s.stats.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "job_id", Value: 1}, {Key: "day", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "recruiter_id", Value: 1}, {Key: "day", Value: 1}}},
+	})
+	return err
+}
+
+func (s *AnalyticsService) memoryStats(f AnalyticsFilter) []models.JobDailyStats {
+	jobStatsMemory.Lock()
+	defer jobStatsMemory.Unlock()
+	from := dayOf(f.From)
+	var out []models.JobDailyStats
+	for _, st := range jobStatsMemory.data {
+		if st.RecruiterID != f.RecruiterID || (f.JobID != nil && st.JobID != *f.JobID) {
+			continue
+		}
+		if st.Day.Before(from) || st.Day.After(f.To) {
+			continue
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Day.Before(out[j].Day) })
+	return out
+}
+
+func statsMatch(f AnalyticsFilter) bson.M {
+	match := bson.M{"recruiter_id": f.RecruiterID, "day": bson.M{"$gte": dayOf(f.From), "$lte": f.To}}
+	if f.JobID != nil {
+		match["job_id"] = *f.JobID
+	}
+	return match
+}
+
+func countersGroup(id interface{}) bson.M {
+	return bson.M{
+		"_id":          id,
+		EventView:      bson.M{"$sum": "$" + EventView},
+		EventApply:     bson.M{"$sum": "$" + EventApply},
+		EventShortlist: bson.M{"$sum": "$" + EventShortlist},
+		EventHire:      bson.M{"$sum": "$" + EventHire},
+		EventReject:    bson.M{"$sum": "$" + EventReject},
+	}
+}
+
+func statCounts(st models.JobDailyStats) FunnelCounts {
+	return FunnelCounts{Views: st.Views, Applies: st.Applies, Shortlisted: st.Shortlisted, Hired: st.Hired, Rejected: st.Rejected}
+}
+
+func incrementStat(st *models.JobDailyStats, event string, n int) {
+	switch event {
+	case EventView:
+		st.Views += n
+	case EventApply:
+		st.Applies += n
+	case EventShortlist:
+		st.Shortlisted += n
+	case EventHire:
+		st.Hired += n
+	case EventReject:
+		st.Rejected += n
+	}
+}
+
+func validEvent(event string) bool {
+	switch event {
+	case EventView, EventApply, EventShortlist, EventHire, EventReject:
+		return true
+	}
+	return false
+}
+
+func statsKey(jobID primitive.ObjectID, day time.Time) string {
+	return jobID.Hex() + ":" + day.Format("2006-01-02")
+}
+
+// dayOf truncates t to midnight UTC.
+func dayOf(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// periodStart truncates t to the start of its day, Monday-based week or month (UTC).
+func periodStart(t time.Time, interval string) time.Time {
+	day := dayOf(t)
+	switch interval {
+	case IntervalWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case IntervalMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func nextPeriod(p time.Time, interval string) time.Time {
+	switch interval {
+	case IntervalWeek:
+		return p.AddDate(0, 0, 7)
+	case IntervalMonth:
+		return p.AddDate(0, 1, 0)
+	}
+	return p.AddDate(0, 0, 1)
+}
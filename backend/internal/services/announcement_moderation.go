@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+// RecruiterAnnouncementsPerDay is how many announcements a recruiter may
+// submit per UTC day, whatever their outcome.
+const RecruiterAnnouncementsPerDay = 3
+
+var (
+	// ErrAnnouncementRateLimited is returned when a recruiter has submitted
+	// RecruiterAnnouncementsPerDay announcements today.
+	ErrAnnouncementRateLimited = errors.New("announcement limit reached, try again later")
+	// ErrInvalidDecision is returned for a review decision other than approve
+	// or reject, or a rejection without a reason.
+	ErrInvalidDecision = errors.New("decision must be approve, or reject with a reason")
+)
+
+var (
+	linkPattern     = regexp.MustCompile(`(?i)(https?://|www\.)\S+|\b[a-z0-9-]+\.(com|net|org|io|co|xyz|info|biz|ly|me|ru|top)\b`)
+	repeatedPattern = regexp.MustCompile(`([!?$*])[!?$*]{3,}`)
+	profanity       = map[string]bool{
+		"fuck": true, "fucking": true, "shit": true, "bitch": true, "bastard": true,
+		"asshole": true, "cunt": true,
+	}
+	// mildLanguage is flagged for review rather than rejected: these words
+	// also appear in names and harmless phrases ("Dick Smith", "damn good").
+	mildLanguage = map[string]bool{"damn": true, "crap": true, "dick": true}
+	spamPhrases  = []string{
+		"click here", "act now", "limited time offer", "earn money fast", "make money fast",
+		"100% free", "guaranteed income", "work from home and earn", "no experience needed, earn",
+	}
+)
+
+// ScreenAnnouncement runs the automated pre-filter over an announcement
+// message and returns its flags: links, profanity, mild language and spam
+// markers such as shouting, runs of punctuation and common spam phrases.
+func ScreenAnnouncement(message string) []string {
+	var flags []string
+	if linkPattern.MatchString(message) {
+		flags = append(flags, models.FlagLink)
+	}
+	profane, mild := false, false
+	for _, word := range strings.FieldsFunc(strings.ToLower(message), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		profane = profane || profanity[word]
+		mild = mild || mildLanguage[word]
+	}
+	switch {
+	case profane:
+		flags = append(flags, models.FlagProfanity)
+	case mild:
+		flags = append(flags, models.FlagLanguage)
+	}
+	if isSpam(message) {
+		flags = append(flags, models.FlagSpam)
+	}
+	return flags
+}
+
+func isSpam(message string) bool {
+	lower := strings.ToLower(message)
+	for _, phrase := range spamPhrases {
+		if strings.Contains(lower, phrase) {
+			return true
+		}
+	}
+	if repeatedPattern.MatchString(message) {
+		return true
+	}
+	letters, upper := 0, 0
+	for _, r := range message {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= 20 && upper*10 >= letters*7
+}
+
+// Submit queues a recruiter announcement for review. The pre-filter rejects
+// announcements with profanity or spam outright; others, including ones with
+// links or mild language, stay PENDING until an admin reviews them. Authors
+// are limited to RecruiterAnnouncementsPerDay submissions.
+func (s *AnnouncementService) Submit(ctx context.Context, announcement models.Announcement) (models.Announcement, error) {
+	now := time.Now()
+	announcement, err := prepareAnnouncement(announcement, now)
+	if err != nil {
+		return models.Announcement{}, err
+	}
+	if announcement.CreatedBy != nil {
+		allowed, err := s.takeSubmission(ctx, *announcement.CreatedBy, now)
+		if err != nil {
+			return models.Announcement{}, err
+		}
+		if !allowed {
+			return models.Announcement{}, ErrAnnouncementRateLimited
+		}
+	}
+
+	flags := ScreenAnnouncement(announcement.Message)
+	announcement.Status = models.AnnouncementPending
+	announcement.Moderation = &models.Moderation{Flags: flags}
+	for _, flag := range flags {
+		if flag == models.FlagProfanity || flag == models.FlagSpam {
+			announcement.Status = models.AnnouncementRejected
+			announcement.Moderation.Reason = "automatically rejected: " + flag
+			announcement.Moderation.ReviewedAt = &now
+			break
+		}
+	}
+	return s.insert(ctx, announcement)
+}
+
+// takeSubmission counts one submission by userID on now's UTC day and
+// reports whether it is within RecruiterAnnouncementsPerDay. The counter is
+// only incremented below the limit, so concurrent submissions cannot overrun
+// it; once it is reached the upsert collides with the existing document.
+func (s *AnnouncementService) takeSubmission(ctx context.Context, userID primitive.ObjectID, now time.Time) (bool, error) {
+	day := now.UTC().Truncate(24 * time.Hour)
+	id := userID.Hex() + ":" + day.Format("2006-01-02")
+	if s.limits == nil {
+		announcementMemory.Lock()
+		defer announcementMemory.Unlock()
+		if announcementMemory.limits[id] >= RecruiterAnnouncementsPerDay {
+			return false, nil
+		}
+		announcementMemory.limits[id]++
+		return true, nil
+	}
+	_, err := s.limits.UpdateOne(ctx,
+		bson.M{"_id": id, "count": bson.M{"$lt": RecruiterAnnouncementsPerDay}},
+		bson.M{"$inc": bson.M{"count": 1}, "$setOnInsert": bson.M{"day": day}},
+		options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Review approves or rejects a PENDING announcement. Approved announcements
+// are scheduled for delivery; a rejection needs a reason, shown to the author.
+func (s *AnnouncementService) Review(ctx context.Context, id, adminID primitive.ObjectID, approve bool, reason string) (models.Announcement, error) {
+	reason = strings.TrimSpace(reason)
+	if !approve && reason == "" {
+		return models.Announcement{}, ErrInvalidDecision
+	}
+	now := time.Now()
+	status := models.AnnouncementRejected
+	if approve {
+		status = models.AnnouncementScheduled
+		defer s.Wake()
+	}
+	if s.col == nil {
+		announcementMemory.Lock()
+		defer announcementMemory.Unlock()
+		a, ok := announcementMemory.data[id.Hex()]
+		if !ok {
+			return models.Announcement{}, mongo.ErrNoDocuments
+		}
+		if a.Status != models.AnnouncementPending {
+			return models.Announcement{}, ErrAnnouncementState
+		}
+		if a.Moderation == nil {
+			a.Moderation = &models.Moderation{}
+		}
+		a.Status = status
+		a.Moderation.Reason = reason
+		a.Moderation.ReviewedBy = &adminID
+		a.Moderation.ReviewedAt = &now
+		announcementMemory.data[id.Hex()] = a
+		return a, nil
+	}
+	var a models.Announcement
+	err := s.col.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.AnnouncementPending},
+		bson.M{"$set": bson.M{
+			"status":                 status,
+			"moderation.reason":      reason,
+			"moderation.reviewed_by": adminID,
+			"moderation.reviewed_at": now,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&a)
+	if err == mongo.ErrNoDocuments {
+		if _, ferr := s.FindByID(ctx, id); ferr == nil {
+			return models.Announcement{}, ErrAnnouncementState
+		}
+	}
+	return a, err
+}
@@ -2,6 +2,11 @@ package services
 
 import (
 	"context"
+	"errors"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,51 +17,172 @@ import (
 	"rizeos/backend/internal/models"
 )
 
-// AnnouncementService handles announcement persistence.
+const (
+	// announcementBatchSize is the number of users delivered per write.
+	announcementBatchSize = 500
+	// announcementLease is how long a worker may hold an announcement without
+	// progress before another worker takes it over.
+	announcementLease = 2 * time.Minute
+	// MaxAnnouncementAttempts is how many failed fan-out attempts mark an
+	// announcement FAILED.
+	MaxAnnouncementAttempts = 5
+	// announcementFeedLimit caps the announcements returned to one user.
+	announcementFeedLimit = 100
+)
+
+var (
+	// ErrInvalidAudience is returned for an unknown audience segment.
+	ErrInvalidAudience = errors.New("audience segment must be all, recruiters, seekers or premium")
+	// ErrInvalidSchedule is returned when an announcement expires before it
+	// is published.
+	ErrInvalidSchedule = errors.New("expires_at must be after publish_at")
+	// ErrAnnouncementState is returned for a cancel or retry the
+	// announcement's status does not allow.
+	ErrAnnouncementState = errors.New("announcement cannot be changed in its current status")
+)
+
+// AnnouncementService handles announcement persistence and delivers
+// announcements to their audience in the background.
 type AnnouncementService struct {
-	col *mongo.Collection
+	col       *mongo.Collection
+	receipts  *mongo.Collection
+	limits    *mongo.Collection // per-recruiter daily submission counters
+	messages  *mongo.Collection // legacy announcements sent as messages
+	users     *UserService
+	batchSize int
+	wake      chan struct{}
 }
 
 var announcementMemory = struct {
 	sync.Mutex
-	data map[string]models.Announcement
-}{data: map[string]models.Announcement{}}
+	data     map[string]models.Announcement
+	receipts map[string]models.AnnouncementReceipt // announcement id + user id
+	limits   map[string]int                        // user id + day -> submissions
+}{data: map[string]models.Announcement{}, receipts: map[string]models.AnnouncementReceipt{}, limits: map[string]int{}}
 
-// NewAnnouncementService creates an AnnouncementService.
-func NewAnnouncementService(db *mongo.Database) *AnnouncementService {
-	if db == nil {
-		return &AnnouncementService{col: nil}
+// NewAnnouncementService creates an AnnouncementService delivering to users.
+func NewAnnouncementService(db *mongo.Database, users *UserService) *AnnouncementService {
+	s := &AnnouncementService{users: users, batchSize: announcementBatchSize, wake: make(chan struct{}, 1)}
+	if db != nil {
+		s.col = db.Collection("announcements")
+		s.receipts = db.Collection("announcement_receipts")
+		s.limits = db.Collection("announcement_limits")
+		s.messages = db.Collection("messages")
 	}
-	return &AnnouncementService{col: db.Collection("announcements")}
+	return s
 }
 
-// Create inserts a new announcement.
+// AnnouncementFeedItem is an announcement delivered to a user.
+type AnnouncementFeedItem struct {
+	Announcement models.Announcement
+	Receipt      models.AnnouncementReceipt
+}
+
+func receiptKey(announcementID, userID primitive.ObjectID) string {
+	return announcementID.Hex() + ":" + userID.Hex()
+}
+
+// Create schedules a new announcement. A zero PublishAt publishes it now.
 func (s *AnnouncementService) Create(ctx context.Context, announcement models.Announcement) (models.Announcement, error) {
+	now := time.Now()
+	announcement, err := prepareAnnouncement(announcement, now)
+	if err != nil {
+		return models.Announcement{}, err
+	}
+	announcement.Status = models.AnnouncementScheduled
+	announcement, err = s.insert(ctx, announcement)
+	if err != nil {
+		return models.Announcement{}, err
+	}
+	if !announcement.PublishAt.After(now) {
+		s.Wake()
+	}
+	return announcement, nil
+}
+
+// prepareAnnouncement validates a new announcement and fills in defaults.
+func prepareAnnouncement(announcement models.Announcement, now time.Time) (models.Announcement, error) {
+	if announcement.Audience.Segment == "" {
+		announcement.Audience.Segment = models.AudienceAll
+	}
+	if _, err := audienceQuery(announcement.Audience); err != nil {
+		return models.Announcement{}, err
+	}
+	if announcement.PublishAt.IsZero() {
+		announcement.PublishAt = now
+	}
+	if announcement.ExpiresAt != nil && !announcement.ExpiresAt.After(announcement.PublishAt) {
+		return models.Announcement{}, ErrInvalidSchedule
+	}
+	announcement.FanOut = models.FanOut{}
+	announcement.CreatedAt = now
+	return announcement, nil
+}
+
+func (s *AnnouncementService) insert(ctx context.Context, announcement models.Announcement) (models.Announcement, error) {
 	if s.col == nil {
 		announcementMemory.Lock()
-		defer announcementMemory.Unlock()
 		announcement.ID = primitive.NewObjectID()
-		announcement.CreatedAt = time.Now()
 		announcementMemory.data[announcement.ID.Hex()] = announcement
-		return announcement, nil
-	}
-	announcement.CreatedAt = time.Now()
-	res, err := s.col.InsertOne(ctx, announcement)
-	if err != nil {
-		return models.Announcement{}, err
+		announcementMemory.Unlock()
+	} else {
+		res, err := s.col.InsertOne(ctx, announcement)
+		if err != nil {
+			return models.Announcement{}, err
+		}
+		announcement.ID = res.InsertedID.(primitive.ObjectID)
 	}
-	announcement.ID = res.InsertedID.(primitive.ObjectID)
 	return announcement, nil
 }
 
-// List returns all announcements, sorted by latest first.
-func (s *AnnouncementService) List(ctx context.Context) ([]models.Announcement, error) {
+// audienceQuery maps an audience to the users it selects.
+func audienceQuery(a models.Audience) (UserQuery, error) {
+	q := UserQuery{AnySkills: a.Skills, Location: strings.TrimSpace(a.Location)}
+	switch a.Segment {
+	case models.AudienceAll:
+		q.Roles = []string{models.RoleRecruiter, models.RoleSeeker}
+	case models.AudienceRecruiters:
+		q.Roles = []string{models.RoleRecruiter}
+	case models.AudienceSeekers:
+		q.Roles = []string{models.RoleSeeker}
+	case models.AudiencePremium:
+		q.Roles = []string{models.RoleSeeker}
+		q.PremiumOnly = true
+	default:
+		return UserQuery{}, ErrInvalidAudience
+	}
+	return q, nil
+}
+
+// List returns all announcements with the given status (any for ""), sorted
+// by latest first.
+func (s *AnnouncementService) List(ctx context.Context, status string) ([]models.Announcement, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	return s.find(ctx, filter, func(a models.Announcement) bool { return status == "" || a.Status == status })
+}
+
+// ListByAuthor returns the announcements created by userID, latest first,
+// with their moderation status.
+func (s *AnnouncementService) ListByAuthor(ctx context.Context, userID primitive.ObjectID) ([]models.Announcement, error) {
+	return s.find(ctx, bson.M{"created_by": userID}, func(a models.Announcement) bool {
+		return a.CreatedBy != nil && *a.CreatedBy == userID
+	})
+}
+
+// find returns the announcements matching filter in mongo or match in
+// memory, latest first.
+func (s *AnnouncementService) find(ctx context.Context, filter bson.M, match func(models.Announcement) bool) ([]models.Announcement, error) {
 	if s.col == nil {
 		announcementMemory.Lock()
 		defer announcementMemory.Unlock()
 		announcements := make([]models.Announcement, 0, len(announcementMemory.data))
 		for _, a := range announcementMemory.data {
-			announcements = append(announcements, a)
+			if match(a) {
+				announcements = append(announcements, a)
+			}
 		}
 		// Sort by CreatedAt descending (latest first)
 		for i := 0; i < len(announcements)-1; i++ {
@@ -69,7 +195,7 @@ func (s *AnnouncementService) List(ctx context.Context) ([]models.Announcement,
 		return announcements, nil
 	}
 	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := s.col.Find(ctx, bson.M{}, opts)
+	cursor, err := s.col.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -81,3 +207,570 @@ func (s *AnnouncementService) List(ctx context.Context) ([]models.Announcement,
 	return announcements, nil
 }
 
+// FindByID returns an announcement by id.
+func (s *AnnouncementService) FindByID(ctx context.Context, id primitive.ObjectID) (models.Announcement, error) {
+	if s.col == nil {
+		announcementMemory.Lock()
+		defer announcementMemory.Unlock()
+		a, ok := announcementMemory.data[id.Hex()]
+		if !ok {
+			return models.Announcement{}, mongo.ErrNoDocuments
+		}
+		return a, nil
+	}
+	var a models.Announcement
+	err := s.col.FindOne(ctx, bson.M{"_id": id}).Decode(&a)
+	return a, err
+}
+
+// Cancel withdraws an announcement that is not cancelled yet: delivery stops
+// and users no longer see it.
+func (s *AnnouncementService) Cancel(ctx context.Context, id primitive.ObjectID) (models.Announcement, error) {
+	return s.transition(ctx, id,
+		[]string{models.AnnouncementPending, models.AnnouncementScheduled, models.AnnouncementSending, models.AnnouncementPublished, models.AnnouncementFailed},
+		func(a *models.Announcement) { a.Status = models.AnnouncementCancelled; a.FanOut.LeaseUntil = nil },
+		bson.M{"$set": bson.M{"status": models.AnnouncementCancelled}, "$unset": bson.M{"fan_out.lease_until": ""}})
+}
+
+// Retry reschedules a FAILED announcement; delivery resumes where it stopped.
+func (s *AnnouncementService) Retry(ctx context.Context, id primitive.ObjectID) (models.Announcement, error) {
+	defer s.Wake()
+	return s.transition(ctx, id, []string{models.AnnouncementFailed},
+		func(a *models.Announcement) {
+			a.Status = models.AnnouncementScheduled
+			a.FanOut.Attempts = 0
+			a.FanOut.NextAttemptAt = nil
+		},
+		bson.M{"$set": bson.M{"status": models.AnnouncementScheduled, "fan_out.attempts": 0}, "$unset": bson.M{"fan_out.next_attempt_at": ""}})
+}
+
+// transition applies edit in memory or update in mongo to an announcement
+// whose status is one of from, returning ErrAnnouncementState otherwise.
+func (s *AnnouncementService) transition(ctx context.Context, id primitive.ObjectID, from []string, edit func(*models.Announcement), update bson.M) (models.Announcement, error) {
+	if s.col == nil {
+		announcementMemory.Lock()
+		defer announcementMemory.Unlock()
+		a, ok := announcementMemory.data[id.Hex()]
+		if !ok {
+			return models.Announcement{}, mongo.ErrNoDocuments
+		}
+		allowed := false
+		for _, status := range from {
+			allowed = allowed || a.Status == status
+		}
+		if !allowed {
+			return models.Announcement{}, ErrAnnouncementState
+		}
+		edit(&a)
+		announcementMemory.data[id.Hex()] = a
+		return a, nil
+	}
+	var a models.Announcement
+	err := s.col.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": bson.M{"$in": from}}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&a)
+	if err == mongo.ErrNoDocuments {
+		if _, ferr := s.FindByID(ctx, id); ferr == nil {
+			return models.Announcement{}, ErrAnnouncementState
+		}
+	}
+	return a, err
+}
+
+// Wake asks the background worker to look for due announcements now.
+func (s *AnnouncementService) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start delivers due announcements every interval, and when woken, until ctx
+// is done.
+func (s *AnnouncementService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+			if _, err := s.ProcessDue(ctx, time.Now()); err != nil {
+				log.Printf("announcements: fan-out failed: %v", err)
+			}
+		}
+	}()
+}
+
+// ProcessDue delivers every announcement due at now, including ones whose
+// worker stopped without finishing, and returns how many it published.
+// Delivery failures are recorded on the announcement and retried with
+// backoff; the returned error is for failures to claim work.
+func (s *AnnouncementService) ProcessDue(ctx context.Context, now time.Time) (int, error) {
+	published := 0
+	for {
+		a, ok, err := s.claim(ctx, now)
+		if err != nil || !ok {
+			return published, err
+		}
+		if s.fanOut(ctx, a, now) {
+			published++
+		}
+	}
+}
+
+// claim takes the next due announcement for delivery.
+func (s *AnnouncementService) claim(ctx context.Context, now time.Time) (models.Announcement, bool, error) {
+	lease := now.Add(announcementLease)
+	if s.col == nil {
+		announcementMemory.Lock()
+		defer announcementMemory.Unlock()
+		due := make([]models.Announcement, 0)
+		for _, a := range announcementMemory.data {
+			f := a.FanOut
+			scheduled := a.Status == models.AnnouncementScheduled && !a.PublishAt.After(now) && (f.NextAttemptAt == nil || !f.NextAttemptAt.After(now))
+			abandoned := a.Status == models.AnnouncementSending && f.LeaseUntil != nil && f.LeaseUntil.Before(now)
+			if scheduled || abandoned {
+				due = append(due, a)
+			}
+		}
+		if len(due) == 0 {
+			return models.Announcement{}, false, nil
+		}
+		sort.Slice(due, func(i, j int) bool { return due[i].PublishAt.Before(due[j].PublishAt) })
+		a := due[0]
+		a.Status = models.AnnouncementSending
+		a.FanOut.LeaseUntil = &lease
+		if a.FanOut.StartedAt == nil {
+			a.FanOut.StartedAt = &now
+		}
+		announcementMemory.data[a.ID.Hex()] = a
+		return a, true, nil
+	}
+	filter := bson.M{"$or": bson.A{
+		bson.M{
+			"status":     models.AnnouncementScheduled,
+			"publish_at": bson.M{"$lte": now},
+			"$or": bson.A{
+				bson.M{"fan_out.next_attempt_at": bson.M{"$exists": false}},
+				bson.M{"fan_out.next_attempt_at": bson.M{"$lte": now}},
+			},
+		},
+		bson.M{"status": models.AnnouncementSending, "fan_out.lease_until": bson.M{"$lt": now}},
+	}}
+	update := bson.A{bson.M{"$set": bson.M{
+		"status":              models.AnnouncementSending,
+		"fan_out.lease_until": lease,
+		"fan_out.started_at":  bson.M{"$ifNull": bson.A{"$fan_out.started_at", now}},
+	}}}
+	var a models.Announcement
+	err := s.col.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "publish_at", Value: 1}}).
+		SetReturnDocument(options.After)).Decode(&a)
+	if err == mongo.ErrNoDocuments {
+		return models.Announcement{}, false, nil
+	}
+	if err != nil {
+		return models.Announcement{}, false, err
+	}
+	return a, true, nil
+}
+
+// fanOut delivers a claimed announcement to its audience in batches,
+// recording progress after each, and reports whether it was published.
+func (s *AnnouncementService) fanOut(ctx context.Context, a models.Announcement, now time.Time) bool {
+	q, err := audienceQuery(a.Audience)
+	if err != nil {
+		s.fail(ctx, a, err, now)
+		return false
+	}
+	if a.FanOut.Cursor.IsZero() {
+		total, err := s.users.CountUsers(ctx, q)
+		if err != nil {
+			s.fail(ctx, a, err, now)
+			return false
+		}
+		if !s.progress(ctx, a.ID, func(f *models.FanOut) { f.Total = total }, bson.M{"$set": bson.M{"fan_out.total": total}}) {
+			return false
+		}
+	}
+	cursor := a.FanOut.Cursor
+	for {
+		users, err := s.users.ListAfter(ctx, q, cursor, s.batchSize)
+		if err != nil {
+			s.fail(ctx, a, err, now)
+			return false
+		}
+		if len(users) == 0 {
+			return s.complete(ctx, a.ID)
+		}
+		added, err := s.deliver(ctx, a.ID, users, now)
+		if err != nil {
+			s.fail(ctx, a, err, now)
+			return false
+		}
+		cursor = users[len(users)-1].ID
+		lease := time.Now().Add(announcementLease)
+		last := cursor
+		if !s.progress(ctx, a.ID, func(f *models.FanOut) {
+			f.Cursor = last
+			f.Delivered += added
+			f.LeaseUntil = &lease
+		}, bson.M{"$set": bson.M{"fan_out.cursor": last, "fan_out.lease_until": lease}, "$inc": bson.M{"fan_out.delivered": added}}) {
+			return false
+		}
+	}
+}
+
+// deliver records receipts for users, skipping those who already have one,
+// and returns how many were added.
+func (s *AnnouncementService) deliver(ctx context.Context, announcementID primitive.ObjectID, users []models.User, now time.Time) (int64, error) {
+	if s.receipts == nil {
+		announcementMemory.Lock()
+		defer announcementMemory.Unlock()
+		var added int64
+		for _, u := range users {
+			key := receiptKey(announcementID, u.ID)
+			if _, ok := announcementMemory.receipts[key]; ok {
+				continue
+			}
+			announcementMemory.receipts[key] = models.AnnouncementReceipt{
+				ID:             primitive.NewObjectID(),
+				AnnouncementID: announcementID,
+				UserID:         u.ID,
+				DeliveredAt:    now,
+			}
+			added++
+		}
+		return added, nil
+	}
+	writes := make([]mongo.WriteModel, len(users))
+	for i, u := range users {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"announcement_id": announcementID, "user_id": u.ID}).
+			SetUpdate(bson.M{"$setOnInsert": bson.M{"delivered_at": now}}).
+			SetUpsert(true)
+	}
+	res, err := s.receipts.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+	return res.UpsertedCount, nil
+}
+
+// progress updates a SENDING announcement's fan-out state. It reports false
+// when the announcement is no longer SENDING, e.g. because it was cancelled.
+func (s *AnnouncementService) progress(ctx context.Context, id primitive.ObjectID, edit func(*models.FanOut), update bson.M) bool {
+	if s.col == nil {
+		announcementMemory.Lock()
+		defer announcementMemory.Unlock()
+		a, ok := announcementMemory.data[id.Hex()]
+		if !ok || a.Status != models.AnnouncementSending {
+			return false
+		}
+		edit(&a.FanOut)
+		announcementMemory.data[id.Hex()] = a
+		return true
+	}
+	res, err := s.col.UpdateOne(ctx, bson.M{"_id": id, "status": models.AnnouncementSending}, update)
+	if err != nil {
+		log.Printf("announcements: recording progress of %s failed: %v", id.Hex(), err)
+		return false
+	}
+	return res.MatchedCount > 0
+}
+
+func (s *AnnouncementService) complete(ctx context.Context, id primitive.ObjectID) bool {
+	now := time.Now()
+	if s.col == nil {
+		announcementMemory.Lock()
+		defer announcementMemory.Unlock()
+		a, ok := announcementMemory.data[id.Hex()]
+		if !ok || a.Status != models.AnnouncementSending {
+			return false
+		}
+		a.Status = models.AnnouncementPublished
+		a.FanOut.CompletedAt = &now
+		a.FanOut.LeaseUntil = nil
+		a.FanOut.NextAttemptAt = nil
+		a.FanOut.LastError = ""
+		announcementMemory.data[id.Hex()] = a
+		return true
+	}
+	res, err := s.col.UpdateOne(ctx, bson.M{"_id": id, "status": models.AnnouncementSending}, bson.M{
+		"$set":   bson.M{"status": models.AnnouncementPublished, "fan_out.completed_at": now},
+		"$unset": bson.M{"fan_out.lease_until": "", "fan_out.next_attempt_at": "", "fan_out.last_error": ""},
+	})
+	if err != nil {
+		log.Printf("announcements: completing %s failed: %v", id.Hex(), err)
+		return false
+	}
+	return res.MatchedCount > 0
+}
+
+// fail records a failed attempt: the announcement is retried after a backoff
+// of one minute doubling per attempt, or marked FAILED after
+// MaxAnnouncementAttempts.
+func (s *AnnouncementService) fail(ctx context.Context, a models.Announcement, cause error, now time.Time) {
+	log.Printf("announcements: delivering %s failed: %v", a.ID.Hex(), cause)
+	attempts := a.FanOut.Attempts + 1
+	status := models.AnnouncementScheduled
+	next := now.Add(time.Minute << (attempts - 1))
+	if attempts >= MaxAnnouncementAttempts {
+		status = models.AnnouncementFailed
+	}
+	s.progress(ctx, a.ID, func(f *models.FanOut) {
+		f.Attempts = attempts
+		f.LastError = cause.Error()
+		f.LeaseUntil = nil
+		f.NextAttemptAt = &next
+	}, bson.M{
+		"$set":   bson.M{"fan_out.attempts": attempts, "fan_out.last_error": cause.Error(), "fan_out.next_attempt_at": next},
+		"$unset": bson.M{"fan_out.lease_until": ""},
+	})
+	// progress only touches SENDING announcements, so set the status last.
+	if s.col == nil {
+		announcementMemory.Lock()
+		if cur, ok := announcementMemory.data[a.ID.Hex()]; ok && cur.Status == models.AnnouncementSending {
+			cur.Status = status
+			announcementMemory.data[a.ID.Hex()] = cur
+		}
+		announcementMemory.Unlock()
+		return
+	}
+	if _, err := s.col.UpdateOne(ctx, bson.M{"_id": a.ID, "status": models.AnnouncementSending}, bson.M{"$set": bson.M{"status": status}}); err != nil {
+		log.Printf("announcements: recording failure of %s failed: %v", a.ID.Hex(), err)
+	}
+}
+
+// Feed returns the announcements delivered to userID that are live at now
+// and not dismissed, newest first.
+func (s *AnnouncementService) Feed(ctx context.Context, userID primitive.ObjectID, now time.Time) ([]AnnouncementFeedItem, error) {
+	var receipts []models.AnnouncementReceipt
+	if s.receipts == nil {
+		announcementMemory.Lock()
+		for _, r := range announcementMemory.receipts {
+			if r.UserID == userID && r.DismissedAt == nil {
+				receipts = append(receipts, r)
+			}
+		}
+		announcementMemory.Unlock()
+	} else {
+		cursor, err := s.receipts.Find(ctx,
+			bson.M{"user_id": userID, "dismissed_at": bson.M{"$exists": false}},
+			options.Find().SetSort(bson.D{{Key: "delivered_at", Value: -1}}).SetLimit(announcementFeedLimit))
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+		if err := cursor.All(ctx, &receipts); err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]AnnouncementFeedItem, 0, len(receipts))
+	for _, r := range receipts {
+		a, err := s.FindByID(ctx, r.AnnouncementID)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if a.Status == models.AnnouncementCancelled || (a.ExpiresAt != nil && !a.ExpiresAt.After(now)) {
+			continue
+		}
+		items = append(items, AnnouncementFeedItem{Announcement: a, Receipt: r})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Announcement.PublishAt.After(items[j].Announcement.PublishAt) })
+	if len(items) > announcementFeedLimit {
+		items = items[:announcementFeedLimit]
+	}
+	return items, nil
+}
+
+// MarkRead marks an announcement delivered to userID as read.
+func (s *AnnouncementService) MarkRead(ctx context.Context, announcementID, userID primitive.ObjectID) error {
+	return s.updateReceipt(ctx, announcementID, userID, func(r *models.AnnouncementReceipt, now time.Time) {
+		if r.ReadAt == nil {
+			r.ReadAt = &now
+		}
+	}, "read_at")
+}
+
+// Dismiss hides an announcement delivered to userID from their feed.
+func (s *AnnouncementService) Dismiss(ctx context.Context, announcementID, userID primitive.ObjectID) error {
+	return s.updateReceipt(ctx, announcementID, userID, func(r *models.AnnouncementReceipt, now time.Time) {
+		if r.DismissedAt == nil {
+			r.DismissedAt = &now
+		}
+	}, "dismissed_at")
+}
+
+// updateReceipt stamps field on userID's receipt the first time. It returns
+// mongo.ErrNoDocuments when the announcement was not delivered to them.
+func (s *AnnouncementService) updateReceipt(ctx context.Context, announcementID, userID primitive.ObjectID, edit func(*models.AnnouncementReceipt, time.Time), field string) error {
+	now := time.Now()
+	if s.receipts == nil {
+		announcementMemory.Lock()
+		defer announcementMemory.Unlock()
+		key := receiptKey(announcementID, userID)
+		r, ok := announcementMemory.receipts[key]
+		if !ok {
+			return mongo.ErrNoDocuments
+		}
+		edit(&r, now)
+		announcementMemory.receipts[key] = r
+		return nil
+	}
+	filter := bson.M{"announcement_id": announcementID, "user_id": userID}
+	res, err := s.receipts.UpdateOne(ctx, filter, bson.A{
+		bson.M{"$set": bson.M{field: bson.M{"$ifNull": bson.A{"$" + field, now}}}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// legacyAnnouncementPrefix marks the inbox messages admin announcements were
+// sent as before announcements had feeds.
+const legacyAnnouncementPrefix = "[ANNOUNCEMENT] "
+
+// MigrateLegacy schedules announcements created before audiences existed for
+// delivery to the audience they were shown to: everyone for admin
+// announcements, recruiters for recruiter ones. The inbox messages admin
+// announcements used to be sent as are replaced by receipts, keeping their
+// read state, so they are not delivered twice. It returns how many
+// announcements and messages changed.
+func (s *AnnouncementService) MigrateLegacy(ctx context.Context) (int64, error) {
+	if s.col == nil {
+		return 0, nil
+	}
+	var migrated int64
+	for role, segment := range map[string]string{models.RoleAdmin: models.AudienceAll, models.RoleRecruiter: models.AudienceRecruiters} {
+		res, err := s.col.UpdateMany(ctx,
+			bson.M{"status": bson.M{"$exists": false}, "from_role": role},
+			bson.A{bson.M{"$set": bson.M{
+				"status":     models.AnnouncementScheduled,
+				"audience":   bson.M{"segment": segment},
+				"publish_at": "$created_at",
+				"fan_out":    bson.M{"total": 0, "delivered": 0, "attempts": 0},
+			}}})
+		if err != nil {
+			return migrated, err
+		}
+		migrated += res.ModifiedCount
+	}
+	moved, err := s.migrateLegacyMessages(ctx)
+	return migrated + moved, err
+}
+
+// migrateLegacyMessages turns each legacy announcement message into a receipt
+// of the admin announcement it was sent for, the latest with its text created
+// before it, and deletes the message.
+func (s *AnnouncementService) migrateLegacyMessages(ctx context.Context) (int64, error) {
+	cursor, err := s.col.Find(ctx, bson.M{"from_role": models.RoleAdmin}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return 0, err
+	}
+	var anns []models.Announcement
+	err = cursor.All(ctx, &anns)
+	cursor.Close(ctx)
+	if err != nil || len(anns) == 0 {
+		return 0, err
+	}
+	byText := map[string][]models.Announcement{}
+	for _, a := range anns {
+		byText[a.Message] = append(byText[a.Message], a)
+	}
+
+	cursor, err = s.messages.Find(ctx, bson.M{
+		"from_user_id": primitive.NilObjectID,
+		"from_role":    models.RoleAdmin,
+		"message":      primitive.Regex{Pattern: "^" + regexp.QuoteMeta(legacyAnnouncementPrefix)},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+	var writes []mongo.WriteModel
+	var ids []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var msg models.Message
+		if err := cursor.Decode(&msg); err != nil {
+			return 0, err
+		}
+		candidates := byText[strings.TrimPrefix(msg.Message, legacyAnnouncementPrefix)]
+		if len(candidates) == 0 {
+			continue
+		}
+		ann := candidates[0]
+		for _, a := range candidates {
+			if !a.CreatedAt.After(msg.CreatedAt) {
+				ann = a
+			}
+		}
+		set := bson.M{"delivered_at": bson.M{"$ifNull": bson.A{"$delivered_at", msg.CreatedAt}}}
+		if msg.IsRead {
+			readAt := msg.CreatedAt
+			if msg.ReadAt != nil {
+				readAt = *msg.ReadAt
+			}
+			set["read_at"] = bson.M{"$ifNull": bson.A{"$read_at", readAt}}
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"announcement_id": ann.ID, "user_id": msg.ToUserID}).
+			SetUpdate(bson.A{bson.M{"$set": set}}).
+			SetUpsert(true))
+		ids = append(ids, msg.ID)
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+	if len(writes) == 0 {
+		return 0, nil
+	}
+	if _, err := s.receipts.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return 0, err
+	}
+	res, err := s.messages.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// EnsureIndexes creates the indexes used by the fan-out worker and feeds,
+// and expires submission counters after two days.
+func (s *AnnouncementService) EnsureIndexes(ctx context.Context) error {
+	if s.col == nil {
+		return nil
+	}
+	if _, err := s.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "publish_at", Value: 1}}},
+		{Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "created_at", Value: -1}}},
+	}); err != nil {
+		return err
+	}
+	_, err := s.receipts.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "announcement_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "delivered_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.limits.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "day", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32((48 * time.Hour).Seconds())),
+	})
+	return err
+}
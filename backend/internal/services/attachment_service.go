@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/storage"
+)
+
+// MaxAttachmentsPerMessage caps the files sent with one message.
+const MaxAttachmentsPerMessage = 5
+
+var (
+	// ErrAttachmentTooLarge is returned for a file over the size limit.
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrAttachmentType is returned for a file type that is not allowed, or
+	// whose content does not match its extension.
+	ErrAttachmentType = errors.New("attachment type not allowed; use PDF, Word, PNG, JPEG or plain text")
+)
+
+// attachmentType is an allowed extension, the content type it is served
+// as, and the sniffed content types its files may have.
+type attachmentType struct {
+	contentType string
+	sniffed     []string
+}
+
+// Resumes, offer letters and assignments: documents, images and text.
+var attachmentTypes = map[string]attachmentType{
+	".pdf":  {"application/pdf", []string{"application/pdf"}},
+	".doc":  {"application/msword", []string{"application/octet-stream"}},
+	".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", []string{"application/zip"}},
+	".png":  {"image/png", []string{"image/png"}},
+	".jpg":  {"image/jpeg", []string{"image/jpeg"}},
+	".jpeg": {"image/jpeg", []string{"image/jpeg"}},
+	".txt":  {"text/plain; charset=utf-8", []string{"text/plain"}},
+}
+
+// AttachmentService validates, scans and stores message attachments.
+type AttachmentService struct {
+	store    storage.BlobStore
+	scanner  storage.Scanner
+	maxBytes int64
+}
+
+// NewAttachmentService creates an AttachmentService accepting files up to
+// maxBytes. A nil scanner accepts every file.
+func NewAttachmentService(store storage.BlobStore, scanner storage.Scanner, maxBytes int64) *AttachmentService {
+	if scanner == nil {
+		scanner = storage.NopScanner{}
+	}
+	return &AttachmentService{store: store, scanner: scanner, maxBytes: maxBytes}
+}
+
+// MaxBytes is the size limit of one attachment.
+func (s *AttachmentService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// MaxRequestBytes bounds a request carrying a message and its attachments.
+func (s *AttachmentService) MaxRequestBytes() int64 {
+	return MaxAttachmentsPerMessage*s.maxBytes + 1<<20
+}
+
+// Store checks a file's size and type, scans it and saves it, returning the
+// attachment to put on a message.
+func (s *AttachmentService) Store(ctx context.Context, name string, data []byte) (models.Attachment, error) {
+	if int64(len(data)) > s.maxBytes {
+		return models.Attachment{}, ErrAttachmentTooLarge
+	}
+	name = attachmentName(name)
+	kind, ok := attachmentTypes[strings.ToLower(filepath.Ext(name))]
+	if !ok || len(data) == 0 {
+		return models.Attachment{}, ErrAttachmentType
+	}
+	sniffed := http.DetectContentType(data)
+	matches := false
+	for _, prefix := range kind.sniffed {
+		matches = matches || strings.HasPrefix(sniffed, prefix)
+	}
+	if !matches {
+		return models.Attachment{}, ErrAttachmentType
+	}
+	if err := s.scanner.Scan(ctx, name, data); err != nil {
+		return models.Attachment{}, err
+	}
+	att := models.Attachment{
+		ID:          primitive.NewObjectID(),
+		Name:        name,
+		ContentType: kind.contentType,
+		Size:        int64(len(data)),
+	}
+	att.Key = att.ID.Hex()
+	if err := s.store.Put(ctx, att.Key, bytes.NewReader(data)); err != nil {
+		return models.Attachment{}, err
+	}
+	return att, nil
+}
+
+// Open returns an attachment's contents; the caller closes it.
+func (s *AttachmentService) Open(ctx context.Context, att models.Attachment) (io.ReadCloser, error) {
+	return s.store.Open(ctx, att.Key)
+}
+
+// Delete removes attachments' contents. Failures are logged: a leftover blob
+// is unreachable once its message is gone.
+func (s *AttachmentService) Delete(ctx context.Context, atts []models.Attachment) {
+	for _, att := range atts {
+		if err := s.store.Delete(ctx, att.Key); err != nil {
+			log.Printf("attachment %s cleanup failed: %v", att.ID.Hex(), err)
+		}
+	}
+}
+
+// attachmentName keeps the base name of an uploaded file without control
+// characters, capped at 200 runes.
+func attachmentName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > 200 {
+		ext := filepath.Ext(name)
+		name = string(runes[:200-len([]rune(ext))]) + ext
+	}
+	return name
+}
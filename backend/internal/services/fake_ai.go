@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync/atomic"
+
+	"rizeos/backend/internal/models"
+)
+
+// FakeAI is a deterministic AIBackend for tests and offline demos. Scores are
+// the share of job skills the candidate lists (50 when the job lists none);
+// extraction returns the entries of Skills that appear in the text.
+type FakeAI struct {
+	// Score, when non-nil, is returned for every non-empty pair instead.
+	Score *float64
+	// Skills is the extraction vocabulary.
+	Skills []string
+	// Err, when set, is returned by every call.
+	Err error
+
+	calls int64
+}
+
+// Calls returns how many pairs were scored or texts extracted.
+func (f *FakeAI) Calls() int {
+	return int(atomic.LoadInt64(&f.calls))
+}
+
+// MatchScoreDetailed implements Matcher.
+func (f *FakeAI) MatchScoreDetailed(_ context.Context, jobDesc, candidateBio string, jobSkills, candidateSkills []string) (MatchBreakdown, error) {
+	atomic.AddInt64(&f.calls, 1)
+	if f.Err != nil {
+		return MatchBreakdown{}, f.Err
+	}
+	if strings.TrimSpace(jobDesc) == "" || strings.TrimSpace(candidateBio) == "" {
+		return MatchBreakdown{Engine: EngineFake}, nil
+	}
+	if f.Score != nil {
+		return MatchBreakdown{Score: *f.Score, Engine: EngineFake}, nil
+	}
+	coverage := 50.0
+	if len(jobSkills) > 0 {
+		have := models.SkillKeySet(candidateSkills)
+		matched := 0
+		for _, s := range jobSkills {
+			if have[models.SkillKey(s)] {
+				matched++
+			}
+		}
+		coverage = math.Round(float64(matched)/float64(len(jobSkills))*1000) / 10
+	}
+	return MatchBreakdown{Score: coverage, SemanticScore: &coverage, SkillScore: &coverage, Engine: EngineFake}, nil
+}
+
+// MatchJobAgainstCandidates implements Matcher.
+func (f *FakeAI) MatchJobAgainstCandidates(ctx context.Context, job MatchItem, candidates []MatchItem) []MatchResult {
+	return matchPairs(ctx, f, job, candidates, false)
+}
+
+// MatchCandidateAgainstJobs implements Matcher.
+func (f *FakeAI) MatchCandidateAgainstJobs(ctx context.Context, candidate MatchItem, jobs []MatchItem) []MatchResult {
+	return matchPairs(ctx, f, candidate, jobs, true)
+}
+
+// ExtractSkillsDetailed implements SkillExtractor.
+func (f *FakeAI) ExtractSkillsDetailed(_ context.Context, text string) ([]string, string, error) {
+	atomic.AddInt64(&f.calls, 1)
+	if f.Err != nil {
+		return nil, "", f.Err
+	}
+	lower := " " + strings.ToLower(text) + " "
+	skills := []string{}
+	for _, s := range f.Skills {
+		if strings.Contains(lower, " "+strings.ToLower(s)+" ") {
+			skills = append(skills, s)
+		}
+	}
+	return skills, EngineFake, nil
+}
@@ -118,3 +118,107 @@ func (s *JobApplicationService) FindByJobSeekerID(ctx context.Context, jobSeeker
 	return applications, nil
 }
 
+
+// All returns every application, e.g. for collaborative recommendations.
+func (s *JobApplicationService) All(ctx context.Context) ([]models.JobApplication, error) {
+	if s.col == nil {
+		jobApplicationMemory.Lock()
+		defer jobApplicationMemory.Unlock()
+		applications := make([]models.JobApplication, 0, len(jobApplicationMemory.data))
+		for _, app := range jobApplicationMemory.data {
+			applications = append(applications, app)
+		}
+		return applications, nil
+	}
+
+	cursor, err := s.col.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var applications []models.JobApplication
+	if err := cursor.All(ctx, &applications); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// ErrInvalidStatusTransition is returned for status changes the hiring flow does not allow.
+var ErrInvalidStatusTransition = errors.New("invalid application status transition")
+
+// statusTransitions lists the statuses each status may move to.
+var statusTransitions = map[string][]string{
+	models.ApplicationApplied:     {models.ApplicationShortlisted, models.ApplicationRejected},
+	models.ApplicationShortlisted: {models.ApplicationHired, models.ApplicationRejected},
+}
+
+// FindByID returns a single application.
+func (s *JobApplicationService) FindByID(ctx context.Context, id primitive.ObjectID) (models.JobApplication, error) {
+	if s.col == nil {
+		jobApplicationMemory.Lock()
+		defer jobApplicationMemory.Unlock()
+		app, ok := jobApplicationMemory.data[id.Hex()]
+		if !ok {
+			return models.JobApplication{}, mongo.ErrNoDocuments
+		}
+		return app, nil
+	}
+	var app models.JobApplication
+	err := s.col.FindOne(ctx, bson.M{"_id": id}).Decode(&app)
+	return app, err
+}
+
+// UpdateStatus moves an application to status, stamping the matching
+// *_at field. Transitions outside the hiring flow return ErrInvalidStatusTransition.
+func (s *JobApplicationService) UpdateStatus(ctx context.Context, id primitive.ObjectID, status string, at time.Time) (models.JobApplication, error) {
+	app, err := s.FindByID(ctx, id)
+	if err != nil {
+		return models.JobApplication{}, err
+	}
+	previous := app.ApplicationStatus
+	current := previous
+	if current == "" {
+		current = models.ApplicationApplied
+	}
+	allowed := false
+	for _, next := range statusTransitions[current] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return models.JobApplication{}, ErrInvalidStatusTransition
+	}
+
+	set := bson.M{"application_status": status, "updated_at": at}
+	switch status {
+	case models.ApplicationShortlisted:
+		set["shortlisted_at"] = at
+		app.ShortlistedAt = &at
+	case models.ApplicationHired:
+		set["hired_at"] = at
+		app.HiredAt = &at
+	case models.ApplicationRejected:
+		set["rejected_at"] = at
+		app.RejectedAt = &at
+	}
+	app.ApplicationStatus = status
+	app.UpdatedAt = at
+
+	if s.col == nil {
+		jobApplicationMemory.Lock()
+		defer jobApplicationMemory.Unlock()
+		jobApplicationMemory.data[id.Hex()] = app
+		return app, nil
+	}
+	// Match on the status read above so concurrent updates cannot both apply.
+	res, err := s.col.UpdateOne(ctx, bson.M{"_id": id, "application_status": previous}, bson.M{"$set": set})
+	if err != nil {
+		return models.JobApplication{}, err
+	}
+	if res.MatchedCount == 0 {
+		return models.JobApplication{}, ErrInvalidStatusTransition
+	}
+	return app, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,7 +14,8 @@ import (
 
 // JobService manages job persistence.
 type JobService struct {
-	col *mongo.Collection
+	col      *mongo.Collection
+	onUpdate []func(ctx context.Context, job models.Job)
 }
 
 var jobMemory = struct {
@@ -73,8 +75,87 @@ func (s *JobService) List(ctx context.Context, filters map[string]interface{}) (
 	return jobs, nil
 }
 
-// SetMatchScores updates match scores for a job.
-func (s *JobService) SetMatchScores(ctx context.Context, jobID primitive.ObjectID, scores map[string]float64) error {
+// ListPage returns a page (from 1) of all jobs, newest first, and the number
+// of jobs.
+func (s *JobService) ListPage(ctx context.Context, page, limit int) ([]models.Job, int64, error) {
+	jobs := make([]models.Job, 0)
+	if s.col == nil {
+		jobMemory.Lock()
+		for _, j := range jobMemory.data {
+			jobs = append(jobs, j)
+		}
+		jobMemory.Unlock()
+		sort.Slice(jobs, func(i, j int) bool { return newerThan(jobs[i].CreatedAt, jobs[i].ID, jobs[j].CreatedAt, jobs[j].ID) })
+		start, end := pageBounds(len(jobs), page, limit)
+		return jobs[start:end], int64(len(jobs)), nil
+	}
+	total, err := findPage(ctx, s.col, page, limit, nil, &jobs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// Update applies editable fields (title, description, skills, location, tags,
+// budget, expires_at) to a job and notifies OnJobUpdate listeners.
+func (s *JobService) Update(ctx context.Context, jobID primitive.ObjectID, update bson.M) (models.Job, error) {
+	job, err := s.update(ctx, jobID, update)
+	if err != nil {
+		return models.Job{}, err
+	}
+	for _, fn := range s.onUpdate {
+		fn(ctx, job)
+	}
+	return job, nil
+}
+
+// OnJobUpdate registers fn to run after a job's content changes.
+func (s *JobService) OnJobUpdate(fn func(ctx context.Context, job models.Job)) {
+	s.onUpdate = append(s.onUpdate, fn)
+}
+
+func (s *JobService) update(ctx context.Context, jobID primitive.ObjectID, update bson.M) (models.Job, error) {
+	if s.col == nil {
+		jobMemory.Lock()
+		defer jobMemory.Unlock()
+		job, ok := jobMemory.data[jobID.Hex()]
+		if !ok {
+			return models.Job{}, mongo.ErrNoDocuments
+		}
+		if v, ok := update["title"].(string); ok {
+			job.Title = v
+		}
+		if v, ok := update["description"].(string); ok {
+			job.Description = v
+		}
+		if v, ok := update["skills"].([]string); ok {
+			job.Skills = v
+		}
+		if v, ok := update["location"].(string); ok {
+			job.Location = v
+		}
+		if v, ok := update["tags"].([]string); ok {
+			job.Tags = v
+		}
+		if v, ok := update["budget"].(float64); ok {
+			job.Budget = v
+		}
+		if v, ok := update["expires_at"].(time.Time); ok {
+			job.ExpiresAt = &v
+		}
+		job.UpdatedAt = time.Now()
+		jobMemory.data[jobID.Hex()] = job
+		return job, nil
+	}
+	update["updated_at"] = time.Now()
+	if _, err := s.col.UpdateByID(ctx, jobID, bson.M{"$set": update}); err != nil {
+		return models.Job{}, err
+	}
+	return s.FindByID(ctx, jobID)
+}
+
+// SetScoringProfile updates the ranking configuration for a job.
+func (s *JobService) SetScoringProfile(ctx context.Context, jobID primitive.ObjectID, profile models.ScoringProfile) error {
 	if s.col == nil {
 		jobMemory.Lock()
 		defer jobMemory.Unlock()
@@ -82,11 +163,11 @@ func (s *JobService) SetMatchScores(ctx context.Context, jobID primitive.ObjectI
 		if !ok {
 			return mongo.ErrNoDocuments
 		}
-		job.MatchScores = scores
+		job.ScoringProfile = &profile
 		jobMemory.data[jobID.Hex()] = job
 		return nil
 	}
-	_, err := s.col.UpdateByID(ctx, jobID, bson.M{"$set": bson.M{"match_scores": scores, "updated_at": time.Now()}})
+	_, err := s.col.UpdateByID(ctx, jobID, bson.M{"$set": bson.M{"scoring_profile": profile, "updated_at": time.Now()}})
 	return err
 }
 
@@ -123,3 +204,29 @@ func (s *JobService) SetCandidates(ctx context.Context, jobID primitive.ObjectID
 	_, err := s.col.UpdateByID(ctx, jobID, bson.M{"$set": bson.M{"candidates": candidates, "updated_at": time.Now()}})
 	return err
 }
+
+// ClosingBetween returns jobs whose closing date is after from and no later
+// than to.
+func (s *JobService) ClosingBetween(ctx context.Context, from, to time.Time) ([]models.Job, error) {
+	if s.col == nil {
+		jobMemory.Lock()
+		defer jobMemory.Unlock()
+		jobs := make([]models.Job, 0)
+		for _, j := range jobMemory.data {
+			if j.ExpiresAt != nil && j.ExpiresAt.After(from) && !j.ExpiresAt.After(to) {
+				jobs = append(jobs, j)
+			}
+		}
+		return jobs, nil
+	}
+	cursor, err := s.col.Find(ctx, bson.M{"expires_at": bson.M{"$gt": from, "$lte": to}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	jobs := make([]models.Job, 0)
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+// MaxAnonymousViewsPerIP is how many anonymous views one IP address may add
+// per day, across all jobs; further views from it are not counted.
+const MaxAnonymousViewsPerIP = 50
+
+// JobViewService records job page views, deduplicated per viewer per day.
+// Each first view of the day is also counted in the analytics funnel.
+type JobViewService struct {
+	col       *mongo.Collection
+	limits    *mongo.Collection
+	analytics *AnalyticsService
+}
+
+var jobViewMemory = struct {
+	sync.Mutex
+	data   map[string]models.JobView
+	limits map[string]int
+}{data: map[string]models.JobView{}, limits: map[string]int{}}
+
+// NewJobViewService creates a JobViewService. analytics may be nil.
+func NewJobViewService(db *mongo.Database, analytics *AnalyticsService) *JobViewService {
+	if db == nil {
+		return &JobViewService{analytics: analytics}
+	}
+	return &JobViewService{col: db.Collection("job_views"), limits: db.Collection("job_view_limits"), analytics: analytics}
+}
+
+// ViewCount summarizes a job's views.
+type ViewCount struct {
+	Views   int `json:"views"`   // viewer-days: one per viewer per day
+	Viewers int `json:"viewers"` // distinct viewers
+}
+
+// UserViewerKey identifies an authenticated viewer.
+func UserViewerKey(userID primitive.ObjectID) string {
+	return "user:" + userID.Hex()
+}
+
+// AnonymousViewerKey identifies an anonymous viewer by IP address and user
+// agent. Client-supplied ids are not trusted, since a client could send a new
+// one per request. Inputs are hashed so raw addresses are never stored.
+func AnonymousViewerKey(ip, userAgent string) string {
+	return "anon:" + hashKey("client:"+ip+"|"+userAgent)
+}
+
+func hashKey(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:16])
+}
+
+// TrackAnonymous records a view of job by an anonymous client like Track.
+// Views beyond MaxAnonymousViewsPerIP from the client's IP address that day
+// are not counted.
+func (s *JobViewService) TrackAnonymous(ctx context.Context, job models.Job, ip, userAgent string, at time.Time) (bool, error) {
+	viewerKey := AnonymousViewerKey(ip, userAgent)
+	day := dayOf(at)
+	seen, err := s.seen(ctx, job.ID, viewerKey, day)
+	if err != nil || seen {
+		return false, err
+	}
+	allowed, err := s.takeAnonymousView(ctx, "ip:"+hashKey(ip), day)
+	if err != nil || !allowed {
+		return false, err
+	}
+	return s.Track(ctx, job, viewerKey, nil, at)
+}
+
+// seen reports whether viewerKey already viewed the job on day.
+func (s *JobViewService) seen(ctx context.Context, jobID primitive.ObjectID, viewerKey string, day time.Time) (bool, error) {
+	if s.col == nil {
+		jobViewMemory.Lock()
+		defer jobViewMemory.Unlock()
+		_, ok := jobViewMemory.data[statsKey(jobID, day)+":"+viewerKey]
+		return ok, nil
+	}
+	err := s.col.FindOne(ctx, bson.M{"job_id": jobID, "viewer_key": viewerKey, "day": day}).Err()
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// takeAnonymousView counts one anonymous view for client on day and reports
+// whether it is within MaxAnonymousViewsPerIP. The counter is only
+// incremented below the limit; once it is reached the upsert collides with
+// the existing document instead.
+func (s *JobViewService) takeAnonymousView(ctx context.Context, client string, day time.Time) (bool, error) {
+	id := client + ":" + day.Format("2006-01-02")
+	if s.limits == nil {
+		jobViewMemory.Lock()
+		defer jobViewMemory.Unlock()
+		if jobViewMemory.limits[id] >= MaxAnonymousViewsPerIP {
+			return false, nil
+		}
+		jobViewMemory.limits[id]++
+		return true, nil
+	}
+	_, err := s.limits.UpdateOne(ctx,
+		bson.M{"_id": id, "count": bson.M{"$lt": MaxAnonymousViewsPerIP}},
+		bson.M{"$inc": bson.M{"count": 1}, "$setOnInsert": bson.M{"day": day}},
+		options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Track records a view of job by viewerKey at the given time. It returns
+// false when the viewer already viewed the job that day.
+func (s *JobViewService) Track(ctx context.Context, job models.Job, viewerKey string, userID *primitive.ObjectID, at time.Time) (bool, error) {
+	day := dayOf(at)
+	view := models.JobView{JobID: job.ID, RecruiterID: job.RecruiterID, ViewerKey: viewerKey, UserID: userID, Day: day, ViewedAt: at}
+	if s.col == nil {
+		jobViewMemory.Lock()
+		key := statsKey(job.ID, day) + ":" + viewerKey
+		_, seen := jobViewMemory.data[key]
+		if !seen {
+			view.ID = primitive.NewObjectID()
+			jobViewMemory.data[key] = view
+		}
+		jobViewMemory.Unlock()
+		if seen {
+			return false, nil
+		}
+	} else {
+		res, err := s.col.UpdateOne(ctx,
+			bson.M{"job_id": job.ID, "viewer_key": viewerKey, "day": day},
+			bson.M{"$setOnInsert": bson.M{"recruiter_id": job.RecruiterID, "user_id": userID, "viewed_at": at}},
+			options.Update().SetUpsert(true))
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil // a concurrent request recorded the same view
+		}
+		if err != nil {
+			return false, err
+		}
+		if res.UpsertedCount == 0 {
+			return false, nil
+		}
+	}
+	if s.analytics != nil {
+		if err := s.analytics.Record(ctx, job.ID, job.RecruiterID, EventView, at); err != nil {
+			log.Printf("analytics: recording view for job %s failed: %v", job.ID.Hex(), err)
+		}
+	}
+	return true, nil
+}
+
+// Counts returns view counts for the given jobs over their lifetime.
+func (s *JobViewService) Counts(ctx context.Context, jobIDs []primitive.ObjectID) (map[primitive.ObjectID]ViewCount, error) {
+	out := make(map[primitive.ObjectID]ViewCount, len(jobIDs))
+	if len(jobIDs) == 0 {
+		return out, nil
+	}
+	if s.col == nil {
+		want := make(map[primitive.ObjectID]bool, len(jobIDs))
+		for _, id := range jobIDs {
+			want[id] = true
+		}
+		viewers := map[primitive.ObjectID]map[string]bool{}
+		jobViewMemory.Lock()
+		defer jobViewMemory.Unlock()
+		for _, v := range jobViewMemory.data {
+			if !want[v.JobID] {
+				continue
+			}
+			if viewers[v.JobID] == nil {
+				viewers[v.JobID] = map[string]bool{}
+			}
+			viewers[v.JobID][v.ViewerKey] = true
+			c := out[v.JobID]
+			c.Views++
+			out[v.JobID] = c
+		}
+		for id, set := range viewers {
+			c := out[id]
+			c.Viewers = len(set)
+			out[id] = c
+		}
+		return out, nil
+	}
+	cursor, err := s.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"job_id": bson.M{"$in": jobIDs}}}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"job_id": "$job_id", "viewer": "$viewer_key"}, "days": bson.M{"$sum": 1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$_id.job_id", "views": bson.M{"$sum": "$days"}, "viewers": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var row struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			ViewCount `bson:",inline"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		out[row.ID] = row.ViewCount
+	}
+	return out, cursor.Err()
+}
+
+// EnsureIndexes creates the dedup index and expires anonymous view limits
+// after two days; a no-op in memory mode.
+func (s *JobViewService) EnsureIndexes(ctx context.Context) error {
+	if s.col == nil {
+		return nil
+	}
+	_, err := s.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "job_id", Value: 1}, {Key: "viewer_key", Value: 1}, {Key: "day", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.limits.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "day", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32((48 * time.Hour).Seconds())),
+	})
+	return err
+}
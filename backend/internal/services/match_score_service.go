@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+// recomputeQueueSize bounds pending background recomputations; extra requests
+// are dropped and recomputed lazily on the next read instead.
+const recomputeQueueSize = 1024
+
+// MatchScoreService stores AI match scores keyed by (job, seeker) and the
+// content hashes of both sides, so scores are only recomputed when the job
+// description/skills or the seeker bio/skills actually change.
+type MatchScoreService struct {
+	col   *mongo.Collection
+	ai    Matcher
+	jobs  *JobService
+	users *UserService
+	ttl   time.Duration
+	queue chan matchPair
+}
+
+type matchPair struct {
+	jobID, seekerID primitive.ObjectID
+}
+
+var matchScoreMemory = struct {
+	sync.Mutex
+	data map[string]models.MatchScore
+}{data: map[string]models.MatchScore{}}
+
+// NewMatchScoreService creates a MatchScoreService; ttl bounds how long a score
+// is trusted even when neither side changed (the AI model may have).
+func NewMatchScoreService(db *mongo.Database, ai Matcher, jobs *JobService, users *UserService, ttl time.Duration) *MatchScoreService {
+	s := &MatchScoreService{ai: ai, jobs: jobs, users: users, ttl: ttl, queue: make(chan matchPair, recomputeQueueSize)}
+	if db != nil {
+		s.col = db.Collection("match_scores")
+	}
+	return s
+}
+
+// CandidateText returns the text used to describe a seeker to the AI service:
+// summary, then bio, then name as a last resort.
+func CandidateText(seeker models.User) string {
+	if strings.TrimSpace(seeker.Summary) != "" {
+		return strings.TrimSpace(seeker.Summary)
+	}
+	if strings.TrimSpace(seeker.Bio) != "" {
+		return strings.TrimSpace(seeker.Bio)
+	}
+	return strings.TrimSpace(seeker.Name)
+}
+
+// JobContentHash fingerprints the job fields that affect match scores.
+func JobContentHash(job models.Job) string {
+	return contentHash(strings.TrimSpace(job.Description), job.Skills)
+}
+
+// SeekerContentHash fingerprints the seeker fields that affect match scores.
+func SeekerContentHash(seeker models.User) string {
+	return contentHash(CandidateText(seeker), seeker.Skills)
+}
+
+func contentHash(text string, skills []string) string {
+	keys := make([]string, 0, len(skills))
+	for _, s := range skills {
+		if k := models.SkillKey(s); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	sum := sha256.Sum256([]byte(text + "\x00" + strings.Join(keys, "\x1f")))
+	return hex.EncodeToString(sum[:12])
+}
+
+// Get returns a fresh cached score, or false when missing, expired or stale.
+func (s *MatchScoreService) Get(ctx context.Context, job models.Job, seeker models.User) (MatchBreakdown, bool) {
+	entry, err := s.find(ctx, job.ID, seeker.ID)
+	if err != nil {
+		return MatchBreakdown{}, false
+	}
+	if entry.JobHash != JobContentHash(job) || entry.SeekerHash != SeekerContentHash(seeker) || time.Now().After(entry.ExpiresAt) {
+		return MatchBreakdown{}, false
+	}
+	return cachedBreakdown(entry), true
+}
+
+// GetOrCompute returns the cached score or computes and stores a new one.
+// Pairs with an empty job description or candidate text score 0 without an AI call.
+func (s *MatchScoreService) GetOrCompute(ctx context.Context, job models.Job, seeker models.User) (MatchBreakdown, error) {
+	if res, ok := s.Get(ctx, job, seeker); ok {
+		return res, nil
+	}
+	jobDesc := strings.TrimSpace(job.Description)
+	text := CandidateText(seeker)
+	if jobDesc == "" || text == "" || s.ai == nil {
+		return MatchBreakdown{}, nil
+	}
+	res, err := s.ai.MatchScoreDetailed(ctx, jobDesc, text, job.Skills, seeker.Skills)
+	if err != nil {
+		return MatchBreakdown{}, err
+	}
+	s.store(ctx, job, seeker, res)
+	return res, nil
+}
+
+// GetOrComputeForJob returns scores for many seekers against one job, in
+// seeker order. Cache misses are scored in one batch AI request; per-seeker
+// failures are reported in MatchResult.Err.
+func (s *MatchScoreService) GetOrComputeForJob(ctx context.Context, job models.Job, seekers []models.User) []MatchResult {
+	cached, _ := s.list(ctx, bson.M{"job_id": job.ID}, func(m models.MatchScore) bool { return m.JobID == job.ID })
+	byID := make(map[primitive.ObjectID]models.MatchScore, len(cached))
+	for _, e := range cached {
+		byID[e.SeekerID] = e
+	}
+	pairs := make([]scorePair, len(seekers))
+	for i, seeker := range seekers {
+		pairs[i] = scorePair{job: job, seeker: seeker, cached: byID[seeker.ID]}
+	}
+	return s.fill(ctx, pairs, func(misses []scorePair) []MatchResult {
+		items := make([]MatchItem, len(misses))
+		for i, p := range misses {
+			items[i] = SeekerMatchItem(p.seeker)
+		}
+		return s.ai.MatchJobAgainstCandidates(ctx, JobMatchItem(job), items)
+	})
+}
+
+// GetOrComputeForSeeker returns scores for one seeker against many jobs, in
+// job order, batching cache misses like GetOrComputeForJob.
+func (s *MatchScoreService) GetOrComputeForSeeker(ctx context.Context, seeker models.User, jobs []models.Job) []MatchResult {
+	cached, _ := s.list(ctx, bson.M{"seeker_id": seeker.ID}, func(m models.MatchScore) bool { return m.SeekerID == seeker.ID })
+	byID := make(map[primitive.ObjectID]models.MatchScore, len(cached))
+	for _, e := range cached {
+		byID[e.JobID] = e
+	}
+	pairs := make([]scorePair, len(jobs))
+	for i, job := range jobs {
+		pairs[i] = scorePair{job: job, seeker: seeker, cached: byID[job.ID]}
+	}
+	return s.fill(ctx, pairs, func(misses []scorePair) []MatchResult {
+		items := make([]MatchItem, len(misses))
+		for i, p := range misses {
+			items[i] = JobMatchItem(p.job)
+		}
+		return s.ai.MatchCandidateAgainstJobs(ctx, SeekerMatchItem(seeker), items)
+	})
+}
+
+type scorePair struct {
+	job    models.Job
+	seeker models.User
+	cached models.MatchScore
+}
+
+// fill resolves fresh cache entries and scores the remaining pairs with one
+// batch call, storing every successful result.
+func (s *MatchScoreService) fill(ctx context.Context, pairs []scorePair, score func(misses []scorePair) []MatchResult) []MatchResult {
+	results := make([]MatchResult, len(pairs))
+	var misses []scorePair
+	var missIdx []int
+	now := time.Now()
+	for i, p := range pairs {
+		results[i] = MatchResult{JobID: p.job.ID.Hex(), CandidateID: p.seeker.ID.Hex()}
+		e := p.cached
+		if !e.JobID.IsZero() && e.JobHash == JobContentHash(p.job) && e.SeekerHash == SeekerContentHash(p.seeker) && now.Before(e.ExpiresAt) {
+			results[i].MatchBreakdown = cachedBreakdown(e)
+			continue
+		}
+		if s.ai == nil || strings.TrimSpace(p.job.Description) == "" || CandidateText(p.seeker) == "" {
+			continue
+		}
+		misses = append(misses, p)
+		missIdx = append(missIdx, i)
+	}
+	if len(misses) == 0 {
+		return results
+	}
+	for n, res := range score(misses) {
+		i := missIdx[n]
+		results[i].MatchBreakdown, results[i].Err = res.MatchBreakdown, res.Err
+		if res.Err == nil {
+			s.store(ctx, pairs[i].job, pairs[i].seeker, res.MatchBreakdown)
+		}
+	}
+	return results
+}
+
+// JobMatchItem is the AI batch request view of a job.
+func JobMatchItem(job models.Job) MatchItem {
+	return MatchItem{ID: job.ID.Hex(), Text: strings.TrimSpace(job.Description), Skills: job.Skills}
+}
+
+// SeekerMatchItem is the AI batch request view of a job seeker.
+func SeekerMatchItem(seeker models.User) MatchItem {
+	return MatchItem{ID: seeker.ID.Hex(), Text: CandidateText(seeker), Skills: seeker.Skills}
+}
+
+// store caches an AI service score. Local fallback scores are not cached so
+// pairs are rescored by the AI service once it is reachable again.
+func (s *MatchScoreService) store(ctx context.Context, job models.Job, seeker models.User, res MatchBreakdown) {
+	if res.Engine == EngineLocal {
+		return
+	}
+	if err := s.Put(ctx, job, seeker, res); err != nil {
+		log.Printf("match score cache write failed: %v", err)
+	}
+}
+
+// cachedBreakdown converts a stored entry; entries from before engines were
+// recorded came from the AI service.
+func cachedBreakdown(e models.MatchScore) MatchBreakdown {
+	engine := e.Engine
+	if engine == "" {
+		engine = EngineAIService
+	}
+	return MatchBreakdown{Score: e.Score, SemanticScore: e.SemanticScore, SkillScore: e.SkillScore, Engine: engine}
+}
+
+// Put stores a computed score for the current content of job and seeker.
+func (s *MatchScoreService) Put(ctx context.Context, job models.Job, seeker models.User, res MatchBreakdown) error {
+	now := time.Now()
+	entry := models.MatchScore{
+		JobID:         job.ID,
+		SeekerID:      seeker.ID,
+		JobHash:       JobContentHash(job),
+		SeekerHash:    SeekerContentHash(seeker),
+		Score:         res.Score,
+		SemanticScore: res.SemanticScore,
+		SkillScore:    res.SkillScore,
+		Engine:        res.Engine,
+		ComputedAt:    now,
+		ExpiresAt:     now.Add(s.ttl),
+	}
+	if s.col == nil {
+		matchScoreMemory.Lock()
+		defer matchScoreMemory.Unlock()
+		key := matchKey(job.ID, seeker.ID)
+		entry.ID = matchScoreMemory.data[key].ID
+		if entry.ID.IsZero() {
+			entry.ID = primitive.NewObjectID()
+		}
+		matchScoreMemory.data[key] = entry
+		return nil
+	}
+	_, err := s.col.UpdateOne(ctx,
+		bson.M{"job_id": job.ID, "seeker_id": seeker.ID},
+		bson.M{"$set": entry},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+// ScoresForJob returns fresh-or-stale cached scores for a job keyed by seeker id.
+func (s *MatchScoreService) ScoresForJob(ctx context.Context, jobID primitive.ObjectID) (map[string]float64, error) {
+	entries, err := s.list(ctx, bson.M{"job_id": jobID}, func(m models.MatchScore) bool { return m.JobID == jobID })
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[string]float64, len(entries))
+	for _, e := range entries {
+		scores[e.SeekerID.Hex()] = e.Score
+	}
+	return scores, nil
+}
+
+// InvalidateJob drops every cached score for a job and queues their recomputation.
+func (s *MatchScoreService) InvalidateJob(ctx context.Context, jobID primitive.ObjectID) error {
+	return s.invalidate(ctx, bson.M{"job_id": jobID}, func(m models.MatchScore) bool { return m.JobID == jobID })
+}
+
+// InvalidateSeeker drops every cached score for a seeker and queues their recomputation.
+func (s *MatchScoreService) InvalidateSeeker(ctx context.Context, seekerID primitive.ObjectID) error {
+	return s.invalidate(ctx, bson.M{"seeker_id": seekerID}, func(m models.MatchScore) bool { return m.SeekerID == seekerID })
+}
+
+// Enqueue schedules a background recomputation; it never blocks.
+func (s *MatchScoreService) Enqueue(jobID, seekerID primitive.ObjectID) {
+	select {
+	case s.queue <- matchPair{jobID: jobID, seekerID: seekerID}:
+	default:
+		// Queue full: the pair will be recomputed on its next read.
+	}
+}
+
+// Start ensures indexes and runs background recompute workers until ctx ends.
+func (s *MatchScoreService) Start(ctx context.Context, workers int) {
+	if s.col != nil {
+		if err := s.ensureIndexes(ctx); err != nil {
+			log.Printf("match score index setup failed: %v", err)
+		}
+	}
+	for i := 0; i < workers; i++ {
+		go s.worker(ctx)
+	}
+}
+
+func (s *MatchScoreService) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case pair := <-s.queue:
+			s.recompute(ctx, pair)
+		}
+	}
+}
+
+func (s *MatchScoreService) recompute(ctx context.Context, pair matchPair) {
+	if s.jobs == nil || s.users == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	job, err := s.jobs.FindByID(ctx, pair.jobID)
+	if err != nil {
+		return
+	}
+	seeker, err := s.users.FindByID(ctx, pair.seekerID)
+	if err != nil {
+		return
+	}
+	if _, err := s.GetOrCompute(ctx, job, seeker); err != nil {
+		log.Printf("background match score recompute failed for job %s seeker %s: %v", pair.jobID.Hex(), pair.seekerID.Hex(), err)
+	}
+}
+
+func (s *MatchScoreService) invalidate(ctx context.Context, filter bson.M, match func(models.MatchScore) bool) error {
+	entries, err := s.list(ctx, filter, match)
+	if err != nil {
+		return err
+	}
+	if s.col == nil {
+		matchScoreMemory.Lock()
+		for _, e := range entries {
+			delete(matchScoreMemory.data, matchKey(e.JobID, e.SeekerID))
+		}
+		matchScoreMemory.Unlock()
+	} else if _, err := s.col.DeleteMany(ctx, filter); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		s.Enqueue(e.JobID, e.SeekerID)
+	}
+	return nil
+}
+
+func (s *MatchScoreService) find(ctx context.Context, jobID, seekerID primitive.ObjectID) (models.MatchScore, error) {
+	if s.col == nil {
+		matchScoreMemory.Lock()
+		defer matchScoreMemory.Unlock()
+		if m, ok := matchScoreMemory.data[matchKey(jobID, seekerID)]; ok {
+			return m, nil
+		}
+		return models.MatchScore{}, mongo.ErrNoDocuments
+	}
+	var m models.MatchScore
+	if err := s.col.FindOne(ctx, bson.M{"job_id": jobID, "seeker_id": seekerID}).Decode(&m); err != nil {
+		return models.MatchScore{}, err
+	}
+	return m, nil
+}
+
+func (s *MatchScoreService) list(ctx context.Context, filter bson.M, match func(models.MatchScore) bool) ([]models.MatchScore, error) {
+	if s.col == nil {
+		matchScoreMemory.Lock()
+		defer matchScoreMemory.Unlock()
+		var entries []models.MatchScore
+		for _, m := range matchScoreMemory.data {
+			if match(m) {
+				entries = append(entries, m)
+			}
+		}
+		return entries, nil
+	}
+	cur, err := s.col.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var entries []models.MatchScore
+	if err := cur.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *MatchScoreService) ensureIndexes(ctx context.Context) error {
+	_, err := s.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "job_id", Value: 1}, {Key: "seeker_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "seeker_id", Value: 1}}},
+		// Expired entries are removed by Mongo; Get also checks expiry.
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+func matchKey(jobID, seekerID primitive.ObjectID) string {
+	return jobID.Hex() + ":" + seekerID.Hex()
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"rizeos/backend/internal/localai"
+)
+
+// Matcher scores how well candidates fit jobs. Scores are 0-100 and
+// MatchBreakdown.Engine names the backend that produced them.
+type Matcher interface {
+	MatchScoreDetailed(ctx context.Context, jobDesc, candidateBio string, jobSkills, candidateSkills []string) (MatchBreakdown, error)
+	// MatchJobAgainstCandidates scores one job against many candidates, in candidate order.
+	MatchJobAgainstCandidates(ctx context.Context, job MatchItem, candidates []MatchItem) []MatchResult
+	// MatchCandidateAgainstJobs scores one candidate against many jobs, in job order.
+	MatchCandidateAgainstJobs(ctx context.Context, candidate MatchItem, jobs []MatchItem) []MatchResult
+}
+
+// SkillExtractor extracts skills from free text and reports the engine used.
+type SkillExtractor interface {
+	ExtractSkillsDetailed(ctx context.Context, text string) ([]string, string, error)
+}
+
+// AIBackend is a complete scoring backend.
+type AIBackend interface {
+	Matcher
+	SkillExtractor
+}
+
+// AI backend names accepted by NewAIBackend (config AI_ENGINE).
+const (
+	BackendHTTP  = "http"  // FastAPI service with local fallback
+	BackendLocal = "local" // in-process TF-IDF scorer only
+	BackendFake  = "fake"  // deterministic fake for tests and demos
+)
+
+// NewAIBackend returns the backend selected by name; an empty name selects
+// BackendHTTP. vocabulary feeds local skill extraction and may be nil.
+func NewAIBackend(name, baseURL string, vocabulary func(ctx context.Context) []string) (AIBackend, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", BackendHTTP:
+		ai := NewAIService(baseURL)
+		ai.Vocabulary = vocabulary
+		return ai, nil
+	case BackendLocal:
+		return &LocalAI{Vocabulary: vocabulary}, nil
+	case BackendFake:
+		return &FakeAI{}, nil
+	default:
+		return nil, fmt.Errorf("unknown AI engine %q", name)
+	}
+}
+
+// LocalAI scores and extracts skills in-process with localai, without the
+// FastAPI service.
+type LocalAI struct {
+	// Vocabulary supplies skill names for extraction; nil uses localai.DefaultVocabulary.
+	Vocabulary func(ctx context.Context) []string
+}
+
+// MatchScoreDetailed implements Matcher.
+func (l *LocalAI) MatchScoreDetailed(_ context.Context, jobDesc, candidateBio string, jobSkills, candidateSkills []string) (MatchBreakdown, error) {
+	return localMatch(jobDesc, candidateBio, jobSkills, candidateSkills), nil
+}
+
+// MatchJobAgainstCandidates implements Matcher.
+func (l *LocalAI) MatchJobAgainstCandidates(ctx context.Context, job MatchItem, candidates []MatchItem) []MatchResult {
+	return matchPairs(ctx, l, job, candidates, false)
+}
+
+// MatchCandidateAgainstJobs implements Matcher.
+func (l *LocalAI) MatchCandidateAgainstJobs(ctx context.Context, candidate MatchItem, jobs []MatchItem) []MatchResult {
+	return matchPairs(ctx, l, candidate, jobs, true)
+}
+
+// ExtractSkillsDetailed implements SkillExtractor.
+func (l *LocalAI) ExtractSkillsDetailed(ctx context.Context, text string) ([]string, string, error) {
+	var vocab []string
+	if l.Vocabulary != nil {
+		vocab = l.Vocabulary(ctx)
+	}
+	return localai.ExtractSkills(text, vocab), EngineLocal, nil
+}
+
+// matchPairs scores fixed against each of others one at a time, for backends
+// without a batch API. fixedIsCandidate selects which side fixed is on.
+func matchPairs(ctx context.Context, m Matcher, fixed MatchItem, others []MatchItem, fixedIsCandidate bool) []MatchResult {
+	results := make([]MatchResult, len(others))
+	for i, other := range others {
+		job, cand := fixed, other
+		if fixedIsCandidate {
+			job, cand = other, fixed
+		}
+		results[i] = MatchResult{JobID: job.ID, CandidateID: cand.ID}
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if strings.TrimSpace(job.Text) == "" || strings.TrimSpace(cand.Text) == "" {
+			continue
+		}
+		results[i].MatchBreakdown, results[i].Err = m.MatchScoreDetailed(ctx, job.Text, cand.Text, job.Skills, cand.Skills)
+	}
+	return results
+}
@@ -0,0 +1,520 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+// conversationPreviewLength caps the last-message preview stored on a
+// conversation, in runes.
+const conversationPreviewLength = 140
+
+var conversationMemory = struct {
+	sync.Mutex
+	data map[string]models.Conversation
+}{data: map[string]models.Conversation{}}
+
+// conversationKey identifies the conversation between two users about a job
+// (or about no job), independent of who wrote first.
+func conversationKey(a, b, jobID primitive.ObjectID) string {
+	ids := []string{a.Hex(), b.Hex()}
+	sort.Strings(ids)
+	key := ids[0] + ":" + ids[1]
+	if !jobID.IsZero() {
+		key += ":" + jobID.Hex()
+	}
+	return key
+}
+
+// conversationIdentity returns the key, participants and job of the
+// conversation msg belongs to. Messages between a user and admins go to that
+// user's support conversation, which has no job context.
+func conversationIdentity(msg models.Message) (key string, participants []models.ConversationParticipant, jobID *primitive.ObjectID, support bool) {
+	switch {
+	case msg.ToRole == models.RoleAdmin:
+		return "support:" + msg.FromUserID.Hex(), []models.ConversationParticipant{{UserID: msg.FromUserID, Role: msg.FromRole}}, nil, true
+	case msg.FromRole == models.RoleAdmin:
+		return "support:" + msg.ToUserID.Hex(), []models.ConversationParticipant{{UserID: msg.ToUserID, Role: msg.ToRole}}, nil, true
+	}
+	participants = []models.ConversationParticipant{
+		{UserID: msg.FromUserID, Role: msg.FromRole},
+		{UserID: msg.ToUserID, Role: msg.ToRole},
+	}
+	if !msg.JobID.IsZero() {
+		id := msg.JobID
+		jobID = &id
+	}
+	return conversationKey(msg.FromUserID, msg.ToUserID, msg.JobID), participants, jobID, false
+}
+
+// supportStatusAfter is the status a support thread takes after msg: open
+// when the requester writes, pending on them when an admin does.
+func supportStatusAfter(msg models.Message) string {
+	if msg.FromRole == models.RoleAdmin {
+		return models.SupportPending
+	}
+	return models.SupportOpen
+}
+
+func conversationPreview(text string) string {
+	runes := []rune(text)
+	if len(runes) <= conversationPreviewLength {
+		return text
+	}
+	return string(runes[:conversationPreviewLength]) + "…"
+}
+
+// touchConversation files msg in its conversation: it starts the
+// conversation if needed, moves the last message forward and counts the
+// message as unread for the recipient. Admins' unread support messages are
+// counted from the messages themselves (see SupportUnreadCounts), and a
+// support thread's status follows supportStatusAfter.
+func (s *MessageService) touchConversation(ctx context.Context, msg models.Message, now time.Time) (models.Conversation, error) {
+	key, participants, jobID, support := conversationIdentity(msg)
+	preview := conversationPreview(msg.Message)
+	countUnread := msg.ToRole != models.RoleAdmin
+
+	if s.convs == nil {
+		conversationMemory.Lock()
+		defer conversationMemory.Unlock()
+		var conv models.Conversation
+		found := false
+		for _, c := range conversationMemory.data {
+			if c.Key == key {
+				conv, found = c, true
+				break
+			}
+		}
+		if !found {
+			conv = models.Conversation{ID: primitive.NewObjectID(), Key: key, Participants: participants, JobID: jobID, Unread: map[string]int{}, CreatedAt: now}
+		}
+		conv.LastMessage = preview
+		conv.LastSenderID = msg.FromUserID
+		conv.LastMessageAt = now
+		conv.UpdatedAt = now
+		if countUnread {
+			conv.Unread[msg.ToUserID.Hex()]++
+		}
+		if support {
+			thread := models.SupportThread{}
+			if conv.Support != nil {
+				thread = *conv.Support
+			}
+			thread.Status = supportStatusAfter(msg)
+			conv.Support = &thread
+		}
+		conversationMemory.data[conv.ID.Hex()] = conv
+		return conv, nil
+	}
+
+	onInsert := bson.M{"key": key, "participants": participants, "created_at": now}
+	if jobID != nil {
+		onInsert["job_id"] = *jobID
+	}
+	set := bson.M{"last_message": preview, "last_sender_id": msg.FromUserID, "last_message_at": now, "updated_at": now}
+	if support {
+		set["support.status"] = supportStatusAfter(msg)
+	}
+	update := bson.M{"$setOnInsert": onInsert, "$set": set}
+	if countUnread {
+		update["$inc"] = bson.M{"unread." + msg.ToUserID.Hex(): 1}
+	} else {
+		onInsert["unread"] = bson.M{}
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var conv models.Conversation
+	err := s.convs.FindOneAndUpdate(ctx, bson.M{"key": key}, update, opts).Decode(&conv)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another request started the conversation concurrently; it exists now.
+		err = s.convs.FindOneAndUpdate(ctx, bson.M{"key": key}, update, opts).Decode(&conv)
+	}
+	return conv, err
+}
+
+// conversationRead takes n messages to msg's recipient off the conversation's
+// unread count, never going below zero.
+func (s *MessageService) conversationRead(ctx context.Context, msg models.Message, n int) {
+	if msg.ConversationID == nil || n <= 0 || msg.ToRole == models.RoleAdmin {
+		return
+	}
+	field := "unread." + msg.ToUserID.Hex()
+	if s.convs == nil {
+		conversationMemory.Lock()
+		defer conversationMemory.Unlock()
+		conv, ok := conversationMemory.data[msg.ConversationID.Hex()]
+		if !ok {
+			return
+		}
+		conv.Unread[msg.ToUserID.Hex()] -= n
+		if conv.Unread[msg.ToUserID.Hex()] < 0 {
+			conv.Unread[msg.ToUserID.Hex()] = 0
+		}
+		conversationMemory.data[msg.ConversationID.Hex()] = conv
+		return
+	}
+	_, err := s.convs.UpdateOne(ctx, bson.M{"_id": *msg.ConversationID}, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{field: bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$" + field, n}}}}}}},
+	})
+	if err != nil {
+		log.Printf("conversation %s unread update failed: %v", msg.ConversationID.Hex(), err)
+	}
+}
+
+// FindConversation returns a conversation by id.
+func (s *MessageService) FindConversation(ctx context.Context, id primitive.ObjectID) (models.Conversation, error) {
+	if s.convs == nil {
+		conversationMemory.Lock()
+		defer conversationMemory.Unlock()
+		conv, ok := conversationMemory.data[id.Hex()]
+		if !ok {
+			return models.Conversation{}, mongo.ErrNoDocuments
+		}
+		return conv, nil
+	}
+	var conv models.Conversation
+	err := s.convs.FindOne(ctx, bson.M{"_id": id}).Decode(&conv)
+	return conv, err
+}
+
+// ListConversations returns a page (from 1) of userID's conversations, most
+// recently active first, and the total number of conversations.
+func (s *MessageService) ListConversations(ctx context.Context, userID primitive.ObjectID, page, limit int) ([]models.Conversation, int64, error) {
+	if s.convs == nil {
+		conversationMemory.Lock()
+		defer conversationMemory.Unlock()
+		convs := make([]models.Conversation, 0)
+		for _, c := range conversationMemory.data {
+			if _, ok := c.Participant(userID); ok {
+				convs = append(convs, c)
+			}
+		}
+		sort.Slice(convs, func(i, j int) bool { return convs[i].LastMessageAt.After(convs[j].LastMessageAt) })
+		total := int64(len(convs))
+		start := (page - 1) * limit
+		if start > len(convs) {
+			start = len(convs)
+		}
+		end := start + limit
+		if end > len(convs) {
+			end = len(convs)
+		}
+		return convs[start:end], total, nil
+	}
+	filter := bson.M{"participants.user_id": userID}
+	total, err := s.convs.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "last_message_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := s.convs.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	convs := make([]models.Conversation, 0)
+	if err := cursor.All(ctx, &convs); err != nil {
+		return nil, 0, err
+	}
+	return convs, total, nil
+}
+
+// ConversationMessages returns up to limit messages of a conversation older
+// than the message before (all messages when before is zero), oldest first,
+// leaving out those viewer deleted. hasMore reports whether even older
+// messages exist; page back by passing the first returned message's id as
+// before.
+func (s *MessageService) ConversationMessages(ctx context.Context, convID, viewer, before primitive.ObjectID, limit int) ([]models.Message, bool, error) {
+	var messages []models.Message
+	if s.col == nil {
+		messageMemory.Lock()
+		for _, m := range messageMemory.data {
+			if m.ConversationID != nil && *m.ConversationID == convID && !containsID(m.DeletedBy, viewer) && (before.IsZero() || m.ID.Hex() < before.Hex()) {
+				messages = append(messages, m)
+			}
+		}
+		messageMemory.Unlock()
+		sort.Slice(messages, func(i, j int) bool { return messages[i].ID.Hex() > messages[j].ID.Hex() })
+		if len(messages) > limit+1 {
+			messages = messages[:limit+1]
+		}
+	} else {
+		filter := bson.M{"conversation_id": convID, "deleted_by": bson.M{"$ne": viewer}}
+		if !before.IsZero() {
+			filter["_id"] = bson.M{"$lt": before}
+		}
+		opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(limit + 1))
+		cursor, err := s.col.Find(ctx, filter, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		defer cursor.Close(ctx)
+		if err := cursor.All(ctx, &messages); err != nil {
+			return nil, false, err
+		}
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	if messages == nil {
+		messages = []models.Message{}
+	}
+	return messages, hasMore, nil
+}
+
+// MarkConversationRead marks every unread message to userID in a
+// conversation as read and returns how many changed. OnRead listeners run for
+// each of them.
+func (s *MessageService) MarkConversationRead(ctx context.Context, convID, userID primitive.ObjectID) (int, error) {
+	return s.markRead(ctx,
+		bson.M{"conversation_id": convID, "to_user_id": userID, "is_read": false},
+		func(m models.Message) bool {
+			return m.ConversationID != nil && *m.ConversationID == convID && m.ToUserID == userID && !m.IsRead
+		})
+}
+
+// markRead marks the unread messages matching filter (match in memory) as
+// read, updates their conversations' unread counts and runs OnRead listeners.
+func (s *MessageService) markRead(ctx context.Context, filter bson.M, match func(models.Message) bool) (int, error) {
+	now := time.Now()
+	var read []models.Message
+	if s.col == nil {
+		messageMemory.Lock()
+		for id, m := range messageMemory.data {
+			if match(m) {
+				m.IsRead = true
+				m.ReadAt = &now
+				messageMemory.data[id] = m
+				read = append(read, m)
+			}
+		}
+		messageMemory.Unlock()
+	} else {
+		cursor, err := s.col.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return 0, err
+		}
+		var ids []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		err = cursor.All(ctx, &ids)
+		cursor.Close(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if len(ids) == 0 {
+			return 0, nil
+		}
+		oids := make([]primitive.ObjectID, len(ids))
+		for i, id := range ids {
+			oids[i] = id.ID
+		}
+		if read, err = s.setRead(ctx, oids, now); err != nil {
+			return 0, err
+		}
+	}
+
+	perConversation := map[primitive.ObjectID][]models.Message{}
+	for _, msg := range read {
+		if msg.ConversationID != nil {
+			perConversation[*msg.ConversationID] = append(perConversation[*msg.ConversationID], msg)
+		}
+	}
+	for _, msgs := range perConversation {
+		s.conversationRead(ctx, msgs[0], len(msgs))
+	}
+	for _, msg := range read {
+		s.notify(ctx, s.onRead, msg)
+	}
+	return len(read), nil
+}
+
+// setRead marks the unread messages among ids as read at now and returns
+// them.
+func (s *MessageService) setRead(ctx context.Context, ids []primitive.ObjectID, now time.Time) ([]models.Message, error) {
+	// Re-check is_read so messages read concurrently are not reported twice.
+	if _, err := s.col.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "is_read": false},
+		bson.M{"$set": bson.M{"is_read": true, "read_at": now}}); err != nil {
+		return nil, err
+	}
+	cursor, err := s.col.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "read_at": now})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var read []models.Message
+	if err := cursor.All(ctx, &read); err != nil {
+		return nil, err
+	}
+	return read, nil
+}
+
+// conversationBackfill accumulates one conversation's legacy messages.
+type conversationBackfill struct {
+	first, last models.Message
+	unread      map[string]int
+	ids         []primitive.ObjectID
+}
+
+// MigrateConversations files messages written before conversations existed
+// into conversations, setting their last message and unread counts. Admin
+// messages filed in a conversation with one admin, before support threads
+// existed, are refiled into the user's support thread. It returns the number
+// of messages migrated and is safe to run on every start.
+func (s *MessageService) MigrateConversations(ctx context.Context) (int, error) {
+	if s.col == nil {
+		// In-memory messages are filed on creation.
+		return 0, nil
+	}
+	if err := s.unfileAdminConversations(ctx); err != nil {
+		return 0, err
+	}
+	filter := bson.M{"conversation_id": bson.M{"$exists": false}, "from_user_id": bson.M{"$ne": primitive.NilObjectID}}
+	cursor, err := s.col.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+	backfills := map[string]*conversationBackfill{}
+	for cursor.Next(ctx) {
+		var msg models.Message
+		if err := cursor.Decode(&msg); err != nil {
+			return 0, err
+		}
+		key, _, _, _ := conversationIdentity(msg)
+		b, ok := backfills[key]
+		if !ok {
+			b = &conversationBackfill{first: msg, unread: map[string]int{}}
+			backfills[key] = b
+		}
+		b.last = msg
+		if !msg.IsRead && msg.ToRole != models.RoleAdmin {
+			b.unread["unread."+msg.ToUserID.Hex()]++
+		}
+		b.ids = append(b.ids, msg.ID)
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for key, b := range backfills {
+		_, participants, jobID, support := conversationIdentity(b.first)
+		onInsert := bson.M{
+			"key":             key,
+			"participants":    participants,
+			"created_at":      b.first.CreatedAt,
+			"last_message_at": time.Time{},
+		}
+		if jobID != nil {
+			onInsert["job_id"] = *jobID
+		}
+		if support {
+			onInsert["support"] = bson.M{"status": supportStatusAfter(b.last)}
+		}
+		update := bson.M{"$setOnInsert": onInsert}
+		if len(b.unread) > 0 {
+			update["$inc"] = b.unread
+		}
+		var conv models.Conversation
+		err := s.convs.FindOneAndUpdate(ctx, bson.M{"key": key}, update,
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&conv)
+		if err != nil {
+			return migrated, err
+		}
+		// Legacy messages only become the last message if nothing newer exists.
+		set := bson.M{
+			"last_message":    conversationPreview(b.last.Message),
+			"last_sender_id":  b.last.FromUserID,
+			"last_message_at": b.last.CreatedAt,
+			"updated_at":      time.Now(),
+		}
+		if support {
+			set["support.status"] = supportStatusAfter(b.last)
+		}
+		if _, err := s.convs.UpdateOne(ctx,
+			bson.M{"_id": conv.ID, "last_message_at": bson.M{"$lt": b.last.CreatedAt}},
+			bson.M{"$set": set}); err != nil {
+			return migrated, err
+		}
+		res, err := s.col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": b.ids}}, bson.M{"$set": bson.M{"conversation_id": conv.ID}})
+		if err != nil {
+			return migrated, err
+		}
+		migrated += int(res.ModifiedCount)
+	}
+	return migrated, nil
+}
+
+// unfileAdminConversations removes two-person conversations with an admin and
+// detaches their messages so they are refiled into support threads.
+func (s *MessageService) unfileAdminConversations(ctx context.Context) error {
+	cursor, err := s.convs.Find(ctx,
+		bson.M{"participants.role": models.RoleAdmin, "support": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err = cursor.All(ctx, &docs)
+	cursor.Close(ctx)
+	if err != nil || len(docs) == 0 {
+		return err
+	}
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, d := range docs {
+		ids[i] = d.ID
+	}
+	if _, err := s.col.UpdateMany(ctx, bson.M{"conversation_id": bson.M{"$in": ids}}, bson.M{"$unset": bson.M{"conversation_id": ""}}); err != nil {
+		return err
+	}
+	_, err = s.convs.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+// EnsureIndexes creates the conversation indexes (one conversation per
+// participant pair and job, listing by participant, the support queue,
+// thread history and admin unread counts) and allows one report per message
+// and reporter.
+func (s *MessageService) EnsureIndexes(ctx context.Context) error {
+	if s.col == nil {
+		return nil
+	}
+	if _, err := s.convs.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "participants.user_id", Value: 1}, {Key: "last_message_at", Value: -1}}},
+		{Keys: bson.D{{Key: "support.status", Value: 1}, {Key: "support.assignee_id", Value: 1}, {Key: "last_message_at", Value: -1}}, Options: options.Index().SetSparse(true)},
+	}); err != nil {
+		return err
+	}
+	if _, err := s.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "conversation_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "to_role", Value: 1}, {Key: "to_user_id", Value: 1}, {Key: "read_by", Value: 1}}},
+	}); err != nil {
+		return err
+	}
+	_, err := s.reports.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "message_id", Value: 1}, {Key: "reporter_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+var (
+	// ErrAlreadyReported is returned when a user reports the same message twice.
+	ErrAlreadyReported = errors.New("message already reported")
+	// ErrReportReviewed is returned when reviewing a report that is not open.
+	ErrReportReviewed = errors.New("report already reviewed")
+	// ErrInvalidReportStatus is returned for a review outcome other than
+	// resolved or dismissed.
+	ErrInvalidReportStatus = errors.New("report status must be RESOLVED or DISMISSED")
+)
+
+var messageReportMemory = struct {
+	sync.Mutex
+	data map[string]models.MessageReport
+}{data: map[string]models.MessageReport{}}
+
+func containsID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isRecipient reports whether userID received msg. Messages to admins belong
+// to the support thread's assignee, or to every admin while the thread waits
+// in the shared queue (addressed to no one).
+func isRecipient(msg models.Message, userID primitive.ObjectID, role string) bool {
+	if role == models.RoleAdmin {
+		return msg.ToRole == models.RoleAdmin && (msg.ToUserID == userID || msg.ToUserID.IsZero())
+	}
+	return msg.ToUserID == userID
+}
+
+func isParticipant(msg models.Message, userID primitive.ObjectID, role string) bool {
+	return msg.FromUserID == userID || isRecipient(msg, userID, role)
+}
+
+// CanAccessMessage reports whether userID sent or received msg and has not
+// deleted it.
+func CanAccessMessage(msg models.Message, userID primitive.ObjectID, role string) bool {
+	return isParticipant(msg, userID, role) && !containsID(msg.DeletedBy, userID)
+}
+
+// recipientFilter is isRecipient as a query.
+func recipientFilter(userID primitive.ObjectID, role string) bson.M {
+	if role == models.RoleAdmin {
+		return bson.M{"to_role": models.RoleAdmin, "to_user_id": bson.M{"$in": bson.A{userID, primitive.NilObjectID}}}
+	}
+	return bson.M{"to_user_id": userID}
+}
+
+// participantFilter is isParticipant as a query.
+func participantFilter(userID primitive.ObjectID, role string) bson.M {
+	return bson.M{"$or": bson.A{bson.M{"from_user_id": userID}, recipientFilter(userID, role)}}
+}
+
+// visibleTo reports whether msg belongs in viewer's inbox (archived selects
+// the archive instead). Deleted messages are never shown.
+func visibleTo(msg models.Message, viewer primitive.ObjectID, archived bool) bool {
+	return !containsID(msg.DeletedBy, viewer) && containsID(msg.ArchivedBy, viewer) == archived
+}
+
+// visibilityFilter is visibleTo as a query.
+func visibilityFilter(viewer primitive.ObjectID, archived bool) bson.M {
+	filter := bson.M{"deleted_by": bson.M{"$ne": viewer}}
+	if archived {
+		filter["archived_by"] = viewer
+	} else {
+		filter["archived_by"] = bson.M{"$ne": viewer}
+	}
+	return filter
+}
+
+// deletedByAll reports whether both the sender and the recipient deleted msg.
+// A message to admins stays visible to whichever admin handles its thread,
+// including ones assigned later, so it is never deleted by all.
+func deletedByAll(msg models.Message) bool {
+	if msg.ToRole == models.RoleAdmin {
+		return false
+	}
+	return containsID(msg.DeletedBy, msg.FromUserID) && containsID(msg.DeletedBy, msg.ToUserID)
+}
+
+// FindByID returns a message by id.
+func (s *MessageService) FindByID(ctx context.Context, id primitive.ObjectID) (models.Message, error) {
+	if s.col == nil {
+		messageMemory.Lock()
+		defer messageMemory.Unlock()
+		msg, ok := messageMemory.data[id.Hex()]
+		if !ok {
+			return models.Message{}, mongo.ErrNoDocuments
+		}
+		return msg, nil
+	}
+	var msg models.Message
+	err := s.col.FindOne(ctx, bson.M{"_id": id}).Decode(&msg)
+	return msg, err
+}
+
+// MarkAllRead marks every unread message to userID as read and returns how
+// many changed. For an admin that is their assigned threads and the shared
+// queue, read on their behalf only.
+func (s *MessageService) MarkAllRead(ctx context.Context, userID primitive.ObjectID, role string) (int, error) {
+	filter := recipientFilter(userID, role)
+	if role == models.RoleAdmin {
+		filter["deleted_by"] = bson.M{"$ne": userID}
+		return s.markAdminRead(ctx, userID, filter, func(m models.Message) bool {
+			return isRecipient(m, userID, role) && !containsID(m.DeletedBy, userID)
+		})
+	}
+	filter["is_read"] = false
+	filter["deleted_by"] = bson.M{"$ne": userID}
+	return s.markRead(ctx, filter, func(m models.Message) bool {
+		return isRecipient(m, userID, role) && !m.IsRead && !containsID(m.DeletedBy, userID)
+	})
+}
+
+// SetArchived moves a message in or out of userID's archive. The other party
+// keeps their copy as it is. It returns mongo.ErrNoDocuments when the message
+// does not exist, was deleted by userID, or userID did not send or receive it.
+func (s *MessageService) SetArchived(ctx context.Context, messageID, userID primitive.ObjectID, role string, archived bool) error {
+	if s.col == nil {
+		messageMemory.Lock()
+		defer messageMemory.Unlock()
+		msg, ok := messageMemory.data[messageID.Hex()]
+		if !ok || !isParticipant(msg, userID, role) || containsID(msg.DeletedBy, userID) {
+			return mongo.ErrNoDocuments
+		}
+		kept := make([]primitive.ObjectID, 0, len(msg.ArchivedBy)+1)
+		for _, id := range msg.ArchivedBy {
+			if id != userID {
+				kept = append(kept, id)
+			}
+		}
+		if archived {
+			kept = append(kept, userID)
+		}
+		msg.ArchivedBy = kept
+		messageMemory.data[messageID.Hex()] = msg
+		return nil
+	}
+	filter := participantFilter(userID, role)
+	filter["_id"] = messageID
+	filter["deleted_by"] = bson.M{"$ne": userID}
+	update := bson.M{"$pull": bson.M{"archived_by": userID}}
+	if archived {
+		update = bson.M{"$addToSet": bson.M{"archived_by": userID}}
+	}
+	res, err := s.col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Delete removes a message from userID's inboxes and threads only; the other
+// party keeps their copy. Deleting an unread message received by userID
+// takes it off their unread counts, and OnDelete listeners run once both
+// sides deleted it; never for messages to admins. It returns mongo.ErrNoDocuments like SetArchived.
+func (s *MessageService) Delete(ctx context.Context, messageID, userID primitive.ObjectID, role string) error {
+	var msg models.Message
+	if s.col == nil {
+		messageMemory.Lock()
+		var ok bool
+		msg, ok = messageMemory.data[messageID.Hex()]
+		if !ok || !isParticipant(msg, userID, role) || containsID(msg.DeletedBy, userID) {
+			messageMemory.Unlock()
+			return mongo.ErrNoDocuments
+		}
+		msg.DeletedBy = append(append([]primitive.ObjectID{}, msg.DeletedBy...), userID)
+		messageMemory.data[messageID.Hex()] = msg
+		messageMemory.Unlock()
+	} else {
+		filter := participantFilter(userID, role)
+		filter["_id"] = messageID
+		filter["deleted_by"] = bson.M{"$ne": userID}
+		err := s.col.FindOneAndUpdate(ctx, filter, bson.M{"$addToSet": bson.M{"deleted_by": userID}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&msg)
+		if err != nil {
+			return err
+		}
+	}
+	if !msg.IsRead && msg.ToUserID == userID {
+		s.conversationRead(ctx, msg, 1)
+	}
+	if deletedByAll(msg) {
+		s.notify(ctx, s.onDelete, msg)
+	}
+	return nil
+}
+
+// Report flags a message received by reporterID as abusive for admins to
+// review. It returns mongo.ErrNoDocuments when the message does not exist or
+// was not sent to reporterID, and ErrAlreadyReported on a repeat report.
+func (s *MessageService) Report(ctx context.Context, messageID, reporterID primitive.ObjectID, role, reason string) (models.MessageReport, error) {
+	msg, err := s.FindByID(ctx, messageID)
+	if err != nil {
+		return models.MessageReport{}, err
+	}
+	if !isRecipient(msg, reporterID, role) {
+		return models.MessageReport{}, mongo.ErrNoDocuments
+	}
+	report := models.MessageReport{
+		MessageID:  messageID,
+		ReporterID: reporterID,
+		SenderID:   msg.FromUserID,
+		Reason:     reason,
+		Status:     models.ReportOpen,
+		CreatedAt:  time.Now(),
+	}
+	if s.reports == nil {
+		messageReportMemory.Lock()
+		defer messageReportMemory.Unlock()
+		for _, r := range messageReportMemory.data {
+			if r.MessageID == messageID && r.ReporterID == reporterID {
+				return models.MessageReport{}, ErrAlreadyReported
+			}
+		}
+		report.ID = primitive.NewObjectID()
+		messageReportMemory.data[report.ID.Hex()] = report
+		return report, nil
+	}
+	res, err := s.reports.InsertOne(ctx, report)
+	if mongo.IsDuplicateKeyError(err) {
+		return models.MessageReport{}, ErrAlreadyReported
+	}
+	if err != nil {
+		return models.MessageReport{}, err
+	}
+	report.ID = res.InsertedID.(primitive.ObjectID)
+	return report, nil
+}
+
+// ListReports returns message reports with the given status (all when empty),
+// newest first.
+func (s *MessageService) ListReports(ctx context.Context, status string) ([]models.MessageReport, error) {
+	reports := make([]models.MessageReport, 0)
+	if s.reports == nil {
+		messageReportMemory.Lock()
+		for _, r := range messageReportMemory.data {
+			if status == "" || r.Status == status {
+				reports = append(reports, r)
+			}
+		}
+		messageReportMemory.Unlock()
+		sort.Slice(reports, func(i, j int) bool { return reports[i].CreatedAt.After(reports[j].CreatedAt) })
+		return reports, nil
+	}
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	cursor, err := s.reports.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// ReviewReport closes an open report as RESOLVED or DISMISSED.
+func (s *MessageService) ReviewReport(ctx context.Context, reportID, adminID primitive.ObjectID, status, note string) (models.MessageReport, error) {
+	if status != models.ReportResolved && status != models.ReportDismissed {
+		return models.MessageReport{}, ErrInvalidReportStatus
+	}
+	now := time.Now()
+	if s.reports == nil {
+		messageReportMemory.Lock()
+		defer messageReportMemory.Unlock()
+		report, ok := messageReportMemory.data[reportID.Hex()]
+		if !ok {
+			return models.MessageReport{}, mongo.ErrNoDocuments
+		}
+		if report.Status != models.ReportOpen {
+			return models.MessageReport{}, ErrReportReviewed
+		}
+		report.Status = status
+		report.ReviewNote = note
+		report.ReviewedBy = &adminID
+		report.ReviewedAt = &now
+		messageReportMemory.data[reportID.Hex()] = report
+		return report, nil
+	}
+	var report models.MessageReport
+	err := s.reports.FindOneAndUpdate(ctx,
+		bson.M{"_id": reportID, "status": models.ReportOpen},
+		bson.M{"$set": bson.M{"status": status, "review_note": note, "reviewed_by": adminID, "reviewed_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&report)
+	if err == mongo.ErrNoDocuments {
+		if n, cerr := s.reports.CountDocuments(ctx, bson.M{"_id": reportID}); cerr == nil && n > 0 {
+			return models.MessageReport{}, ErrReportReviewed
+		}
+	}
+	return report, err
+}
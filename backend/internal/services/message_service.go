@@ -12,9 +12,14 @@ import (
 	"rizeos/backend/internal/models"
 )
 
-// MessageService handles message persistence.
+// MessageService handles message and conversation persistence.
 type MessageService struct {
-	col *mongo.Collection
+	col      *mongo.Collection
+	convs    *mongo.Collection
+	reports  *mongo.Collection
+	onSend   []func(ctx context.Context, msg models.Message)
+	onRead   []func(ctx context.Context, msg models.Message)
+	onDelete []func(ctx context.Context, msg models.Message)
 }
 
 var messageMemory = struct {
@@ -27,18 +32,33 @@ func NewMessageService(db *mongo.Database) *MessageService {
 	if db == nil {
 		return &MessageService{col: nil}
 	}
-	return &MessageService{col: db.Collection("messages")}
+	return &MessageService{col: db.Collection("messages"), convs: db.Collection("conversations"), reports: db.Collection("message_reports")}
 }
 
-// Create inserts a new message.
+// Create inserts a new message and files it in the conversation between
+// sender and recipient about msg.JobID, starting one if needed. Announcements
+// (no sender) stay outside conversations. Messages to admins go to the
+// sender's support thread and are addressed to its assignee, if any.
 func (s *MessageService) Create(ctx context.Context, msg models.Message) (models.Message, error) {
+	msg.ConversationID = nil
+	if !msg.FromUserID.IsZero() {
+		conv, err := s.touchConversation(ctx, msg, time.Now())
+		if err != nil {
+			return models.Message{}, err
+		}
+		msg.ConversationID = &conv.ID
+		if msg.ToRole == models.RoleAdmin && conv.Support != nil && conv.Support.AssigneeID != nil {
+			msg.ToUserID = *conv.Support.AssigneeID
+		}
+	}
 	if s.col == nil {
 		messageMemory.Lock()
-		defer messageMemory.Unlock()
 		msg.ID = primitive.NewObjectID()
 		msg.IsRead = false
 		msg.CreatedAt = time.Now()
 		messageMemory.data[msg.ID.Hex()] = msg
+		messageMemory.Unlock()
+		s.notify(ctx, s.onSend, msg)
 		return msg, nil
 	}
 	msg.IsRead = false
@@ -48,17 +68,72 @@ func (s *MessageService) Create(ctx context.Context, msg models.Message) (models
 		return models.Message{}, err
 	}
 	msg.ID = res.InsertedID.(primitive.ObjectID)
+	s.notify(ctx, s.onSend, msg)
 	return msg, nil
 }
 
-// GetAdminInbox returns all messages for admin, sorted by latest first.
-func (s *MessageService) GetAdminInbox(ctx context.Context) ([]models.Message, error) {
+// OnSend registers fn to run after a message is stored.
+func (s *MessageService) OnSend(fn func(ctx context.Context, msg models.Message)) {
+	s.onSend = append(s.onSend, fn)
+}
+
+// OnRead registers fn to run after an unread message is marked as read.
+func (s *MessageService) OnRead(fn func(ctx context.Context, msg models.Message)) {
+	s.onRead = append(s.onRead, fn)
+}
+
+// OnDelete registers fn to run once both sides have deleted a message, so
+// its attachments can be removed.
+func (s *MessageService) OnDelete(fn func(ctx context.Context, msg models.Message)) {
+	s.onDelete = append(s.onDelete, fn)
+}
+
+func (s *MessageService) notify(ctx context.Context, fns []func(context.Context, models.Message), msg models.Message) {
+	for _, fn := range fns {
+		fn(ctx, msg)
+	}
+}
+
+// MarkDelivered stamps delivered_at the first time a message reaches the
+// recipient. It returns false when the message was already delivered or
+// does not exist.
+func (s *MessageService) MarkDelivered(ctx context.Context, messageID primitive.ObjectID, at time.Time) (models.Message, bool, error) {
+	if s.col == nil {
+		messageMemory.Lock()
+		defer messageMemory.Unlock()
+		msg, ok := messageMemory.data[messageID.Hex()]
+		if !ok || msg.DeliveredAt != nil {
+			return msg, false, nil
+		}
+		msg.DeliveredAt = &at
+		messageMemory.data[messageID.Hex()] = msg
+		return msg, true, nil
+	}
+	var msg models.Message
+	err := s.col.FindOneAndUpdate(ctx,
+		bson.M{"_id": messageID, "delivered_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"delivered_at": at}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&msg)
+	if err == mongo.ErrNoDocuments {
+		return models.Message{}, false, nil
+	}
+	if err != nil {
+		return models.Message{}, false, err
+	}
+	return msg, true, nil
+}
+
+// GetAdminInbox returns the messages to admins in support threads assigned to
+// adminID and in the shared queue, sorted by latest first. It leaves out
+// messages adminID deleted, and lists only those adminID archived when
+// archived is set.
+func (s *MessageService) GetAdminInbox(ctx context.Context, adminID primitive.ObjectID, archived bool) ([]models.Message, error) {
 	if s.col == nil {
 		messageMemory.Lock()
 		defer messageMemory.Unlock()
 		messages := make([]models.Message, 0, len(messageMemory.data))
 		for _, m := range messageMemory.data {
-			if m.ToRole == models.RoleAdmin {
+			if isRecipient(m, adminID, models.RoleAdmin) && visibleTo(m, adminID, archived) {
 				messages = append(messages, m)
 			}
 		}
@@ -73,7 +148,11 @@ func (s *MessageService) GetAdminInbox(ctx context.Context) ([]models.Message, e
 		return messages, nil
 	}
 	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := s.col.Find(ctx, bson.M{"to_role": models.RoleAdmin}, opts)
+	filter := visibilityFilter(adminID, archived)
+	for k, v := range recipientFilter(adminID, models.RoleAdmin) {
+		filter[k] = v
+	}
+	cursor, err := s.col.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -85,14 +164,15 @@ func (s *MessageService) GetAdminInbox(ctx context.Context) ([]models.Message, e
 	return messages, nil
 }
 
-// GetRecruiterInbox returns all messages for a specific recruiter, sorted by latest first.
-func (s *MessageService) GetRecruiterInbox(ctx context.Context, recruiterID primitive.ObjectID) ([]models.Message, error) {
+// GetRecruiterInbox returns all messages for a specific recruiter, sorted by
+// latest first, with the same archive handling as GetAdminInbox.
+func (s *MessageService) GetRecruiterInbox(ctx context.Context, recruiterID primitive.ObjectID, archived bool) ([]models.Message, error) {
 	if s.col == nil {
 		messageMemory.Lock()
 		defer messageMemory.Unlock()
 		messages := make([]models.Message, 0, len(messageMemory.data))
 		for _, m := range messageMemory.data {
-			if m.ToRole == models.RoleRecruiter && m.ToUserID == recruiterID {
+			if m.ToRole == models.RoleRecruiter && m.ToUserID == recruiterID && visibleTo(m, recruiterID, archived) {
 				messages = append(messages, m)
 			}
 		}
@@ -107,10 +187,10 @@ func (s *MessageService) GetRecruiterInbox(ctx context.Context, recruiterID prim
 		return messages, nil
 	}
 	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := s.col.Find(ctx, bson.M{
-		"to_role":   models.RoleRecruiter,
-		"to_user_id": recruiterID,
-	}, opts)
+	filter := visibilityFilter(recruiterID, archived)
+	filter["to_role"] = models.RoleRecruiter
+	filter["to_user_id"] = recruiterID
+	cursor, err := s.col.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -122,14 +202,15 @@ func (s *MessageService) GetRecruiterInbox(ctx context.Context, recruiterID prim
 	return messages, nil
 }
 
-// GetSeekerInbox returns all messages for a specific job seeker, sorted by latest first.
-func (s *MessageService) GetSeekerInbox(ctx context.Context, seekerID primitive.ObjectID) ([]models.Message, error) {
+// GetSeekerInbox returns all messages for a specific job seeker, sorted by
+// latest first, with the same archive handling as GetAdminInbox.
+func (s *MessageService) GetSeekerInbox(ctx context.Context, seekerID primitive.ObjectID, archived bool) ([]models.Message, error) {
 	if s.col == nil {
 		messageMemory.Lock()
 		defer messageMemory.Unlock()
 		messages := make([]models.Message, 0, len(messageMemory.data))
 		for _, m := range messageMemory.data {
-			if m.ToRole == models.RoleSeeker && m.ToUserID == seekerID {
+			if m.ToRole == models.RoleSeeker && m.ToUserID == seekerID && visibleTo(m, seekerID, archived) {
 				messages = append(messages, m)
 			}
 		}
@@ -144,10 +225,10 @@ func (s *MessageService) GetSeekerInbox(ctx context.Context, seekerID primitive.
 		return messages, nil
 	}
 	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := s.col.Find(ctx, bson.M{
-		"to_role":   models.RoleSeeker,
-		"to_user_id": seekerID,
-	}, opts)
+	filter := visibilityFilter(seekerID, archived)
+	filter["to_role"] = models.RoleSeeker
+	filter["to_user_id"] = seekerID
+	cursor, err := s.col.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -166,16 +247,17 @@ func (s *MessageService) GetSeekerUnreadCount(ctx context.Context, seekerID prim
 		defer messageMemory.Unlock()
 		count := int64(0)
 		for _, m := range messageMemory.data {
-			if m.ToRole == models.RoleSeeker && m.ToUserID == seekerID && !m.IsRead {
+			if m.ToRole == models.RoleSeeker && m.ToUserID == seekerID && !m.IsRead && !containsID(m.DeletedBy, seekerID) {
 				count++
 			}
 		}
 		return count, nil
 	}
 	count, err := s.col.CountDocuments(ctx, bson.M{
-		"to_role":   models.RoleSeeker,
+		"to_role":    models.RoleSeeker,
 		"to_user_id": seekerID,
-		"is_read":   false,
+		"is_read":    false,
+		"deleted_by": bson.M{"$ne": seekerID},
 	})
 	return count, err
 }
@@ -187,52 +269,87 @@ func (s *MessageService) GetRecruiterUnreadCount(ctx context.Context, recruiterI
 		defer messageMemory.Unlock()
 		count := int64(0)
 		for _, m := range messageMemory.data {
-			if m.ToRole == models.RoleRecruiter && m.ToUserID == recruiterID && !m.IsRead {
+			if m.ToRole == models.RoleRecruiter && m.ToUserID == recruiterID && !m.IsRead && !containsID(m.DeletedBy, recruiterID) {
 				count++
 			}
 		}
 		return count, nil
 	}
 	count, err := s.col.CountDocuments(ctx, bson.M{
-		"to_role":   models.RoleRecruiter,
+		"to_role":    models.RoleRecruiter,
 		"to_user_id": recruiterID,
-		"is_read":   false,
+		"is_read":    false,
+		"deleted_by": bson.M{"$ne": recruiterID},
 	})
 	return count, err
 }
 
-// MarkAsRead marks a message as read.
-func (s *MessageService) MarkAsRead(ctx context.Context, messageID primitive.ObjectID) error {
+// MarkAsRead marks a message as read on behalf of its recipient; admins read
+// messages to admins for themselves only (see isRecipient). It returns
+// mongo.ErrNoDocuments when the message does not exist or readerID is not its
+// recipient. OnRead listeners run only when the message was unread.
+func (s *MessageService) MarkAsRead(ctx context.Context, messageID, readerID primitive.ObjectID, readerRole string) error {
+	if readerRole == models.RoleAdmin {
+		filter := recipientFilter(readerID, readerRole)
+		filter["_id"] = messageID
+		n, err := s.markAdminRead(ctx, readerID, filter, func(m models.Message) bool {
+			return m.ID == messageID && isRecipient(m, readerID, readerRole)
+		})
+		if err != nil || n > 0 {
+			return err
+		}
+		// Already read is fine; missing or someone else's is not.
+		msg, err := s.FindByID(ctx, messageID)
+		if err != nil {
+			return err
+		}
+		if !isRecipient(msg, readerID, readerRole) {
+			return mongo.ErrNoDocuments
+		}
+		return nil
+	}
+	now := time.Now()
 	if s.col == nil {
 		messageMemory.Lock()
-		defer messageMemory.Unlock()
-		if msg, ok := messageMemory.data[messageID.Hex()]; ok {
+		msg, ok := messageMemory.data[messageID.Hex()]
+		if !ok || !isRecipient(msg, readerID, readerRole) {
+			messageMemory.Unlock()
+			return mongo.ErrNoDocuments
+		}
+		changed := !msg.IsRead
+		if changed {
 			msg.IsRead = true
+			msg.ReadAt = &now
 			messageMemory.data[messageID.Hex()] = msg
 		}
+		messageMemory.Unlock()
+		if changed {
+			s.conversationRead(ctx, msg, 1)
+			s.notify(ctx, s.onRead, msg)
+		}
 		return nil
 	}
-	_, err := s.col.UpdateByID(ctx, messageID, bson.M{"$set": bson.M{"is_read": true}})
-	return err
-}
-
-// GetUnreadCount returns the count of unread messages for admin.
-func (s *MessageService) GetUnreadCount(ctx context.Context) (int64, error) {
-	if s.col == nil {
-		messageMemory.Lock()
-		defer messageMemory.Unlock()
-		count := int64(0)
-		for _, m := range messageMemory.data {
-			if m.ToRole == models.RoleAdmin && !m.IsRead {
-				count++
-			}
+	filter := recipientFilter(readerID, readerRole)
+	filter["_id"] = messageID
+	filter["is_read"] = false
+	var msg models.Message
+	err := s.col.FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"is_read": true, "read_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&msg)
+	if err == mongo.ErrNoDocuments {
+		// Already read is fine; missing or someone else's is not.
+		delete(filter, "is_read")
+		if n, err := s.col.CountDocuments(ctx, filter); err != nil {
+			return err
+		} else if n == 0 {
+			return mongo.ErrNoDocuments
 		}
-		return count, nil
+		return nil
 	}
-	count, err := s.col.CountDocuments(ctx, bson.M{
-		"to_role": models.RoleAdmin,
-		"is_read": false,
-	})
-	return count, err
+	if err != nil {
+		return err
+	}
+	s.conversationRead(ctx, msg, 1)
+	s.notify(ctx, s.onRead, msg)
+	return nil
 }
-
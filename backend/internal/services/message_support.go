@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+// ErrInvalidSupportStatus is returned for a status other than the Support*
+// statuses.
+var ErrInvalidSupportStatus = errors.New("status must be OPEN, PENDING or RESOLVED")
+
+// SupportFilter selects support threads. Assignee and Unassigned are
+// exclusive; neither selects every thread.
+type SupportFilter struct {
+	Status     string // empty for any status
+	Assignee   *primitive.ObjectID
+	Unassigned bool
+}
+
+func (f SupportFilter) matches(conv models.Conversation) bool {
+	if conv.Support == nil || (f.Status != "" && conv.Support.Status != f.Status) {
+		return false
+	}
+	switch {
+	case f.Assignee != nil:
+		return conv.Support.AssigneeID != nil && *conv.Support.AssigneeID == *f.Assignee
+	case f.Unassigned:
+		return conv.Support.AssigneeID == nil
+	}
+	return true
+}
+
+func (f SupportFilter) query() bson.M {
+	filter := bson.M{"support": bson.M{"$exists": true}}
+	if f.Status != "" {
+		filter["support.status"] = f.Status
+	}
+	switch {
+	case f.Assignee != nil:
+		filter["support.assignee_id"] = *f.Assignee
+	case f.Unassigned:
+		filter["support.assignee_id"] = bson.M{"$exists": false}
+	}
+	return filter
+}
+
+// FindSupportThread returns a support conversation by id, or
+// mongo.ErrNoDocuments if it is not one.
+func (s *MessageService) FindSupportThread(ctx context.Context, id primitive.ObjectID) (models.Conversation, error) {
+	conv, err := s.FindConversation(ctx, id)
+	if err != nil {
+		return models.Conversation{}, err
+	}
+	if conv.Support == nil {
+		return models.Conversation{}, mongo.ErrNoDocuments
+	}
+	return conv, nil
+}
+
+// ListSupportThreads returns a page (from 1) of the support queue, most
+// recently active first, and the number of matching threads.
+func (s *MessageService) ListSupportThreads(ctx context.Context, f SupportFilter, page, limit int) ([]models.Conversation, int64, error) {
+	if s.convs == nil {
+		conversationMemory.Lock()
+		convs := make([]models.Conversation, 0)
+		for _, c := range conversationMemory.data {
+			if f.matches(c) {
+				convs = append(convs, c)
+			}
+		}
+		conversationMemory.Unlock()
+		sort.Slice(convs, func(i, j int) bool { return convs[i].LastMessageAt.After(convs[j].LastMessageAt) })
+		total := int64(len(convs))
+		start := (page - 1) * limit
+		if start > len(convs) {
+			start = len(convs)
+		}
+		end := start + limit
+		if end > len(convs) {
+			end = len(convs)
+		}
+		return convs[start:end], total, nil
+	}
+	filter := f.query()
+	total, err := s.convs.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "last_message_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := s.convs.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	convs := make([]models.Conversation, 0)
+	if err := cursor.All(ctx, &convs); err != nil {
+		return nil, 0, err
+	}
+	return convs, total, nil
+}
+
+// SupportUnread returns the number of messages to admins adminID has not read
+// in each of the given support threads.
+func (s *MessageService) SupportUnread(ctx context.Context, adminID primitive.ObjectID, convIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	unread := map[primitive.ObjectID]int{}
+	if len(convIDs) == 0 {
+		return unread, nil
+	}
+	if s.col == nil {
+		wanted := map[primitive.ObjectID]bool{}
+		for _, id := range convIDs {
+			wanted[id] = true
+		}
+		messageMemory.Lock()
+		defer messageMemory.Unlock()
+		for _, m := range messageMemory.data {
+			if m.ToRole == models.RoleAdmin && !m.ReadFor(adminID) && m.ConversationID != nil && wanted[*m.ConversationID] {
+				unread[*m.ConversationID]++
+			}
+		}
+		return unread, nil
+	}
+	cursor, err := s.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"conversation_id": bson.M{"$in": convIDs}, "to_role": models.RoleAdmin, "read_by": bson.M{"$ne": adminID}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$conversation_id", "n": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var rows []struct {
+		ID primitive.ObjectID `bson:"_id"`
+		N  int                `bson:"n"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		unread[r.ID] = r.N
+	}
+	return unread, nil
+}
+
+// SupportUnreadCounts returns the messages adminID has not read in support
+// threads assigned to them and in unassigned threads.
+func (s *MessageService) SupportUnreadCounts(ctx context.Context, adminID primitive.ObjectID) (assigned, queue int64, err error) {
+	if s.col == nil {
+		conversationMemory.Lock()
+		assignees := map[primitive.ObjectID]*primitive.ObjectID{}
+		for _, c := range conversationMemory.data {
+			if c.Support != nil {
+				assignees[c.ID] = c.Support.AssigneeID
+			}
+		}
+		conversationMemory.Unlock()
+		messageMemory.Lock()
+		defer messageMemory.Unlock()
+		for _, m := range messageMemory.data {
+			if m.ToRole != models.RoleAdmin || m.ReadFor(adminID) || m.ConversationID == nil {
+				continue
+			}
+			assignee, ok := assignees[*m.ConversationID]
+			switch {
+			case !ok:
+			case assignee == nil:
+				queue++
+			case *assignee == adminID:
+				assigned++
+			}
+		}
+		return assigned, queue, nil
+	}
+	cursor, err := s.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"to_role": models.RoleAdmin, "read_by": bson.M{"$ne": adminID}, "conversation_id": bson.M{"$exists": true}}}},
+		{{Key: "$lookup", Value: bson.M{"from": s.convs.Name(), "localField": "conversation_id", "foreignField": "_id", "as": "conv"}}},
+		{{Key: "$unwind", Value: "$conv"}},
+		{{Key: "$group", Value: bson.M{"_id": "$conv.support.assignee_id", "n": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+	var rows []struct {
+		Assignee *primitive.ObjectID `bson:"_id"`
+		N        int64               `bson:"n"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return 0, 0, err
+	}
+	for _, r := range rows {
+		switch {
+		case r.Assignee == nil:
+			queue += r.N
+		case *r.Assignee == adminID:
+			assigned += r.N
+		}
+	}
+	return assigned, queue, nil
+}
+
+// AssignSupport hands a support thread to an admin, or back to the shared
+// queue when adminID is nil, and readdresses the thread's messages to admins
+// accordingly. The caller checks that adminID is an admin.
+func (s *MessageService) AssignSupport(ctx context.Context, convID primitive.ObjectID, adminID *primitive.ObjectID) (models.Conversation, error) {
+	var conv models.Conversation
+	var err error
+	to := primitive.NilObjectID
+	if adminID == nil {
+		conv, err = s.updateSupport(ctx, convID, func(t *models.SupportThread) { t.AssigneeID = nil },
+			bson.M{"$unset": bson.M{"support.assignee_id": ""}, "$set": bson.M{"updated_at": time.Now()}})
+	} else {
+		to = *adminID
+		conv, err = s.updateSupport(ctx, convID, func(t *models.SupportThread) { t.AssigneeID = &to },
+			bson.M{"$set": bson.M{"support.assignee_id": to, "updated_at": time.Now()}})
+	}
+	if err != nil {
+		return conv, err
+	}
+	if s.col == nil {
+		messageMemory.Lock()
+		defer messageMemory.Unlock()
+		for id, m := range messageMemory.data {
+			if m.ToRole == models.RoleAdmin && m.ConversationID != nil && *m.ConversationID == convID {
+				m.ToUserID = to
+				messageMemory.data[id] = m
+			}
+		}
+		return conv, nil
+	}
+	_, err = s.col.UpdateMany(ctx,
+		bson.M{"conversation_id": convID, "to_role": models.RoleAdmin},
+		bson.M{"$set": bson.M{"to_user_id": to}})
+	return conv, err
+}
+
+// SetSupportStatus sets a support thread's status.
+func (s *MessageService) SetSupportStatus(ctx context.Context, convID primitive.ObjectID, status string) (models.Conversation, error) {
+	if status != models.SupportOpen && status != models.SupportPending && status != models.SupportResolved {
+		return models.Conversation{}, ErrInvalidSupportStatus
+	}
+	return s.updateSupport(ctx, convID, func(t *models.SupportThread) { t.Status = status },
+		bson.M{"$set": bson.M{"support.status": status, "updated_at": time.Now()}})
+}
+
+// AddSupportNote appends an internal admin note to a support thread.
+func (s *MessageService) AddSupportNote(ctx context.Context, convID, authorID primitive.ObjectID, text string) (models.SupportNote, error) {
+	note := models.SupportNote{ID: primitive.NewObjectID(), AuthorID: authorID, Note: text, CreatedAt: time.Now()}
+	_, err := s.updateSupport(ctx, convID, func(t *models.SupportThread) {
+		t.Notes = append(append([]models.SupportNote{}, t.Notes...), note)
+	}, bson.M{"$push": bson.M{"support.notes": note}, "$set": bson.M{"updated_at": note.CreatedAt}})
+	if err != nil {
+		return models.SupportNote{}, err
+	}
+	return note, nil
+}
+
+// MarkSupportRead marks every message to admins in a support thread as read
+// by adminID and returns how many changed.
+func (s *MessageService) MarkSupportRead(ctx context.Context, convID, adminID primitive.ObjectID) (int, error) {
+	return s.markAdminRead(ctx, adminID,
+		bson.M{"conversation_id": convID},
+		func(m models.Message) bool { return m.ConversationID != nil && *m.ConversationID == convID })
+}
+
+// markAdminRead marks the messages to admins matching filter (match in
+// memory) as read by adminID and returns how many changed. The first admin
+// to read a message also marks it read for its sender and runs OnRead
+// listeners.
+func (s *MessageService) markAdminRead(ctx context.Context, adminID primitive.ObjectID, filter bson.M, match func(models.Message) bool) (int, error) {
+	now := time.Now()
+	var first []models.Message
+	changed := 0
+	if s.col == nil {
+		messageMemory.Lock()
+		for id, m := range messageMemory.data {
+			if m.ToRole != models.RoleAdmin || m.ReadFor(adminID) || !match(m) {
+				continue
+			}
+			m.ReadBy = append(append([]primitive.ObjectID{}, m.ReadBy...), adminID)
+			if !m.IsRead {
+				m.IsRead = true
+				m.ReadAt = &now
+				first = append(first, m)
+			}
+			messageMemory.data[id] = m
+			changed++
+		}
+		messageMemory.Unlock()
+	} else {
+		filter["to_role"] = models.RoleAdmin
+		filter["read_by"] = bson.M{"$ne": adminID}
+		cursor, err := s.col.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return 0, err
+		}
+		var ids []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		err = cursor.All(ctx, &ids)
+		cursor.Close(ctx)
+		if err != nil || len(ids) == 0 {
+			return 0, err
+		}
+		oids := make([]primitive.ObjectID, len(ids))
+		for i, id := range ids {
+			oids[i] = id.ID
+		}
+		res, err := s.col.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": oids}, "read_by": bson.M{"$ne": adminID}},
+			bson.M{"$addToSet": bson.M{"read_by": adminID}})
+		if err != nil {
+			return 0, err
+		}
+		changed = int(res.ModifiedCount)
+		if first, err = s.setRead(ctx, oids, now); err != nil {
+			return changed, err
+		}
+	}
+	for _, msg := range first {
+		s.notify(ctx, s.onRead, msg)
+	}
+	return changed, nil
+}
+
+// MigrateAdminReads gives messages to admins read before admins kept separate
+// read state a read_by of every admin in adminIDs, so they stay read. It
+// returns the number of messages updated and is safe to run on every start.
+func (s *MessageService) MigrateAdminReads(ctx context.Context, adminIDs []primitive.ObjectID) (int64, error) {
+	if s.col == nil || len(adminIDs) == 0 {
+		return 0, nil
+	}
+	res, err := s.col.UpdateMany(ctx,
+		bson.M{"to_role": models.RoleAdmin, "is_read": true, "read_by": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_by": adminIDs}})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// updateSupport applies edit in memory or update in mongo to a support
+// thread and returns the updated thread.
+func (s *MessageService) updateSupport(ctx context.Context, convID primitive.ObjectID, edit func(*models.SupportThread), update bson.M) (models.Conversation, error) {
+	if s.convs == nil {
+		conversationMemory.Lock()
+		defer conversationMemory.Unlock()
+		conv, ok := conversationMemory.data[convID.Hex()]
+		if !ok || conv.Support == nil {
+			return models.Conversation{}, mongo.ErrNoDocuments
+		}
+		thread := *conv.Support
+		edit(&thread)
+		conv.Support = &thread
+		conv.UpdatedAt = time.Now()
+		conversationMemory.data[convID.Hex()] = conv
+		return conv, nil
+	}
+	var conv models.Conversation
+	err := s.convs.FindOneAndUpdate(ctx,
+		bson.M{"_id": convID, "support": bson.M{"$exists": true}},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&conv)
+	return conv, err
+}
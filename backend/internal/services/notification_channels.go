@@ -0,0 +1,154 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Mailer sends plain-text email.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// LogMailer writes email to the log instead of sending it; used when no
+// SMTP server is configured.
+type LogMailer struct{}
+
+// Send logs the email.
+func (LogMailer) Send(_ context.Context, to, subject, _ string) error {
+	log.Printf("mail to %s: %s", to, subject)
+	return nil
+}
+
+// SMTPMailer sends email through an SMTP server with PLAIN auth.
+type SMTPMailer struct {
+	Addr     string // host:port
+	Username string // empty skips auth
+	Password string
+	From     string
+}
+
+// Send delivers the email. The context is not used: net/smtp has no
+// cancellation.
+func (m SMTPMailer) Send(_ context.Context, to, subject, body string) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, err := net.SplitHostPort(m.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
+		m.From, to, headerSafe(subject), body)
+	return smtp.SendMail(m.Addr, auth, m.From, []string{to}, []byte(msg))
+}
+
+// headerSafe keeps user-influenced text such as job titles on one header line.
+func headerSafe(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// Webhook posts notification payloads to user-configured URLs.
+type Webhook interface {
+	Post(ctx context.Context, url string, payload interface{}) error
+}
+
+// ErrUnsafeWebhook is returned for webhook URLs that are not http(s) or
+// whose host resolves to a private, loopback or link-local address.
+var ErrUnsafeWebhook = errors.New("webhook_url must be a public http(s) URL")
+
+// publicIP reports whether ip may be reached by webhooks. Private, loopback,
+// link-local and unspecified addresses are refused so user-configured URLs
+// cannot reach internal services.
+func publicIP(ip net.IP) bool {
+	return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// ValidateWebhookURL checks that raw is an http(s) URL whose host resolves
+// only to public addresses.
+func ValidateWebhookURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return ErrUnsafeWebhook
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: cannot resolve %s", ErrUnsafeWebhook, u.Hostname())
+	}
+	for _, a := range addrs {
+		if !publicIP(a.IP) {
+			return ErrUnsafeWebhook
+		}
+	}
+	return nil
+}
+
+// webhookClient refuses to connect to non-public addresses at dial time, so
+// DNS changes after the URL was saved cannot redirect webhooks inward, and
+// does not follow redirects.
+var webhookClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: func(_, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+					return fmt.Errorf("%w: %s", ErrUnsafeWebhook, host)
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// HTTPWebhook posts payloads as JSON.
+type HTTPWebhook struct {
+	Client *http.Client // nil only dials public addresses and does not follow redirects
+}
+
+// Post sends payload and fails on a non-2xx response.
+func (w HTTPWebhook) Post(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := w.Client
+	if client == nil {
+		client = webhookClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
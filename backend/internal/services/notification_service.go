@@ -0,0 +1,569 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+// JobExpiryNotice is how long before a job's closing date its recruiter is
+// told it is expiring.
+const JobExpiryNotice = 72 * time.Hour
+
+// ErrInvalidPreferences is returned for notification preferences naming an
+// unknown type, channel or digest, or a webhook channel without a valid URL.
+var ErrInvalidPreferences = errors.New("invalid notification preferences")
+
+var digestPeriods = map[string]time.Duration{
+	models.DigestHourly: time.Hour,
+	models.DigestDaily:  24 * time.Hour,
+}
+
+// NotificationService stores notifications for the notification center and
+// delivers them by email and webhook, immediately or in digests, following
+// each user's preferences. External delivery runs in the background worker:
+// notifications wait in an outbox (their Pending channels) until it sends them.
+type NotificationService struct {
+	col      *mongo.Collection
+	prefs    *mongo.Collection
+	users    *UserService
+	mailer   Mailer
+	webhook  Webhook
+	onCreate []func(ctx context.Context, n models.Notification)
+	wake     chan struct{}
+}
+
+var notificationMemory = struct {
+	sync.Mutex
+	data  map[string]models.Notification
+	prefs map[string]models.NotificationPreferences
+}{data: map[string]models.Notification{}, prefs: map[string]models.NotificationPreferences{}}
+
+// NewNotificationService creates a NotificationService. users provides email
+// addresses; a nil mailer logs email and a nil webhook posts JSON over HTTP.
+func NewNotificationService(db *mongo.Database, users *UserService, mailer Mailer, webhook Webhook) *NotificationService {
+	if mailer == nil {
+		mailer = LogMailer{}
+	}
+	if webhook == nil {
+		webhook = HTTPWebhook{}
+	}
+	s := &NotificationService{users: users, mailer: mailer, webhook: webhook, wake: make(chan struct{}, 1)}
+	if db != nil {
+		s.col = db.Collection("notifications")
+		s.prefs = db.Collection("notification_preferences")
+	}
+	return s
+}
+
+// OnCreate registers fn to run after a notification is added to a user's
+// notification center.
+func (s *NotificationService) OnCreate(fn func(ctx context.Context, n models.Notification)) {
+	s.onCreate = append(s.onCreate, fn)
+}
+
+// Notify records n for its user and queues it for the external channels they
+// chose for its type; only the notification center is updated before it
+// returns. It returns a zero notification, without error, when the user muted
+// the type or a notification with the same Key was already sent.
+func (s *NotificationService) Notify(ctx context.Context, n models.Notification) (models.Notification, error) {
+	prefs, err := s.Preferences(ctx, n.UserID)
+	if err != nil {
+		return models.Notification{}, err
+	}
+	n.InApp = prefs.Wants(n.Type, models.ChannelInApp)
+	external := make([]string, 0, 2)
+	if prefs.Wants(n.Type, models.ChannelEmail) {
+		external = append(external, models.ChannelEmail)
+	}
+	if prefs.Wants(n.Type, models.ChannelWebhook) && prefs.WebhookURL != "" {
+		external = append(external, models.ChannelWebhook)
+	}
+	if !n.InApp && len(external) == 0 {
+		return models.Notification{}, nil
+	}
+	if len(external) > 0 {
+		n.Pending = external
+	}
+	n.IsRead = false
+	n.CreatedAt = time.Now()
+
+	if s.col == nil {
+		notificationMemory.Lock()
+		if n.Key != "" {
+			for _, existing := range notificationMemory.data {
+				if existing.UserID == n.UserID && existing.Key == n.Key {
+					notificationMemory.Unlock()
+					return models.Notification{}, nil
+				}
+			}
+		}
+		n.ID = primitive.NewObjectID()
+		notificationMemory.data[n.ID.Hex()] = n
+		notificationMemory.Unlock()
+	} else {
+		res, err := s.col.InsertOne(ctx, n)
+		if mongo.IsDuplicateKeyError(err) {
+			return models.Notification{}, nil
+		}
+		if err != nil {
+			return models.Notification{}, err
+		}
+		n.ID = res.InsertedID.(primitive.ObjectID)
+	}
+
+	if len(external) > 0 && prefs.Digest == models.DigestOff {
+		s.Wake()
+	}
+	if n.InApp {
+		for _, fn := range s.onCreate {
+			fn(ctx, n)
+		}
+	}
+	return n, nil
+}
+
+// deliver sends a batch of notifications on the given external channels.
+// Failures are logged: the notification center keeps the notifications.
+func (s *NotificationService) deliver(ctx context.Context, prefs models.NotificationPreferences, channels []string, batch []models.Notification) {
+	if len(batch) == 0 {
+		return
+	}
+	for _, channel := range channels {
+		var err error
+		switch channel {
+		case models.ChannelEmail:
+			var user models.User
+			if user, err = s.users.FindByID(ctx, prefs.UserID); err == nil {
+				subject, body := digestEmail(batch)
+				err = s.mailer.Send(ctx, user.Email, subject, body)
+			}
+		case models.ChannelWebhook:
+			err = s.webhook.Post(ctx, prefs.WebhookURL, map[string]interface{}{"notifications": batch})
+		}
+		if err != nil {
+			log.Printf("notifications: %s delivery to user %s failed: %v", channel, prefs.UserID.Hex(), err)
+		}
+	}
+}
+
+// digestEmail renders one notification, or a digest of several, as email.
+func digestEmail(batch []models.Notification) (subject, body string) {
+	if len(batch) == 1 {
+		return batch[0].Title, batch[0].Body
+	}
+	var b strings.Builder
+	for _, n := range batch {
+		fmt.Fprintf(&b, "- %s\n  %s\n", n.Title, n.Body)
+	}
+	return fmt.Sprintf("You have %d new notifications", len(batch)), b.String()
+}
+
+// List returns a page (from 1) of userID's notification center, newest
+// first, and the number of matching notifications.
+func (s *NotificationService) List(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, page, limit int) ([]models.Notification, int64, error) {
+	if s.col == nil {
+		notificationMemory.Lock()
+		items := make([]models.Notification, 0)
+		for _, n := range notificationMemory.data {
+			if n.UserID == userID && n.InApp && !(unreadOnly && n.IsRead) {
+				items = append(items, n)
+			}
+		}
+		notificationMemory.Unlock()
+		sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.After(items[j].CreatedAt) })
+		total := int64(len(items))
+		start := (page - 1) * limit
+		if start > len(items) {
+			start = len(items)
+		}
+		end := start + limit
+		if end > len(items) {
+			end = len(items)
+		}
+		return items[start:end], total, nil
+	}
+	filter := bson.M{"user_id": userID, "in_app": true}
+	if unreadOnly {
+		filter["is_read"] = false
+	}
+	total, err := s.col.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := s.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	items := make([]models.Notification, 0)
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// UnreadCount returns the number of unread notifications in userID's
+// notification center.
+func (s *NotificationService) UnreadCount(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	_, total, err := s.List(ctx, userID, true, 1, 1)
+	return total, err
+}
+
+// MarkRead marks one of userID's notifications as read. It returns
+// mongo.ErrNoDocuments when the notification is not in their center.
+func (s *NotificationService) MarkRead(ctx context.Context, id, userID primitive.ObjectID) error {
+	now := time.Now()
+	if s.col == nil {
+		notificationMemory.Lock()
+		defer notificationMemory.Unlock()
+		n, ok := notificationMemory.data[id.Hex()]
+		if !ok || n.UserID != userID || !n.InApp {
+			return mongo.ErrNoDocuments
+		}
+		if !n.IsRead {
+			n.IsRead = true
+			n.ReadAt = &now
+			notificationMemory.data[id.Hex()] = n
+		}
+		return nil
+	}
+	filter := bson.M{"_id": id, "user_id": userID, "in_app": true}
+	res, err := s.col.UpdateOne(ctx, filter, bson.A{
+		bson.M{"$set": bson.M{
+			"read_at": bson.M{"$cond": bson.A{"$is_read", "$read_at", now}},
+			"is_read": true,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of userID as read and returns
+// how many changed.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int, error) {
+	now := time.Now()
+	if s.col == nil {
+		notificationMemory.Lock()
+		defer notificationMemory.Unlock()
+		changed := 0
+		for id, n := range notificationMemory.data {
+			if n.UserID == userID && n.InApp && !n.IsRead {
+				n.IsRead = true
+				n.ReadAt = &now
+				notificationMemory.data[id] = n
+				changed++
+			}
+		}
+		return changed, nil
+	}
+	res, err := s.col.UpdateMany(ctx,
+		bson.M{"user_id": userID, "in_app": true, "is_read": false},
+		bson.M{"$set": bson.M{"is_read": true, "read_at": now}})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.ModifiedCount), nil
+}
+
+// Preferences returns userID's notification preferences; types they never
+// configured use the defaults.
+func (s *NotificationService) Preferences(ctx context.Context, userID primitive.ObjectID) (models.NotificationPreferences, error) {
+	var stored models.NotificationPreferences
+	found := false
+	if s.prefs == nil {
+		notificationMemory.Lock()
+		stored, found = notificationMemory.prefs[userID.Hex()]
+		notificationMemory.Unlock()
+	} else {
+		err := s.prefs.FindOne(ctx, bson.M{"_id": userID}).Decode(&stored)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return models.NotificationPreferences{}, err
+		}
+		found = err == nil
+	}
+	prefs := models.DefaultNotificationPreferences(userID)
+	if !found {
+		return prefs, nil
+	}
+	for t, channels := range stored.Channels {
+		prefs.Channels[t] = channels
+	}
+	prefs.WebhookURL = stored.WebhookURL
+	if stored.Digest != "" {
+		prefs.Digest = stored.Digest
+	}
+	prefs.LastDigestAt = stored.LastDigestAt
+	prefs.UpdatedAt = stored.UpdatedAt
+	return prefs, nil
+}
+
+// SetPreferences validates and saves userID's preferences. Types missing from
+// p.Channels keep their current channels, an empty p.Digest keeps the current
+// digest, and p.WebhookURL replaces the current URL.
+func (s *NotificationService) SetPreferences(ctx context.Context, userID primitive.ObjectID, p models.NotificationPreferences) (models.NotificationPreferences, error) {
+	current, err := s.Preferences(ctx, userID)
+	if err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	for t, channels := range p.Channels {
+		if _, ok := current.Channels[t]; !ok {
+			return models.NotificationPreferences{}, fmt.Errorf("%w: unknown notification type %q", ErrInvalidPreferences, t)
+		}
+		seen := map[string]bool{}
+		clean := make([]string, 0, len(channels))
+		for _, c := range channels {
+			if c != models.ChannelInApp && c != models.ChannelEmail && c != models.ChannelWebhook {
+				return models.NotificationPreferences{}, fmt.Errorf("%w: unknown channel %q", ErrInvalidPreferences, c)
+			}
+			if !seen[c] {
+				seen[c] = true
+				clean = append(clean, c)
+			}
+		}
+		current.Channels[t] = clean
+	}
+	if p.Digest != "" {
+		if _, ok := digestPeriods[p.Digest]; !ok && p.Digest != models.DigestOff {
+			return models.NotificationPreferences{}, fmt.Errorf("%w: digest must be off, hourly or daily", ErrInvalidPreferences)
+		}
+		current.Digest = p.Digest
+	}
+	current.WebhookURL = strings.TrimSpace(p.WebhookURL)
+	if current.WebhookURL != "" {
+		if err := ValidateWebhookURL(ctx, current.WebhookURL); err != nil {
+			return models.NotificationPreferences{}, fmt.Errorf("%w: %v", ErrInvalidPreferences, err)
+		}
+	}
+	for _, t := range models.NotificationTypes {
+		if current.Wants(t, models.ChannelWebhook) && current.WebhookURL == "" {
+			return models.NotificationPreferences{}, fmt.Errorf("%w: the webhook channel needs a webhook_url", ErrInvalidPreferences)
+		}
+	}
+	current.UpdatedAt = time.Now()
+
+	if s.prefs == nil {
+		notificationMemory.Lock()
+		notificationMemory.prefs[userID.Hex()] = current
+		notificationMemory.Unlock()
+		return current, nil
+	}
+	_, err = s.prefs.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{
+		"channels":    current.Channels,
+		"webhook_url": current.WebhookURL,
+		"digest":      current.Digest,
+		"updated_at":  current.UpdatedAt,
+	}}, options.Update().SetUpsert(true))
+	if err != nil {
+		return models.NotificationPreferences{}, err
+	}
+	return current, nil
+}
+
+// DeliverPending empties the outbox. Users without a digest get each
+// notification on its own; digest users get one email and one webhook call
+// holding all of theirs, at most once per digest period. Users who turned
+// digests off since get theirs at once. It returns the number of users sent
+// notifications.
+func (s *NotificationService) DeliverPending(ctx context.Context, now time.Time) (int, error) {
+	pending, err := s.pendingByUser(ctx)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for userID, batch := range pending {
+		prefs, err := s.Preferences(ctx, userID)
+		if err != nil {
+			return sent, err
+		}
+		period, digest := digestPeriods[prefs.Digest]
+		if digest && prefs.LastDigestAt != nil && now.Sub(*prefs.LastDigestAt) < period {
+			continue
+		}
+		if digest {
+			for _, channel := range []string{models.ChannelEmail, models.ChannelWebhook} {
+				var items []models.Notification
+				for _, n := range batch {
+					for _, c := range n.Pending {
+						if c == channel {
+							items = append(items, n)
+						}
+					}
+				}
+				s.deliver(ctx, prefs, []string{channel}, items)
+			}
+		} else {
+			for _, n := range batch {
+				s.deliver(ctx, prefs, n.Pending, []models.Notification{n})
+			}
+		}
+		if err := s.clearPending(ctx, userID, batch, digest, now); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// pendingByUser returns notifications in the outbox, oldest first, by user.
+func (s *NotificationService) pendingByUser(ctx context.Context) (map[primitive.ObjectID][]models.Notification, error) {
+	var items []models.Notification
+	if s.col == nil {
+		notificationMemory.Lock()
+		for _, n := range notificationMemory.data {
+			if len(n.Pending) > 0 {
+				items = append(items, n)
+			}
+		}
+		notificationMemory.Unlock()
+	} else {
+		cursor, err := s.col.Find(ctx, bson.M{"pending.0": bson.M{"$exists": true}},
+			options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+		if err := cursor.All(ctx, &items); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].CreatedAt.Before(items[j].CreatedAt) })
+	byUser := map[primitive.ObjectID][]models.Notification{}
+	for _, n := range items {
+		byUser[n.UserID] = append(byUser[n.UserID], n)
+	}
+	return byUser, nil
+}
+
+// clearPending removes batch from the outbox and, for a digest, records it as
+// sent at now.
+func (s *NotificationService) clearPending(ctx context.Context, userID primitive.ObjectID, batch []models.Notification, digest bool, now time.Time) error {
+	ids := make([]primitive.ObjectID, len(batch))
+	for i, n := range batch {
+		ids[i] = n.ID
+	}
+	if s.col == nil {
+		notificationMemory.Lock()
+		defer notificationMemory.Unlock()
+		for _, id := range ids {
+			if n, ok := notificationMemory.data[id.Hex()]; ok {
+				n.Pending = nil
+				notificationMemory.data[id.Hex()] = n
+			}
+		}
+		if !digest {
+			return nil
+		}
+		prefs, ok := notificationMemory.prefs[userID.Hex()]
+		if !ok {
+			prefs = models.NotificationPreferences{UserID: userID}
+		}
+		prefs.LastDigestAt = &now
+		notificationMemory.prefs[userID.Hex()] = prefs
+		return nil
+	}
+	if _, err := s.col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$unset": bson.M{"pending": ""}}); err != nil {
+		return err
+	}
+	if !digest {
+		return nil
+	}
+	_, err := s.prefs.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"last_digest_at": now}}, options.Update().SetUpsert(true))
+	return err
+}
+
+// NotifyExpiringJobs tells recruiters about their jobs closing within
+// JobExpiryNotice of now, once per closing date, and returns how many were
+// notified.
+func (s *NotificationService) NotifyExpiringJobs(ctx context.Context, jobs *JobService, now time.Time) (int, error) {
+	closing, err := jobs.ClosingBetween(ctx, now, now.Add(JobExpiryNotice))
+	if err != nil {
+		return 0, err
+	}
+	notified := 0
+	for _, job := range closing {
+		n, err := s.Notify(ctx, models.Notification{
+			UserID: job.RecruiterID,
+			Type:   models.NotifyJobExpiring,
+			Title:  fmt.Sprintf("%q closes soon", job.Title),
+			Body:   fmt.Sprintf("Applications for %s close on %s.", job.Title, job.ExpiresAt.Format("Jan 2, 2006 15:04 MST")),
+			Data:   map[string]string{"job_id": job.ID.Hex()},
+			Key:    fmt.Sprintf("%s:%s:%d", models.NotifyJobExpiring, job.ID.Hex(), job.ExpiresAt.Unix()),
+		})
+		if err != nil {
+			return notified, err
+		}
+		if !n.ID.IsZero() {
+			notified++
+		}
+	}
+	return notified, nil
+}
+
+// Wake asks the background worker to deliver queued notifications now.
+func (s *NotificationService) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start delivers queued notifications when woken, and sends due digests and
+// job expiry notices every interval, until ctx is done.
+func (s *NotificationService) Start(ctx context.Context, jobs *JobService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.wake:
+			case now := <-ticker.C:
+				if _, err := s.NotifyExpiringJobs(ctx, jobs, now); err != nil {
+					log.Printf("notifications: job expiry sweep failed: %v", err)
+				}
+			}
+			if _, err := s.DeliverPending(ctx, time.Now()); err != nil {
+				log.Printf("notifications: delivery failed: %v", err)
+			}
+		}
+	}()
+}
+
+// EnsureIndexes creates the indexes used by the notification center, digests
+// and notification dedupe.
+func (s *NotificationService) EnsureIndexes(ctx context.Context) error {
+	if s.col == nil {
+		return nil
+	}
+	_, err := s.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "in_app", Value: 1}, {Key: "created_at", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"key": bson.M{"$exists": true}}),
+		},
+		{Keys: bson.D{{Key: "pending", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	return err
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// pageBounds returns the slice bounds of a page (from 1) of n items.
+func pageBounds(n, page, limit int) (int, int) {
+	start := (page - 1) * limit
+	if start > n {
+		start = n
+	}
+	end := start + limit
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+// newerThan orders documents newest first, by creation time and then id, as
+// findPage does.
+func newerThan(aCreated time.Time, aID primitive.ObjectID, bCreated time.Time, bID primitive.ObjectID) bool {
+	if !aCreated.Equal(bCreated) {
+		return aCreated.After(bCreated)
+	}
+	return aID.Hex() > bID.Hex()
+}
+
+// findPage decodes a page (from 1) of col, newest first, into out and returns
+// the number of documents in col.
+func findPage(ctx context.Context, col *mongo.Collection, page, limit int, projection bson.M, out interface{}) (int64, error) {
+	total, err := col.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	if projection != nil {
+		opts.SetProjection(projection)
+	}
+	cursor, err := col.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+	return total, cursor.All(ctx, out)
+}
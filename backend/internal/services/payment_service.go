@@ -7,6 +7,7 @@ import (
 	"errors"
 	"math/big"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -206,6 +207,29 @@ func (s *PaymentService) List(ctx context.Context, filter bson.M) ([]models.Paym
 	return items, nil
 }
 
+// ListPage returns a page (from 1) of all payments, newest first, and the
+// number of payments.
+func (s *PaymentService) ListPage(ctx context.Context, page, limit int) ([]models.Payment, int64, error) {
+	items := make([]models.Payment, 0)
+	if s.col == nil {
+		paymentMemory.Lock()
+		for _, p := range paymentMemory.data {
+			items = append(items, p)
+		}
+		paymentMemory.Unlock()
+		sort.Slice(items, func(i, j int) bool {
+			return newerThan(items[i].CreatedAt, items[i].ID, items[j].CreatedAt, items[j].ID)
+		})
+		start, end := pageBounds(len(items), page, limit)
+		return items[start:end], int64(len(items)), nil
+	}
+	total, err := findPage(ctx, s.col, page, limit, nil, &items)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
 type rpcRequest struct {
 	Jsonrpc string        `json:"jsonrpc"`
 	Method  string        `json:"method"`
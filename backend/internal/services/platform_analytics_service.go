@@ -0,0 +1,519 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+// activeJobWindow is how recently a job must have been viewed or applied to
+// to count as active; jobs have no explicit open/closed status.
+const activeJobWindow = 30 * 24 * time.Hour
+
+// topRecruiterLimit bounds PlatformMetrics.TopRecruiters.
+const topRecruiterLimit = 10
+
+// PlatformAnalyticsService computes platform-wide admin metrics with
+// aggregation pipelines; no raw documents leave the database.
+type PlatformAnalyticsService struct {
+	payments *mongo.Collection
+	users    *mongo.Collection
+	jobs     *mongo.Collection
+	apps     *mongo.Collection
+	messages *mongo.Collection
+	stats    *mongo.Collection
+}
+
+// NewPlatformAnalyticsService creates a PlatformAnalyticsService.
+func NewPlatformAnalyticsService(db *mongo.Database) *PlatformAnalyticsService {
+	if db == nil {
+		return &PlatformAnalyticsService{}
+	}
+	return &PlatformAnalyticsService{
+		payments: db.Collection("payments"),
+		users:    db.Collection("users"),
+		jobs:     db.Collection("jobs"),
+		apps:     db.Collection("job_applications"),
+		messages: db.Collection("messages"),
+		stats:    db.Collection("job_daily_stats"),
+	}
+}
+
+// PlatformRange selects the reporting window and series granularity
+// (IntervalDay, IntervalWeek or IntervalMonth).
+type PlatformRange struct {
+	From, To time.Time
+	Interval string
+}
+
+// CountPoint is a count for one period, optionally split by Key.
+type CountPoint struct {
+	Period time.Time `json:"period"`
+	Key    string    `json:"key,omitempty"`
+	Count  int       `json:"count"`
+}
+
+// AmountPoint is revenue for one period and payment type.
+type AmountPoint struct {
+	Period      time.Time `json:"period"`
+	PaymentType string    `json:"payment_type"`
+	Amount      float64   `json:"amount"`
+	Count       int       `json:"count"`
+}
+
+// RevenueMetrics covers verified payments in the range.
+type RevenueMetrics struct {
+	Total  float64            `json:"total"`
+	ByType map[string]float64 `json:"by_type"`
+	Series []AmountPoint      `json:"series"`
+}
+
+// SignupMetrics covers users registered in the range.
+type SignupMetrics struct {
+	Total  int            `json:"total"`
+	ByRole map[string]int `json:"by_role"`
+	Series []CountPoint   `json:"series"` // Key is the role
+}
+
+// JobMetrics counts jobs overall, posted in the range, and active now.
+type JobMetrics struct {
+	Total  int `json:"total"`
+	Posted int `json:"posted"`
+	Active int `json:"active"` // viewed or applied to in the last 30 days
+}
+
+// ApplicationMetrics covers applications submitted in the range.
+type ApplicationMetrics struct {
+	Total int          `json:"total"`
+	Daily []CountPoint `json:"daily"`
+}
+
+// PremiumMetrics is job seeker premium adoption.
+type PremiumMetrics struct {
+	Seekers        int      `json:"seekers"`
+	Premium        int      `json:"premium"`
+	ConversionRate *float64 `json:"conversion_rate"` // percent of seekers; nil without seekers
+	Purchases      int      `json:"purchases"`       // premium payments in the range
+}
+
+// RecruiterSpend is a recruiter's verified spend in the range.
+type RecruiterSpend struct {
+	RecruiterID string  `json:"recruiter_id"`
+	Name        string  `json:"name"`
+	Amount      float64 `json:"amount"`
+	Payments    int     `json:"payments"`
+}
+
+// MessageMetrics covers messages sent in the range.
+type MessageMetrics struct {
+	Total  int            `json:"total"`
+	ByRole map[string]int `json:"by_role"` // by sender role
+	Series []CountPoint   `json:"series"`
+}
+
+// PlatformMetrics is the admin dashboard. Series omit periods without activity.
+type PlatformMetrics struct {
+	From          time.Time          `json:"from"`
+	To            time.Time          `json:"to"`
+	Interval      string             `json:"interval"`
+	Revenue       RevenueMetrics     `json:"revenue"`
+	Signups       SignupMetrics      `json:"signups"`
+	Users         int                `json:"users"`
+	Jobs          JobMetrics         `json:"jobs"`
+	Applications  ApplicationMetrics `json:"applications"`
+	Premium       PremiumMetrics     `json:"premium"`
+	TopRecruiters []RecruiterSpend   `json:"top_recruiters"`
+	Messages      MessageMetrics     `json:"messages"`
+}
+
+// record is a dated, keyed event used by the in-memory fallback.
+type record struct {
+	at     time.Time
+	key    string
+	amount float64
+}
+
+// Metrics computes the admin dashboard for r.
+func (s *PlatformAnalyticsService) Metrics(ctx context.Context, r PlatformRange) (PlatformMetrics, error) {
+	if err := CheckTrendRange(r.From, r.To, r.Interval); err != nil {
+		return PlatformMetrics{}, err
+	}
+	m := PlatformMetrics{From: r.From, To: r.To, Interval: r.Interval}
+	if s.payments == nil {
+		s.memoryMetrics(&m, r)
+		return m, nil
+	}
+
+	steps := []func(context.Context, *PlatformMetrics, PlatformRange) error{
+		s.revenue, s.signups, s.jobMetrics, s.applications, s.premium, s.topRecruiters, s.messageMetrics,
+	}
+	for _, step := range steps {
+		if err := step(ctx, &m, r); err != nil {
+			return PlatformMetrics{}, err
+		}
+	}
+	return m, nil
+}
+
+func (s *PlatformAnalyticsService) revenue(ctx context.Context, m *PlatformMetrics, r PlatformRange) error {
+	cursor, err := s.payments.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": "verified", "created_at": rangeMatch(r)}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    bson.M{"period": truncExpr("$created_at", r.Interval), "type": paymentTypeExpr},
+			"amount": bson.M{"$sum": "$amount"},
+			"count":  bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	var rows []struct {
+		ID struct {
+			Period time.Time `bson:"period"`
+			Type   string    `bson:"type"`
+		} `bson:"_id"`
+		Amount float64 `bson:"amount"`
+		Count  int     `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return err
+	}
+	points := make([]AmountPoint, len(rows))
+	for i, row := range rows {
+		points[i] = AmountPoint{Period: row.ID.Period.UTC(), PaymentType: row.ID.Type, Amount: row.Amount, Count: row.Count}
+	}
+	m.Revenue = revenueOf(points)
+	return nil
+}
+
+func (s *PlatformAnalyticsService) signups(ctx context.Context, m *PlatformMetrics, r PlatformRange) error {
+	series, err := countSeries(ctx, s.users, bson.M{"created_at": rangeMatch(r)}, "$created_at", "$role", r.Interval)
+	if err != nil {
+		return err
+	}
+	m.Signups = SignupMetrics{ByRole: map[string]int{}, Series: series}
+	for _, p := range series {
+		m.Signups.Total += p.Count
+		m.Signups.ByRole[p.Key] += p.Count
+	}
+	users, err := s.users.CountDocuments(ctx, bson.M{})
+	m.Users = int(users)
+	return err
+}
+
+func (s *PlatformAnalyticsService) jobMetrics(ctx context.Context, m *PlatformMetrics, r PlatformRange) error {
+	total, err := s.jobs.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	posted, err := s.jobs.CountDocuments(ctx, bson.M{"created_at": rangeMatch(r)})
+	if err != nil {
+		return err
+	}
+	active, err := s.stats.Distinct(ctx, "job_id", bson.M{
+		"day": bson.M{"$gte": dayOf(time.Now().Add(-activeJobWindow))},
+		"$or": bson.A{bson.M{EventView: bson.M{"$gt": 0}}, bson.M{EventApply: bson.M{"$gt": 0}}},
+	})
+	if err != nil {
+		return err
+	}
+	m.Jobs = JobMetrics{Total: int(total), Posted: int(posted), Active: len(active)}
+	return nil
+}
+
+func (s *PlatformAnalyticsService) applications(ctx context.Context, m *PlatformMetrics, r PlatformRange) error {
+	daily, err := countSeries(ctx, s.apps, bson.M{"applied_at": rangeMatch(r)}, "$applied_at", nil, IntervalDay)
+	if err != nil {
+		return err
+	}
+	m.Applications = ApplicationMetrics{Daily: daily}
+	for _, p := range daily {
+		m.Applications.Total += p.Count
+	}
+	return nil
+}
+
+func (s *PlatformAnalyticsService) premium(ctx context.Context, m *PlatformMetrics, r PlatformRange) error {
+	seekers, err := s.users.CountDocuments(ctx, bson.M{"role": models.RoleSeeker})
+	if err != nil {
+		return err
+	}
+	premium, err := s.users.CountDocuments(ctx, bson.M{"role": models.RoleSeeker, "is_premium": true})
+	if err != nil {
+		return err
+	}
+	purchases, err := s.payments.CountDocuments(ctx, bson.M{"status": "verified", "payment_type": "JOB_SEEKER_PREMIUM", "created_at": rangeMatch(r)})
+	if err != nil {
+		return err
+	}
+	m.Premium = premiumOf(int(seekers), int(premium), int(purchases))
+	return nil
+}
+
+func (s *PlatformAnalyticsService) topRecruiters(ctx context.Context, m *PlatformMetrics, r PlatformRange) error {
+	cursor, err := s.payments.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": "verified", "recruiter_id": bson.M{"$type": "objectId"}, "created_at": rangeMatch(r)}}},
+		{{Key: "$group", Value: bson.M{"_id": "$recruiter_id", "amount": bson.M{"$sum": "$amount"}, "payments": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "amount", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: topRecruiterLimit}},
+		{{Key: "$lookup", Value: bson.M{"from": "users", "localField": "_id", "foreignField": "_id", "as": "user"}}},
+		{{Key: "$project", Value: bson.M{"amount": 1, "payments": 1, "name": bson.M{"$ifNull": bson.A{bson.M{"$first": "$user.name"}, ""}}}}},
+	})
+	if err != nil {
+		return err
+	}
+	var rows []struct {
+		ID       primitive.ObjectID `bson:"_id"`
+		Name     string             `bson:"name"`
+		Amount   float64            `bson:"amount"`
+		Payments int                `bson:"payments"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return err
+	}
+	m.TopRecruiters = make([]RecruiterSpend, len(rows))
+	for i, row := range rows {
+		m.TopRecruiters[i] = RecruiterSpend{RecruiterID: row.ID.Hex(), Name: row.Name, Amount: row.Amount, Payments: row.Payments}
+	}
+	return nil
+}
+
+func (s *PlatformAnalyticsService) messageMetrics(ctx context.Context, m *PlatformMetrics, r PlatformRange) error {
+	series, err := countSeries(ctx, s.messages, bson.M{"created_at": rangeMatch(r)}, "$created_at", "$from_role", r.Interval)
+	if err != nil {
+		return err
+	}
+	m.Messages = messagesOf(series)
+	return nil
+}
+
+// countSeries counts documents matching match per period of dateField,
+// split by keyExpr when it is non-nil.
+func countSeries(ctx context.Context, col *mongo.Collection, match bson.M, dateField string, keyExpr interface{}, interval string) ([]CountPoint, error) {
+	id := bson.M{"period": truncExpr(dateField, interval)}
+	if keyExpr != nil {
+		id["key"] = bson.M{"$ifNull": bson.A{keyExpr, ""}}
+	}
+	cursor, err := col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": id, "count": bson.M{"$sum": 1}}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		ID struct {
+			Period time.Time `bson:"period"`
+			Key    string    `bson:"key"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]CountPoint, len(rows))
+	for i, row := range rows {
+		out[i] = CountPoint{Period: row.ID.Period.UTC(), Key: row.ID.Key, Count: row.Count}
+	}
+	sortCountPoints(out)
+	return out, nil
+}
+
+// paymentTypeExpr labels payments stored before payment types existed, which
+// were all job postings.
+var paymentTypeExpr = bson.M{"$ifNull": bson.A{"$payment_type", "JOB_POSTING"}}
+
+func truncExpr(field, interval string) bson.M {
+	return bson.M{"$dateTrunc": bson.M{"date": field, "unit": interval, "startOfWeek": "monday"}}
+}
+
+func rangeMatch(r PlatformRange) bson.M {
+	return bson.M{"$gte": r.From, "$lte": r.To}
+}
+
+// memoryMetrics computes the same metrics from the in-memory stores.
+func (s *PlatformAnalyticsService) memoryMetrics(m *PlatformMetrics, r PlatformRange) {
+	in := func(t time.Time) bool { return !t.Before(r.From) && !t.After(r.To) }
+
+	var payments []models.Payment
+	paymentMemory.Lock()
+	for _, p := range paymentMemory.data {
+		payments = append(payments, p)
+	}
+	paymentMemory.Unlock()
+	revenue := map[[2]string]*AmountPoint{}
+	spend := map[primitive.ObjectID]*RecruiterSpend{}
+	purchases := 0
+	for _, p := range payments {
+		if p.Status != "verified" || !in(p.CreatedAt) {
+			continue
+		}
+		kind := p.PaymentType
+		if kind == "" {
+			kind = "JOB_POSTING"
+		}
+		if kind == "JOB_SEEKER_PREMIUM" {
+			purchases++
+		}
+		period := periodStart(p.CreatedAt, r.Interval)
+		key := [2]string{period.Format(time.RFC3339), kind}
+		if revenue[key] == nil {
+			revenue[key] = &AmountPoint{Period: period, PaymentType: kind}
+		}
+		revenue[key].Amount += p.Amount
+		revenue[key].Count++
+		if p.RecruiterID != nil {
+			if spend[*p.RecruiterID] == nil {
+				spend[*p.RecruiterID] = &RecruiterSpend{RecruiterID: p.RecruiterID.Hex()}
+			}
+			spend[*p.RecruiterID].Amount += p.Amount
+			spend[*p.RecruiterID].Payments++
+		}
+	}
+	points := make([]AmountPoint, 0, len(revenue))
+	for _, p := range revenue {
+		points = append(points, *p)
+	}
+	m.Revenue = revenueOf(points)
+
+	var signups, messages []record
+	seekers, premium := 0, 0
+	names := map[primitive.ObjectID]string{}
+	userMemory.Lock()
+	m.Users = len(userMemory.data)
+	for _, u := range userMemory.data {
+		names[u.ID] = u.Name
+		if u.Role == models.RoleSeeker {
+			seekers++
+			if u.IsPremium {
+				premium++
+			}
+		}
+		if in(u.CreatedAt) {
+			signups = append(signups, record{at: u.CreatedAt, key: u.Role})
+		}
+	}
+	userMemory.Unlock()
+	m.Signups = SignupMetrics{ByRole: map[string]int{}, Series: recordSeries(signups, r.Interval)}
+	for _, rec := range signups {
+		m.Signups.Total++
+		m.Signups.ByRole[rec.key]++
+	}
+	m.Premium = premiumOf(seekers, premium, purchases)
+
+	m.TopRecruiters = make([]RecruiterSpend, 0, len(spend))
+	for id, rs := range spend {
+		rs.Name = names[id]
+		m.TopRecruiters = append(m.TopRecruiters, *rs)
+	}
+	sort.Slice(m.TopRecruiters, func(i, j int) bool {
+		if m.TopRecruiters[i].Amount != m.TopRecruiters[j].Amount {
+			return m.TopRecruiters[i].Amount > m.TopRecruiters[j].Amount
+		}
+		return m.TopRecruiters[i].RecruiterID < m.TopRecruiters[j].RecruiterID
+	})
+	if len(m.TopRecruiters) > topRecruiterLimit {
+		m.TopRecruiters = m.TopRecruiters[:topRecruiterLimit]
+	}
+
+	jobMemory.Lock()
+	m.Jobs.Total = len(jobMemory.data)
+	for _, j := range jobMemory.data {
+		if in(j.CreatedAt) {
+			m.Jobs.Posted++
+		}
+	}
+	jobMemory.Unlock()
+	activeSince := dayOf(time.Now().Add(-activeJobWindow))
+	active := map[primitive.ObjectID]bool{}
+	jobStatsMemory.Lock()
+	for _, st := range jobStatsMemory.data {
+		if !st.Day.Before(activeSince) && (st.Views > 0 || st.Applies > 0) {
+			active[st.JobID] = true
+		}
+	}
+	jobStatsMemory.Unlock()
+	m.Jobs.Active = len(active)
+
+	var apps []record
+	jobApplicationMemory.Lock()
+	for _, a := range jobApplicationMemory.data {
+		if in(a.AppliedAt) {
+			apps = append(apps, record{at: a.AppliedAt})
+		}
+	}
+	jobApplicationMemory.Unlock()
+	m.Applications = ApplicationMetrics{Total: len(apps), Daily: recordSeries(apps, IntervalDay)}
+
+	messageMemory.Lock()
+	for _, msg := range messageMemory.data {
+		if in(msg.CreatedAt) {
+			messages = append(messages, record{at: msg.CreatedAt, key: msg.FromRole})
+		}
+	}
+	messageMemory.Unlock()
+	m.Messages = messagesOf(recordSeries(messages, r.Interval))
+}
+
+// recordSeries buckets records per period and key.
+func recordSeries(records []record, interval string) []CountPoint {
+	counts := map[CountPoint]int{}
+	for _, rec := range records {
+		counts[CountPoint{Period: periodStart(rec.at, interval), Key: rec.key}]++
+	}
+	out := make([]CountPoint, 0, len(counts))
+	for p, n := range counts {
+		p.Count = n
+		out = append(out, p)
+	}
+	sortCountPoints(out)
+	return out
+}
+
+func revenueOf(points []AmountPoint) RevenueMetrics {
+	sort.Slice(points, func(i, j int) bool {
+		if !points[i].Period.Equal(points[j].Period) {
+			return points[i].Period.Before(points[j].Period)
+		}
+		return points[i].PaymentType < points[j].PaymentType
+	})
+	rev := RevenueMetrics{ByType: map[string]float64{}, Series: points}
+	for _, p := range points {
+		rev.Total += p.Amount
+		rev.ByType[p.PaymentType] += p.Amount
+	}
+	return rev
+}
+
+func premiumOf(seekers, premium, purchases int) PremiumMetrics {
+	pm := PremiumMetrics{Seekers: seekers, Premium: premium, Purchases: purchases}
+	if seekers > 0 {
+		rate := float64(int(float64(premium)/float64(seekers)*1000+0.5)) / 10
+		pm.ConversionRate = &rate
+	}
+	return pm
+}
+
+func messagesOf(series []CountPoint) MessageMetrics {
+	mm := MessageMetrics{ByRole: map[string]int{}, Series: series}
+	for _, p := range series {
+		mm.Total += p.Count
+		mm.ByRole[p.Key] += p.Count
+	}
+	return mm
+}
+
+func sortCountPoints(points []CountPoint) {
+	sort.Slice(points, func(i, j int) bool {
+		if !points[i].Period.Equal(points[j].Period) {
+			return points[i].Period.Before(points[j].Period)
+		}
+		return points[i].Key < points[j].Key
+	})
+}
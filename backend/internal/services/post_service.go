@@ -0,0 +1,717 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+const (
+	maxPostTitle   = 200
+	maxPostContent = 5000
+	maxPostTags    = 10
+	maxComment     = 2000
+	// feedWindow and feedCandidates bound the posts ranked for a feed.
+	feedWindow     = 30 * 24 * time.Hour
+	feedCandidates = 1000
+	// feedHalfLife is the age at which a post's recency score halves.
+	feedHalfLife = 48 * time.Hour
+	// feedRelevanceWeight is how much a post tagged only with the viewer's
+	// skills outranks an equally recent unrelated one, minus one.
+	feedRelevanceWeight = 2.0
+)
+
+var (
+	// ErrInvalidPost is returned for a post that is empty or too long.
+	ErrInvalidPost = errors.New("post needs content of at most 5000 characters, a title of at most 200 and at most 10 tags")
+	// ErrInvalidComment is returned for an empty or too long comment.
+	ErrInvalidComment = errors.New("comment must be between 1 and 2000 characters")
+	// ErrPostNotEditable is returned when editing a post generated from a job;
+	// it follows the job instead.
+	ErrPostNotEditable = errors.New("job posts follow their job and cannot be edited")
+	// ErrHideReason is returned when hiding a post without a reason.
+	ErrHideReason = errors.New("a reason is required to hide a post")
+)
+
+// PostService handles feed posts, likes and comments.
+type PostService struct {
+	col      *mongo.Collection
+	likes    *mongo.Collection
+	comments *mongo.Collection
+	jobs     *mongo.Collection
+}
+
+var postMemory = struct {
+	sync.Mutex
+	data     map[string]models.Post
+	likes    map[string]models.PostLike // post id + user id
+	comments map[string]models.PostComment
+}{data: map[string]models.Post{}, likes: map[string]models.PostLike{}, comments: map[string]models.PostComment{}}
+
+// NewPostService creates a PostService.
+func NewPostService(db *mongo.Database) *PostService {
+	if db == nil {
+		return &PostService{}
+	}
+	return &PostService{
+		col:      db.Collection("posts"),
+		likes:    db.Collection("post_likes"),
+		comments: db.Collection("post_comments"),
+		jobs:     db.Collection("jobs"),
+	}
+}
+
+// FeedPost is a ranked post in a viewer's feed.
+type FeedPost struct {
+	models.Post
+	Score float64 `json:"score"`
+	Liked bool    `json:"liked"` // by the viewer
+}
+
+// cleanTags trims tags and drops blanks and case-insensitive duplicates.
+func cleanTags(tags []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[strings.ToLower(t)] {
+			continue
+		}
+		seen[strings.ToLower(t)] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+func validPost(p models.Post) bool {
+	return strings.TrimSpace(p.Content) != "" && len(p.Content) <= maxPostContent &&
+		len(p.Title) <= maxPostTitle && len(p.Tags) <= maxPostTags
+}
+
+// Create publishes an update post by a user.
+func (s *PostService) Create(ctx context.Context, post models.Post) (models.Post, error) {
+	post.Type = models.PostUpdate
+	post.JobID = nil
+	post.Title = strings.TrimSpace(post.Title)
+	post.Tags = cleanTags(post.Tags)
+	if !validPost(post) {
+		return models.Post{}, ErrInvalidPost
+	}
+	return s.insert(ctx, post)
+}
+
+// CreateForJob publishes the job_post announcing a new job, tagged with its
+// skills and tags. The post leaves the feed once the job closes.
+func (s *PostService) CreateForJob(ctx context.Context, job models.Job) (models.Post, error) {
+	jobID := job.ID
+	return s.insert(ctx, models.Post{
+		UserID:      job.RecruiterID,
+		Type:        models.PostJob,
+		JobID:       &jobID,
+		Title:       job.Title,
+		Content:     jobPostContent(job),
+		Tags:        jobPostTags(job),
+		JobClosesAt: job.ExpiresAt,
+	})
+}
+
+// SyncJob updates the job_post of a job after the job changes, including when
+// it closes.
+func (s *PostService) SyncJob(ctx context.Context, job models.Job) error {
+	content, tags, now := jobPostContent(job), jobPostTags(job), time.Now()
+	if s.col == nil {
+		postMemory.Lock()
+		defer postMemory.Unlock()
+		for id, p := range postMemory.data {
+			if p.JobID != nil && *p.JobID == job.ID {
+				p.Title, p.Content, p.Tags, p.JobClosesAt, p.UpdatedAt = job.Title, content, tags, job.ExpiresAt, now
+				postMemory.data[id] = p
+			}
+		}
+		return nil
+	}
+	_, err := s.col.UpdateMany(ctx, bson.M{"job_id": job.ID}, bson.M{"$set": bson.M{
+		"title": job.Title, "content": content, "tags": tags, "job_closes_at": job.ExpiresAt, "updated_at": now,
+	}})
+	return err
+}
+
+// MigrateJobClosing copies the expiry of jobs onto job posts created before
+// posts tracked it, so the feed hides the posts of closed jobs. It returns how
+// many posts changed.
+func (s *PostService) MigrateJobClosing(ctx context.Context) (int64, error) {
+	if s.col == nil {
+		return 0, nil
+	}
+	cursor, err := s.jobs.Find(ctx, bson.M{"expires_at": bson.M{"$ne": nil}},
+		options.Find().SetProjection(bson.M{"_id": 1, "expires_at": 1}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+	var writes []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var job models.Job
+		if err := cursor.Decode(&job); err != nil {
+			return 0, err
+		}
+		writes = append(writes, mongo.NewUpdateManyModel().
+			SetFilter(bson.M{"job_id": job.ID, "job_closes_at": bson.M{"$exists": false}}).
+			SetUpdate(bson.M{"$set": bson.M{"job_closes_at": job.ExpiresAt}}))
+	}
+	if err := cursor.Err(); err != nil || len(writes) == 0 {
+		return 0, err
+	}
+	res, err := s.col.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// jobPostContent is the job's location and description, cut to fit a post.
+func jobPostContent(job models.Job) string {
+	content := job.Description
+	if job.Location != "" {
+		content = job.Location + " · " + content
+	}
+	if len(content) > maxPostContent {
+		content = strings.ToValidUTF8(content[:maxPostContent-3], "") + "..."
+	}
+	return content
+}
+
+// jobPostTags are the job's skills and tags, at most maxPostTags of them.
+func jobPostTags(job models.Job) []string {
+	tags := cleanTags(append(append([]string{}, job.Skills...), job.Tags...))
+	if len(tags) > maxPostTags {
+		tags = tags[:maxPostTags]
+	}
+	return tags
+}
+
+func (s *PostService) insert(ctx context.Context, post models.Post) (models.Post, error) {
+	now := time.Now()
+	post.Status = models.PostActive
+	post.LikeCount, post.CommentCount = 0, 0
+	post.CreatedAt, post.UpdatedAt = now, now
+	if post.Tags == nil {
+		post.Tags = []string{}
+	}
+	if s.col == nil {
+		postMemory.Lock()
+		defer postMemory.Unlock()
+		post.ID = primitive.NewObjectID()
+		postMemory.data[post.ID.Hex()] = post
+		return post, nil
+	}
+	res, err := s.col.InsertOne(ctx, post)
+	if err != nil {
+		return models.Post{}, err
+	}
+	post.ID = res.InsertedID.(primitive.ObjectID)
+	return post, nil
+}
+
+// FindByID returns a post by id.
+func (s *PostService) FindByID(ctx context.Context, id primitive.ObjectID) (models.Post, error) {
+	if s.col == nil {
+		postMemory.Lock()
+		defer postMemory.Unlock()
+		p, ok := postMemory.data[id.Hex()]
+		if !ok {
+			return models.Post{}, mongo.ErrNoDocuments
+		}
+		return p, nil
+	}
+	var p models.Post
+	err := s.col.FindOne(ctx, bson.M{"_id": id}).Decode(&p)
+	return p, err
+}
+
+// Visible returns a post userID may see: active posts, and hidden ones to
+// their author and admins. Others get mongo.ErrNoDocuments.
+func (s *PostService) Visible(ctx context.Context, id, userID primitive.ObjectID, role string) (models.Post, error) {
+	p, err := s.FindByID(ctx, id)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if p.Status != models.PostActive && p.UserID != userID && role != models.RoleAdmin {
+		return models.Post{}, mongo.ErrNoDocuments
+	}
+	return p, nil
+}
+
+// Update edits the title, content and tags of userID's update post. Posts of
+// other users are reported as mongo.ErrNoDocuments.
+func (s *PostService) Update(ctx context.Context, id, userID primitive.ObjectID, title, content string, tags []string) (models.Post, error) {
+	p, err := s.FindByID(ctx, id)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if p.UserID != userID {
+		return models.Post{}, mongo.ErrNoDocuments
+	}
+	if p.Type == models.PostJob {
+		return models.Post{}, ErrPostNotEditable
+	}
+	p.Title, p.Content, p.Tags = strings.TrimSpace(title), content, cleanTags(tags)
+	if !validPost(p) {
+		return models.Post{}, ErrInvalidPost
+	}
+	p.UpdatedAt = time.Now()
+	if s.col == nil {
+		postMemory.Lock()
+		defer postMemory.Unlock()
+		cur, ok := postMemory.data[id.Hex()]
+		if !ok {
+			return models.Post{}, mongo.ErrNoDocuments
+		}
+		cur.Title, cur.Content, cur.Tags, cur.UpdatedAt = p.Title, p.Content, p.Tags, p.UpdatedAt
+		postMemory.data[id.Hex()] = cur
+		return cur, nil
+	}
+	var updated models.Post
+	err = s.col.FindOneAndUpdate(ctx, bson.M{"_id": id, "user_id": userID},
+		bson.M{"$set": bson.M{"title": p.Title, "content": p.Content, "tags": p.Tags, "updated_at": p.UpdatedAt}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updated)
+	return updated, err
+}
+
+// Delete removes a post with its likes and comments. Only the author may
+// delete it unless asAdmin; posts of other users are reported as
+// mongo.ErrNoDocuments.
+func (s *PostService) Delete(ctx context.Context, id, userID primitive.ObjectID, asAdmin bool) error {
+	p, err := s.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if p.UserID != userID && !asAdmin {
+		return mongo.ErrNoDocuments
+	}
+	if s.col == nil {
+		postMemory.Lock()
+		defer postMemory.Unlock()
+		delete(postMemory.data, id.Hex())
+		for key, l := range postMemory.likes {
+			if l.PostID == id {
+				delete(postMemory.likes, key)
+			}
+		}
+		for key, c := range postMemory.comments {
+			if c.PostID == id {
+				delete(postMemory.comments, key)
+			}
+		}
+		return nil
+	}
+	if _, err := s.col.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return err
+	}
+	if _, err := s.likes.DeleteMany(ctx, bson.M{"post_id": id}); err != nil {
+		return err
+	}
+	_, err = s.comments.DeleteMany(ctx, bson.M{"post_id": id})
+	return err
+}
+
+// Feed ranks the active posts of the last 30 days, leaving out those of closed
+// jobs, for a viewer with the given skills and returns a page of them with the total ranked. A post's score is
+// its recency, halving every 48 hours, boosted by the share of its tags that
+// are among the viewer's skills.
+func (s *PostService) Feed(ctx context.Context, viewerID primitive.ObjectID, skills []string, now time.Time, page, limit int) ([]FeedPost, int, error) {
+	since := now.Add(-feedWindow)
+	var candidates []models.Post
+	if s.col == nil {
+		postMemory.Lock()
+		for _, p := range postMemory.data {
+			if p.Status == models.PostActive && !p.CreatedAt.Before(since) && !p.JobClosed(now) {
+				candidates = append(candidates, p)
+			}
+		}
+		postMemory.Unlock()
+	} else {
+		cursor, err := s.col.Find(ctx,
+			bson.M{
+				"status":     models.PostActive,
+				"created_at": bson.M{"$gte": since},
+				"$or":        bson.A{bson.M{"job_closes_at": nil}, bson.M{"job_closes_at": bson.M{"$gt": now}}},
+			},
+			options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(feedCandidates))
+		if err != nil {
+			return nil, 0, err
+		}
+		defer cursor.Close(ctx)
+		if err := cursor.All(ctx, &candidates); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	has := map[string]bool{}
+	for _, sk := range skills {
+		has[strings.ToLower(sk)] = true
+	}
+	ranked := make([]FeedPost, len(candidates))
+	for i, p := range candidates {
+		ranked[i] = FeedPost{Post: p, Score: feedScore(p, has, now)}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].CreatedAt.After(ranked[j].CreatedAt)
+	})
+
+	total := len(ranked)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	items := ranked[start:end]
+	ids := make([]primitive.ObjectID, len(items))
+	for i, it := range items {
+		ids[i] = it.ID
+	}
+	liked, err := s.likedBy(ctx, viewerID, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range items {
+		items[i].Liked = liked[items[i].ID]
+	}
+	return items, total, nil
+}
+
+func feedScore(p models.Post, skills map[string]bool, now time.Time) float64 {
+	age := now.Sub(p.CreatedAt)
+	if age < 0 {
+		age = 0
+	}
+	recency := math.Pow(0.5, float64(age)/float64(feedHalfLife))
+	relevance := 0.0
+	if len(p.Tags) > 0 && len(skills) > 0 {
+		matched := 0
+		for _, t := range p.Tags {
+			if skills[strings.ToLower(t)] {
+				matched++
+			}
+		}
+		relevance = float64(matched) / float64(len(p.Tags))
+	}
+	return recency * (1 + feedRelevanceWeight*relevance)
+}
+
+func likeKey(postID, userID primitive.ObjectID) string {
+	return postID.Hex() + ":" + userID.Hex()
+}
+
+// likedBy reports which of postIDs userID liked.
+func (s *PostService) likedBy(ctx context.Context, userID primitive.ObjectID, postIDs []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	liked := map[primitive.ObjectID]bool{}
+	if len(postIDs) == 0 {
+		return liked, nil
+	}
+	if s.likes == nil {
+		postMemory.Lock()
+		defer postMemory.Unlock()
+		for _, id := range postIDs {
+			if _, ok := postMemory.likes[likeKey(id, userID)]; ok {
+				liked[id] = true
+			}
+		}
+		return liked, nil
+	}
+	cursor, err := s.likes.Find(ctx, bson.M{"user_id": userID, "post_id": bson.M{"$in": postIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var likes []models.PostLike
+	if err := cursor.All(ctx, &likes); err != nil {
+		return nil, err
+	}
+	for _, l := range likes {
+		liked[l.PostID] = true
+	}
+	return liked, nil
+}
+
+// Like records that userID likes a post; liking twice has no effect. It
+// returns the post's like count.
+func (s *PostService) Like(ctx context.Context, postID, userID primitive.ObjectID) (int64, error) {
+	return s.setLike(ctx, postID, userID, true)
+}
+
+// Unlike removes userID's like of a post, if any, and returns the post's like
+// count.
+func (s *PostService) Unlike(ctx context.Context, postID, userID primitive.ObjectID) (int64, error) {
+	return s.setLike(ctx, postID, userID, false)
+}
+
+func (s *PostService) setLike(ctx context.Context, postID, userID primitive.ObjectID, like bool) (int64, error) {
+	if s.col == nil {
+		postMemory.Lock()
+		defer postMemory.Unlock()
+		p, ok := postMemory.data[postID.Hex()]
+		if !ok {
+			return 0, mongo.ErrNoDocuments
+		}
+		key := likeKey(postID, userID)
+		_, exists := postMemory.likes[key]
+		switch {
+		case like && !exists:
+			postMemory.likes[key] = models.PostLike{PostID: postID, UserID: userID, CreatedAt: time.Now()}
+			p.LikeCount++
+		case !like && exists:
+			delete(postMemory.likes, key)
+			p.LikeCount--
+		}
+		postMemory.data[postID.Hex()] = p
+		return p.LikeCount, nil
+	}
+	filter := bson.M{"post_id": postID, "user_id": userID}
+	var delta int64
+	if like {
+		res, err := s.likes.UpdateOne(ctx, filter,
+			bson.M{"$setOnInsert": bson.M{"created_at": time.Now()}}, options.Update().SetUpsert(true))
+		if err != nil {
+			return 0, err
+		}
+		delta = res.UpsertedCount
+	} else {
+		res, err := s.likes.DeleteOne(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		delta = -res.DeletedCount
+	}
+	var p models.Post
+	err := s.col.FindOneAndUpdate(ctx, bson.M{"_id": postID}, bson.M{"$inc": bson.M{"like_count": delta}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&p)
+	return p.LikeCount, err
+}
+
+// AddComment comments on a post as userID.
+func (s *PostService) AddComment(ctx context.Context, postID, userID primitive.ObjectID, content string) (models.PostComment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || len(content) > maxComment {
+		return models.PostComment{}, ErrInvalidComment
+	}
+	comment := models.PostComment{PostID: postID, UserID: userID, Content: content, CreatedAt: time.Now()}
+	if s.col == nil {
+		postMemory.Lock()
+		defer postMemory.Unlock()
+		p, ok := postMemory.data[postID.Hex()]
+		if !ok {
+			return models.PostComment{}, mongo.ErrNoDocuments
+		}
+		comment.ID = primitive.NewObjectID()
+		postMemory.comments[comment.ID.Hex()] = comment
+		p.CommentCount++
+		postMemory.data[postID.Hex()] = p
+		return comment, nil
+	}
+	res, err := s.comments.InsertOne(ctx, comment)
+	if err != nil {
+		return models.PostComment{}, err
+	}
+	comment.ID = res.InsertedID.(primitive.ObjectID)
+	if _, err := s.col.UpdateOne(ctx, bson.M{"_id": postID}, bson.M{"$inc": bson.M{"comment_count": 1}}); err != nil {
+		return models.PostComment{}, err
+	}
+	return comment, nil
+}
+
+// Comments returns a page of a post's comments, oldest first, and their total.
+func (s *PostService) Comments(ctx context.Context, postID primitive.ObjectID, page, limit int) ([]models.PostComment, int64, error) {
+	if s.comments == nil {
+		postMemory.Lock()
+		all := make([]models.PostComment, 0)
+		for _, c := range postMemory.comments {
+			if c.PostID == postID {
+				all = append(all, c)
+			}
+		}
+		postMemory.Unlock()
+		sort.Slice(all, func(i, j int) bool { return all[i].CreatedAt.Before(all[j].CreatedAt) })
+		total := int64(len(all))
+		start := (page - 1) * limit
+		if start > len(all) {
+			start = len(all)
+		}
+		end := start + limit
+		if end > len(all) {
+			end = len(all)
+		}
+		return all[start:end], total, nil
+	}
+	filter := bson.M{"post_id": postID}
+	total, err := s.comments.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := s.comments.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetSkip(int64((page-1)*limit)).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	comments := make([]models.PostComment, 0)
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// DeleteComment removes a comment. Its author and the post's author may
+// delete it, and admins when asAdmin; others get mongo.ErrNoDocuments.
+func (s *PostService) DeleteComment(ctx context.Context, postID, commentID, userID primitive.ObjectID, asAdmin bool) error {
+	p, err := s.FindByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if s.comments == nil {
+		postMemory.Lock()
+		defer postMemory.Unlock()
+		c, ok := postMemory.comments[commentID.Hex()]
+		if !ok || c.PostID != postID || (c.UserID != userID && p.UserID != userID && !asAdmin) {
+			return mongo.ErrNoDocuments
+		}
+		delete(postMemory.comments, commentID.Hex())
+		if cur, ok := postMemory.data[postID.Hex()]; ok {
+			cur.CommentCount--
+			postMemory.data[postID.Hex()] = cur
+		}
+		return nil
+	}
+	filter := bson.M{"_id": commentID, "post_id": postID}
+	if p.UserID != userID && !asAdmin {
+		filter["user_id"] = userID
+	}
+	res, err := s.comments.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	_, err = s.col.UpdateOne(ctx, bson.M{"_id": postID}, bson.M{"$inc": bson.M{"comment_count": -1}})
+	return err
+}
+
+// List returns posts with the given status (any for ""), latest first, for
+// admin moderation.
+func (s *PostService) List(ctx context.Context, status string, page, limit int) ([]models.Post, int64, error) {
+	if s.col == nil {
+		postMemory.Lock()
+		all := make([]models.Post, 0)
+		for _, p := range postMemory.data {
+			if status == "" || p.Status == status {
+				all = append(all, p)
+			}
+		}
+		postMemory.Unlock()
+		sort.Slice(all, func(i, j int) bool { return all[i].CreatedAt.After(all[j].CreatedAt) })
+		total := int64(len(all))
+		start := (page - 1) * limit
+		if start > len(all) {
+			start = len(all)
+		}
+		end := start + limit
+		if end > len(all) {
+			end = len(all)
+		}
+		return all[start:end], total, nil
+	}
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	total, err := s.col.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	cursor, err := s.col.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page-1)*limit)).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+	posts := make([]models.Post, 0)
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, 0, err
+	}
+	return posts, total, nil
+}
+
+// Moderate hides a post from the feed with a reason, or restores it.
+func (s *PostService) Moderate(ctx context.Context, id, adminID primitive.ObjectID, hide bool, reason string) (models.Post, error) {
+	reason = strings.TrimSpace(reason)
+	if hide && reason == "" {
+		return models.Post{}, ErrHideReason
+	}
+	now := time.Now()
+	status := models.PostActive
+	if hide {
+		status = models.PostHidden
+	}
+	moderation := &models.Moderation{Reason: reason, ReviewedBy: &adminID, ReviewedAt: &now}
+	if s.col == nil {
+		postMemory.Lock()
+		defer postMemory.Unlock()
+		p, ok := postMemory.data[id.Hex()]
+		if !ok {
+			return models.Post{}, mongo.ErrNoDocuments
+		}
+		p.Status = status
+		p.Moderation = moderation
+		postMemory.data[id.Hex()] = p
+		return p, nil
+	}
+	var p models.Post
+	err := s.col.FindOneAndUpdate(ctx, bson.M{"_id": id},
+		bson.M{"$set": bson.M{"status": status, "moderation": moderation}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&p)
+	return p, err
+}
+
+// EnsureIndexes creates the indexes used by feeds, likes and comments.
+func (s *PostService) EnsureIndexes(ctx context.Context) error {
+	if s.col == nil {
+		return nil
+	}
+	if _, err := s.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "job_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+	}); err != nil {
+		return err
+	}
+	if _, err := s.likes.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "post_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+	_, err := s.comments.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "post_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	return err
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/recommend"
+)
+
+// contentPoolSize bounds how many items get an AI content score per request;
+// larger pools are first cut down by the cheap skill/collaborative/recency signals.
+const contentPoolSize = 200
+
+// RecommendationService recommends jobs to seekers and candidates to jobs by
+// combining AI content similarity, skill overlap, co-application signals and
+// recency (see package recommend).
+type RecommendationService struct {
+	jobs   *JobService
+	users  *UserService
+	apps   *JobApplicationService
+	scores *MatchScoreService // optional score cache
+	ai     Matcher
+	skills *SkillService // optional taxonomy for skill normalization
+}
+
+// JobRecommendation is a recommended job with its ranking details.
+type JobRecommendation struct {
+	recommend.Recommendation
+	Job models.Job
+}
+
+// CandidateRecommendation is a recommended seeker with its ranking details.
+type CandidateRecommendation struct {
+	recommend.Recommendation
+	Seeker models.User
+}
+
+// NewRecommendationService creates a RecommendationService. scores and skills may be nil.
+func NewRecommendationService(jobs *JobService, users *UserService, apps *JobApplicationService, scores *MatchScoreService, ai Matcher, skills *SkillService) *RecommendationService {
+	return &RecommendationService{jobs: jobs, users: users, apps: apps, scores: scores, ai: ai, skills: skills}
+}
+
+// RecommendJobs ranks jobs the seeker has not applied to.
+func (s *RecommendationService) RecommendJobs(ctx context.Context, seeker models.User, opts recommend.Options) ([]JobRecommendation, error) {
+	jobs, err := s.jobs.List(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	interactions, err := s.interactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ranked := s.rankJobs(ctx, seeker, jobs, interactions, s.scoreJobs, opts)
+	byID := make(map[string]models.Job, len(jobs))
+	for _, job := range jobs {
+		byID[job.ID.Hex()] = job
+	}
+	out := make([]JobRecommendation, len(ranked))
+	for i, rec := range ranked {
+		out[i] = JobRecommendation{Recommendation: rec, Job: byID[rec.ID]}
+	}
+	return out, nil
+}
+
+// RecommendCandidates ranks active seekers who have not applied to the job;
+// applicants are already listed on the job's applicants page.
+func (s *RecommendationService) RecommendCandidates(ctx context.Context, job models.Job, opts recommend.Options) ([]CandidateRecommendation, error) {
+	seekers, err := s.users.Search(ctx, models.RoleSeeker, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	interactions, err := s.interactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobID := job.ID.Hex()
+	pool := make([]models.User, 0, len(seekers))
+	byID := make(map[string]models.User, len(seekers))
+	for _, u := range seekers {
+		if u.IsActive != nil && !*u.IsActive {
+			continue
+		}
+		if interactions.Applied(u.ID.Hex(), jobID) {
+			continue
+		}
+		pool = append(pool, u)
+		byID[u.ID.Hex()] = u
+	}
+
+	items := make([]recommend.Item, len(pool))
+	for i, u := range pool {
+		items[i] = recommend.Item{ID: u.ID.Hex(), Skills: s.expand(ctx, u.Skills), CreatedAt: u.UpdatedAt}
+	}
+	query := recommend.Query{Skills: s.normalize(ctx, job.Skills), Required: true}
+	collaborative := interactions.CandidatesForJob(jobID)
+	items, keep := prefilter(query, items, collaborative, opts)
+	if keep != nil {
+		kept := make([]models.User, 0, len(items))
+		for _, u := range pool {
+			if keep[u.ID.Hex()] {
+				kept = append(kept, u)
+			}
+		}
+		pool = kept
+	}
+	scores := s.scoreSeekers(ctx, job, pool)
+	for i := range items {
+		items[i].Content = scores[items[i].ID]
+	}
+
+	ranked := recommend.Rank(query, items, collaborative, opts)
+	out := make([]CandidateRecommendation, len(ranked))
+	for i, rec := range ranked {
+		out[i] = CandidateRecommendation{Recommendation: rec, Seeker: byID[rec.ID]}
+	}
+	return out, nil
+}
+
+// Evaluate measures job recommendations offline against historical
+// applications (see recommend.Evaluate), scoring content with matcher instead
+// of the service's own so evaluation can run against a local engine.
+func (s *RecommendationService) Evaluate(ctx context.Context, k int, weights recommend.Weights, matcher Matcher) (recommend.Metrics, error) {
+	apps, err := s.applications(ctx)
+	if err != nil {
+		return recommend.Metrics{}, err
+	}
+	jobs, err := s.jobs.List(ctx, map[string]interface{}{})
+	if err != nil {
+		return recommend.Metrics{}, err
+	}
+	seekers, err := s.users.Search(ctx, models.RoleSeeker, "", nil)
+	if err != nil {
+		return recommend.Metrics{}, err
+	}
+	byID := make(map[string]models.User, len(seekers))
+	for _, u := range seekers {
+		byID[u.ID.Hex()] = u
+	}
+
+	score := func(ctx context.Context, seeker models.User, jobs []models.Job) map[string]*float64 {
+		return contentScores(matchItemsForSeeker(ctx, matcher, seeker, jobs), true)
+	}
+	rank := func(train *recommend.Interactions, seekerID string, k int) []string {
+		seeker, ok := byID[seekerID]
+		if !ok {
+			return nil
+		}
+		ranked := s.rankJobs(ctx, seeker, jobs, train, score, recommend.Options{Limit: k, Weights: weights, Diversity: recommend.DefaultDiversity})
+		ids := make([]string, len(ranked))
+		for i, rec := range ranked {
+			ids[i] = rec.ID
+		}
+		return ids
+	}
+	return recommend.Evaluate(apps, k, rank), nil
+}
+
+// rankJobs ranks the jobs a seeker has not applied to, per interactions.
+func (s *RecommendationService) rankJobs(ctx context.Context, seeker models.User, jobs []models.Job, interactions *recommend.Interactions, score func(context.Context, models.User, []models.Job) map[string]*float64, opts recommend.Options) []recommend.Recommendation {
+	seekerID := seeker.ID.Hex()
+	pool := make([]models.Job, 0, len(jobs))
+	items := make([]recommend.Item, 0, len(jobs))
+	for _, job := range jobs {
+		if interactions.Applied(seekerID, job.ID.Hex()) {
+			continue
+		}
+		pool = append(pool, job)
+		items = append(items, recommend.Item{
+			ID:        job.ID.Hex(),
+			Skills:    s.normalize(ctx, job.Skills),
+			CreatedAt: job.CreatedAt,
+			Group:     job.RecruiterID.Hex(),
+		})
+	}
+	query := recommend.Query{Skills: s.expand(ctx, seeker.Skills)}
+	collaborative := interactions.JobsForSeeker(seekerID)
+	items, keep := prefilter(query, items, collaborative, opts)
+	if keep != nil {
+		kept := make([]models.Job, 0, len(items))
+		for _, job := range pool {
+			if keep[job.ID.Hex()] {
+				kept = append(kept, job)
+			}
+		}
+		pool = kept
+	}
+	scores := score(ctx, seeker, pool)
+	for i := range items {
+		items[i].Content = scores[items[i].ID]
+	}
+	return recommend.Rank(query, items, collaborative, opts)
+}
+
+// prefilter keeps the contentPoolSize best items by the signals that need no
+// AI call. keep is nil when every item was kept.
+func prefilter(q recommend.Query, items []recommend.Item, collaborative map[string]float64, opts recommend.Options) ([]recommend.Item, map[string]bool) {
+	if len(items) <= contentPoolSize {
+		return items, nil
+	}
+	w := opts.Weights
+	if w == (recommend.Weights{}) {
+		w = recommend.DefaultWeights
+	}
+	w.Content = 0
+	if w == (recommend.Weights{}) {
+		w.Skills = 1
+	}
+	cheap := recommend.Rank(q, items, collaborative, recommend.Options{Limit: contentPoolSize, Weights: w, HalfLife: opts.HalfLife, Now: opts.Now})
+	keep := make(map[string]bool, len(cheap))
+	for _, rec := range cheap {
+		keep[rec.ID] = true
+	}
+	kept := make([]recommend.Item, 0, len(cheap))
+	for _, it := range items {
+		if keep[it.ID] {
+			kept = append(kept, it)
+		}
+	}
+	return kept, keep
+}
+
+// scoreJobs returns content scores by job ID, via the score cache when available.
+func (s *RecommendationService) scoreJobs(ctx context.Context, seeker models.User, jobs []models.Job) map[string]*float64 {
+	if s.scores != nil {
+		return contentScores(s.scores.GetOrComputeForSeeker(ctx, seeker, jobs), true)
+	}
+	return contentScores(matchItemsForSeeker(ctx, s.ai, seeker, jobs), true)
+}
+
+// scoreSeekers returns content scores by seeker ID, via the score cache when available.
+func (s *RecommendationService) scoreSeekers(ctx context.Context, job models.Job, seekers []models.User) map[string]*float64 {
+	if s.scores != nil {
+		return contentScores(s.scores.GetOrComputeForJob(ctx, job, seekers), false)
+	}
+	if s.ai == nil {
+		return nil
+	}
+	items := make([]MatchItem, len(seekers))
+	for i, u := range seekers {
+		items[i] = SeekerMatchItem(u)
+	}
+	return contentScores(s.ai.MatchJobAgainstCandidates(ctx, JobMatchItem(job), items), false)
+}
+
+func matchItemsForSeeker(ctx context.Context, ai Matcher, seeker models.User, jobs []models.Job) []MatchResult {
+	if ai == nil {
+		return nil
+	}
+	items := make([]MatchItem, len(jobs))
+	for i, job := range jobs {
+		items[i] = JobMatchItem(job)
+	}
+	return ai.MatchCandidateAgainstJobs(ctx, SeekerMatchItem(seeker), items)
+}
+
+// contentScores keys successful results by job ID (byJob) or candidate ID.
+// Failed pairs are left out so their content signal is treated as unavailable.
+func contentScores(results []MatchResult, byJob bool) map[string]*float64 {
+	out := make(map[string]*float64, len(results))
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		score := r.Score
+		if byJob {
+			out[r.JobID] = &score
+		} else {
+			out[r.CandidateID] = &score
+		}
+	}
+	return out
+}
+
+func (s *RecommendationService) interactions(ctx context.Context) (*recommend.Interactions, error) {
+	apps, err := s.applications(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return recommend.NewInteractions(apps), nil
+}
+
+func (s *RecommendationService) applications(ctx context.Context) ([]recommend.Application, error) {
+	if s.apps == nil {
+		return nil, nil
+	}
+	apps, err := s.apps.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]recommend.Application, 0, len(apps))
+	for _, a := range apps {
+		at := a.AppliedAt
+		if at.IsZero() {
+			at = a.CreatedAt
+		}
+		out = append(out, recommend.Application{SeekerID: a.JobSeekerID.Hex(), JobID: a.JobID.Hex(), AppliedAt: at})
+	}
+	return out, nil
+}
+
+func (s *RecommendationService) normalize(ctx context.Context, skills []string) []string {
+	if s.skills == nil {
+		return cleanSkills(skills)
+	}
+	return s.skills.Normalize(ctx, skills)
+}
+
+func (s *RecommendationService) expand(ctx context.Context, skills []string) []string {
+	if s.skills == nil {
+		return cleanSkills(skills)
+	}
+	return s.skills.Expand(ctx, skills)
+}
+
+func cleanSkills(skills []string) []string {
+	out := make([]string, 0, len(skills))
+	for _, sk := range skills {
+		if sk = strings.TrimSpace(sk); sk != "" {
+			out = append(out, sk)
+		}
+	}
+	return out
+}
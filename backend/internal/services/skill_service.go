@@ -0,0 +1,506 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+// skillIndexTTL bounds how stale the cached taxonomy can get on other instances.
+const skillIndexTTL = 5 * time.Minute
+
+// SkillService manages the skill taxonomy and canonicalizes free-form skills.
+type SkillService struct {
+	col *mongo.Collection
+
+	mu       sync.RWMutex
+	byKey    map[string]models.Skill // normalized slug/name/alias -> skill
+	bySlug   map[string]models.Skill
+	loadedAt time.Time
+}
+
+var errSkillSlugTaken = errors.New("skill slug already exists")
+
+var skillMemory = struct {
+	sync.Mutex
+	data map[string]models.Skill
+}{data: map[string]models.Skill{}}
+
+// NewSkillService creates a SkillService.
+func NewSkillService(db *mongo.Database) *SkillService {
+	if db == nil {
+		return &SkillService{col: nil}
+	}
+	return &SkillService{col: db.Collection("skills")}
+}
+
+// List returns taxonomy entries, optionally filtered by category, sorted by name.
+func (s *SkillService) List(ctx context.Context, category string) ([]models.Skill, error) {
+	all, err := s.loadAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	skills := make([]models.Skill, 0, len(all))
+	for _, sk := range all {
+		if category != "" && !strings.EqualFold(sk.Category, category) {
+			continue
+		}
+		skills = append(skills, sk)
+	}
+	sort.Slice(skills, func(i, j int) bool { return skills[i].Name < skills[j].Name })
+	return skills, nil
+}
+
+// FindBySlug returns a taxonomy entry by canonical id.
+func (s *SkillService) FindBySlug(ctx context.Context, slug string) (models.Skill, error) {
+	_, bySlug, err := s.index(ctx)
+	if err != nil {
+		return models.Skill{}, err
+	}
+	if sk, ok := bySlug[slug]; ok {
+		return sk, nil
+	}
+	return models.Skill{}, mongo.ErrNoDocuments
+}
+
+// Create adds a taxonomy entry after validating slug, aliases and parent.
+func (s *SkillService) Create(ctx context.Context, skill models.Skill) (models.Skill, error) {
+	skill = cleanSkill(skill)
+	if err := s.validate(ctx, skill, ""); err != nil {
+		return models.Skill{}, err
+	}
+	skill.CreatedAt = time.Now()
+	skill.UpdatedAt = time.Now()
+
+	if s.col == nil {
+		skillMemory.Lock()
+		skill.ID = primitive.NewObjectID()
+		skillMemory.data[skill.Slug] = skill
+		skillMemory.Unlock()
+		s.invalidate()
+		return skill, nil
+	}
+	res, err := s.col.InsertOne(ctx, skill)
+	if mongo.IsDuplicateKeyError(err) {
+		return models.Skill{}, errSkillSlugTaken
+	}
+	if err != nil {
+		return models.Skill{}, err
+	}
+	skill.ID = res.InsertedID.(primitive.ObjectID)
+	s.invalidate()
+	return skill, nil
+}
+
+// Update replaces the name, aliases, category and parent of an entry.
+func (s *SkillService) Update(ctx context.Context, slug string, skill models.Skill) (models.Skill, error) {
+	existing, err := s.FindBySlug(ctx, slug)
+	if err != nil {
+		return models.Skill{}, err
+	}
+	skill.Slug = slug
+	skill = cleanSkill(skill)
+	if err := s.validate(ctx, skill, slug); err != nil {
+		return models.Skill{}, err
+	}
+	skill.ID = existing.ID
+	skill.CreatedAt = existing.CreatedAt
+	skill.UpdatedAt = time.Now()
+
+	if s.col == nil {
+		skillMemory.Lock()
+		skillMemory.data[slug] = skill
+		skillMemory.Unlock()
+		s.invalidate()
+		return skill, nil
+	}
+	_, err = s.col.UpdateOne(ctx, bson.M{"slug": slug}, bson.M{"$set": bson.M{
+		"name":        skill.Name,
+		"aliases":     skill.Aliases,
+		"category":    skill.Category,
+		"parent_slug": skill.ParentSlug,
+		"updated_at":  skill.UpdatedAt,
+	}})
+	if err != nil {
+		return models.Skill{}, err
+	}
+	s.invalidate()
+	return skill, nil
+}
+
+// Delete removes an entry; entries that still have children cannot be removed.
+func (s *SkillService) Delete(ctx context.Context, slug string) error {
+	all, err := s.loadAll(ctx)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, sk := range all {
+		if sk.ParentSlug == slug {
+			return errors.New("skill has child skills; reassign them first")
+		}
+		if sk.Slug == slug {
+			found = true
+		}
+	}
+	if !found {
+		return mongo.ErrNoDocuments
+	}
+
+	if s.col == nil {
+		skillMemory.Lock()
+		delete(skillMemory.data, slug)
+		skillMemory.Unlock()
+		s.invalidate()
+		return nil
+	}
+	if _, err := s.col.DeleteOne(ctx, bson.M{"slug": slug}); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Canonicalize resolves a free-form skill (name, slug or alias) to its taxonomy entry.
+func (s *SkillService) Canonicalize(ctx context.Context, raw string) (models.Skill, bool) {
+	byKey, _, err := s.index(ctx)
+	if err != nil {
+		return models.Skill{}, false
+	}
+	sk, ok := byKey[models.SkillKey(raw)]
+	return sk, ok
+}
+
+// minVocabularyAlias is the shortest alias used for keyword extraction; two
+// letter aliases such as "ts" or "ml" match too many unrelated words.
+const minVocabularyAlias = 3
+
+// Vocabulary returns the skill names in the taxonomy plus their aliases of at
+// least minVocabularyAlias characters, for keyword based skill extraction.
+func (s *SkillService) Vocabulary(ctx context.Context) []string {
+	_, bySlug, err := s.index(ctx)
+	if err != nil {
+		return nil
+	}
+	vocab := make([]string, 0, len(bySlug)*2)
+	for _, sk := range bySlug {
+		vocab = append(vocab, sk.Name)
+		for _, a := range sk.Aliases {
+			if utf8.RuneCountInString(a) >= minVocabularyAlias {
+				vocab = append(vocab, a)
+			}
+		}
+	}
+	sort.Strings(vocab)
+	return vocab
+}
+
+// Normalize maps free-form skills to canonical display names, dropping blanks
+// and duplicates. Unknown skills are kept with whitespace cleaned up.
+func (s *SkillService) Normalize(ctx context.Context, skills []string) []string {
+	byKey, _, err := s.index(ctx)
+	if err != nil {
+		byKey = nil
+	}
+	seen := make(map[string]bool, len(skills))
+	out := make([]string, 0, len(skills))
+	for _, raw := range skills {
+		key := models.SkillKey(raw)
+		if key == "" {
+			continue
+		}
+		name := strings.Join(strings.Fields(raw), " ")
+		if sk, ok := byKey[key]; ok {
+			name = sk.Name
+		}
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		out = append(out, name)
+	}
+	return out
+}
+
+// NormalizeProficiencies canonicalizes skill names and validates levels.
+func (s *SkillService) NormalizeProficiencies(ctx context.Context, levels []models.SkillProficiency) ([]models.SkillProficiency, error) {
+	seen := map[string]bool{}
+	out := make([]models.SkillProficiency, 0, len(levels))
+	for _, lvl := range levels {
+		names := s.Normalize(ctx, []string{lvl.Skill})
+		if len(names) == 0 {
+			continue
+		}
+		lvl.Skill = names[0]
+		lvl.Level = strings.ToLower(strings.TrimSpace(lvl.Level))
+		switch lvl.Level {
+		case "", models.ProficiencyBeginner, models.ProficiencyIntermediate, models.ProficiencyAdvanced, models.ProficiencyExpert:
+		default:
+			return nil, errors.New("invalid proficiency level for " + lvl.Skill)
+		}
+		if lvl.Years < 0 {
+			return nil, errors.New("skill years cannot be negative")
+		}
+		if seen[strings.ToLower(lvl.Skill)] {
+			continue
+		}
+		seen[strings.ToLower(lvl.Skill)] = true
+		out = append(out, lvl)
+	}
+	return out, nil
+}
+
+// Expand returns the normalized skills plus all of their taxonomy ancestors,
+// so a candidate who knows React also counts as knowing JavaScript.
+func (s *SkillService) Expand(ctx context.Context, skills []string) []string {
+	normalized := s.Normalize(ctx, skills)
+	_, bySlug, err := s.index(ctx)
+	if err != nil {
+		return normalized
+	}
+	seen := make(map[string]bool, len(normalized))
+	for _, name := range normalized {
+		seen[strings.ToLower(name)] = true
+	}
+	out := append([]string{}, normalized...)
+	for _, name := range normalized {
+		sk, ok := s.Canonicalize(ctx, name)
+		if !ok {
+			continue
+		}
+		// Walk up the parent chain; depth guard protects against bad data.
+		for depth := 0; sk.ParentSlug != "" && depth < 10; depth++ {
+			parent, ok := bySlug[sk.ParentSlug]
+			if !ok {
+				break
+			}
+			if !seen[strings.ToLower(parent.Name)] {
+				seen[strings.ToLower(parent.Name)] = true
+				out = append(out, parent.Name)
+			}
+			sk = parent
+		}
+	}
+	return out
+}
+
+// Category returns the taxonomy category of a skill, or "" when unknown.
+func (s *SkillService) Category(ctx context.Context, raw string) string {
+	if sk, ok := s.Canonicalize(ctx, raw); ok {
+		return sk.Category
+	}
+	return ""
+}
+
+func cleanSkill(skill models.Skill) models.Skill {
+	skill.Slug = strings.ReplaceAll(models.SkillKey(skill.Slug), " ", "-")
+	skill.Name = strings.Join(strings.Fields(skill.Name), " ")
+	skill.Category = strings.ToLower(strings.TrimSpace(skill.Category))
+	skill.ParentSlug = strings.ReplaceAll(models.SkillKey(skill.ParentSlug), " ", "-")
+	aliases := make([]string, 0, len(skill.Aliases))
+	seen := map[string]bool{}
+	for _, a := range skill.Aliases {
+		key := models.SkillKey(a)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		aliases = append(aliases, key)
+	}
+	skill.Aliases = aliases
+	return skill
+}
+
+// validate checks an entry against the taxonomy; current is the slug being updated.
+func (s *SkillService) validate(ctx context.Context, skill models.Skill, current string) error {
+	if skill.Slug == "" || skill.Name == "" {
+		return errors.New("slug and name are required")
+	}
+	byKey, bySlug, err := s.index(ctx)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		if _, exists := bySlug[skill.Slug]; exists {
+			return errSkillSlugTaken
+		}
+	}
+	for _, key := range append([]string{models.SkillKey(skill.Name), models.SkillKey(skill.Slug)}, skill.Aliases...) {
+		if other, ok := byKey[key]; ok && other.Slug != current {
+			return errors.New("\"" + key + "\" already refers to " + other.Name)
+		}
+	}
+	if skill.ParentSlug != "" {
+		if skill.ParentSlug == skill.Slug {
+			return errors.New("skill cannot be its own parent")
+		}
+		parent, ok := bySlug[skill.ParentSlug]
+		if !ok {
+			return errors.New("parent skill not found")
+		}
+		for depth := 0; parent.ParentSlug != "" && depth < 10; depth++ {
+			if parent.ParentSlug == skill.Slug {
+				return errors.New("parent relation would create a cycle")
+			}
+			if parent, ok = bySlug[parent.ParentSlug]; !ok {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SkillService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// index returns the cached lookup maps, reloading them when stale.
+func (s *SkillService) index(ctx context.Context) (map[string]models.Skill, map[string]models.Skill, error) {
+	s.mu.RLock()
+	if s.byKey != nil && time.Since(s.loadedAt) < skillIndexTTL {
+		byKey, bySlug := s.byKey, s.bySlug
+		s.mu.RUnlock()
+		return byKey, bySlug, nil
+	}
+	s.mu.RUnlock()
+
+	all, err := s.loadAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	byKey := make(map[string]models.Skill, len(all)*3)
+	bySlug := make(map[string]models.Skill, len(all))
+	for _, sk := range all {
+		bySlug[sk.Slug] = sk
+		byKey[sk.Slug] = sk
+		byKey[models.SkillKey(sk.Name)] = sk
+		for _, a := range sk.Aliases {
+			byKey[models.SkillKey(a)] = sk
+		}
+	}
+
+	s.mu.Lock()
+	s.byKey, s.bySlug, s.loadedAt = byKey, bySlug, time.Now()
+	s.mu.Unlock()
+	return byKey, bySlug, nil
+}
+
+// EnsureIndexes creates the unique slug index the taxonomy relies on.
+func (s *SkillService) EnsureIndexes(ctx context.Context) error {
+	if s.col == nil {
+		return nil
+	}
+	_, err := s.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// loadAll reads the whole taxonomy, seeding the defaults on first use.
+func (s *SkillService) loadAll(ctx context.Context) ([]models.Skill, error) {
+	if s.col == nil {
+		skillMemory.Lock()
+		defer skillMemory.Unlock()
+		if len(skillMemory.data) == 0 {
+			for _, sk := range defaultSkills() {
+				sk.ID = primitive.NewObjectID()
+				skillMemory.data[sk.Slug] = sk
+			}
+		}
+		skills := make([]models.Skill, 0, len(skillMemory.data))
+		for _, sk := range skillMemory.data {
+			skills = append(skills, sk)
+		}
+		return skills, nil
+	}
+
+	count, err := s.col.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		// Upserts keyed on slug let concurrent first loads seed without
+		// duplicating entries.
+		writes := make([]mongo.WriteModel, 0)
+		for _, sk := range defaultSkills() {
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"slug": sk.Slug}).
+				SetUpdate(bson.M{"$setOnInsert": sk}).
+				SetUpsert(true))
+		}
+		if _, err := s.col.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+	}
+	cur, err := s.col.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+	var skills []models.Skill
+	if err := cur.All(ctx, &skills); err != nil {
+		return nil, err
+	}
+	return skills, nil
+}
+
+// defaultSkills is the starter taxonomy admins can curate from. Aliases that
+// are common words or name a different product ("spring", "node", "github")
+// are left out: they would canonicalize unrelated skills.
+func defaultSkills() []models.Skill {
+	now := time.Now()
+	entry := func(slug, name, category, parent string, aliases ...string) models.Skill {
+		return models.Skill{Slug: slug, Name: name, Category: category, ParentSlug: parent, Aliases: aliases, CreatedAt: now, UpdatedAt: now}
+	}
+	return []models.Skill{
+		entry("javascript", "JavaScript", "language", "", "js", "ecmascript", "es6"),
+		entry("typescript", "TypeScript", "language", "javascript", "ts"),
+		entry("react", "React", "framework", "javascript", "react.js", "reactjs"),
+		entry("nextjs", "Next.js", "framework", "react", "next js"),
+		entry("vue", "Vue", "framework", "javascript", "vue.js", "vuejs"),
+		entry("angular", "Angular", "framework", "typescript", "angularjs", "angular.js"),
+		entry("nodejs", "Node.js", "runtime", "javascript", "node js"),
+		entry("express", "Express", "framework", "nodejs", "express.js", "expressjs"),
+		entry("python", "Python", "language", "", "py", "python3"),
+		entry("django", "Django", "framework", "python"),
+		entry("flask", "Flask", "framework", "python"),
+		entry("fastapi", "FastAPI", "framework", "python", "fast api"),
+		entry("go", "Go", "language", "", "golang"),
+		entry("java", "Java", "language", ""),
+		entry("spring-boot", "Spring Boot", "framework", "java", "springboot"),
+		entry("csharp", "C#", "language", "", "c sharp", "c-sharp"),
+		entry("cpp", "C++", "language", "", "cplusplus", "c plus plus"),
+		entry("sql", "SQL", "database", ""),
+		entry("postgresql", "PostgreSQL", "database", "sql", "postgres", "psql"),
+		entry("mysql", "MySQL", "database", "sql"),
+		entry("mongodb", "MongoDB", "database", "", "mongo"),
+		entry("html", "HTML", "language", "", "html5"),
+		entry("css", "CSS", "language", "", "css3"),
+		entry("tailwind", "Tailwind CSS", "framework", "css", "tailwind", "tailwindcss"),
+		entry("docker", "Docker", "devops", ""),
+		entry("kubernetes", "Kubernetes", "devops", "", "k8s"),
+		entry("aws", "AWS", "cloud", "", "amazon web services"),
+		entry("gcp", "Google Cloud", "cloud", "", "google cloud platform"),
+		entry("azure", "Azure", "cloud", "", "microsoft azure"),
+		entry("git", "Git", "tool", "", "version control"),
+		entry("machine-learning", "Machine Learning", "data", "", "ml"),
+		entry("deep-learning", "Deep Learning", "data", "machine-learning", "dl"),
+		entry("data-science", "Data Science", "data", ""),
+		entry("solidity", "Solidity", "language", ""),
+		entry("blockchain", "Blockchain", "domain", "", "web3"),
+		entry("cyber-security", "Cyber Security", "domain", "", "cybersecurity", "infosec"),
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"rizeos/backend/internal/models"
+)
+
+// UserQuery selects active users for bulk delivery such as announcements.
+type UserQuery struct {
+	Roles       []string
+	PremiumOnly bool
+	AnySkills   []string // users with at least one of these skills, ignoring case
+	Location    string   // users whose location contains this, ignoring case
+}
+
+func (q UserQuery) matches(u models.User) bool {
+	if u.IsActive != nil && !*u.IsActive {
+		return false
+	}
+	if len(q.Roles) > 0 {
+		found := false
+		for _, r := range q.Roles {
+			found = found || u.Role == r
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.PremiumOnly && !u.IsPremium {
+		return false
+	}
+	if len(q.AnySkills) > 0 {
+		found := false
+		for _, want := range q.AnySkills {
+			for _, have := range u.Skills {
+				found = found || strings.EqualFold(want, have)
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return q.Location == "" || strings.Contains(strings.ToLower(u.Location), strings.ToLower(q.Location))
+}
+
+func (q UserQuery) filter() bson.M {
+	filter := bson.M{"is_active": bson.M{"$ne": false}}
+	if len(q.Roles) > 0 {
+		filter["role"] = bson.M{"$in": q.Roles}
+	}
+	if q.PremiumOnly {
+		filter["is_premium"] = true
+	}
+	if len(q.AnySkills) > 0 {
+		// Ignore case like matches does: older profiles store skills as typed.
+		skills := make(bson.A, 0, len(q.AnySkills))
+		for _, skill := range q.AnySkills {
+			skills = append(skills, primitive.Regex{Pattern: "^" + regexp.QuoteMeta(skill) + "$", Options: "i"})
+		}
+		filter["skills"] = bson.M{"$in": skills}
+	}
+	if q.Location != "" {
+		filter["location"] = bson.M{"$regex": regexp.QuoteMeta(q.Location), "$options": "i"}
+	}
+	return filter
+}
+
+// CountUsers returns the number of users matching q.
+func (s *UserService) CountUsers(ctx context.Context, q UserQuery) (int64, error) {
+	if s.col == nil {
+		userMemory.Lock()
+		defer userMemory.Unlock()
+		var n int64
+		for _, u := range userMemory.data {
+			if q.matches(u) {
+				n++
+			}
+		}
+		return n, nil
+	}
+	return s.col.CountDocuments(ctx, q.filter())
+}
+
+// ListAfter returns up to limit users matching q with ids after the given
+// one (all for a zero id), in id order, for paging through large audiences.
+// Only ID and Role are guaranteed to be set.
+func (s *UserService) ListAfter(ctx context.Context, q UserQuery, after primitive.ObjectID, limit int) ([]models.User, error) {
+	if s.col == nil {
+		userMemory.Lock()
+		users := make([]models.User, 0)
+		for _, u := range userMemory.data {
+			if q.matches(u) && u.ID.Hex() > after.Hex() {
+				users = append(users, u)
+			}
+		}
+		userMemory.Unlock()
+		sort.Slice(users, func(i, j int) bool { return users[i].ID.Hex() < users[j].ID.Hex() })
+		if len(users) > limit {
+			users = users[:limit]
+		}
+		return users, nil
+	}
+	filter := q.filter()
+	if !after.IsZero() {
+		filter["_id"] = bson.M{"$gt": after}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"_id": 1, "role": 1})
+	cursor, err := s.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	users := make([]models.User, 0)
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"rizeos/backend/internal/utils"
+)
+
+// MigrateLegacyProfiles converts pre-structured profile fields into typed lists.
+//
+// Older documents stored experience as a free "string or number", education as
+// a single string and tenth/twelfth marks as separate fields. These are parsed
+// into Experience/Education entries, experience_years is computed and the
+// legacy marks fields are removed. Returns the number of migrated users.
+func (s *UserService) MigrateLegacyProfiles(ctx context.Context) (int, error) {
+	if s.col == nil {
+		// In-memory users are always created with typed fields.
+		return 0, nil
+	}
+
+	scalarTypes := bson.A{"string", "double", "int", "long", "decimal"}
+	filter := bson.M{"$or": bson.A{
+		bson.M{"experience": bson.M{"$type": scalarTypes}},
+		bson.M{"education": bson.M{"$type": "string"}},
+		bson.M{"tenth_marks": bson.M{"$exists": true}},
+		bson.M{"twelfth_marks": bson.M{"$exists": true}},
+	}}
+	cur, err := s.col.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close(ctx)
+
+	migrated := 0
+	for cur.Next(ctx) {
+		var doc bson.M
+		if err := cur.Decode(&doc); err != nil {
+			return migrated, err
+		}
+
+		set, unset := LegacyProfileUpdate(doc, time.Now())
+		if _, err := s.col.UpdateByID(ctx, doc["_id"], bson.M{"$set": set, "$unset": unset}); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, cur.Err()
+}
+
+// LegacyProfileUpdate computes the $set and $unset documents that convert a
+// legacy user document into structured Experience/Education fields.
+func LegacyProfileUpdate(doc bson.M, now time.Time) (set bson.M, unset bson.M) {
+	set = bson.M{"updated_at": now}
+	unset = bson.M{"tenth_marks": "", "twelfth_marks": ""}
+
+	// Only rewrite fields still in their legacy shape.
+	if _, isList := doc["experience"].(bson.A); !isList {
+		positions := utils.LegacyPositions(doc["experience"])
+		if len(positions) > 0 {
+			set["experience"] = positions
+			set["experience_years"] = utils.TotalExperienceYears(positions, now)
+		} else {
+			unset["experience"] = ""
+		}
+	}
+	existing, structured := doc["education"].(bson.A)
+	var legacyEducation interface{}
+	if !structured {
+		legacyEducation = doc["education"]
+	}
+	degrees := utils.LegacyDegrees(legacyEducation, doc["tenth_marks"], doc["twelfth_marks"])
+	switch {
+	case structured && len(degrees) > 0:
+		// Append marks to an already structured education history.
+		combined := append(bson.A{}, existing...)
+		for _, d := range degrees {
+			combined = append(combined, d)
+		}
+		set["education"] = combined
+	case len(degrees) > 0:
+		set["education"] = degrees
+	case !structured:
+		unset["education"] = ""
+	}
+	return set, unset
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -16,7 +17,8 @@ import (
 
 // UserService handles user persistence.
 type UserService struct {
-	col *mongo.Collection
+	col             *mongo.Collection
+	onProfileUpdate []func(ctx context.Context, user models.User)
 }
 
 var userMemory = struct {
@@ -155,6 +157,30 @@ func (s *UserService) Search(ctx context.Context, role string, name string, skil
 	return users, nil
 }
 
+// ListPage returns a page (from 1) of all users, newest first, without
+// password hashes, and the number of users.
+func (s *UserService) ListPage(ctx context.Context, page, limit int) ([]models.User, int64, error) {
+	users := make([]models.User, 0)
+	if s.col == nil {
+		userMemory.Lock()
+		for _, u := range userMemory.data {
+			u.PasswordHash = ""
+			users = append(users, u)
+		}
+		userMemory.Unlock()
+		sort.Slice(users, func(i, j int) bool {
+			return newerThan(users[i].CreatedAt, users[i].ID, users[j].CreatedAt, users[j].ID)
+		})
+		start, end := pageBounds(len(users), page, limit)
+		return users[start:end], int64(len(users)), nil
+	}
+	total, err := findPage(ctx, s.col, page, limit, bson.M{"password_hash": 0}, &users)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
 // FindByEmail returns user by email.
 func (s *UserService) FindByEmail(ctx context.Context, email string) (models.User, error) {
 	if s.col == nil {
@@ -191,8 +217,24 @@ func (s *UserService) FindByID(ctx context.Context, id primitive.ObjectID) (mode
 	return user, nil
 }
 
-// UpdateProfile updates profile fields.
+// UpdateProfile updates profile fields and notifies OnProfileUpdate listeners.
 func (s *UserService) UpdateProfile(ctx context.Context, id primitive.ObjectID, update bson.M) (models.User, error) {
+	user, err := s.updateProfile(ctx, id, update)
+	if err != nil {
+		return models.User{}, err
+	}
+	for _, fn := range s.onProfileUpdate {
+		fn(ctx, user)
+	}
+	return user, nil
+}
+
+// OnProfileUpdate registers fn to run after a user's profile changes.
+func (s *UserService) OnProfileUpdate(fn func(ctx context.Context, user models.User)) {
+	s.onProfileUpdate = append(s.onProfileUpdate, fn)
+}
+
+func (s *UserService) updateProfile(ctx context.Context, id primitive.ObjectID, update bson.M) (models.User, error) {
 	if s.col == nil {
 		userMemory.Lock()
 		defer userMemory.Unlock()
@@ -215,10 +257,26 @@ func (s *UserService) UpdateProfile(ctx context.Context, id primitive.ObjectID,
 		if v, ok := update["skills"].([]string); ok {
 			u.Skills = v
 		}
+		if v, ok := update["location"].(string); ok {
+			u.Location = v
+		}
+		if v, ok := update["skill_levels"].([]models.SkillProficiency); ok {
+			u.SkillLevels = v
+		}
+		if v, ok := update["education"].([]models.Degree); ok {
+			u.Education = v
+		}
+		if v, ok := update["experience"].([]models.Position); ok {
+			u.Experience = v
+			u.ExperienceYears = utils.TotalExperienceYears(v, time.Now())
+		}
 		u.UpdatedAt = time.Now()
 		userMemory.data[id.Hex()] = u
 		return u, nil
 	}
+	if v, ok := update["experience"].([]models.Position); ok {
+		update["experience_years"] = utils.TotalExperienceYears(v, time.Now())
+	}
 	update["updated_at"] = time.Now()
 	_, err := s.col.UpdateByID(ctx, id, bson.M{"$set": update})
 	if err != nil {
@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInfected is returned by a Scanner that finds malware in a file.
+var ErrInfected = errors.New("file failed the virus scan")
+
+// Scanner checks uploaded files before they are stored. Implementations
+// return ErrInfected (or wrap it) to reject a file and any other error when
+// the scan could not run.
+type Scanner interface {
+	Scan(ctx context.Context, name string, data []byte) error
+}
+
+// NopScanner accepts every file. It is the default until a real scanner,
+// such as a clamd client, is configured.
+type NopScanner struct{}
+
+// Scan accepts the file.
+func (NopScanner) Scan(ctx context.Context, name string, data []byte) error {
+	return nil
+}
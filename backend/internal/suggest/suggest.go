@@ -0,0 +1,342 @@
+// Package suggest proposes job postings from platform supply and demand.
+//
+// Supply is how many seekers list a skill; demand is how many jobs ask for it.
+// Skills with the most supply per unit of demand seed clusters, which grow by
+// adding the skill that keeps the largest share of seekers having every skill
+// in the cluster (co-occurrence). Each cluster becomes a suggestion with the
+// matching candidate pool, budget benchmarks from similar existing jobs, a
+// title and a templated description.
+package suggest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"rizeos/backend/internal/models"
+)
+
+// Defaults for Options fields left zero.
+const (
+	DefaultLimit       = 3
+	DefaultClusterSize = 3
+	DefaultMinSupport  = 2
+)
+
+// Job is an existing job posting, used for demand, budgets and titles.
+type Job struct {
+	Title  string
+	Skills []string
+	Budget float64
+}
+
+// Input is the platform snapshot suggestions are computed from. Skills should
+// already be canonicalized so different spellings count together.
+type Input struct {
+	SeekerSkills [][]string // one skill set per job seeker
+	Jobs         []Job      // all jobs on the platform
+	Existing     [][]string // skill sets of the requesting recruiter's jobs, never re-suggested
+	// Category returns a skill's taxonomy category for templates; may be nil.
+	Category func(skill string) string
+}
+
+// Options tune Analyze.
+type Options struct {
+	Limit       int
+	ClusterSize int // skills per suggestion
+	MinSupport  int // minimum candidate pool for a suggestion
+	Templates   *Templates
+}
+
+// Budget summarizes budgets of similar existing jobs.
+type Budget struct {
+	Median  float64 `json:"median"`
+	P25     float64 `json:"p25"`
+	P75     float64 `json:"p75"`
+	Samples int     `json:"samples"`
+}
+
+// Suggestion is a proposed job posting.
+type Suggestion struct {
+	Title             string   `json:"title"`
+	Skills            []string `json:"skills"`
+	Description       string   `json:"description"`
+	Reason            string   `json:"reason"`
+	Confidence        float64  `json:"confidence"`        // 0-1
+	CandidatePoolSize int      `json:"candidatePoolSize"` // seekers with every suggested skill
+	Demand            int      `json:"demand"`            // jobs asking for at least half of the skills
+	Budget            *Budget  `json:"budget,omitempty"`  // nil without comparable budgeted jobs
+}
+
+type skillStat struct {
+	name    string
+	seekers map[int]bool
+	demand  int
+}
+
+// Analyze returns up to opts.Limit suggestions, best opportunity first.
+func Analyze(in Input, opts Options) []Suggestion {
+	opts = withDefaults(opts)
+
+	stats := map[string]*skillStat{}
+	stat := func(skill string) *skillStat {
+		key := models.SkillKey(skill)
+		if key == "" {
+			return nil
+		}
+		if stats[key] == nil {
+			stats[key] = &skillStat{name: strings.TrimSpace(skill), seekers: map[int]bool{}}
+		}
+		return stats[key]
+	}
+	for i, skills := range in.SeekerSkills {
+		for _, skill := range skills {
+			if st := stat(skill); st != nil {
+				st.seekers[i] = true
+			}
+		}
+	}
+	for _, job := range in.Jobs {
+		for key := range models.SkillKeySet(job.Skills) {
+			if st := stats[key]; st != nil {
+				st.demand++
+			}
+		}
+	}
+
+	seeds := make([]string, 0, len(stats))
+	for key, st := range stats {
+		if len(st.seekers) >= opts.MinSupport {
+			seeds = append(seeds, key)
+		}
+	}
+	opportunity := func(key string) float64 {
+		return float64(len(stats[key].seekers)) / float64(stats[key].demand+1)
+	}
+	sort.Slice(seeds, func(i, j int) bool {
+		oi, oj := opportunity(seeds[i]), opportunity(seeds[j])
+		if oi != oj {
+			return oi > oj
+		}
+		return seeds[i] < seeds[j]
+	})
+
+	type cluster struct {
+		keys     []string
+		pool     map[int]bool
+		cohesion float64
+	}
+	var clusters []cluster
+	used := map[string]bool{} // skills already suggested are not reused as seeds
+	for _, seed := range seeds {
+		if used[seed] {
+			continue
+		}
+		c := cluster{keys: []string{seed}, pool: stats[seed].seekers}
+		for len(c.keys) < opts.ClusterSize {
+			best, bestPool := "", map[int]bool(nil)
+			for _, key := range seeds {
+				if containsKey(c.keys, key) {
+					continue
+				}
+				pool := intersect(c.pool, stats[key].seekers)
+				if len(pool) >= opts.MinSupport && len(pool) > len(bestPool) {
+					best, bestPool = key, pool
+				}
+			}
+			if best == "" {
+				break
+			}
+			c.keys = append(c.keys, best)
+			c.pool = bestPool
+		}
+		if len(c.keys) < 2 || coveredBy(c.keys, in.Existing) {
+			continue
+		}
+		c.cohesion = float64(len(c.pool)) / float64(len(stats[seed].seekers))
+		for _, key := range c.keys {
+			used[key] = true
+		}
+		clusters = append(clusters, c)
+	}
+
+	tmpl := opts.Templates
+	if tmpl == nil {
+		tmpl = DefaultTemplates()
+	}
+	out := make([]Suggestion, 0, len(clusters))
+	for _, c := range clusters {
+		names := make([]string, len(c.keys))
+		for i, key := range c.keys {
+			names[i] = stats[key].name
+		}
+		similar := similarJobs(c.keys, in.Jobs)
+		budget := budgetOf(similar)
+		samples := 0
+		if budget != nil {
+			samples = budget.Samples
+		}
+		data := TemplateData{Lead: names[0], Skills: names, PoolSize: len(c.pool), Budget: budget}
+		if in.Category != nil {
+			data.Category = in.Category(names[0])
+		}
+		data.Title = commonTitle(similar)
+		if data.Title == "" {
+			data.Title = tmpl.title(data)
+		}
+		poolFactor := 1 - math.Exp(-float64(len(c.pool))/5)
+		confidence := 0.5*poolFactor + 0.3*c.cohesion + 0.2*math.Min(1, float64(samples)/5)
+		out = append(out, Suggestion{
+			Title:             data.Title,
+			Skills:            names,
+			Description:       tmpl.description(data),
+			Reason:            reason(len(c.pool), len(similar)),
+			Confidence:        math.Round(confidence*100) / 100,
+			CandidatePoolSize: len(c.pool),
+			Demand:            len(similar),
+			Budget:            budget,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		oi := float64(out[i].CandidatePoolSize) / float64(out[i].Demand+1)
+		oj := float64(out[j].CandidatePoolSize) / float64(out[j].Demand+1)
+		return oi > oj
+	})
+	if len(out) > opts.Limit {
+		out = out[:opts.Limit]
+	}
+	return out
+}
+
+func withDefaults(opts Options) Options {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLimit
+	}
+	if opts.ClusterSize < 2 {
+		opts.ClusterSize = DefaultClusterSize
+	}
+	if opts.MinSupport <= 0 {
+		opts.MinSupport = DefaultMinSupport
+	}
+	return opts
+}
+
+func reason(pool, demand int) string {
+	switch demand {
+	case 0:
+		return fmt.Sprintf("%d seekers have all of these skills and no job on the platform asks for them yet", pool)
+	case 1:
+		return fmt.Sprintf("%d seekers have all of these skills; 1 job on the platform asks for them", pool)
+	default:
+		return fmt.Sprintf("%d seekers have all of these skills; %d jobs on the platform ask for them", pool, demand)
+	}
+}
+
+// similarJobs returns jobs that ask for at least half of the cluster's skills.
+func similarJobs(keys []string, jobs []Job) []Job {
+	need := (len(keys) + 1) / 2
+	var out []Job
+	for _, job := range jobs {
+		have := models.SkillKeySet(job.Skills)
+		n := 0
+		for _, key := range keys {
+			if have[key] {
+				n++
+			}
+		}
+		if n >= need {
+			out = append(out, job)
+		}
+	}
+	return out
+}
+
+// budgetOf returns quartiles of positive budgets, or nil when there are none.
+func budgetOf(jobs []Job) *Budget {
+	var budgets []float64
+	for _, job := range jobs {
+		if job.Budget > 0 {
+			budgets = append(budgets, job.Budget)
+		}
+	}
+	if len(budgets) == 0 {
+		return nil
+	}
+	sort.Float64s(budgets)
+	return &Budget{
+		Median:  quantile(budgets, 0.5),
+		P25:     quantile(budgets, 0.25),
+		P75:     quantile(budgets, 0.75),
+		Samples: len(budgets),
+	}
+}
+
+// quantile linearly interpolates a sorted slice.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	v := sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+	return math.Round(v*100) / 100
+}
+
+// commonTitle returns the most frequent title among jobs, ties broken
+// alphabetically, or "" when there are none.
+func commonTitle(jobs []Job) string {
+	counts := map[string]int{}
+	names := map[string]string{}
+	for _, job := range jobs {
+		key := models.SkillKey(job.Title)
+		if key == "" {
+			continue
+		}
+		counts[key]++
+		if names[key] == "" {
+			names[key] = strings.TrimSpace(job.Title)
+		}
+	}
+	best := ""
+	for key, n := range counts {
+		if best == "" || n > counts[best] || (n == counts[best] && key < best) {
+			best = key
+		}
+	}
+	return names[best]
+}
+
+func coveredBy(keys []string, existing [][]string) bool {
+	for _, skills := range existing {
+		have := models.SkillKeySet(skills)
+		all := true
+		for _, key := range keys {
+			if !have[key] {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+func intersect(a, b map[int]bool) map[int]bool {
+	out := map[int]bool{}
+	for k := range a {
+		if b[k] {
+			out[k] = true
+		}
+	}
+	return out
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package suggest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// Built-in templates, used for any template a configuration file leaves empty.
+const (
+	DefaultTitleTemplate       = `{{.Lead}} Developer`
+	DefaultDescriptionTemplate = `We are looking for a {{.Title}} with expertise in {{join .Skills ", "}}.` +
+		`{{if .Budget}} Comparable roles on the platform budget around {{printf "%.0f" .Budget.Median}}.{{end}}` +
+		` Join our team and work on exciting projects.`
+)
+
+// TemplateData is available to title and description templates.
+type TemplateData struct {
+	Title    string   // resolved title; empty while rendering the title template
+	Lead     string   // the skill the suggestion was seeded from
+	Category string   // taxonomy category of Lead, when known
+	Skills   []string // all suggested skills, Lead first
+	PoolSize int      // seekers with every suggested skill
+	Budget   *Budget  // nil without comparable budgeted jobs
+}
+
+// Templates render suggestion titles (when no similar job title exists) and
+// descriptions with text/template; the join function joins string slices.
+type Templates struct {
+	titleTmpl       *template.Template
+	descriptionTmpl *template.Template
+}
+
+var funcs = template.FuncMap{"join": strings.Join}
+
+// NewTemplates parses title and description templates; empty strings use the
+// built-in defaults.
+func NewTemplates(title, description string) (*Templates, error) {
+	if strings.TrimSpace(title) == "" {
+		title = DefaultTitleTemplate
+	}
+	if strings.TrimSpace(description) == "" {
+		description = DefaultDescriptionTemplate
+	}
+	t, err := template.New("title").Funcs(funcs).Parse(title)
+	if err != nil {
+		return nil, fmt.Errorf("title template: %w", err)
+	}
+	d, err := template.New("description").Funcs(funcs).Parse(description)
+	if err != nil {
+		return nil, fmt.Errorf("description template: %w", err)
+	}
+	return &Templates{titleTmpl: t, descriptionTmpl: d}, nil
+}
+
+// DefaultTemplates returns the built-in templates.
+func DefaultTemplates() *Templates {
+	t, err := NewTemplates("", "")
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// LoadTemplates reads {"title": "...", "description": "..."} from a JSON file.
+// An empty path returns the built-in templates.
+func LoadTemplates(path string) (*Templates, error) {
+	if strings.TrimSpace(path) == "" {
+		return DefaultTemplates(), nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return NewTemplates(cfg.Title, cfg.Description)
+}
+
+func (t *Templates) title(data TemplateData) string {
+	if out := render(t.titleTmpl, data); out != "" {
+		return out
+	}
+	return render(DefaultTemplates().titleTmpl, data)
+}
+
+func (t *Templates) description(data TemplateData) string {
+	if out := render(t.descriptionTmpl, data); out != "" {
+		return out
+	}
+	return render(DefaultTemplates().descriptionTmpl, data)
+}
+
+// render executes tmpl, returning "" on error so callers fall back to defaults.
+func render(tmpl *template.Template, data TemplateData) string {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(buf.String())
+}
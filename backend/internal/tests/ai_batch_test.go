@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"rizeos/backend/internal/services"
+)
+
+func TestBatchMatchUsesBatchEndpoint(t *testing.T) {
+	var singleCalls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/match/batch":
+			var req struct {
+				Jobs       []struct{ ID string } `json:"jobs"`
+				Candidates []struct{ ID string } `json:"candidates"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			// Leave out the last candidate to exercise per-item errors.
+			results := []map[string]interface{}{}
+			for _, c := range req.Candidates[:len(req.Candidates)-1] {
+				results = append(results, map[string]interface{}{"job_id": req.Jobs[0].ID, "candidate_id": c.ID, "score": 80.0, "semantic_score": 75.0})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		default:
+			atomic.AddInt32(&singleCalls, 1)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"score": 10.0})
+		}
+	}))
+	defer srv.Close()
+
+	ai := services.NewAIService(srv.URL)
+	job := services.MatchItem{ID: "job", Text: "Go developer", Skills: []string{"Go"}}
+	results := ai.MatchJobAgainstCandidates(context.Background(), job, []services.MatchItem{
+		{ID: "a", Text: "Go engineer"},
+		{ID: "empty"},
+		{ID: "b", Text: "Designer"},
+	})
+	if len(results) != 3 || results[0].Err != nil || results[0].Score != 80 || results[0].SemanticScore == nil {
+		t.Fatalf("unexpected first result: %+v", results)
+	}
+	if results[1].Err != nil || results[1].Score != 0 {
+		t.Fatalf("empty bio should score 0 without error, got %+v", results[1])
+	}
+	if results[2].Err != services.ErrMissingBatchResult {
+		t.Fatalf("expected missing result error, got %+v", results[2])
+	}
+	if singleCalls != 0 {
+		t.Fatalf("batch endpoint available, expected no single calls, got %d", singleCalls)
+	}
+}
+
+func TestBatchMatchFallsBackToSingleCalls(t *testing.T) {
+	var singleCalls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/match/batch" {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&singleCalls, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"score": 42.0})
+	}))
+	defer srv.Close()
+
+	ai := services.NewAIService(srv.URL)
+	ai.BatchConcurrency = 2
+	jobs := []services.MatchItem{{ID: "1", Text: "a"}, {ID: "2", Text: "b"}, {ID: "3", Text: "c"}}
+	results := ai.MatchCandidateAgainstJobs(context.Background(), services.MatchItem{ID: "seeker", Text: "bio"}, jobs)
+	for _, r := range results {
+		if r.Err != nil || r.Score != 42 || r.CandidateID != "seeker" {
+			t.Fatalf("unexpected fallback result: %+v", r)
+		}
+	}
+	if singleCalls != 3 {
+		t.Fatalf("expected 3 single calls, got %d", singleCalls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, r := range ai.MatchCandidateAgainstJobs(ctx, services.MatchItem{ID: "seeker", Text: "bio"}, jobs) {
+		if r.Err == nil {
+			t.Fatalf("cancelled context should fail every pair, got %+v", r)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"rizeos/backend/internal/services"
+)
+
+// aiStandIn is an httptest stand-in for the FastAPI AI service. It enforces
+// the request side of the contract (method, headers, strict body schema) and
+// replies with the configured raw responses.
+type aiStandIn struct {
+	t         *testing.T
+	responses map[string]string // path -> raw JSON body
+	version   string            // response version header; "" omits it
+	calls     int32
+}
+
+func (s *aiStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&s.calls, 1)
+	if r.Method != http.MethodPost {
+		s.t.Errorf("%s: expected POST, got %s", r.URL.Path, r.Method)
+	}
+	if got := r.Header.Get(services.AIVersionHeader); got != services.AIAPIVersion {
+		s.t.Errorf("%s: expected version header %q, got %q", r.URL.Path, services.AIAPIVersion, got)
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+		s.t.Errorf("%s: expected JSON content type, got %q", r.URL.Path, ct)
+	}
+	var req interface{}
+	switch r.URL.Path {
+	case "/match":
+		req = &services.MatchRequest{}
+	case "/match/batch":
+		req = &services.MatchBatchRequest{}
+	case "/skills/extract":
+		req = &services.SkillExtractRequest{}
+	case "/recommendations/recruiter", "/recommendations/seeker":
+		req = &services.RecommendationRequest{}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(req); err != nil {
+		s.t.Errorf("%s: request does not match contract: %v", r.URL.Path, err)
+	}
+	if s.version != "" {
+		w.Header().Set(services.AIVersionHeader, s.version)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(s.responses[r.URL.Path]))
+}
+
+func newStandIn(t *testing.T, responses map[string]string) (*aiStandIn, *services.AIService, func()) {
+	stand := &aiStandIn{t: t, responses: responses, version: services.AIAPIVersion}
+	srv := httptest.NewServer(stand)
+	return stand, services.NewAIService(srv.URL), srv.Close
+}
+
+func TestAIContractHappyPaths(t *testing.T) {
+	_, ai, done := newStandIn(t, map[string]string{
+		"/match":                     `{"score": 81.5, "semantic_score": 77.2, "skill_score": 90}`,
+		"/skills/extract":            `{"skills": ["Go", "Docker"]}`,
+		"/recommendations/recruiter": `{"data": {"suggestions": ["Search candidates with Go"], "top_skills": ["Go"]}}`,
+	})
+	defer done()
+	ctx := context.Background()
+
+	match, err := ai.MatchScoreDetailed(ctx, "Go developer", "Gopher", []string{"Go"}, []string{"Go"})
+	if err != nil || match.Score != 81.5 || *match.SemanticScore != 77.2 || *match.SkillScore != 90 || match.Engine != services.EngineAIService {
+		t.Fatalf("unexpected match: %+v err=%v", match, err)
+	}
+
+	skills, engine, err := ai.ExtractSkillsDetailed(ctx, "Go and Docker")
+	if err != nil || engine != services.EngineAIService || !reflect.DeepEqual(skills, []string{"Go", "Docker"}) {
+		t.Fatalf("unexpected skills: %v %q err=%v", skills, engine, err)
+	}
+
+	recs, err := ai.Recommendations(ctx, services.RecommendationRecruiter, services.RecommendationRequest{Skills: []string{"Go"}})
+	if err != nil || len(recs.Suggestions) != 1 || !reflect.DeepEqual(recs.TopSkills, []string{"Go"}) {
+		t.Fatalf("unexpected recommendations: %+v err=%v", recs, err)
+	}
+	if _, err := ai.Recommendations(ctx, "admin", services.RecommendationRequest{}); err == nil {
+		t.Fatalf("unknown role should fail before calling the service")
+	}
+}
+
+func TestAIContractRejectsSchemaMismatches(t *testing.T) {
+	cases := map[string]struct {
+		path, body string
+		call       func(ai *services.AIService) error
+	}{
+		"legacy data envelope": {"/match", `{"data": {"score": 5}}`, matchCall},
+		"missing score":        {"/match", `{"semantic_score": 50}`, matchCall},
+		"score out of range":   {"/match", `{"score": 140}`, matchCall},
+		"wrong score type":     {"/match", `{"score": "high"}`, matchCall},
+		"missing skills":       {"/skills/extract", `{}`, extractCall},
+		"blank skill":          {"/skills/extract", `{"skills": ["Go", " "]}`, extractCall},
+		"missing data":         {"/recommendations/seeker", `{"suggestions": []}`, recommendCall},
+	}
+	for name, tc := range cases {
+		stand, ai, done := newStandIn(t, map[string]string{tc.path: tc.body})
+		err := tc.call(ai)
+		done()
+		if !services.IsSchemaError(err) {
+			t.Fatalf("%s: expected schema error, got %v", name, err)
+		}
+		if stand.calls != 1 {
+			t.Fatalf("%s: schema errors must not be retried, got %d calls", name, stand.calls)
+		}
+	}
+
+	stand, ai, done := newStandIn(t, map[string]string{"/match": `{"score": 50}`})
+	defer done()
+	stand.version = "2"
+	if err := matchCall(ai); !services.IsSchemaError(err) {
+		t.Fatalf("version mismatch should be a schema error, got %v", err)
+	}
+}
+
+func matchCall(ai *services.AIService) error {
+	_, err := ai.MatchScoreDetailed(context.Background(), "job", "bio", nil, nil)
+	return err
+}
+
+func extractCall(ai *services.AIService) error {
+	_, _, err := ai.ExtractSkillsDetailed(context.Background(), "text")
+	return err
+}
+
+func recommendCall(ai *services.AIService) error {
+	_, err := ai.Recommendations(context.Background(), services.RecommendationSeeker, services.RecommendationRequest{})
+	return err
+}
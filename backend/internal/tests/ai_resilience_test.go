@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"rizeos/backend/internal/localai"
+	"rizeos/backend/internal/services"
+)
+
+func TestAIServiceRetriesThenFallsBackAndOpensCircuit(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ai := services.NewAIService(srv.URL)
+	ai.RetryBackoff = time.Millisecond
+	ctx := context.Background()
+
+	res, err := ai.MatchScoreDetailed(ctx, "Senior Go developer building APIs", "Go developer building REST APIs", []string{"Go"}, []string{"Go"})
+	if err != nil || res.Engine != services.EngineLocal || res.Score < 95 {
+		t.Fatalf("expected local fallback with full-coverage floor, got %+v err=%v", res, err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 1 call + 2 retries, got %d", calls)
+	}
+
+	// Five consecutive failed calls open the circuit; later calls skip the service.
+	for i := 0; i < 4; i++ {
+		_, _ = ai.MatchScore(ctx, "job", "bio")
+	}
+	before := atomic.LoadInt32(&calls)
+	skills, engine, err := ai.ExtractSkillsDetailed(ctx, "Built services in Golang with Docker and PostgreSQL")
+	if err != nil || engine != services.EngineLocal {
+		t.Fatalf("expected local extraction, got engine=%q err=%v", engine, err)
+	}
+	if atomic.LoadInt32(&calls) != before {
+		t.Fatalf("open circuit should not call the service")
+	}
+	if len(skills) != 3 || skills[0] != "Golang" || skills[1] != "Docker" || skills[2] != "PostgreSQL" {
+		t.Fatalf("unexpected local skills: %v", skills)
+	}
+}
+
+func TestAIServiceIgnoresCallerCancellation(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ai := services.NewAIService(srv.URL)
+	ai.MaxRetries = 0
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		_, _ = ai.MatchScore(ctx, "job", "bio")
+	}
+	// A call the caller already gave up on must not reset the failure count.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _ = ai.MatchScore(cancelled, "job", "bio")
+	_, _ = ai.MatchScore(ctx, "job", "bio")
+
+	before := atomic.LoadInt32(&calls)
+	_, _ = ai.MatchScore(ctx, "job", "bio")
+	if atomic.LoadInt32(&calls) != before {
+		t.Fatal("five service failures must open the circuit despite a cancelled call in between")
+	}
+}
+
+func TestSkillVocabularySkipsShortAliases(t *testing.T) {
+	vocab := services.NewSkillService(nil).Vocabulary(context.Background())
+	has := map[string]bool{}
+	for _, term := range vocab {
+		has[term] = true
+	}
+	for _, want := range []string{"TypeScript", "Go", "golang", "k8s"} {
+		if !has[want] {
+			t.Errorf("vocabulary must contain %q", want)
+		}
+	}
+	for _, short := range []string{"ts", "ml", "js", "py"} {
+		if has[short] {
+			t.Errorf("vocabulary must not contain the short alias %q", short)
+		}
+	}
+	skills := localai.ExtractSkills("Our team ships ML models; ts of data", vocab)
+	if len(skills) != 0 {
+		t.Fatalf("short aliases must not be extracted, got %v", skills)
+	}
+}
+
+func TestLocalExtractionIgnoresEverydayWords(t *testing.T) {
+	skills := localai.ExtractSkills("Ready to go, happy to take a rest and express ideas", nil)
+	if len(skills) != 0 {
+		t.Fatalf("everyday words must not be extracted, got %v", skills)
+	}
+	skills = localai.ExtractSkills("Built REST APIs in Go and Rust, versioned with Git", nil)
+	if strings.Join(skills, ",") != "REST,Go,Rust,Git" {
+		t.Fatalf("expected REST, Go, Rust and Git, got %v", skills)
+	}
+}
+
+func TestLocalScoreRanksRelatedTextHigher(t *testing.T) {
+	job := "Backend engineer to build Go microservices on Kubernetes"
+	related := localai.Score(job, "I build Go microservices and deploy them on Kubernetes", nil, nil)
+	unrelated := localai.Score(job, "Pastry chef specialising in French desserts", nil, nil)
+	if related.SemanticScore <= unrelated.SemanticScore || unrelated.SemanticScore != 0 {
+		t.Fatalf("expected related > unrelated = 0, got %v vs %v", related.SemanticScore, unrelated.SemanticScore)
+	}
+	if empty := localai.Score(job, "  ", nil, nil); empty.Score != 0 {
+		t.Fatalf("empty bio should score 0, got %v", empty.Score)
+	}
+}
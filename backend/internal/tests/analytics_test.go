@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+func TestHiringFunnelRollups(t *testing.T) {
+	ctx := context.Background()
+	apps := services.NewJobApplicationService(nil)
+	analytics := services.NewAnalyticsService(nil)
+	recruiter, jobID := primitive.NewObjectID(), primitive.NewObjectID()
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC) // a Monday
+
+	for i := 0; i < 4; i++ {
+		if err := analytics.Record(ctx, jobID, recruiter, services.EventView, day.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var created []models.JobApplication
+	for i := 0; i < 2; i++ {
+		app, err := apps.Create(ctx, models.JobApplication{JobID: jobID, JobSeekerID: primitive.NewObjectID(), RecruiterID: recruiter, ApplicationStatus: models.ApplicationApplied, AppliedAt: day})
+		if err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, app)
+		_ = analytics.Record(ctx, jobID, recruiter, services.EventApply, day)
+	}
+
+	if _, err := apps.UpdateStatus(ctx, created[0].ID, models.ApplicationHired, day); !errors.Is(err, services.ErrInvalidStatusTransition) {
+		t.Fatalf("APPLIED -> HIRED must be rejected, got %v", err)
+	}
+	hiredAt := day.AddDate(0, 0, 8)
+	for _, step := range []struct {
+		status string
+		at     time.Time
+	}{{models.ApplicationShortlisted, day.AddDate(0, 0, 1)}, {models.ApplicationHired, hiredAt}} {
+		app, err := apps.UpdateStatus(ctx, created[0].ID, step.status, step.at)
+		if err != nil {
+			t.Fatalf("%s: %v", step.status, err)
+		}
+		event, _ := services.StatusEvent(app.ApplicationStatus)
+		_ = analytics.Record(ctx, jobID, recruiter, event, step.at)
+	}
+	if _, err := apps.UpdateStatus(ctx, created[0].ID, models.ApplicationRejected, hiredAt); !errors.Is(err, services.ErrInvalidStatusTransition) {
+		t.Fatalf("HIRED is final, got %v", err)
+	}
+
+	filter := services.AnalyticsFilter{RecruiterID: recruiter, From: day.AddDate(0, 0, -1), To: day.AddDate(0, 0, 20)}
+	funnel, err := analytics.FunnelByJob(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := funnel[jobID]
+	if got != (services.FunnelCounts{Views: 4, Applies: 2, Shortlisted: 1, Hired: 1}) {
+		t.Fatalf("unexpected funnel %+v", got)
+	}
+	rates := got.Rates()
+	if *rates.ViewToApply != 50 || *rates.ApplyToShortlist != 50 || *rates.ShortlistToHire != 100 {
+		t.Fatalf("unexpected rates %+v", rates)
+	}
+	if empty := (services.FunnelCounts{}).Rates(); empty.ViewToApply != nil {
+		t.Fatal("rates over an empty stage must be nil")
+	}
+
+	times, err := analytics.HiringTimesByJob(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ht := times[jobID]; ht.Hires != 1 || *ht.AvgTimeToHire != 8*24*time.Hour || !ht.FirstAppliedAt.Equal(day) {
+		t.Fatalf("unexpected hiring times %+v", ht)
+	}
+
+	weekly, err := analytics.Trend(ctx, filter, services.IntervalWeek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(weekly) != 4 || !weekly[0].Period.Equal(day.AddDate(0, 0, -7).Truncate(24*time.Hour)) {
+		t.Fatalf("expected four Monday-aligned weeks, got %+v", weekly)
+	}
+	if weekly[1].Views != 4 || weekly[1].Shortlisted != 1 || weekly[2].Hired != 1 || weekly[3].Applies != 0 {
+		t.Fatalf("unexpected weekly trend %+v", weekly)
+	}
+
+	// Rebuilding from applications reproduces the recorded counters and keeps views.
+	if err := analytics.RebuildApplicationRollups(ctx); err != nil {
+		t.Fatal(err)
+	}
+	rebuilt, _ := analytics.FunnelByJob(ctx, filter)
+	if rebuilt[jobID] != got {
+		t.Fatalf("rebuild changed the funnel: %+v -> %+v", got, rebuilt[jobID])
+	}
+}
+
+func TestAnalyticsRangeLimitsPerInterval(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter := services.AnalyticsFilter{RecruiterID: primitive.NewObjectID(), From: from, To: from.AddDate(2, 0, 0)}
+	if _, err := services.NewAnalyticsService(nil).Trend(context.Background(), filter, services.IntervalDay); !errors.Is(err, services.ErrRangeTooLong) {
+		t.Fatalf("two years by day must be rejected, got %v", err)
+	}
+
+	app := newTestApp(t, routes.Deps{AnalyticsSvc: services.NewAnalyticsService(nil), PlatformAnalytics: services.NewPlatformAnalyticsService(nil)})
+	recToken, _ := app.register("range-rec", models.RoleRecruiter)
+	adminToken, _ := app.register("range-admin", models.RoleAdmin)
+	cases := []struct {
+		query string
+		code  int
+	}{
+		{"from=2025-01-01&to=2025-12-31&interval=day", http.StatusOK},
+		{"from=2024-01-01&to=2025-12-31&interval=day", http.StatusBadRequest},
+		{"from=2024-01-01&to=2025-12-31&interval=week", http.StatusOK},
+		{"from=2020-01-01&to=2025-12-31&interval=week", http.StatusBadRequest},
+		{"from=2016-01-01&to=2025-12-31&interval=month", http.StatusOK},
+		{"from=1990-01-01&to=2025-12-31&interval=month", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		if res := app.request(http.MethodGet, "/api/recruiter/analytics/trends?"+tc.query, "", recToken); res.Code != tc.code {
+			t.Errorf("trends %s: expected %d, got %d %s", tc.query, tc.code, res.Code, res.Body.String())
+		}
+		if res := app.request(http.MethodGet, "/api/admin/dashboard?"+tc.query, "", adminToken); res.Code != tc.code {
+			t.Errorf("dashboard %s: expected %d, got %d %s", tc.query, tc.code, res.Code, res.Body.String())
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+func TestScreenAnnouncement(t *testing.T) {
+	cases := map[string][]string{
+		"We are hiring Go engineers in Pune next month.":         nil,
+		"Apply at https://jobs.example.com/go today":             {models.FlagLink},
+		"Visit acme.io for details":                              {models.FlagLink},
+		"This shit is hiring":                                    {models.FlagProfanity},
+		"Damn good engineers wanted":                             {models.FlagLanguage},
+		"Join Dick Smith's team":                                 {models.FlagLanguage},
+		"No crap, just shit":                                     {models.FlagProfanity},
+		"Click here to earn":                                     {models.FlagSpam},
+		"WE ARE HIRING RIGHT NOW EVERYONE APPLY":                 {models.FlagSpam},
+		"Hiring!!!!! Apply www.spam.example now":                 {models.FlagLink, models.FlagSpam},
+		"Our team uses Node.js and React for frontend projects.": nil,
+	}
+	for message, want := range cases {
+		got := services.ScreenAnnouncement(message)
+		if len(got) != len(want) {
+			t.Errorf("%q: expected flags %v, got %v", message, want, got)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%q: expected flags %v, got %v", message, want, got)
+			}
+		}
+	}
+}
+
+func TestRecruiterAnnouncementModeration(t *testing.T) {
+	ctx := context.Background()
+	users := services.NewUserService(nil)
+	announcements := services.NewAnnouncementService(nil, users)
+	app := newTestApp(t, routes.Deps{UserSvc: users, AnnouncementSvc: announcements})
+	suffix := app.suffix
+	adminToken, _ := app.register("mod-admin", models.RoleAdmin)
+	authorToken, _ := app.register("mod-author", models.RoleRecruiter)
+	readerToken, _ := app.register("mod-reader", models.RoleRecruiter)
+	_, mildAuthor := app.register("mod-mild", models.RoleRecruiter)
+
+	submit := func(message string) (int, models.Announcement) {
+		t.Helper()
+		res := app.request(http.MethodPost, "/api/recruiter/announcements", `{"message":"`+message+`"}`, authorToken)
+		var a models.Announcement
+		decodeData(t, res, &a)
+		return res.Code, a
+	}
+	list := func(path, token string) []models.Announcement {
+		t.Helper()
+		var items []models.Announcement
+		decodeData(t, app.request(http.MethodGet, path, "", token), &items)
+		return items
+	}
+	inFeed := func(token, message string) bool {
+		t.Helper()
+		var items []struct {
+			Message string `json:"message"`
+		}
+		decodeData(t, app.request(http.MethodGet, "/api/recruiter/announcements", "", token), &items)
+		for _, it := range items {
+			if it.Message == message {
+				return true
+			}
+		}
+		return false
+	}
+
+	goodMsg := "Referral drive " + suffix
+	code, good := submit(goodMsg)
+	if code != http.StatusCreated || good.Status != models.AnnouncementPending {
+		t.Fatalf("recruiter announcements must start PENDING, got %d %s", code, good.Status)
+	}
+	_, spam := submit("Click here " + suffix)
+	if spam.Status != models.AnnouncementRejected || spam.Moderation == nil || spam.Moderation.Reason == "" {
+		t.Fatalf("the pre-filter must reject spam with a reason, got %s %+v", spam.Status, spam.Moderation)
+	}
+	_, linked := submit("See careers.example.com " + suffix)
+	if linked.Status != models.AnnouncementPending || len(linked.Moderation.Flags) != 1 || linked.Moderation.Flags[0] != models.FlagLink {
+		t.Fatalf("links must be flagged for review, got %s %+v", linked.Status, linked.Moderation)
+	}
+	if code, _ := submit("One more " + suffix); code != http.StatusTooManyRequests {
+		t.Fatalf("recruiters must be rate limited, got %d", code)
+	}
+	mild, err := announcements.Submit(ctx, models.Announcement{FromRole: models.RoleRecruiter, CreatedBy: &mildAuthor.ID, Message: "Damn good crew " + suffix})
+	if err != nil || mild.Status != models.AnnouncementPending || len(mild.Moderation.Flags) != 1 || mild.Moderation.Flags[0] != models.FlagLanguage {
+		t.Fatalf("mild language must be left for review, got %+v (%v)", mild.Moderation, err)
+	}
+
+	announcements.ProcessDue(ctx, time.Now())
+	if inFeed(readerToken, goodMsg) {
+		t.Fatal("pending announcements must not be delivered")
+	}
+
+	queue := list("/api/admin/announcements?status=PENDING", adminToken)
+	queued := map[primitive.ObjectID]bool{}
+	for _, a := range queue {
+		queued[a.ID] = true
+	}
+	if !queued[good.ID] || !queued[linked.ID] || !queued[mild.ID] || queued[spam.ID] {
+		t.Fatalf("the moderation queue must hold the pending announcements only")
+	}
+
+	review := func(id primitive.ObjectID, body string) int {
+		return app.request(http.MethodPut, "/api/admin/announcements/"+id.Hex()+"/review", body, adminToken).Code
+	}
+	if code := review(linked.ID, `{"decision":"reject"}`); code != http.StatusBadRequest {
+		t.Fatalf("rejections must have a reason, got %d", code)
+	}
+	if code := review(linked.ID, `{"decision":"reject","reason":"no external links"}`); code != http.StatusOK {
+		t.Fatalf("reject failed: %d", code)
+	}
+	if code := review(good.ID, `{"decision":"approve"}`); code != http.StatusOK {
+		t.Fatalf("approve failed: %d", code)
+	}
+	if code := review(good.ID, `{"decision":"reject","reason":"too late"}`); code != http.StatusConflict {
+		t.Fatalf("reviewed announcements must not be reviewed again, got %d", code)
+	}
+	if code := app.request(http.MethodPut, "/api/admin/announcements/"+good.ID.Hex()+"/review", `{"decision":"approve"}`, authorToken).Code; code != http.StatusForbidden {
+		t.Fatalf("only admins may review, got %d", code)
+	}
+
+	announcements.ProcessDue(ctx, time.Now())
+	if !inFeed(readerToken, goodMsg) {
+		t.Fatal("approved announcements must be delivered to recruiters")
+	}
+
+	mine := list("/api/recruiter/announcements/mine", authorToken)
+	statuses := map[primitive.ObjectID]models.Announcement{}
+	for _, a := range mine {
+		statuses[a.ID] = a
+	}
+	if len(mine) != 3 || statuses[good.ID].Status != models.AnnouncementPublished {
+		t.Fatalf("the author must see all their announcements, got %d", len(mine))
+	}
+	if r := statuses[linked.ID]; r.Status != models.AnnouncementRejected || r.Moderation.Reason != "no external links" {
+		t.Fatalf("the author must see the rejection reason, got %s %+v", r.Status, r.Moderation)
+	}
+	if len(list("/api/recruiter/announcements/mine", readerToken)) != 0 {
+		t.Fatal("authors must only see their own announcements")
+	}
+}
+
+func TestRecruiterAnnouncementLimitUnderConcurrency(t *testing.T) {
+	ctx := context.Background()
+	announcements := services.NewAnnouncementService(nil, services.NewUserService(nil))
+	author := primitive.NewObjectID()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	accepted, limited := 0, 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := announcements.Submit(ctx, models.Announcement{FromRole: models.RoleRecruiter, CreatedBy: &author, Message: fmt.Sprintf("Opening %d", i)})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				accepted++
+			case errors.Is(err, services.ErrAnnouncementRateLimited):
+				limited++
+			default:
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if accepted != services.RecruiterAnnouncementsPerDay || limited != 10-services.RecruiterAnnouncementsPerDay {
+		t.Fatalf("expected %d submissions accepted, got %d accepted and %d limited", services.RecruiterAnnouncementsPerDay, accepted, limited)
+	}
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+func TestTargetedScheduledAnnouncements(t *testing.T) {
+	ctx := context.Background()
+	users := services.NewUserService(nil)
+	announcements := services.NewAnnouncementService(nil, users)
+	app := newTestApp(t, routes.Deps{UserSvc: users, AnnouncementSvc: announcements})
+
+	suffix := app.suffix
+	skill := "skill-" + suffix
+	city := "City-" + suffix
+	adminToken, _ := app.register("ann-admin", models.RoleAdmin)
+	recToken, _ := app.registerUser(models.User{Name: "ann-rec", Role: models.RoleRecruiter, Skills: []string{skill}, Location: city})
+	premiumToken, _ := app.registerUser(models.User{Name: "ann-premium", Role: models.RoleSeeker, IsPremium: true, Skills: []string{skill}, Location: "North " + city})
+	seekerToken, seeker := app.registerUser(models.User{Name: "ann-seeker", Role: models.RoleSeeker, Skills: []string{skill}, Location: "Elsewhere"})
+
+	type feedItem struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+		IsRead  bool   `json:"is_read"`
+	}
+	feed := func(token string) map[string]feedItem {
+		t.Helper()
+		res := app.request(http.MethodGet, "/api/announcements", "", token)
+		if res.Code != http.StatusOK {
+			t.Fatalf("feed failed: %d %s", res.Code, res.Body.String())
+		}
+		var items []feedItem
+		decodeData(t, res, &items)
+		byMessage := map[string]feedItem{}
+		for _, it := range items {
+			byMessage[it.Message] = it
+		}
+		return byMessage
+	}
+	create := func(body string) models.Announcement {
+		t.Helper()
+		res := app.request(http.MethodPost, "/api/admin/announcements", body, adminToken)
+		if res.Code != http.StatusCreated {
+			t.Fatalf("create failed: %d %s", res.Code, res.Body.String())
+		}
+		var a models.Announcement
+		decodeData(t, res, &a)
+		return a
+	}
+
+	if res := app.request(http.MethodPost, "/api/admin/announcements", `{"message":"x","audience":{"segment":"bogus"}}`, adminToken); res.Code != http.StatusBadRequest {
+		t.Fatalf("unknown segments must be rejected, got %d", res.Code)
+	}
+
+	premiumMsg := "premium " + suffix
+	localMsg := "local " + suffix
+	laterMsg := "later " + suffix
+	premium := create(`{"message":"` + premiumMsg + `","audience":{"segment":"premium","skills":["` + skill + `"]}}`)
+	create(`{"message":"` + localMsg + `","audience":{"segment":"all","location":"` + city + `"}}`)
+	publishAt := time.Now().Add(time.Hour)
+	later := create(`{"message":"` + laterMsg + `","audience":{"segment":"seekers","skills":["` + skill + `"]},"publish_at":"` + publishAt.Format(time.RFC3339) + `"}`)
+	if premium.Status != models.AnnouncementScheduled {
+		t.Fatalf("new announcements must be scheduled, got %s", premium.Status)
+	}
+	if len(feed(premiumToken)) != 0 {
+		t.Fatal("announcements must be delivered by the fan-out job, not the request")
+	}
+
+	if _, err := announcements.ProcessDue(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var progress models.Announcement
+	decodeData(t, app.request(http.MethodGet, "/api/admin/announcements/"+premium.ID.Hex(), "", adminToken), &progress)
+	if progress.Status != models.AnnouncementPublished || progress.FanOut.Total != 1 || progress.FanOut.Delivered != 1 {
+		t.Fatalf("expected the premium announcement delivered to one user, got %s %+v", progress.Status, progress.FanOut)
+	}
+
+	if f := feed(premiumToken); len(f) != 2 {
+		t.Fatalf("the premium seeker in the city must get both announcements, got %v", f)
+	}
+	if f := feed(recToken); len(f) != 1 || f[localMsg].ID == "" {
+		t.Fatalf("the recruiter must only get the local announcement, got %v", f)
+	}
+	if f := feed(seekerToken); len(f) != 0 {
+		t.Fatalf("the seeker elsewhere must get nothing yet, got %v", f)
+	}
+
+	if _, err := announcements.ProcessDue(ctx, publishAt.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if f := feed(seekerToken); f[laterMsg].ID != later.ID.Hex() {
+		t.Fatalf("the scheduled announcement must be delivered once due, got %v", f)
+	}
+	if n, _ := announcements.ProcessDue(ctx, publishAt.Add(time.Minute)); n != 0 {
+		t.Fatalf("published announcements must not be delivered again, got %d", n)
+	}
+
+	local := feed(recToken)[localMsg]
+	if res := app.request(http.MethodPut, "/api/announcements/"+later.ID.Hex()+"/read", "", recToken); res.Code != http.StatusNotFound {
+		t.Fatalf("users must not mark announcements they did not get, got %d", res.Code)
+	}
+	app.request(http.MethodPut, "/api/announcements/"+local.ID+"/read", "", recToken)
+	if !feed(recToken)[localMsg].IsRead {
+		t.Fatal("the announcement must be marked read")
+	}
+	app.request(http.MethodPut, "/api/announcements/"+local.ID+"/dismiss", "", recToken)
+	if f := feed(recToken); len(f) != 0 {
+		t.Fatalf("dismissed announcements must leave the feed, got %v", f)
+	}
+	if f := feed(premiumToken); len(f) != 3 {
+		t.Fatalf("dismissing must only affect the current user, got %v", f)
+	}
+
+	if res := app.request(http.MethodPost, "/api/admin/announcements/"+premium.ID.Hex()+"/retry", "", adminToken); res.Code != http.StatusConflict {
+		t.Fatalf("only failed announcements can be retried, got %d", res.Code)
+	}
+	if res := app.request(http.MethodPost, "/api/admin/announcements/"+premium.ID.Hex()+"/cancel", "", adminToken); res.Code != http.StatusOK {
+		t.Fatalf("cancel failed: %d %s", res.Code, res.Body.String())
+	}
+	if f := feed(premiumToken); len(f) != 2 || f[premiumMsg].ID != "" {
+		t.Fatalf("cancelled announcements must be hidden, got %v", f)
+	}
+
+	expires := time.Now().Add(time.Hour)
+	expiring, err := announcements.Create(ctx, models.Announcement{
+		FromRole: models.RoleAdmin, Message: "expiring " + suffix,
+		Audience: models.Audience{Segment: models.AudienceSeekers, Location: "Elsewhere"}, ExpiresAt: &expires,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	announcements.ProcessDue(ctx, time.Now())
+	if items, _ := announcements.Feed(ctx, seeker.ID, time.Now()); len(items) != 2 {
+		t.Fatalf("expected the expiring announcement in the feed, got %d items", len(items))
+	}
+	items, _ := announcements.Feed(ctx, seeker.ID, expires)
+	for _, it := range items {
+		if it.Announcement.ID == expiring.ID {
+			t.Fatal("expired announcements must be hidden")
+		}
+	}
+}
+
+func TestAudienceSkillsIgnoreCase(t *testing.T) {
+	ctx := context.Background()
+	users := services.NewUserService(nil)
+	suffix := primitive.NewObjectID().Hex()
+	if _, err := users.Register(ctx, models.User{Name: "case-seeker", Email: "case-seeker-" + suffix + "@test.com", Role: models.RoleSeeker, Skills: []string{"rust-" + suffix}}, "password123"); err != nil {
+		t.Fatal(err)
+	}
+	for _, skill := range []string{"Rust-" + suffix, "RUST-" + suffix} {
+		if n, err := users.CountUsers(ctx, services.UserQuery{AnySkills: []string{skill}}); err != nil || n != 1 {
+			t.Fatalf("%s: expected one user regardless of case, got %d (%v)", skill, n, err)
+		}
+	}
+	if n, _ := users.CountUsers(ctx, services.UserQuery{AnySkills: []string{"rust-" + suffix[:8]}}); n != 0 {
+		t.Fatalf("skills must match whole, got %d", n)
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+func TestConversationThreads(t *testing.T) {
+	app := newTestApp(t, routes.Deps{MessageSvc: services.NewMessageService(nil)})
+	recToken, rec := app.register("conv-rec", models.RoleRecruiter)
+	seekToken, seeker := app.register("conv-seek", models.RoleSeeker)
+	otherToken, _ := app.register("conv-other", models.RoleSeeker)
+	recID, seekID := rec.ID.Hex(), seeker.ID.Hex()
+
+	for _, text := range []string{"one", "two", "three"} {
+		res := app.request(http.MethodPost, "/api/messages/send", `{"toUserId":"`+seekID+`","toRole":"seeker","message":"`+text+`"}`, recToken)
+		if res.Code != http.StatusCreated {
+			t.Fatalf("send failed: %d %s", res.Code, res.Body.String())
+		}
+	}
+
+	type conversation struct {
+		ID          string `json:"id"`
+		LastMessage string `json:"last_message"`
+		Unread      int    `json:"unread_count"`
+		Participant struct {
+			ID string `json:"id"`
+		} `json:"participant"`
+	}
+	list := func(token string) []conversation {
+		t.Helper()
+		var page struct {
+			Items []conversation `json:"items"`
+		}
+		decodeData(t, app.request(http.MethodGet, "/api/conversations", "", token), &page)
+		return page.Items
+	}
+	convs := list(seekToken)
+	if len(convs) != 1 || convs[0].LastMessage != "three" || convs[0].Unread != 3 || convs[0].Participant.ID != recID {
+		t.Fatalf("expected one thread with 3 unread from the recruiter, got %+v", convs)
+	}
+	convID := convs[0].ID
+
+	if res := app.request(http.MethodGet, "/api/conversations/"+convID+"/messages", "", otherToken); res.Code != http.StatusNotFound {
+		t.Fatalf("non-participants must not see the thread, got %d", res.Code)
+	}
+	if res := app.request(http.MethodPost, "/api/conversations/"+convID+"/messages", `{"message":"hi"}`, otherToken); res.Code != http.StatusNotFound {
+		t.Fatalf("non-participants must not reply, got %d", res.Code)
+	}
+
+	if res := app.request(http.MethodPost, "/api/conversations/"+convID+"/messages", `{"message":"reply"}`, seekToken); res.Code != http.StatusCreated {
+		t.Fatalf("reply failed: %d %s", res.Code, res.Body.String())
+	}
+	if convs := list(recToken); len(convs) != 1 || convs[0].ID != convID || convs[0].LastMessage != "reply" || convs[0].Unread != 1 {
+		t.Fatalf("the reply must land in the same thread, got %+v", convs)
+	}
+
+	type thread struct {
+		Messages []struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+		} `json:"messages"`
+		HasMore    bool   `json:"has_more"`
+		NextBefore string `json:"next_before"`
+	}
+	history := func(query string) thread {
+		t.Helper()
+		var th thread
+		decodeData(t, app.request(http.MethodGet, "/api/conversations/"+convID+"/messages"+query, "", recToken), &th)
+		return th
+	}
+	latest := history("?limit=2")
+	if len(latest.Messages) != 2 || latest.Messages[0].Message != "three" || latest.Messages[1].Message != "reply" || !latest.HasMore {
+		t.Fatalf("expected the two newest messages oldest first, got %+v", latest)
+	}
+	older := history("?limit=2&before=" + latest.NextBefore)
+	if len(older.Messages) != 2 || older.Messages[0].Message != "one" || older.HasMore {
+		t.Fatalf("expected the two oldest messages, got %+v", older)
+	}
+
+	if res := app.request(http.MethodPut, "/api/conversations/"+convID+"/read", "", seekToken); res.Code != http.StatusOK {
+		t.Fatalf("mark read failed: %d", res.Code)
+	}
+	if convs := list(seekToken); convs[0].Unread != 0 {
+		t.Fatalf("marking the thread read must clear its unread count, got %d", convs[0].Unread)
+	}
+	if convs := list(recToken); convs[0].Unread != 1 {
+		t.Fatalf("the other participant's unread count must not change, got %d", convs[0].Unread)
+	}
+}
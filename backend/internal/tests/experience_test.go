@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/utils"
+)
+
+func TestParseLegacyExperience(t *testing.T) {
+	cases := []struct {
+		in    interface{}
+		years float64
+		ok    bool
+	}{
+		{"2 years", 2, true},
+		{"6 months", 0.5, true},
+		{"18 mos", 1.5, true},
+		{"2.5", 2.5, true},
+		{" 3yrs ", 3, true},
+		{"fresher", 0, true},
+		{"Fresher", 0, true},
+		{"", 0, false},
+		{"   ", 0, false},
+		{"some experience", 0, false},
+		{nil, 0, false},
+		{float64(4), 4, true},
+		{int32(1), 1, true},
+		{int64(-1), -1, false},
+	}
+	for _, tc := range cases {
+		years, ok := utils.ParseLegacyExperience(tc.in)
+		if years != tc.years || ok != tc.ok {
+			t.Errorf("%#v: expected (%v, %v), got (%v, %v)", tc.in, tc.years, tc.ok, years, ok)
+		}
+	}
+}
+
+func TestTotalExperienceYears(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month) *time.Time {
+		d := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	cases := []struct {
+		name      string
+		positions []models.Position
+		want      float64
+	}{
+		{"empty", nil, 0},
+		{"undated years", []models.Position{{Years: 2}, {Years: 0.5}}, 2.5},
+		{"dated position", []models.Position{{StartDate: date(2020, 1), EndDate: date(2022, 1)}}, 2},
+		{"open-ended position runs until now", []models.Position{{StartDate: date(2023, 7)}}, 0.5},
+		{"overlaps are counted once", []models.Position{
+			{StartDate: date(2020, 1), EndDate: date(2022, 1)},
+			{StartDate: date(2021, 1), EndDate: date(2023, 1)},
+		}, 3},
+		{"dated and undated positions add up", []models.Position{
+			{StartDate: date(2022, 1), EndDate: date(2023, 1)},
+			{Years: 1.5},
+		}, 2.5},
+		{"end before start is ignored", []models.Position{{StartDate: date(2022, 1), EndDate: date(2021, 1)}}, 0},
+	}
+	for _, tc := range cases {
+		if got := utils.TotalExperienceYears(tc.positions, now); got != tc.want {
+			t.Errorf("%s: expected %v years, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestLegacyPositions(t *testing.T) {
+	cases := []struct {
+		in   interface{}
+		want []models.Position
+	}{
+		{"2 years", []models.Position{{Title: "Experience", Years: 2, Description: "2 years"}}},
+		{"6 months", []models.Position{{Title: "Experience", Years: 0.5, Description: "6 months"}}},
+		{float64(3), []models.Position{{Title: "Experience", Years: 3, Description: "3"}}},
+		{"fresher", nil},
+		{"", nil},
+		{nil, nil},
+		// Unparseable values are kept as a description.
+		{"worked at a startup", []models.Position{{Title: "Experience", Description: "worked at a startup"}}},
+	}
+	for _, tc := range cases {
+		if got := utils.LegacyPositions(tc.in); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%#v: expected %+v, got %+v", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestUpsertDegreesKeepsDetails(t *testing.T) {
+	history := []models.Degree{{Institution: "City School", Degree: "Class 10", Grade: "80%", StartYear: 2012, EndYear: 2014}}
+	got := utils.UpsertDegrees(history, models.Degree{Degree: "Class 10", Grade: "92%"}, models.Degree{Degree: "Class 12", Grade: "88%"})
+	want := []models.Degree{
+		{Institution: "City School", Degree: "Class 10", Grade: "92%", StartYear: 2012, EndYear: 2014},
+		{Degree: "Class 12", Grade: "88%"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestLegacyProfileUpdate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	structured := bson.A{bson.M{"degree": "B.Tech"}}
+	cases := []struct {
+		name  string
+		doc   bson.M
+		set   bson.M
+		unset bson.M
+	}{
+		{
+			name: "legacy experience, education and marks",
+			doc:  bson.M{"experience": "2 years", "education": "B.Sc", "tenth_marks": float64(85), "twelfth_marks": "78%"},
+			set: bson.M{
+				"updated_at":       now,
+				"experience":       []models.Position{{Title: "Experience", Years: 2, Description: "2 years"}},
+				"experience_years": 2.0,
+				"education":        []models.Degree{{Degree: "B.Sc"}, {Degree: "Class 12", Grade: "78%"}, {Degree: "Class 10", Grade: "85"}},
+			},
+			unset: bson.M{"tenth_marks": "", "twelfth_marks": ""},
+		},
+		{
+			name: "months of experience",
+			doc:  bson.M{"experience": "6 months"},
+			set: bson.M{
+				"updated_at":       now,
+				"experience":       []models.Position{{Title: "Experience", Years: 0.5, Description: "6 months"}},
+				"experience_years": 0.5,
+			},
+			unset: bson.M{"tenth_marks": "", "twelfth_marks": "", "education": ""},
+		},
+		{
+			name:  "fresher and empty education are removed",
+			doc:   bson.M{"experience": "fresher", "education": ""},
+			set:   bson.M{"updated_at": now},
+			unset: bson.M{"tenth_marks": "", "twelfth_marks": "", "experience": "", "education": ""},
+		},
+		{
+			name:  "empty experience is removed",
+			doc:   bson.M{"experience": "", "education": structured},
+			set:   bson.M{"updated_at": now},
+			unset: bson.M{"tenth_marks": "", "twelfth_marks": "", "experience": ""},
+		},
+		{
+			name: "marks are appended to structured education",
+			doc:  bson.M{"experience": bson.A{}, "education": structured, "tenth_marks": "90"},
+			set: bson.M{
+				"updated_at": now,
+				"education":  bson.A{bson.M{"degree": "B.Tech"}, models.Degree{Degree: "Class 10", Grade: "90"}},
+			},
+			unset: bson.M{"tenth_marks": "", "twelfth_marks": ""},
+		},
+	}
+	for _, tc := range cases {
+		set, unset := services.LegacyProfileUpdate(tc.doc, now)
+		if !reflect.DeepEqual(set, tc.set) {
+			t.Errorf("%s: expected $set %+v, got %+v", tc.name, tc.set, set)
+		}
+		if !reflect.DeepEqual(unset, tc.unset) {
+			t.Errorf("%s: expected $unset %+v, got %+v", tc.name, tc.unset, unset)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"rizeos/backend/internal/ranking"
+)
+
+func TestExplainComponentsAndSuggestions(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	jobSkills := []string{"Go", "Docker", "Kubernetes", "AWS", "Terraform"}
+
+	cases := []struct {
+		name        string
+		in          ranking.ExplainInput
+		coverage    float64
+		semantic    float64
+		estimated   bool
+		missing     []string
+		suggestions []string
+	}{
+		{
+			name:      "semantic estimated from score and skill lists",
+			in:        ranking.ExplainInput{Score: 54, JobSkills: jobSkills, CandidateSkills: []string{"go", "docker"}},
+			coverage:  40,
+			semantic:  60,
+			estimated: true,
+			missing:   []string{"Kubernetes", "AWS", "Terraform"},
+			suggestions: []string{
+				"Add Kubernetes to reach 60%",
+				"Then add AWS to reach 66%",
+				"Then add Terraform to reach 95%",
+				"Covering all 5 required skills would reach 95%",
+			},
+		},
+		{
+			name:      "reported skill score overrides the skill lists",
+			in:        ranking.ExplainInput{Score: 54, SkillScore: f(40), JobSkills: jobSkills, CandidateSkills: []string{"go", "docker", "aws"}},
+			coverage:  40,
+			semantic:  60,
+			estimated: true,
+			missing:   []string{"Kubernetes", "Terraform"},
+			suggestions: []string{
+				"Add Kubernetes to reach 60%",
+				"Then add Terraform to reach 66%",
+				"Covering all 5 required skills would reach 95%",
+			},
+		},
+		{
+			name:      "reported scores are used as is",
+			in:        ranking.ExplainInput{Score: 95, SemanticScore: f(30), SkillScore: f(100), JobSkills: []string{"Go"}, CandidateSkills: []string{"Go"}},
+			coverage:  100,
+			semantic:  30,
+			estimated: false,
+			missing:   []string{},
+			suggestions: []string{
+				"Describe relevant experience in the bio or summary to improve similarity with the job description",
+			},
+		},
+	}
+	for _, tc := range cases {
+		exp := ranking.Explain(tc.in)
+		if exp.SkillCoverage != tc.coverage || exp.SemanticScore != tc.semantic || exp.Estimated != tc.estimated {
+			t.Errorf("%s: expected coverage %.1f, semantic %.1f (estimated %v), got %.1f, %.1f (%v)",
+				tc.name, tc.coverage, tc.semantic, tc.estimated, exp.SkillCoverage, exp.SemanticScore, exp.Estimated)
+		}
+		if !reflect.DeepEqual(exp.MissingSkills, tc.missing) {
+			t.Errorf("%s: expected missing %v, got %v", tc.name, tc.missing, exp.MissingSkills)
+		}
+		if !reflect.DeepEqual(exp.Suggestions, tc.suggestions) {
+			t.Errorf("%s: expected suggestions %q, got %q", tc.name, tc.suggestions, exp.Suggestions)
+		}
+	}
+}
+
+func TestExplainExtraSkillsByCategory(t *testing.T) {
+	categories := map[string]string{"go": "backend", "rust": "backend", "figma": "design"}
+	exp := ranking.Explain(ranking.ExplainInput{
+		Score:           70,
+		JobSkills:       []string{"Go"},
+		CandidateSkills: []string{"Go", "Rust", "Figma"},
+		CategoryOf:      func(skill string) string { return categories[strings.ToLower(skill)] },
+	})
+	if !reflect.DeepEqual(exp.ExtraSkills, []string{"Rust"}) {
+		t.Fatalf("only extra skills in a required skill's category must be listed, got %v", exp.ExtraSkills)
+	}
+}
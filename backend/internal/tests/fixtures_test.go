@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/config"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/utils"
+)
+
+// testApp is a router over in-memory services plus the helpers API tests share.
+type testApp struct {
+	t      *testing.T
+	cfg    config.Config
+	router *gin.Engine
+	users  *services.UserService
+	// suffix keeps names unique, since in-memory services share state across tests.
+	suffix string
+}
+
+// newTestApp builds a router from deps. The user, job, payment and skill
+// services default to fresh in-memory instances when deps leaves them nil.
+func newTestApp(t *testing.T, deps routes.Deps) *testApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	if deps.UserSvc == nil {
+		deps.UserSvc = services.NewUserService(nil)
+	}
+	if deps.JobSvc == nil {
+		deps.JobSvc = services.NewJobService(nil)
+	}
+	if deps.PaymentSvc == nil {
+		deps.PaymentSvc = services.NewPaymentService(nil)
+	}
+	if deps.SkillSvc == nil {
+		deps.SkillSvc = services.NewSkillService(nil)
+	}
+	cfg := config.Config{JWTSecret: "testsecret", AllowedOriginsCSV: "*"}
+	return &testApp{
+		t:      t,
+		cfg:    cfg,
+		router: routes.SetupRouterWithDeps(cfg, deps),
+		users:  deps.UserSvc,
+		suffix: primitive.NewObjectID().Hex(),
+	}
+}
+
+// register creates a user with a unique email and returns a token for them.
+func (a *testApp) register(name, role string) (string, models.User) {
+	a.t.Helper()
+	return a.registerUser(models.User{Name: name, Role: role})
+}
+
+// registerUser is register for users that need more profile fields than a name and role.
+func (a *testApp) registerUser(u models.User) (string, models.User) {
+	a.t.Helper()
+	if u.Email == "" {
+		u.Email = u.Name + "-" + a.suffix + "@test.com"
+	}
+	u, err := a.users.Register(context.Background(), u, "password123")
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	token, err := utils.GenerateToken(a.cfg.JWTSecret, u.ID.Hex(), u.Email, u.Role, 1)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return token, u
+}
+
+// request performs a JSON request against the router.
+func (a *testApp) request(method, path, body, token string) *httptest.ResponseRecorder {
+	return performRequest(a.router, method, path, body, token)
+}
+
+// decodeData unmarshals the data field of an API response into v.
+func decodeData(t *testing.T, res *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	var resp apiResponse
+	if err := json.Unmarshal(res.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response %q: %v", res.Body.String(), err)
+	}
+	_ = json.Unmarshal(resp.Data, v)
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"rizeos/backend/internal/insights"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/ranking"
+)
+
+func TestSeekerSkillGapsAndTrends(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	engine := ranking.NewEngine(ranking.SkillScorer{})
+	job := func(daysAgo int, skills ...string) insights.Job {
+		rj := ranking.Job{Profile: ranking.ProfileFor(models.Job{Skills: skills})}
+		return insights.Job{
+			Skills:   skills,
+			PostedAt: now.AddDate(0, 0, -daysAgo),
+			Score:    func(s []string) float64 { return engine.Score(rj, ranking.Candidate{Skills: s}).Score },
+		}
+	}
+	jobs := []insights.Job{
+		job(1, "Go", "Docker"),
+		job(2, "Go", "Kubernetes"),
+		job(3, "Go", "Docker"),
+		job(40, "Python", "Django"),
+		job(45, "Go"),
+	}
+
+	qualified, gaps := insights.SkillGaps([]string{"go"}, jobs, 100, 0)
+	if qualified != 1 {
+		t.Fatalf("expected the Go-only job to qualify, got %d", qualified)
+	}
+	if gaps[0].Skill != "Docker" || gaps[0].Unlocks != 2 || gaps[0].Demand != 2 {
+		t.Fatalf("Docker should be the most valuable gap, got %+v", gaps)
+	}
+	for _, g := range gaps {
+		if g.Skill == "Python" && g.Unlocks != 0 {
+			t.Fatalf("one skill cannot fully unlock a two-skill job: %+v", g)
+		}
+	}
+
+	path := insights.CareerPath([]string{"Go"}, jobs, 100, 3)
+	if len(path) != 2 || path[0].Skill != "Docker" || path[0].Qualified != 3 || path[1].Skill != "Kubernetes" || path[1].Qualified != 4 {
+		t.Fatalf("unexpected career path %+v", path)
+	}
+
+	trends := insights.Trending(jobs, now, 30*24*time.Hour, 3, 0)
+	if len(trends) != 3 || trends[0].Skill != "Go" || trends[1].Skill != "Docker" || trends[2].Skill != "Kubernetes" {
+		t.Fatalf("expected Go and Docker (equal growth, Go busier) ahead of Kubernetes, got %+v", trends)
+	}
+	if trends[0].Recent != 3 || trends[0].Previous != 1 || trends[0].Growth != 200 {
+		t.Fatalf("unexpected Go trend %+v", trends[0])
+	}
+
+	counts := insights.CountSkills([][]string{{"Go", "go"}, {"Go"}, {"Docker"}})
+	if counts[0] != (insights.SkillCount{Skill: "Go", Count: 2}) {
+		t.Fatalf("unexpected counts %+v", counts)
+	}
+}
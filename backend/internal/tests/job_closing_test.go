@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+func TestJobClosed(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+	cases := []struct {
+		name      string
+		expiresAt *time.Time
+		closed    bool
+	}{
+		{"no closing date", nil, false},
+		{"closes later", &future, false},
+		{"closes now", &now, true},
+		{"closed", &past, true},
+	}
+	for _, tc := range cases {
+		if got := (models.Job{ExpiresAt: tc.expiresAt}).Closed(now); got != tc.closed {
+			t.Errorf("%s: expected closed=%v, got %v", tc.name, tc.closed, got)
+		}
+	}
+}
+
+func TestApplyRejectsClosedJobs(t *testing.T) {
+	ctx := context.Background()
+	jobs := services.NewJobService(nil)
+	app := newTestApp(t, routes.Deps{JobSvc: jobs, JobApplicationSvc: services.NewJobApplicationService(nil)})
+	token, _ := app.register("closing-seeker", models.RoleSeeker)
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	for _, tc := range []struct {
+		name      string
+		expiresAt *time.Time
+		code      int
+	}{
+		{"no closing date", nil, http.StatusCreated},
+		{"open", &future, http.StatusCreated},
+		{"closed", &past, http.StatusBadRequest},
+	} {
+		job, err := jobs.Create(ctx, models.Job{RecruiterID: primitive.NewObjectID(), Title: tc.name, ExpiresAt: tc.expiresAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := app.request(http.MethodPost, "/api/job-applications/apply", `{"jobId":"`+job.ID.Hex()+`"}`, token)
+		if res.Code != tc.code {
+			t.Errorf("%s: expected %d, got %d %s", tc.name, tc.code, res.Code, res.Body.String())
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/services"
+)
+
+func TestJobViewsDedupeAndPopularity(t *testing.T) {
+	ctx := context.Background()
+	analytics := services.NewAnalyticsService(nil)
+	views := services.NewJobViewService(nil, analytics)
+	recruiter := primitive.NewObjectID()
+	hot := models.Job{ID: primitive.NewObjectID(), RecruiterID: recruiter}
+	stale := models.Job{ID: primitive.NewObjectID(), RecruiterID: recruiter}
+	now := time.Now().UTC()
+
+	seeker := primitive.NewObjectID()
+	anon := services.AnonymousViewerKey("10.0.0.1", "curl/8")
+	if anon != services.AnonymousViewerKey("10.0.0.1", "curl/8") || anon == services.AnonymousViewerKey("10.0.0.1", "firefox") {
+		t.Fatal("anonymous keys must be stable per IP address and user agent")
+	}
+	track := func(job models.Job, key string, user *primitive.ObjectID, at time.Time) bool {
+		t.Helper()
+		counted, err := views.Track(ctx, job, key, user, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return counted
+	}
+	if !track(hot, services.UserViewerKey(seeker), &seeker, now) || track(hot, services.UserViewerKey(seeker), &seeker, now.Add(time.Minute)) {
+		t.Fatal("a viewer must be counted once per day")
+	}
+	if !track(hot, anon, nil, now) || !track(hot, services.UserViewerKey(seeker), &seeker, now.AddDate(0, 0, -1)) {
+		t.Fatal("other viewers and other days must be counted")
+	}
+	track(stale, anon, nil, now.AddDate(0, 0, -10))
+	track(stale, services.UserViewerKey(seeker), &seeker, now.AddDate(0, 0, -10))
+
+	counts, err := views.Counts(ctx, []primitive.ObjectID{hot.ID, stale.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts[hot.ID] != (services.ViewCount{Views: 3, Viewers: 2}) {
+		t.Fatalf("unexpected view counts %+v", counts[hot.ID])
+	}
+
+	funnel, _ := analytics.FunnelByJob(ctx, services.AnalyticsFilter{RecruiterID: recruiter, From: now.AddDate(0, 0, -2), To: now})
+	if funnel[hot.ID].Views != 3 {
+		t.Fatalf("deduplicated views must feed the funnel, got %+v", funnel[hot.ID])
+	}
+
+	popularity, err := analytics.Popularity(ctx, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if popularity[hot.ID] <= popularity[stale.ID] || popularity[stale.ID] <= 0 {
+		t.Fatalf("recent views must outweigh older ones: %+v", popularity)
+	}
+}
+
+func TestAnonymousJobViewsAreCappedPerIP(t *testing.T) {
+	ctx := context.Background()
+	views := services.NewJobViewService(nil, nil)
+	job := models.Job{ID: primitive.NewObjectID(), RecruiterID: primitive.NewObjectID()}
+	ip := "203.0.113." + primitive.NewObjectID().Hex()[20:]
+	now := time.Now()
+
+	if counted, _ := views.TrackAnonymous(ctx, job, ip, "curl/8", now); !counted {
+		t.Fatal("the first anonymous view must be counted")
+	}
+	if counted, _ := views.TrackAnonymous(ctx, job, ip, "curl/8", now); counted {
+		t.Fatal("repeat views by the same client must not be counted")
+	}
+	// Rotating user agents only adds views up to the per-IP cap.
+	for i := 1; i < services.MaxAnonymousViewsPerIP+10; i++ {
+		_, _ = views.TrackAnonymous(ctx, job, ip, fmt.Sprintf("agent/%d", i), now)
+	}
+	counts, _ := views.Counts(ctx, []primitive.ObjectID{job.ID})
+	if counts[job.ID].Views != services.MaxAnonymousViewsPerIP {
+		t.Fatalf("expected views capped at %d, got %d", services.MaxAnonymousViewsPerIP, counts[job.ID].Views)
+	}
+	if counted, _ := views.TrackAnonymous(ctx, job, ip, "agent/new", now.AddDate(0, 0, 1)); !counted {
+		t.Fatal("the cap must reset the next day")
+	}
+}
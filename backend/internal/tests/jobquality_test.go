@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"rizeos/backend/internal/jobquality"
+)
+
+func TestJobQualityFlagsDraftProblems(t *testing.T) {
+	draft := jobquality.Draft{
+		Title:       "Go Ninja",
+		Description: "We need a young rockstar who knows Go and Kubernetes. He must be aggressive.",
+		Skills:      []string{"Go", "Docker"},
+		Location:    "Anywhere",
+	}
+	in := jobquality.Inputs{
+		ExtractedSkills: []string{"go", "Kubernetes"},
+		SeekerSkills:    [][]string{{"Go", "Docker"}, {"Go"}, {"Python"}},
+	}
+	r := jobquality.Analyze(draft, in)
+
+	codes := map[string]int{}
+	for _, is := range r.Issues {
+		codes[is.Code]++
+	}
+	for _, want := range []string{"short_description", "unlisted_skills", "vague_location", "missing_budget"} {
+		if codes[want] != 1 {
+			t.Fatalf("expected %s, got %+v", want, r.Issues)
+		}
+	}
+	if codes["biased_wording"] != 5 { // ninja, young, rockstar, "he must", aggressive
+		t.Fatalf("expected 5 biased wording hits, got %d: %+v", codes["biased_wording"], r.Issues)
+	}
+	if strings.Join(r.UnlistedSkills, ",") != "Kubernetes" {
+		t.Fatalf("unexpected unlisted skills %v", r.UnlistedSkills)
+	}
+	if r.CandidatePool != (jobquality.CandidatePool{Total: 3, Full: 1, Partial: 2}) {
+		t.Fatalf("unexpected pool %+v", r.CandidatePool)
+	}
+	if r.Score >= 50 || r.Issues[0].Severity != jobquality.SeverityWarning {
+		t.Fatalf("expected a low score sorted by severity, got %d %+v", r.Score, r.Issues)
+	}
+
+	clean := jobquality.Analyze(jobquality.Draft{
+		Title:       "Backend Engineer",
+		Description: strings.Repeat("Build and operate Go services with the platform team. ", 10),
+		Skills:      []string{"Go"},
+		Location:    "Bengaluru",
+		Budget:      1200,
+	}, jobquality.Inputs{ExtractedSkills: []string{"Go"}, SeekerSkills: [][]string{{"go"}}})
+	if clean.Score != 100 || len(clean.Issues) != 0 {
+		t.Fatalf("expected a clean draft, got %d %+v", clean.Score, clean.Issues)
+	}
+}
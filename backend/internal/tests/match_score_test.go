@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/services"
+)
+
+func TestMatchScoreCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	store := services.NewMatchScoreService(nil, nil, nil, nil, time.Hour)
+	job := models.Job{ID: primitive.NewObjectID(), Description: "Go backend engineer", Skills: []string{"Go", "Docker"}}
+	seeker := models.User{ID: primitive.NewObjectID(), Bio: "Backend developer", Skills: []string{"go"}}
+
+	if err := store.Put(ctx, job, seeker, services.MatchBreakdown{Score: 72}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if res, ok := store.Get(ctx, job, seeker); !ok || res.Score != 72 {
+		t.Fatalf("expected cached score 72, got %+v (hit=%v)", res, ok)
+	}
+
+	// Skill order and case do not change the content hash.
+	reordered := job
+	reordered.Skills = []string{"docker", "GO"}
+	if _, ok := store.Get(ctx, reordered, seeker); !ok {
+		t.Fatalf("reordered skills should hit the cache")
+	}
+
+	edited := seeker
+	edited.Bio = "Frontend developer"
+	if _, ok := store.Get(ctx, job, edited); ok {
+		t.Fatalf("changed bio should miss the cache")
+	}
+
+	if err := store.InvalidateJob(ctx, job.ID); err != nil {
+		t.Fatalf("invalidate: %v", err)
+	}
+	if _, ok := store.Get(ctx, job, seeker); ok {
+		t.Fatalf("invalidated job should miss the cache")
+	}
+}
+
+func TestMatchScoreStoreBatchesMissesThroughMatcher(t *testing.T) {
+	ctx := context.Background()
+	fake := &services.FakeAI{}
+	store := services.NewMatchScoreService(nil, fake, nil, nil, time.Hour)
+	job := models.Job{ID: primitive.NewObjectID(), Description: "Go engineer", Skills: []string{"Go", "Docker"}}
+	seekers := []models.User{
+		{ID: primitive.NewObjectID(), Bio: "Gopher", Skills: []string{"Go"}},
+		{ID: primitive.NewObjectID(), Bio: "Ops", Skills: []string{"Go", "Docker"}},
+		{ID: primitive.NewObjectID()}, // no bio: scores 0 without a call
+	}
+
+	first := store.GetOrComputeForJob(ctx, job, seekers)
+	if first[0].Score != 50 || first[1].Score != 100 || first[2].Score != 0 || first[0].Engine != services.EngineFake {
+		t.Fatalf("unexpected scores: %+v", first)
+	}
+	if fake.Calls() != 2 {
+		t.Fatalf("expected 2 scored pairs, got %d", fake.Calls())
+	}
+	second := store.GetOrComputeForJob(ctx, job, seekers)
+	if fake.Calls() != 2 || second[1].Score != 100 {
+		t.Fatalf("second read should be served from cache, calls=%d %+v", fake.Calls(), second)
+	}
+}
+
+func TestNewAIBackendSelection(t *testing.T) {
+	for name, want := range map[string]interface{}{"": &services.AIService{}, "local": &services.LocalAI{}, "fake": &services.FakeAI{}} {
+		backend, err := services.NewAIBackend(name, "http://localhost:8000", nil)
+		if err != nil {
+			t.Fatalf("%q: %v", name, err)
+		}
+		if fmt.Sprintf("%T", backend) != fmt.Sprintf("%T", want) {
+			t.Fatalf("%q: got %T, want %T", name, backend, want)
+		}
+	}
+	if _, err := services.NewAIBackend("gpt", "", nil); err == nil {
+		t.Fatalf("unknown engine should fail")
+	}
+}
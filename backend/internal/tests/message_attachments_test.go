@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+	"rizeos/backend/internal/storage"
+)
+
+// eicarScanner flags files containing the EICAR test marker.
+type eicarScanner struct{}
+
+func (eicarScanner) Scan(_ context.Context, _ string, data []byte) error {
+	if bytes.Contains(data, []byte("EICAR")) {
+		return storage.ErrInfected
+	}
+	return nil
+}
+
+func TestMessageAttachments(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.NewLocalStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(t, routes.Deps{
+		MessageSvc:    services.NewMessageService(nil),
+		AttachmentSvc: services.NewAttachmentService(store, eicarScanner{}, 1<<10),
+	})
+	recToken, _ := app.register("att-rec", models.RoleRecruiter)
+	seekToken, seeker := app.register("att-seek", models.RoleSeeker)
+	strangerToken, _ := app.register("att-other", models.RoleSeeker)
+	seekID := seeker.ID.Hex()
+
+	sendAs := func(token, toRole, toUserID, name string, content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		if toUserID != "" {
+			_ = form.WriteField("toUserId", toUserID)
+		}
+		_ = form.WriteField("toRole", toRole)
+		_ = form.WriteField("message", "see attached")
+		part, _ := form.CreateFormFile("attachments", name)
+		_, _ = part.Write(content)
+		_ = form.Close()
+		req := httptest.NewRequest(http.MethodPost, "/api/messages/send", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		app.router.ServeHTTP(w, req)
+		return w
+	}
+	send := func(name string, content []byte) *httptest.ResponseRecorder {
+		return sendAs(recToken, "seeker", seekID, name, content)
+	}
+	type sentMessage struct {
+		ID          string `json:"id"`
+		Attachments []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			Key  string `json:"key"`
+		} `json:"attachments"`
+	}
+
+	if res := send("script.exe", []byte("MZ binary")); res.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("disallowed types must be rejected, got %d", res.Code)
+	}
+	if res := send("fake.pdf", []byte("plain text, not a pdf")); res.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("content must match the extension, got %d", res.Code)
+	}
+	if res := send("big.txt", bytes.Repeat([]byte("a"), 2<<10)); res.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized files must be rejected, got %d", res.Code)
+	}
+	if res := send("virus.txt", []byte("EICAR test file")); res.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("infected files must be rejected, got %d", res.Code)
+	}
+
+	res := send("offer.txt", []byte("offer letter"))
+	if res.Code != http.StatusCreated {
+		t.Fatalf("send with attachment failed: %d %s", res.Code, res.Body.String())
+	}
+	var msg sentMessage
+	decodeData(t, res, &msg)
+	if len(msg.Attachments) != 1 || msg.Attachments[0].Name != "offer.txt" || msg.Attachments[0].Key != "" {
+		t.Fatalf("expected one attachment without its storage key, got %+v", msg.Attachments)
+	}
+	url := "/api/messages/" + msg.ID + "/attachments/" + msg.Attachments[0].ID
+
+	dl := app.request(http.MethodGet, url, "", seekToken)
+	if dl.Code != http.StatusOK || dl.Body.String() != "offer letter" {
+		t.Fatalf("the recipient must download the file, got %d %q", dl.Code, dl.Body.String())
+	}
+	if cd := dl.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment") || !strings.Contains(cd, "offer.txt") {
+		t.Fatalf("downloads must be served as attachments, got %q", cd)
+	}
+	if res := app.request(http.MethodGet, url, "", strangerToken); res.Code != http.StatusNotFound {
+		t.Fatalf("strangers must not download attachments, got %d", res.Code)
+	}
+
+	blob := filepath.Join(dir, msg.Attachments[0].ID[:2], msg.Attachments[0].ID)
+	app.request(http.MethodDelete, "/api/messages/"+msg.ID, "", seekToken)
+	if _, err := os.Stat(blob); err != nil {
+		t.Fatalf("the file must stay while the sender keeps the message: %v", err)
+	}
+	if res := app.request(http.MethodGet, url, "", seekToken); res.Code != http.StatusNotFound {
+		t.Fatalf("deleted messages' attachments must not be served, got %d", res.Code)
+	}
+	app.request(http.MethodDelete, "/api/messages/"+msg.ID, "", recToken)
+	if _, err := os.Stat(blob); !os.IsNotExist(err) {
+		t.Fatalf("the file must be removed once both sides deleted the message, got %v", err)
+	}
+
+	// A message to admins stays visible to every other admin, so its files
+	// outlive any one admin deleting it.
+	firstAdmin, _ := app.register("att-admin-1", models.RoleAdmin)
+	secondAdmin, _ := app.register("att-admin-2", models.RoleAdmin)
+	res = sendAs(recToken, "admin", "", "ticket.txt", []byte("support ticket"))
+	if res.Code != http.StatusCreated {
+		t.Fatalf("support message with attachment failed: %d %s", res.Code, res.Body.String())
+	}
+	var ticket sentMessage
+	decodeData(t, res, &ticket)
+	ticketURL := "/api/messages/" + ticket.ID + "/attachments/" + ticket.Attachments[0].ID
+	ticketBlob := filepath.Join(dir, ticket.Attachments[0].ID[:2], ticket.Attachments[0].ID)
+	app.request(http.MethodDelete, "/api/messages/"+ticket.ID, "", recToken)
+	app.request(http.MethodDelete, "/api/messages/"+ticket.ID, "", firstAdmin)
+	if _, err := os.Stat(ticketBlob); err != nil {
+		t.Fatalf("the file must stay while other admins can see the message: %v", err)
+	}
+	if dl := app.request(http.MethodGet, ticketURL, "", secondAdmin); dl.Code != http.StatusOK || dl.Body.String() != "support ticket" {
+		t.Fatalf("other admins must still download the file, got %d %q", dl.Code, dl.Body.String())
+	}
+	app.request(http.MethodDelete, "/api/messages/"+ticket.ID, "", secondAdmin)
+	if _, err := os.Stat(ticketBlob); err != nil {
+		t.Fatalf("files of messages to admins must be kept for admins added later: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/services"
+)
+
+func TestMessageOwnershipArchiveAndReports(t *testing.T) {
+	ctx := context.Background()
+	messages := services.NewMessageService(nil)
+	recruiter, seeker, stranger, admin := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	send := func(text string) models.Message {
+		t.Helper()
+		msg, err := messages.Create(ctx, models.Message{FromUserID: recruiter, FromRole: models.RoleRecruiter, ToUserID: seeker, ToRole: models.RoleSeeker, Message: text})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+	first, second, third := send("one"), send("two"), send("spam")
+
+	if err := messages.MarkAsRead(ctx, first.ID, stranger, models.RoleSeeker); err != mongo.ErrNoDocuments {
+		t.Fatalf("only the recipient may mark a message read, got %v", err)
+	}
+	if err := messages.MarkAsRead(ctx, first.ID, recruiter, models.RoleRecruiter); err != mongo.ErrNoDocuments {
+		t.Fatalf("the sender may not mark their own message read, got %v", err)
+	}
+	if err := messages.MarkAsRead(ctx, first.ID, seeker, models.RoleSeeker); err != nil {
+		t.Fatal(err)
+	}
+
+	inbox := func(archived bool) int {
+		t.Helper()
+		msgs, err := messages.GetSeekerInbox(ctx, seeker, archived)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(msgs)
+	}
+	if err := messages.SetArchived(ctx, second.ID, seeker, models.RoleSeeker, true); err != nil {
+		t.Fatal(err)
+	}
+	if inbox(false) != 2 || inbox(true) != 1 {
+		t.Fatalf("archived messages must move to the archive, got %d/%d", inbox(false), inbox(true))
+	}
+	if err := messages.SetArchived(ctx, second.ID, seeker, models.RoleSeeker, false); err != nil || inbox(false) != 3 {
+		t.Fatalf("unarchiving must restore the message, got %v with %d", err, inbox(false))
+	}
+	if err := messages.SetArchived(ctx, second.ID, stranger, models.RoleSeeker, true); err != mongo.ErrNoDocuments {
+		t.Fatalf("strangers must not archive others' messages, got %v", err)
+	}
+
+	if err := messages.Delete(ctx, second.ID, seeker, models.RoleSeeker); err != nil {
+		t.Fatal(err)
+	}
+	if inbox(false) != 2 {
+		t.Fatalf("deleted messages must leave the inbox, got %d", inbox(false))
+	}
+	if count, _ := messages.GetSeekerUnreadCount(ctx, seeker); count != 1 {
+		t.Fatalf("deleted messages must not count as unread, got %d", count)
+	}
+	seekerThread, _, _ := messages.ConversationMessages(ctx, *first.ConversationID, seeker, primitive.NilObjectID, 10)
+	recruiterThread, _, _ := messages.ConversationMessages(ctx, *first.ConversationID, recruiter, primitive.NilObjectID, 10)
+	if len(seekerThread) != 2 || len(recruiterThread) != 3 {
+		t.Fatalf("deletion is per user: seeker sees %d, recruiter sees %d", len(seekerThread), len(recruiterThread))
+	}
+
+	if n, err := messages.MarkAllRead(ctx, seeker, models.RoleSeeker); err != nil || n != 1 {
+		t.Fatalf("expected one message marked read, got %d (%v)", n, err)
+	}
+	if conv, _ := messages.FindConversation(ctx, *first.ConversationID); conv.Unread[seeker.Hex()] != 0 {
+		t.Fatalf("mark all read must clear the thread's unread count, got %d", conv.Unread[seeker.Hex()])
+	}
+
+	if _, err := messages.Report(ctx, third.ID, recruiter, models.RoleRecruiter, "mine"); err != mongo.ErrNoDocuments {
+		t.Fatalf("only the recipient may report a message, got %v", err)
+	}
+	report, err := messages.Report(ctx, third.ID, seeker, models.RoleSeeker, "spam")
+	if err != nil || report.Status != models.ReportOpen || report.SenderID != recruiter {
+		t.Fatalf("unexpected report %+v (%v)", report, err)
+	}
+	if _, err := messages.Report(ctx, third.ID, seeker, models.RoleSeeker, "spam"); err != services.ErrAlreadyReported {
+		t.Fatalf("repeat reports must be rejected, got %v", err)
+	}
+	open, _ := messages.ListReports(ctx, models.ReportOpen)
+	found := false
+	for _, r := range open {
+		found = found || r.ID == report.ID
+	}
+	if !found {
+		t.Fatal("open reports must be listed for admins")
+	}
+	if _, err := messages.ReviewReport(ctx, report.ID, admin, models.ReportOpen, ""); err != services.ErrInvalidReportStatus {
+		t.Fatalf("reviews must close the report, got %v", err)
+	}
+	if reviewed, err := messages.ReviewReport(ctx, report.ID, admin, models.ReportResolved, "warned sender"); err != nil || reviewed.Status != models.ReportResolved || *reviewed.ReviewedBy != admin {
+		t.Fatalf("unexpected review %+v (%v)", reviewed, err)
+	}
+	if _, err := messages.ReviewReport(ctx, report.ID, admin, models.ReportDismissed, ""); err != services.ErrReportReviewed {
+		t.Fatalf("reports can only be reviewed once, got %v", err)
+	}
+}
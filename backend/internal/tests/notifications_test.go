@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+type recordingMailer struct {
+	sync.Mutex
+	sent map[string][]string // to -> subjects
+}
+
+func (m *recordingMailer) Send(_ context.Context, to, subject, _ string) error {
+	m.Lock()
+	defer m.Unlock()
+	m.sent[to] = append(m.sent[to], subject)
+	return nil
+}
+
+type recordingWebhook struct {
+	sync.Mutex
+	calls map[string][]int // url -> notifications per call
+}
+
+func (w *recordingWebhook) Post(_ context.Context, url string, payload interface{}) error {
+	w.Lock()
+	defer w.Unlock()
+	batch := payload.(map[string]interface{})["notifications"].([]models.Notification)
+	w.calls[url] = append(w.calls[url], len(batch))
+	return nil
+}
+
+func TestNotificationCenterPreferencesAndDigests(t *testing.T) {
+	ctx := context.Background()
+	mailer := &recordingMailer{sent: map[string][]string{}}
+	webhook := &recordingWebhook{calls: map[string][]int{}}
+	users := services.NewUserService(nil)
+	jobs := services.NewJobService(nil)
+	notifications := services.NewNotificationService(nil, users, mailer, webhook)
+	app := newTestApp(t, routes.Deps{
+		UserSvc:           users,
+		JobSvc:            jobs,
+		JobApplicationSvc: services.NewJobApplicationService(nil),
+		NotificationSvc:   notifications,
+	})
+
+	type center struct {
+		Items []struct {
+			ID   string            `json:"id"`
+			Type string            `json:"type"`
+			Data map[string]string `json:"data"`
+		} `json:"items"`
+		Unread int `json:"unread_count"`
+	}
+	list := func(token string) center {
+		t.Helper()
+		var c center
+		decodeData(t, app.request(http.MethodGet, "/api/notifications", "", token), &c)
+		return c
+	}
+	recToken, rec := app.register("notify-rec", models.RoleRecruiter)
+	seekToken, _ := app.register("notify-seek", models.RoleSeeker)
+	recEmail := rec.Email
+
+	closes := time.Now().Add(48 * time.Hour)
+	job, err := jobs.Create(ctx, models.Job{RecruiterID: rec.ID, Title: "Go Developer", Skills: []string{"go"}, ExpiresAt: &closes})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := app.request(http.MethodPost, "/api/job-applications/apply", `{"jobId":"`+job.ID.Hex()+`"}`, seekToken)
+	if res.Code != http.StatusCreated {
+		t.Fatalf("apply failed: %d %s", res.Code, res.Body.String())
+	}
+	var application struct {
+		ID string `json:"id"`
+	}
+	decodeData(t, res, &application)
+
+	recCenter := list(recToken)
+	if len(recCenter.Items) != 1 || recCenter.Items[0].Type != models.NotifyNewApplicant || recCenter.Unread != 1 {
+		t.Fatalf("the recruiter must be told about the applicant, got %+v", recCenter)
+	}
+	if len(mailer.sent[recEmail]) != 0 {
+		t.Fatal("email must not be sent on the request path")
+	}
+	if _, err := notifications.DeliverPending(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if len(mailer.sent[recEmail]) != 1 {
+		t.Fatalf("notifications must be emailed by default, got %v", mailer.sent[recEmail])
+	}
+
+	if res := app.request(http.MethodPut, "/api/notifications/preferences", `{"channels":{"bogus":["in_app"]}}`, seekToken); res.Code != http.StatusBadRequest {
+		t.Fatalf("unknown types must be rejected, got %d", res.Code)
+	}
+	if res := app.request(http.MethodPut, "/api/notifications/preferences", `{"channels":{"application_status":["webhook"]}}`, seekToken); res.Code != http.StatusBadRequest {
+		t.Fatalf("the webhook channel must need a URL, got %d", res.Code)
+	}
+	for _, unsafe := range []string{"ftp://93.184.216.34/hook", "http://127.0.0.1:8080/hook", "http://10.0.0.5/hook", "http://169.254.169.254/latest/meta-data", "http://[::1]/hook"} {
+		body := `{"channels":{"application_status":["webhook"]},"webhook_url":"` + unsafe + `"}`
+		if res := app.request(http.MethodPut, "/api/notifications/preferences", body, seekToken); res.Code != http.StatusBadRequest {
+			t.Fatalf("webhook URL %s must be rejected, got %d", unsafe, res.Code)
+		}
+	}
+	hook := "https://93.184.216.34/" + app.suffix
+	prefsBody := `{"channels":{"application_status":["in_app","webhook"]},"webhook_url":"` + hook + `","digest":"daily"}`
+	if res := app.request(http.MethodPut, "/api/notifications/preferences", prefsBody, seekToken); res.Code != http.StatusOK {
+		t.Fatalf("saving preferences failed: %d %s", res.Code, res.Body.String())
+	}
+
+	for _, status := range []string{"SHORTLISTED", "HIRED"} {
+		if res := app.request(http.MethodPut, "/api/recruiter/applications/"+application.ID+"/status", `{"status":"`+status+`"}`, recToken); res.Code != http.StatusOK {
+			t.Fatalf("status update failed: %d %s", res.Code, res.Body.String())
+		}
+	}
+	seekCenter := list(seekToken)
+	if len(seekCenter.Items) != 2 || seekCenter.Items[0].Data["status"] != "HIRED" {
+		t.Fatalf("the seeker must see both status changes, newest first, got %+v", seekCenter)
+	}
+	if len(webhook.calls[hook]) != 0 {
+		t.Fatal("digest notifications must wait for the digest")
+	}
+	now := time.Now()
+	if _, err := notifications.DeliverPending(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if calls := webhook.calls[hook]; len(calls) != 1 || calls[0] != 2 {
+		t.Fatalf("expected one webhook call with both notifications, got %v", calls)
+	}
+
+	if res := app.request(http.MethodPut, "/api/notifications/"+seekCenter.Items[0].ID+"/read", "", recToken); res.Code != http.StatusNotFound {
+		t.Fatalf("users must not read others' notifications, got %d", res.Code)
+	}
+	if res := app.request(http.MethodPut, "/api/notifications/"+seekCenter.Items[0].ID+"/read", "", seekToken); res.Code != http.StatusOK {
+		t.Fatalf("mark read failed: %d", res.Code)
+	}
+	if c := list(seekToken); c.Unread != 1 {
+		t.Fatalf("expected one unread notification left, got %d", c.Unread)
+	}
+	app.request(http.MethodPut, "/api/notifications/read-all", "", seekToken)
+	if c := list(seekToken); c.Unread != 0 {
+		t.Fatalf("mark all read must clear the unread count, got %d", c.Unread)
+	}
+
+	if n, err := notifications.NotifyExpiringJobs(ctx, jobs, now); err != nil || n < 1 {
+		t.Fatalf("expected an expiry notice, got %d (%v)", n, err)
+	}
+	if n, _ := notifications.NotifyExpiringJobs(ctx, jobs, now); n != 0 {
+		t.Fatalf("expiry notices must be sent once, got %d more", n)
+	}
+	if c := list(recToken); len(c.Items) != 2 || c.Items[0].Type != models.NotifyJobExpiring {
+		t.Fatalf("the recruiter must be told once that the job closes soon, got %+v", c.Items)
+	}
+}
+
+func TestHTTPWebhookRefusesInternalAddresses(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer srv.Close()
+
+	err := services.HTTPWebhook{}.Post(context.Background(), srv.URL, map[string]string{"ping": "pong"})
+	if !errors.Is(err, services.ErrUnsafeWebhook) || hits != 0 {
+		t.Fatalf("webhooks must not dial loopback addresses, got %v after %d hits", err, hits)
+	}
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+// The in-memory stores are shared across tests, so this checks deltas.
+func TestPlatformMetricsAggregates(t *testing.T) {
+	ctx := context.Background()
+	analytics := services.NewPlatformAnalyticsService(nil)
+	users := services.NewUserService(nil)
+	payments := services.NewPaymentService(nil)
+	messages := services.NewMessageService(nil)
+	apps := services.NewJobApplicationService(nil)
+	r := services.PlatformRange{From: time.Now().Add(-time.Hour), To: time.Now().Add(time.Hour), Interval: services.IntervalDay}
+
+	before, err := analytics.Metrics(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recruiter, err := users.Register(ctx, models.User{Name: "Big Spender", Email: "spender@platform.test", Role: models.RoleRecruiter}, "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seeker, err := users.Register(ctx, models.User{Name: "Seeker", Email: "seeker@platform.test", Role: models.RoleSeeker}, "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, amount := range []float64{400, 600} {
+		p, _ := payments.VerifyAndStore(ctx, "", "0xadmin", primitive.NewObjectID().Hex(), amount)
+		if err := payments.AttachRecruiter(ctx, p.ID, recruiter.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	premium, _ := payments.VerifyAndStore(ctx, "", "0xadmin", primitive.NewObjectID().Hex(), 5)
+	_ = payments.AttachJobSeeker(ctx, premium.ID, seeker.ID)
+	_ = users.UpdatePremiumStatus(ctx, seeker.ID, premium.ID)
+	_, _ = messages.Create(ctx, models.Message{FromUserID: seeker.ID, FromRole: models.RoleSeeker, ToUserID: recruiter.ID, ToRole: models.RoleRecruiter, Message: "hi"})
+	_, _ = apps.Create(ctx, models.JobApplication{JobID: primitive.NewObjectID(), JobSeekerID: seeker.ID, RecruiterID: recruiter.ID, AppliedAt: time.Now()})
+
+	after, err := analytics.Metrics(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := after.Revenue.ByType["JOB_POSTING"] - before.Revenue.ByType["JOB_POSTING"]; got != 1000 {
+		t.Fatalf("expected 1000 of job posting revenue, got %v", got)
+	}
+	if got := after.Revenue.ByType["JOB_SEEKER_PREMIUM"] - before.Revenue.ByType["JOB_SEEKER_PREMIUM"]; got != 5 {
+		t.Fatalf("expected 5 of premium revenue, got %v", got)
+	}
+	if after.Signups.ByRole[models.RoleRecruiter]-before.Signups.ByRole[models.RoleRecruiter] != 1 || after.Signups.ByRole[models.RoleSeeker]-before.Signups.ByRole[models.RoleSeeker] != 1 {
+		t.Fatalf("unexpected signups %+v -> %+v", before.Signups.ByRole, after.Signups.ByRole)
+	}
+	if after.Premium.Premium-before.Premium.Premium != 1 || after.Premium.Purchases-before.Premium.Purchases != 1 || after.Premium.ConversionRate == nil {
+		t.Fatalf("unexpected premium metrics %+v", after.Premium)
+	}
+	if after.Messages.ByRole[models.RoleSeeker]-before.Messages.ByRole[models.RoleSeeker] != 1 || after.Applications.Total-before.Applications.Total != 1 {
+		t.Fatalf("unexpected activity %+v %+v", after.Messages, after.Applications)
+	}
+	if top := after.TopRecruiters[0]; top.RecruiterID != recruiter.ID.Hex() || top.Name != "Big Spender" || top.Amount != 1000 || top.Payments != 2 {
+		t.Fatalf("expected the recruiter to top spend, got %+v", top)
+	}
+	if len(after.Revenue.Series) == 0 || after.Revenue.Series[0].Period.Hour() != 0 {
+		t.Fatalf("revenue series must be bucketed by day: %+v", after.Revenue.Series)
+	}
+
+	if _, err := analytics.Metrics(ctx, services.PlatformRange{From: r.From, To: r.To, Interval: "hour"}); err == nil {
+		t.Fatal("unknown intervals must be rejected")
+	}
+}
+
+func TestPlatformMetricsUseSnakeCaseJSON(t *testing.T) {
+	rate := 50.0
+	body, err := json.Marshal(services.PlatformMetrics{
+		Revenue:       services.RevenueMetrics{ByType: map[string]float64{}, Series: []services.AmountPoint{{}}},
+		Premium:       services.PremiumMetrics{ConversionRate: &rate},
+		TopRecruiters: []services.RecruiterSpend{{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"by_type"`, `"payment_type"`, `"by_role"`, `"conversion_rate"`, `"top_recruiters"`, `"recruiter_id"`} {
+		if !strings.Contains(string(body), key) {
+			t.Errorf("expected %s in %s", key, body)
+		}
+	}
+}
+
+func TestAdminListsPageInMemory(t *testing.T) {
+	ctx := context.Background()
+	jobs := services.NewJobService(nil)
+	payments := services.NewPaymentService(nil)
+	app := newTestApp(t, routes.Deps{JobSvc: jobs, PaymentSvc: payments})
+	adminToken, _ := app.register("lists-admin", models.RoleAdmin)
+	_, older := app.register("lists-older", models.RoleSeeker)
+	_, newest := app.register("lists-newest", models.RoleSeeker)
+	job, err := jobs.Create(ctx, models.Job{Title: "Lists " + app.suffix, RecruiterID: newest.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment, err := payments.VerifyAndStore(ctx, "", "0xadmin", primitive.NewObjectID().Hex(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type page struct {
+		Items []map[string]interface{} `json:"items"`
+		Total int                      `json:"total"`
+	}
+	var users page
+	decodeData(t, app.request(http.MethodGet, "/api/admin/users?limit=2", "", adminToken), &users)
+	if users.Total < 3 || len(users.Items) != 2 || users.Items[0]["id"] != newest.ID.Hex() || users.Items[1]["id"] != older.ID.Hex() {
+		t.Fatalf("expected the newest users first, got %+v", users)
+	}
+	if _, ok := users.Items[0]["password_hash"]; ok {
+		t.Fatal("password hashes must not be listed")
+	}
+	var second page
+	decodeData(t, app.request(http.MethodGet, "/api/admin/users?limit=2&page=2", "", adminToken), &second)
+	if len(second.Items) == 0 || second.Items[0]["id"] == older.ID.Hex() {
+		t.Fatalf("page 2 must continue after page 1, got %+v", second.Items)
+	}
+	var jobPage, paymentPage page
+	decodeData(t, app.request(http.MethodGet, "/api/admin/jobs?limit=1", "", adminToken), &jobPage)
+	if jobPage.Total < 1 || len(jobPage.Items) != 1 || jobPage.Items[0]["id"] != job.ID.Hex() {
+		t.Fatalf("expected the new job listed, got %+v", jobPage)
+	}
+	decodeData(t, app.request(http.MethodGet, "/api/admin/payments?limit=1", "", adminToken), &paymentPage)
+	if paymentPage.Total < 1 || len(paymentPage.Items) != 1 || paymentPage.Items[0]["id"] != payment.ID.Hex() {
+		t.Fatalf("expected the new payment listed, got %+v", paymentPage)
+	}
+}
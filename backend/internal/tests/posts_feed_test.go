@@ -0,0 +1,230 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+func TestSocialFeed(t *testing.T) {
+	ctx := context.Background()
+	jobs := services.NewJobService(nil)
+	payments := services.NewPaymentService(nil)
+	posts := services.NewPostService(nil)
+	app := newTestApp(t, routes.Deps{JobSvc: jobs, PaymentSvc: payments, PostSvc: posts})
+
+	suffix := app.suffix
+	skill := "feedskill" + suffix[len(suffix)-6:]
+	adminToken, _ := app.register("feed-admin", models.RoleAdmin)
+	recToken, recruiter := app.register("feed-rec", models.RoleRecruiter)
+	seekerToken, _ := app.registerUser(models.User{Name: "feed-seeker", Role: models.RoleSeeker, Skills: []string{skill}})
+	otherToken, _ := app.register("feed-other", models.RoleSeeker)
+
+	type feedPage struct {
+		Items []struct {
+			ID        string   `json:"id"`
+			Type      string   `json:"type"`
+			JobID     string   `json:"job_id"`
+			Tags      []string `json:"tags"`
+			Liked     bool     `json:"liked"`
+			LikeCount int64    `json:"like_count"`
+		} `json:"items"`
+		Total int `json:"total"`
+	}
+	feed := func(token string) feedPage {
+		t.Helper()
+		res := app.request(http.MethodGet, "/api/posts?limit=100", "", token)
+		if res.Code != http.StatusOK {
+			t.Fatalf("feed failed: %d %s", res.Code, res.Body.String())
+		}
+		var page feedPage
+		decodeData(t, res, &page)
+		return page
+	}
+	position := func(page feedPage, id string) int {
+		for i, it := range page.Items {
+			if it.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+	created := func(res *httptest.ResponseRecorder) string {
+		t.Helper()
+		var v struct {
+			ID string `json:"id"`
+		}
+		decodeData(t, res, &v)
+		return v.ID
+	}
+
+	// An older post matching the seeker's skills and a newer unrelated one.
+	relevant, err := posts.Create(ctx, models.Post{UserID: recruiter.ID, Content: "Hiring for " + skill, Tags: []string{skill}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := app.request(http.MethodPost, "/api/posts", `{"title":"News","content":"We moved offices","tags":["office"]}`, recToken)
+	if res.Code != http.StatusCreated {
+		t.Fatalf("create failed: %d %s", res.Code, res.Body.String())
+	}
+	unrelated := created(res)
+	if res := app.request(http.MethodPost, "/api/posts", `{"content":"   "}`, recToken); res.Code != http.StatusBadRequest {
+		t.Fatalf("empty posts must be rejected, got %d", res.Code)
+	}
+
+	seekerFeed := feed(seekerToken)
+	if p, u := position(seekerFeed, relevant.ID.Hex()), position(seekerFeed, unrelated); p < 0 || u < 0 || p > u {
+		t.Fatalf("posts matching the viewer's skills must rank first, got %d and %d", p, u)
+	}
+	otherFeed := feed(otherToken)
+	if p, u := position(otherFeed, relevant.ID.Hex()), position(otherFeed, unrelated); p < 0 || u < 0 || u > p {
+		t.Fatalf("without matching skills the newest post must rank first, got %d and %d", p, u)
+	}
+
+	// Creating a job publishes a job_post, and edits to the job follow it.
+	payment, err := payments.VerifyAndStore(ctx, "", "0xadmin", "0xfeed"+suffix, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobBody := `{"title":"Feed Engineer","description":"Build feeds","skills":["` + skill + `"],"payment_id":"` + payment.ID.Hex() + `"}`
+	res = app.request(http.MethodPost, "/api/jobs", jobBody, recToken)
+	if res.Code != http.StatusCreated {
+		t.Fatalf("job create failed: %d %s", res.Code, res.Body.String())
+	}
+	jobID := created(res)
+	var jobPostID string
+	for _, it := range feed(seekerToken).Items {
+		if it.Type == models.PostJob && it.JobID == jobID {
+			jobPostID = it.ID
+		}
+	}
+	if jobPostID == "" {
+		t.Fatal("creating a job must publish a job post")
+	}
+	jobOID, _ := primitive.ObjectIDFromHex(jobID)
+	if _, err := jobs.Update(ctx, jobOID, bson.M{"title": "Senior Feed Engineer"}); err != nil {
+		t.Fatal(err)
+	}
+	jobPostOID, _ := primitive.ObjectIDFromHex(jobPostID)
+	if p, _ := posts.FindByID(ctx, jobPostOID); p.Title != "Senior Feed Engineer" {
+		t.Fatalf("job posts must follow job edits, got %q", p.Title)
+	}
+	if res := app.request(http.MethodPut, "/api/posts/"+jobPostID, `{"content":"edited"}`, recToken); res.Code != http.StatusConflict {
+		t.Fatalf("job posts must not be edited directly, got %d", res.Code)
+	}
+
+	// Editing and deleting are limited to the author.
+	if res := app.request(http.MethodPut, "/api/posts/"+unrelated, `{"content":"hijacked"}`, seekerToken); res.Code != http.StatusNotFound {
+		t.Fatalf("users must not edit others' posts, got %d", res.Code)
+	}
+	if res := app.request(http.MethodPut, "/api/posts/"+unrelated, `{"content":"We moved to a bigger office"}`, recToken); res.Code != http.StatusOK {
+		t.Fatalf("edit failed: %d %s", res.Code, res.Body.String())
+	}
+
+	// Likes are idempotent and comments are counted.
+	for i := 0; i < 2; i++ {
+		app.request(http.MethodPost, "/api/posts/"+unrelated+"/like", "", seekerToken)
+	}
+	for _, it := range feed(seekerToken).Items {
+		if it.ID == unrelated && (!it.Liked || it.LikeCount != 1) {
+			t.Fatalf("expected one like by the viewer, got %+v", it)
+		}
+	}
+	res = app.request(http.MethodPost, "/api/posts/"+unrelated+"/comments", `{"content":"Congrats!"}`, seekerToken)
+	if res.Code != http.StatusCreated {
+		t.Fatalf("comment failed: %d %s", res.Code, res.Body.String())
+	}
+	commentID := created(res)
+	if res := app.request(http.MethodDelete, "/api/posts/"+unrelated+"/comments/"+commentID, "", otherToken); res.Code != http.StatusNotFound {
+		t.Fatalf("others must not delete comments, got %d", res.Code)
+	}
+	var comments struct {
+		Total int `json:"total"`
+	}
+	decodeData(t, app.request(http.MethodGet, "/api/posts/"+unrelated+"/comments", "", otherToken), &comments)
+	if comments.Total != 1 {
+		t.Fatalf("expected one comment, got %d", comments.Total)
+	}
+
+	// Admin moderation hides posts from everyone but their author.
+	if res := app.request(http.MethodPut, "/api/admin/posts/"+unrelated+"/moderation", `{"hidden":true}`, adminToken); res.Code != http.StatusBadRequest {
+		t.Fatalf("hiding must need a reason, got %d", res.Code)
+	}
+	if res := app.request(http.MethodPut, "/api/admin/posts/"+unrelated+"/moderation", `{"hidden":true,"reason":"off topic"}`, adminToken); res.Code != http.StatusOK {
+		t.Fatalf("hide failed: %d %s", res.Code, res.Body.String())
+	}
+	if position(feed(seekerToken), unrelated) >= 0 {
+		t.Fatal("hidden posts must leave the feed")
+	}
+	if res := app.request(http.MethodPost, "/api/posts/"+unrelated+"/like", "", seekerToken); res.Code != http.StatusNotFound {
+		t.Fatalf("hidden posts must not take likes, got %d", res.Code)
+	}
+	if res := app.request(http.MethodGet, "/api/posts/"+unrelated, "", recToken); res.Code != http.StatusOK {
+		t.Fatalf("authors must still see their hidden posts, got %d", res.Code)
+	}
+	if res := app.request(http.MethodPut, "/api/admin/posts/"+unrelated+"/moderation", `{"hidden":false}`, seekerToken); res.Code != http.StatusForbidden {
+		t.Fatalf("only admins may moderate, got %d", res.Code)
+	}
+
+	if res := app.request(http.MethodDelete, "/api/posts/"+relevant.ID.Hex(), "", adminToken); res.Code != http.StatusOK {
+		t.Fatalf("admins must be able to delete posts, got %d", res.Code)
+	}
+	if res := app.request(http.MethodGet, "/api/posts/"+relevant.ID.Hex(), "", seekerToken); res.Code != http.StatusNotFound {
+		t.Fatalf("deleted posts must be gone, got %d", res.Code)
+	}
+}
+
+func TestFeedHidesPostsOfClosedJobs(t *testing.T) {
+	ctx := context.Background()
+	posts := services.NewPostService(nil)
+	now := time.Now()
+	closes := now.Add(time.Hour)
+	job := models.Job{ID: primitive.NewObjectID(), RecruiterID: primitive.NewObjectID(), Title: "Closing soon", Description: "Apply today", ExpiresAt: &closes}
+	post, err := posts.CreateForJob(ctx, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inFeed := func(at time.Time) bool {
+		t.Helper()
+		items, _, err := posts.Feed(ctx, primitive.NewObjectID(), nil, at, 1, 10000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, it := range items {
+			if it.ID == post.ID {
+				return true
+			}
+		}
+		return false
+	}
+	if !inFeed(now) {
+		t.Fatal("open jobs must be in the feed")
+	}
+	if inFeed(closes.Add(time.Minute)) {
+		t.Fatal("expired jobs must leave the feed")
+	}
+
+	closed := now.Add(-time.Minute)
+	job.ExpiresAt = &closed
+	if err := posts.SyncJob(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	if inFeed(now) {
+		t.Fatal("closed jobs must leave the feed")
+	}
+	job.ExpiresAt = nil
+	if err := posts.SyncJob(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	if !inFeed(now) {
+		t.Fatal("reopened jobs must return to the feed")
+	}
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+)
+
+func TestProfileEducationAndExperienceUpdates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router, _ := buildTestRouter()
+
+	email := "profile-" + primitive.NewObjectID().Hex() + "@test.com"
+	res := performRequest(router, http.MethodPost, "/api/auth/register", `{"name":"Seeker","email":"`+email+`","password":"password123","role":"seeker"}`, "")
+	if res.Code != http.StatusCreated {
+		t.Fatalf("register failed: %d %s", res.Code, res.Body.String())
+	}
+	var resp apiResponse
+	_ = json.Unmarshal(res.Body.Bytes(), &resp)
+	var registered struct {
+		Token string `json:"token"`
+	}
+	_ = json.Unmarshal(resp.Data, &registered)
+
+	update := func(body string) models.User {
+		t.Helper()
+		res := performRequest(router, http.MethodPut, "/api/profile", body, registered.Token)
+		if res.Code != http.StatusOK {
+			t.Fatalf("profile update failed: %d %s", res.Code, res.Body.String())
+		}
+		var resp apiResponse
+		_ = json.Unmarshal(res.Body.Bytes(), &resp)
+		var u models.User
+		_ = json.Unmarshal(resp.Data, &u)
+		return u
+	}
+
+	u := update(`{"name":"Seeker","education":[{"degree":"B.Tech","institution":"IIT"}],"experience":[{"title":"Engineer","years":2}],"tenth_marks":85}`)
+	if len(u.Education) != 2 || len(u.Experience) != 1 {
+		t.Fatalf("expected structured lists to be stored, got %+v %+v", u.Education, u.Experience)
+	}
+
+	// Saving marks again must replace the Class 10 entry, not append another.
+	u = update(`{"name":"Seeker","tenth_marks":90,"twelfth_marks":"88"}`)
+	grades := map[string]string{}
+	for _, d := range u.Education {
+		grades[d.Degree] = d.Grade
+	}
+	if _, ok := grades["B.Tech"]; !ok || len(u.Education) != 3 || grades["Class 10"] != "90" || grades["Class 12"] != "88" {
+		t.Fatalf("expected class 10/12 to be upserted, got %+v", u.Education)
+	}
+
+	// Empty strings and nulls keep the stored history.
+	u = update(`{"name":"Seeker","education":"","experience":null}`)
+	if len(u.Education) != 3 || len(u.Experience) != 1 {
+		t.Fatalf("blank fields must not clear the profile, got %+v %+v", u.Education, u.Experience)
+	}
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/ranking"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+func TestRankingEngineWeightsAndMustHaves(t *testing.T) {
+	engine := ranking.Default()
+	job := ranking.Job{
+		Location: "Remote",
+		Profile: models.ScoringProfile{
+			Skills: []models.SkillRequirement{
+				{Skill: "Go", MustHave: true, Weight: 3},
+				{Skill: "Docker", Weight: 1},
+			},
+		},
+	}
+
+	full := engine.Score(job, ranking.Candidate{Skills: []string{"go", "Docker"}, ExperienceYears: 5})
+	if full.Score != 100 {
+		t.Fatalf("expected perfect score, got %v (%+v)", full.Score, full.Factors)
+	}
+
+	niceOnly := engine.Score(job, ranking.Candidate{Skills: []string{"Docker"}, ExperienceYears: 5})
+	if len(niceOnly.MissingMustHave) != 1 || niceOnly.MustHaveRatio != 0 || niceOnly.Score != 0 {
+		t.Fatalf("missing must-have should zero the score, got %+v", niceOnly)
+	}
+
+	mustOnly := engine.Score(job, ranking.Candidate{Skills: []string{"Go"}, ExperienceYears: 5})
+	if mustOnly.Score <= niceOnly.Score || mustOnly.Score >= full.Score {
+		t.Fatalf("expected must-have-only score between, got %v", mustOnly.Score)
+	}
+	for _, f := range mustOnly.Factors {
+		if f.Name == ranking.FactorPremium {
+			t.Fatal("zero-weight factor should be excluded from breakdown")
+		}
+	}
+}
+
+func TestRankingPremiumIsOptIn(t *testing.T) {
+	engine := ranking.Default()
+	job := ranking.Job{Profile: models.ScoringProfile{Skills: []models.SkillRequirement{{Skill: "Go"}}}}
+	regular := ranking.Candidate{Skills: []string{"Go"}, ExperienceYears: 5}
+	premium := regular
+	premium.IsPremium = true
+
+	if r, p := engine.Score(job, regular), engine.Score(job, premium); r.Score != 100 || p.Score != 100 {
+		t.Fatalf("premium must not affect scores by default, got %v and %v", r.Score, p.Score)
+	}
+
+	job.Profile.FactorWeights = map[string]float64{ranking.FactorPremium: 0.5}
+	if r, p := engine.Score(job, regular), engine.Score(job, premium); p.Score != 100 || r.Score >= p.Score {
+		t.Fatalf("an opted-in premium weight must favour premium seekers, got %v and %v", r.Score, p.Score)
+	}
+}
+
+// stubMatcher returns a fixed breakdown per candidate text, and no score for
+// anyone else.
+type stubMatcher map[string]services.MatchBreakdown
+
+func (m stubMatcher) MatchScoreDetailed(_ context.Context, _, candidateBio string, _, _ []string) (services.MatchBreakdown, error) {
+	return m[candidateBio], nil
+}
+
+func (m stubMatcher) MatchJobAgainstCandidates(_ context.Context, job services.MatchItem, candidates []services.MatchItem) []services.MatchResult {
+	results := make([]services.MatchResult, len(candidates))
+	for i, c := range candidates {
+		results[i] = services.MatchResult{JobID: job.ID, CandidateID: c.ID, MatchBreakdown: m[c.Text]}
+	}
+	return results
+}
+
+func (m stubMatcher) MatchCandidateAgainstJobs(_ context.Context, candidate services.MatchItem, jobs []services.MatchItem) []services.MatchResult {
+	results := make([]services.MatchResult, len(jobs))
+	for i, j := range jobs {
+		results[i] = services.MatchResult{JobID: j.ID, CandidateID: candidate.ID, MatchBreakdown: m[candidate.Text]}
+	}
+	return results
+}
+
+func TestRankedSeekersUseSemanticComponent(t *testing.T) {
+	matcher := stubMatcher{}
+	jobs := services.NewJobService(nil)
+	app := newTestApp(t, routes.Deps{JobSvc: jobs, Matcher: matcher})
+	recToken, rec := app.register("rank-rec", models.RoleRecruiter)
+	job, err := jobs.Create(context.Background(), models.Job{Title: "Backend " + app.suffix, Description: "Build APIs", RecruiterID: rec.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	semantic, coverage := 60.0, 50.0
+	_, reported := app.registerUser(models.User{Name: "rank-reported", Role: models.RoleSeeker, Bio: "reported " + app.suffix})
+	_, estimated := app.registerUser(models.User{Name: "rank-estimated", Role: models.RoleSeeker, Bio: "estimated " + app.suffix})
+	matcher[reported.Bio] = services.MatchBreakdown{Score: 80, SemanticScore: &semantic, SkillScore: &coverage}
+	matcher[estimated.Bio] = services.MatchBreakdown{Score: 80, SkillScore: &coverage}
+
+	var ranked struct {
+		Results []struct {
+			ID        string  `json:"jobSeekerId"`
+			RankScore float64 `json:"rankScore"`
+		} `json:"results"`
+	}
+	decodeData(t, app.request(http.MethodGet, "/api/recruiter/jobs/"+job.ID.Hex()+"/ranked-jobseekers", "", recToken), &ranked)
+	// No job skills and no experience: semantic (0.4), experience (0.15) at 0
+	// and location (0.1) at 100, so rank = (0.4*semantic + 10) / 0.65.
+	want := map[string]float64{
+		reported.ID.Hex():  52.3, // the reported semantic score, 60
+		estimated.ID.Hex(): 72.6, // (80 - 0.3*50) / 0.7 = 92.9, inverted from the hybrid score
+	}
+	for _, r := range ranked.Results {
+		if w, ok := want[r.ID]; ok {
+			if r.RankScore != w {
+				t.Errorf("seeker %s: expected rank score %v, got %v", r.ID, w, r.RankScore)
+			}
+			delete(want, r.ID)
+		}
+	}
+	if len(want) > 0 {
+		t.Fatalf("seekers missing from the ranking: %v", want)
+	}
+}
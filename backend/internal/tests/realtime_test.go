@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/realtime"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+type wsEvent struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+func TestRealtimeMessagesReceiptsAndTyping(t *testing.T) {
+	hub := realtime.NewHub(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := hub.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	app := newTestApp(t, routes.Deps{MessageSvc: services.NewMessageService(nil), Hub: hub})
+	srv := httptest.NewServer(app.router)
+	defer srv.Close()
+
+	recToken, rec := app.register("rt-rec", models.RoleRecruiter)
+	seekToken, seeker := app.register("rt-seek", models.RoleSeeker)
+	recID, seekID := rec.ID.Hex(), seeker.ID.Hex()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/ws?token="
+	dial := func(token string) *websocket.Conn {
+		ws, err := websocket.Dial(wsURL+token, "", "http://localhost/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ws
+	}
+	if _, err := websocket.Dial(wsURL+"bogus", "", "http://localhost/"); err == nil {
+		t.Fatal("connections without a valid token must be refused")
+	}
+	recWS, seekWS := dial(recToken), dial(seekToken)
+	defer recWS.Close()
+	defer seekWS.Close()
+
+	next := func(ws *websocket.Conn, kind string) wsEvent {
+		t.Helper()
+		_ = ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+		for {
+			var ev wsEvent
+			if err := websocket.JSON.Receive(ws, &ev); err != nil {
+				t.Fatalf("waiting for %s: %v", kind, err)
+			}
+			if ev.Type == kind {
+				return ev
+			}
+		}
+	}
+
+	if err := websocket.JSON.Send(seekWS, map[string]interface{}{"type": "typing", "to": recID, "typing": true}); err != nil {
+		t.Fatal(err)
+	}
+	typing := next(recWS, realtime.EventTyping)
+	if !strings.Contains(string(typing.Data), seekID) {
+		t.Fatalf("typing event should name the sender: %s", typing.Data)
+	}
+
+	sendRes := app.request(http.MethodPost, "/api/messages/send", `{"toUserId":"`+seekID+`","toRole":"seeker","message":"hello"}`, recToken)
+	if sendRes.Code != http.StatusCreated {
+		t.Fatalf("send failed: %d %s", sendRes.Code, sendRes.Body.String())
+	}
+	var msg struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(next(seekWS, realtime.EventMessageNew).Data, &msg)
+	if msg.Message != "hello" || msg.ID == "" {
+		t.Fatalf("unexpected pushed message %+v", msg)
+	}
+	delivered := next(recWS, realtime.EventMessageDelivered)
+	if !strings.Contains(string(delivered.Data), msg.ID) {
+		t.Fatalf("delivery receipt should reference the message: %s", delivered.Data)
+	}
+
+	if res := app.request(http.MethodPut, "/api/messages/"+msg.ID+"/read", "", seekToken); res.Code != http.StatusOK {
+		t.Fatalf("mark read failed: %d", res.Code)
+	}
+	read := next(recWS, realtime.EventMessageRead)
+	if !strings.Contains(string(read.Data), msg.ID) {
+		t.Fatalf("read receipt should reference the message: %s", read.Data)
+	}
+
+	// Messages to an unassigned support thread reach every connected admin.
+	adminToken, _ := app.register("rt-admin", models.RoleAdmin)
+	adminWS := dial(adminToken)
+	defer adminWS.Close()
+	sendRes = app.request(http.MethodPost, "/api/messages/send", `{"toRole":"admin","message":"need help"}`, recToken)
+	if sendRes.Code != http.StatusCreated {
+		t.Fatalf("support send failed: %d %s", sendRes.Code, sendRes.Body.String())
+	}
+	var support struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(next(adminWS, realtime.EventMessageNew).Data, &support)
+	if support.Message != "need help" {
+		t.Fatalf("admins must receive unassigned support messages, got %+v", support)
+	}
+	if delivered := next(recWS, realtime.EventMessageDelivered); !strings.Contains(string(delivered.Data), support.ID) {
+		t.Fatalf("support messages must be marked delivered once an admin gets them: %s", delivered.Data)
+	}
+}
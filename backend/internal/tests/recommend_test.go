@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"rizeos/backend/internal/recommend"
+)
+
+func TestRecommendRankBlendsSignalsAndDiversifies(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	high, low := 90.0, 40.0
+	items := []recommend.Item{
+		{ID: "a1", Skills: []string{"Go", "Docker"}, Content: &high, CreatedAt: now, Group: "acme"},
+		{ID: "a2", Skills: []string{"Go", "Docker"}, Content: &high, CreatedAt: now, Group: "acme"},
+		{ID: "b1", Skills: []string{"Go"}, Content: &low, CreatedAt: now.AddDate(0, -2, 0), Group: "beta"},
+	}
+	q := recommend.Query{Skills: []string{"go", "docker", "kubernetes"}}
+
+	plain := recommend.Rank(q, items, nil, recommend.Options{Now: now})
+	if len(plain) != 3 || plain[2].ID != "b1" {
+		t.Fatalf("expected b1 last without diversity, got %+v", plain)
+	}
+	if plain[0].Signals.Skills != 1 || len(plain[0].Reasons) == 0 {
+		t.Fatalf("expected full job-skill coverage with reasons, got %+v", plain[0])
+	}
+
+	diverse := recommend.Rank(q, items, nil, recommend.Options{Now: now, Diversity: 0.5, Limit: 2})
+	if len(diverse) != 2 || diverse[1].ID != "b1" {
+		t.Fatalf("same-recruiter duplicate should be pushed below b1, got %+v", diverse)
+	}
+
+	boosted := recommend.Rank(q, items, map[string]float64{"b1": 1}, recommend.Options{Now: now, Weights: recommend.Weights{Collaborative: 1}})
+	if boosted[0].ID != "b1" {
+		t.Fatalf("collaborative-only ranking should favour b1, got %+v", boosted)
+	}
+}
+
+func TestRecommendCollaborativeAndEvaluate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	apps := []recommend.Application{
+		{SeekerID: "s1", JobID: "go", AppliedAt: day(1)},
+		{SeekerID: "s1", JobID: "k8s", AppliedAt: day(2)},
+		{SeekerID: "s2", JobID: "go", AppliedAt: day(1)},
+		{SeekerID: "s2", JobID: "k8s", AppliedAt: day(3)},
+		{SeekerID: "s3", JobID: "go", AppliedAt: day(4)},
+		{SeekerID: "s4", JobID: "design", AppliedAt: day(1)},
+	}
+	in := recommend.NewInteractions(apps)
+	jobs := in.JobsForSeeker("s3")
+	if jobs["k8s"] != 1 || jobs["go"] != 0 || jobs["design"] != 0 {
+		t.Fatalf("s3 should be pointed at k8s only, got %v", jobs)
+	}
+	cands := in.CandidatesForJob("k8s")
+	if cands["s3"] != 1 || cands["s4"] != 0 || cands["s1"] != 0 {
+		t.Fatalf("s3 should be the only k8s candidate, got %v", cands)
+	}
+
+	collab := func(train *recommend.Interactions, seeker string, k int) []string {
+		recs := recommend.Rank(recommend.Query{}, []recommend.Item{{ID: "go"}, {ID: "k8s"}, {ID: "design"}}, train.JobsForSeeker(seeker), recommend.Options{Limit: k, Weights: recommend.Weights{Collaborative: 1}})
+		ids := []string{}
+		for _, r := range recs {
+			if !train.Applied(seeker, r.ID) {
+				ids = append(ids, r.ID)
+			}
+		}
+		return ids
+	}
+	m := recommend.Evaluate(apps, 1, collab)
+	if m.Evaluated != 2 || m.HitRate != 1 || m.MRR != 1 {
+		t.Fatalf("held-out k8s applications should be hits at rank 1, got %+v", m)
+	}
+}
@@ -40,10 +40,12 @@ func buildTestRouter() (*gin.Engine, config.Config) {
 		AdminSignupCode:   "owner-secret",
 	}
 	deps := routes.Deps{
-		UserSvc:    services.NewUserService(nil),
-		JobSvc:     services.NewJobService(nil),
-		PaymentSvc: services.NewPaymentService(nil),
-		AISvc:      services.NewAIService("http://localhost:8000"),
+		UserSvc:        services.NewUserService(nil),
+		JobSvc:         services.NewJobService(nil),
+		PaymentSvc:     services.NewPaymentService(nil),
+		Matcher:        &services.FakeAI{},
+		SkillExtractor: &services.FakeAI{},
+		SkillSvc:       services.NewSkillService(nil),
 	}
 	return routes.SetupRouterWithDeps(cfg, deps), cfg
 }
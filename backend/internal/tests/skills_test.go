@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+func TestSkillCanonicalizeAndExpand(t *testing.T) {
+	ctx := context.Background()
+	skills := services.NewSkillService(nil)
+
+	for raw, want := range map[string]string{
+		"JS":                  "JavaScript",
+		"  reactjs ":          "React",
+		"golang":              "Go",
+		"Node   JS":           "Node.js",
+		"spring-boot":         "Spring Boot",
+		"Amazon Web Services": "AWS",
+	} {
+		sk, ok := skills.Canonicalize(ctx, raw)
+		if !ok || sk.Name != want {
+			t.Errorf("%q: expected %q, got %q (found %v)", raw, want, sk.Name, ok)
+		}
+	}
+	// Ambiguous words are not aliases.
+	for _, raw := range []string{"spring", "node", "next", "security", "github", "containers"} {
+		if sk, ok := skills.Canonicalize(ctx, raw); ok {
+			t.Errorf("%q must not canonicalize, got %q", raw, sk.Name)
+		}
+	}
+
+	if got := skills.Normalize(ctx, []string{"js", "JavaScript", " ", "Haskell  98"}); !reflect.DeepEqual(got, []string{"JavaScript", "Haskell 98"}) {
+		t.Fatalf("unexpected normalized skills %v", got)
+	}
+	got := skills.Expand(ctx, []string{"nextjs", "ts"})
+	want := []string{"Next.js", "TypeScript", "React", "JavaScript"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected skills with their ancestors %v, got %v", want, got)
+	}
+}
+
+func TestSkillTaxonomyValidation(t *testing.T) {
+	ctx := context.Background()
+	skills := services.NewSkillService(nil)
+	suffix := primitive.NewObjectID().Hex()
+	a, b := "tax-a-"+suffix, "tax-b-"+suffix
+
+	if _, err := skills.Create(ctx, models.Skill{Slug: a, Name: "Tax A " + suffix, Aliases: []string{"alias-a-" + suffix}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := skills.Create(ctx, models.Skill{Slug: b, Name: "Tax B " + suffix, ParentSlug: a}); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]models.Skill{
+		"alias taken by another skill": {Slug: "tax-c-" + suffix, Name: "Tax C " + suffix, Aliases: []string{"ALIAS-A-" + suffix}},
+		"alias naming another skill":   {Slug: "tax-d-" + suffix, Name: "Tax D " + suffix, Aliases: []string{"javascript"}},
+		"duplicate slug":               {Slug: a, Name: "Other " + suffix},
+		"unknown parent":               {Slug: "tax-e-" + suffix, Name: "Tax E " + suffix, ParentSlug: "missing-" + suffix},
+		"missing name":                 {Slug: "tax-f-" + suffix},
+	}
+	for name, skill := range cases {
+		if _, err := skills.Create(ctx, skill); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+	if _, err := skills.Update(ctx, a, models.Skill{Name: "Tax A " + suffix, ParentSlug: b}); err == nil {
+		t.Error("a parent cycle must be rejected")
+	}
+	if _, err := skills.Update(ctx, a, models.Skill{Name: "Tax A " + suffix, ParentSlug: a}); err == nil {
+		t.Error("a skill must not be its own parent")
+	}
+	if err := skills.Delete(ctx, a); err == nil {
+		t.Error("skills with children must not be deleted")
+	}
+}
+
+func TestSkillAdminAPIAndSkillLevels(t *testing.T) {
+	app := newTestApp(t, routes.Deps{})
+	suffix := app.suffix
+	adminToken, _ := app.register("skills-admin", models.RoleAdmin)
+	seekerToken, _ := app.register("skills-seeker", models.RoleSeeker)
+
+	slug := "elixir-" + suffix
+	body := `{"slug":"` + slug + `","name":"Elixir ` + suffix + `","aliases":["ex-` + suffix + `"],"category":"language"}`
+	if res := app.request(http.MethodPost, "/api/admin/skills", body, seekerToken); res.Code != http.StatusForbidden {
+		t.Fatalf("only admins may curate skills, got %d", res.Code)
+	}
+	if res := app.request(http.MethodPost, "/api/admin/skills", body, adminToken); res.Code != http.StatusCreated {
+		t.Fatalf("create failed: %d %s", res.Code, res.Body.String())
+	}
+	if res := app.request(http.MethodPost, "/api/admin/skills", body, adminToken); res.Code != http.StatusBadRequest {
+		t.Fatalf("duplicate skills must be rejected, got %d", res.Code)
+	}
+
+	var found []models.Skill
+	decodeData(t, app.request(http.MethodGet, "/api/skills?q=ex-"+suffix, "", seekerToken), &found)
+	if len(found) != 1 || found[0].Slug != slug {
+		t.Fatalf("skills must be searchable by alias, got %+v", found)
+	}
+
+	update := `{"name":"Elixir Lang ` + suffix + `","aliases":["ex-` + suffix + `"],"category":"language","parent_slug":"missing-` + suffix + `"}`
+	if res := app.request(http.MethodPut, "/api/admin/skills/"+slug, update, adminToken); res.Code != http.StatusBadRequest {
+		t.Fatalf("unknown parents must be rejected, got %d", res.Code)
+	}
+	update = `{"name":"Elixir Lang ` + suffix + `","aliases":["ex-` + suffix + `"],"category":"language"}`
+	if res := app.request(http.MethodPut, "/api/admin/skills/"+slug, update, adminToken); res.Code != http.StatusOK {
+		t.Fatalf("update failed: %d %s", res.Code, res.Body.String())
+	}
+	if res := app.request(http.MethodPut, "/api/admin/skills/missing-"+suffix, update, adminToken); res.Code != http.StatusNotFound {
+		t.Fatalf("updating unknown skills must 404, got %d", res.Code)
+	}
+
+	// Skill levels are canonicalized and their skills added to the profile.
+	levels := `{"name":"Seeker","skills":["golang"],"skill_levels":[{"skill":"EX-` + suffix + `","level":"Expert","years":3},{"skill":"ts","level":"beginner"}]}`
+	res := app.request(http.MethodPut, "/api/profile", levels, seekerToken)
+	if res.Code != http.StatusOK {
+		t.Fatalf("profile update failed: %d %s", res.Code, res.Body.String())
+	}
+	var profile models.User
+	decodeData(t, res, &profile)
+	wantLevels := []models.SkillProficiency{{Skill: "Elixir Lang " + suffix, Level: models.ProficiencyExpert, Years: 3}, {Skill: "TypeScript", Level: models.ProficiencyBeginner}}
+	if !reflect.DeepEqual(profile.SkillLevels, wantLevels) {
+		t.Fatalf("expected canonical skill levels %+v, got %+v", wantLevels, profile.SkillLevels)
+	}
+	if want := []string{"Go", "Elixir Lang " + suffix, "TypeScript"}; !reflect.DeepEqual(profile.Skills, want) {
+		t.Fatalf("skills with a level must be listed as skills, expected %v, got %v", want, profile.Skills)
+	}
+	if res := app.request(http.MethodPut, "/api/profile", `{"name":"Seeker","skill_levels":[{"skill":"go","level":"guru"}]}`, seekerToken); res.Code != http.StatusBadRequest {
+		t.Fatalf("unknown levels must be rejected, got %d", res.Code)
+	}
+
+	if res := app.request(http.MethodDelete, "/api/admin/skills/"+slug, "", adminToken); res.Code != http.StatusOK {
+		t.Fatalf("delete failed: %d %s", res.Code, res.Body.String())
+	}
+	if res := app.request(http.MethodDelete, "/api/admin/skills/"+slug, "", adminToken); res.Code != http.StatusNotFound {
+		t.Fatalf("deleting twice must 404, got %d", res.Code)
+	}
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"rizeos/backend/internal/suggest"
+)
+
+func TestSuggestFromSupplyAndDemand(t *testing.T) {
+	in := suggest.Input{
+		SeekerSkills: [][]string{
+			{"Go", "Docker", "Kubernetes"},
+			{"Go", "Docker", "Kubernetes"},
+			{"Go", "Docker"},
+			{"React", "CSS"},
+			{"React", "CSS"},
+			{"React"},
+		},
+		Jobs: []suggest.Job{
+			{Title: "Frontend Engineer", Skills: []string{"React", "CSS"}, Budget: 1000},
+			{Title: "Frontend Engineer", Skills: []string{"React"}, Budget: 3000},
+			{Title: "UI Developer", Skills: []string{"React", "CSS"}, Budget: 2000},
+		},
+	}
+	got := suggest.Analyze(in, suggest.Options{})
+	if len(got) != 2 {
+		t.Fatalf("expected two clusters, got %+v", got)
+	}
+
+	backend := got[0]
+	if strings.Join(backend.Skills, ",") != "Docker,Go,Kubernetes" {
+		t.Fatalf("unserved Go/Docker supply should rank first, got %+v", backend)
+	}
+	if backend.Demand != 0 || backend.Budget != nil || backend.CandidatePoolSize != 2 {
+		t.Fatalf("unexpected backend suggestion: %+v", backend)
+	}
+	if !strings.Contains(backend.Description, backend.Title) || !strings.Contains(backend.Title, "Developer") {
+		t.Fatalf("title and description should come from templates, got %+v", backend)
+	}
+
+	frontend := got[1]
+	if frontend.Title != "Frontend Engineer" || frontend.Demand != 3 || frontend.Budget == nil || frontend.Budget.Median != 2000 || frontend.Budget.Samples != 3 {
+		t.Fatalf("frontend suggestion should use existing titles and budgets, got %+v", frontend)
+	}
+	if frontend.Confidence <= 0 || frontend.Confidence > 1 {
+		t.Fatalf("confidence out of range: %v", frontend.Confidence)
+	}
+
+	in.Existing = [][]string{{"react", "css", "html"}}
+	if again := suggest.Analyze(in, suggest.Options{}); len(again) != 1 {
+		t.Fatalf("clusters the recruiter already posts should be skipped, got %+v", again)
+	}
+
+	tmpl, err := suggest.NewTemplates("Senior {{.Lead}} Engineer", "{{.PoolSize}} candidates: {{join .Skills \" + \"}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom := suggest.Analyze(in, suggest.Options{Templates: tmpl, Limit: 1})
+	if !strings.HasPrefix(custom[0].Title, "Senior ") || !strings.HasPrefix(custom[0].Description, "2 candidates: ") {
+		t.Fatalf("custom templates not applied: %+v", custom[0])
+	}
+	if _, err := suggest.NewTemplates("{{.Lead", ""); err == nil {
+		t.Fatal("invalid template should fail to parse")
+	}
+}
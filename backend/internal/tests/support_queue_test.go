@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"rizeos/backend/internal/models"
+	"rizeos/backend/internal/routes"
+	"rizeos/backend/internal/services"
+)
+
+func TestSupportQueueAssignmentStatusAndNotes(t *testing.T) {
+	app := newTestApp(t, routes.Deps{MessageSvc: services.NewMessageService(nil)})
+	recToken, rec := app.register("sup-rec", models.RoleRecruiter)
+	adminA, _ := app.register("sup-a", models.RoleAdmin)
+	adminB, adminBUser := app.register("sup-b", models.RoleAdmin)
+	recID, adminBID := rec.ID.Hex(), adminBUser.ID.Hex()
+
+	if res := app.request(http.MethodPost, "/api/messages/send", `{"toRole":"admin","message":"help"}`, recToken); res.Code != http.StatusCreated {
+		t.Fatalf("messages to admins must go to the queue: %d %s", res.Code, res.Body.String())
+	}
+
+	type thread struct {
+		ID        string `json:"id"`
+		Status    string `json:"status"`
+		Unread    int    `json:"unread_count"`
+		Requester struct {
+			ID string `json:"id"`
+		} `json:"requester"`
+	}
+	var queue struct {
+		Items []thread `json:"items"`
+	}
+	decodeData(t, app.request(http.MethodGet, "/api/admin/support/threads?assignee=unassigned&limit=100", "", adminA), &queue)
+	var threadID string
+	for _, th := range queue.Items {
+		if th.Requester.ID == recID {
+			threadID = th.ID
+			if th.Status != "OPEN" || th.Unread != 1 {
+				t.Fatalf("new threads must be open with the message unread, got %+v", th)
+			}
+		}
+	}
+	if threadID == "" {
+		t.Fatal("the requester's thread must be in the unassigned queue")
+	}
+	base := "/api/admin/support/threads/" + threadID
+
+	if res := app.request(http.MethodPut, base+"/assign", `{"adminId":"`+recID+`"}`, adminA); res.Code != http.StatusBadRequest {
+		t.Fatalf("threads must only be assigned to admins, got %d", res.Code)
+	}
+	if res := app.request(http.MethodPut, base+"/assign", `{"adminId":"`+adminBID+`"}`, adminA); res.Code != http.StatusOK {
+		t.Fatalf("assign failed: %d %s", res.Code, res.Body.String())
+	}
+	var counts struct {
+		Assigned int `json:"assigned"`
+	}
+	decodeData(t, app.request(http.MethodGet, "/api/admin/messages/unread-count", "", adminB), &counts)
+	if counts.Assigned != 1 {
+		t.Fatalf("the assignee must see the thread's unread message, got %d", counts.Assigned)
+	}
+	decodeData(t, app.request(http.MethodGet, "/api/admin/messages/unread-count", "", adminA), &counts)
+	if counts.Assigned != 0 {
+		t.Fatalf("other admins must not count it as theirs, got %d", counts.Assigned)
+	}
+
+	var followUp struct {
+		ToUserID string `json:"to_user_id"`
+	}
+	decodeData(t, app.request(http.MethodPost, "/api/messages/send", `{"toRole":"admin","message":"still stuck"}`, recToken), &followUp)
+	if followUp.ToUserID != adminBID {
+		t.Fatalf("follow-ups must be addressed to the assignee, got %s", followUp.ToUserID)
+	}
+
+	if res := app.request(http.MethodPost, base+"/notes", `{"note":"internal-only remark"}`, adminB); res.Code != http.StatusCreated {
+		t.Fatalf("add note failed: %d", res.Code)
+	}
+	if res := app.request(http.MethodPost, base+"/messages", `{"message":"on it"}`, adminB); res.Code != http.StatusCreated {
+		t.Fatalf("reply failed: %d %s", res.Code, res.Body.String())
+	}
+	var detail struct {
+		Thread struct {
+			Status string `json:"status"`
+			Notes  []struct {
+				Note string `json:"note"`
+			} `json:"notes"`
+		} `json:"thread"`
+		Messages []json.RawMessage `json:"messages"`
+	}
+	decodeData(t, app.request(http.MethodGet, base, "", adminA), &detail)
+	if detail.Thread.Status != "PENDING" || len(detail.Thread.Notes) != 1 || len(detail.Messages) != 3 {
+		t.Fatalf("an admin reply must leave the thread pending, got %+v", detail)
+	}
+
+	var mine struct {
+		Items []struct {
+			ID      string `json:"id"`
+			Support bool   `json:"support"`
+			Unread  int    `json:"unread_count"`
+		} `json:"items"`
+	}
+	decodeData(t, app.request(http.MethodGet, "/api/conversations", "", recToken), &mine)
+	if len(mine.Items) != 1 || mine.Items[0].ID != threadID || !mine.Items[0].Support || mine.Items[0].Unread != 1 {
+		t.Fatalf("the requester must see one support thread with the reply unread, got %+v", mine.Items)
+	}
+	if res := app.request(http.MethodGet, "/api/conversations/"+threadID+"/messages", "", recToken); strings.Contains(res.Body.String(), "internal-only") {
+		t.Fatal("internal notes must not be shown to the requester")
+	}
+	if res := app.request(http.MethodPost, "/api/conversations/"+threadID+"/messages", `{"message":"thanks"}`, recToken); res.Code != http.StatusCreated {
+		t.Fatalf("requester reply failed: %d %s", res.Code, res.Body.String())
+	}
+
+	var marked struct {
+		N int `json:"marked_read"`
+	}
+	decodeData(t, app.request(http.MethodPut, base+"/read", "", adminB), &marked)
+	decodeData(t, app.request(http.MethodGet, "/api/admin/messages/unread-count", "", adminB), &counts)
+	if marked.N != 3 || counts.Assigned != 0 {
+		t.Fatalf("expected 3 messages marked read and none left, got %d and %d", marked.N, counts.Assigned)
+	}
+
+	if res := app.request(http.MethodPut, base+"/status", `{"status":"CLOSED"}`, adminB); res.Code != http.StatusBadRequest {
+		t.Fatalf("unknown statuses must be rejected, got %d", res.Code)
+	}
+	var resolved thread
+	decodeData(t, app.request(http.MethodPut, base+"/status", `{"status":"RESOLVED"}`, adminB), &resolved)
+	if resolved.Status != "RESOLVED" {
+		t.Fatalf("expected the thread resolved, got %+v", resolved)
+	}
+}
+
+func TestSupportReadStateIsPerAdmin(t *testing.T) {
+	app := newTestApp(t, routes.Deps{MessageSvc: services.NewMessageService(nil)})
+	queuedToken, _ := app.register("read-queued", models.RoleRecruiter)
+	assignedToken, _ := app.register("read-assigned", models.RoleRecruiter)
+	adminA, _ := app.register("read-a", models.RoleAdmin)
+	adminB, adminBUser := app.register("read-b", models.RoleAdmin)
+
+	type sent struct {
+		ID             string `json:"id"`
+		ConversationID string `json:"conversation_id"`
+	}
+	var queued, assigned sent
+	decodeData(t, app.request(http.MethodPost, "/api/messages/send", `{"toRole":"admin","message":"in the queue"}`, queuedToken), &queued)
+	decodeData(t, app.request(http.MethodPost, "/api/messages/send", `{"toRole":"admin","message":"for b"}`, assignedToken), &assigned)
+	if res := app.request(http.MethodPut, "/api/admin/support/threads/"+assigned.ConversationID+"/assign", `{"adminId":"`+adminBUser.ID.Hex()+`"}`, adminA); res.Code != http.StatusOK {
+		t.Fatalf("assign failed: %d %s", res.Code, res.Body.String())
+	}
+
+	if res := app.request(http.MethodPut, "/api/admin/messages/"+assigned.ID+"/read", "", adminA); res.Code != http.StatusNotFound {
+		t.Fatalf("admins must not read threads assigned to others, got %d", res.Code)
+	}
+	if res := app.request(http.MethodPut, "/api/messages/read-all", "", adminA); res.Code != http.StatusOK {
+		t.Fatalf("mark all read failed: %d %s", res.Code, res.Body.String())
+	}
+
+	var counts struct {
+		Assigned int `json:"assigned"`
+	}
+	decodeData(t, app.request(http.MethodGet, "/api/admin/messages/unread-count", "", adminB), &counts)
+	if counts.Assigned != 1 {
+		t.Fatalf("another admin's mark-all must not clear the assignee's thread, got %d", counts.Assigned)
+	}
+	unread := func(token string) int {
+		var detail struct {
+			Thread struct {
+				Unread int `json:"unread_count"`
+			} `json:"thread"`
+		}
+		decodeData(t, app.request(http.MethodGet, "/api/admin/support/threads/"+queued.ConversationID, "", token), &detail)
+		return detail.Thread.Unread
+	}
+	if a, b := unread(adminA), unread(adminB); a != 0 || b != 1 {
+		t.Fatalf("queue messages must be read per admin, got %d for the reader and %d for the other admin", a, b)
+	}
+
+	inbox := func(token string) map[string]bool {
+		var messages []struct {
+			ID     string `json:"id"`
+			IsRead bool   `json:"is_read"`
+		}
+		decodeData(t, app.request(http.MethodGet, "/api/admin/messages/inbox", "", token), &messages)
+		read := map[string]bool{}
+		for _, m := range messages {
+			read[m.ID] = m.IsRead
+		}
+		return read
+	}
+	inboxA, inboxB := inbox(adminA), inbox(adminB)
+	if _, ok := inboxA[assigned.ID]; ok {
+		t.Fatal("the inbox must leave out threads assigned to other admins")
+	}
+	if read, ok := inboxA[queued.ID]; !ok || !read {
+		t.Fatalf("the reader's inbox must list the queue message as read, got %v (listed %v)", read, ok)
+	}
+	if read, ok := inboxB[queued.ID]; !ok || read {
+		t.Fatalf("the other admin's inbox must list the queue message as unread, got %v (listed %v)", read, ok)
+	}
+	if _, ok := inboxB[assigned.ID]; !ok {
+		t.Fatal("the inbox must list threads assigned to the admin")
+	}
+}
@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"rizeos/backend/internal/models"
+)
+
+const hoursPerYear = 24 * 365.25
+
+// TotalExperienceYears returns the total years of experience across positions.
+//
+// Dated positions are merged so overlapping roles are not double counted;
+// a position with a start date and no end date runs until now. Undated
+// positions contribute their explicit Years value.
+//
+// Returns: years rounded to 1 decimal place
+func TotalExperienceYears(positions []models.Position, now time.Time) float64 {
+	type span struct{ start, end time.Time }
+	var spans []span
+	total := 0.0
+	for _, p := range positions {
+		if p.StartDate == nil {
+			if p.Years > 0 {
+				total += p.Years
+			}
+			continue
+		}
+		end := now
+		if p.EndDate != nil {
+			end = *p.EndDate
+		}
+		if end.After(*p.StartDate) {
+			spans = append(spans, span{start: *p.StartDate, end: end})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+	var merged []span
+	for _, sp := range spans {
+		if n := len(merged); n > 0 && !sp.start.After(merged[n-1].end) {
+			if sp.end.After(merged[n-1].end) {
+				merged[n-1].end = sp.end
+			}
+			continue
+		}
+		merged = append(merged, sp)
+	}
+	for _, sp := range merged {
+		total += sp.end.Sub(sp.start).Hours() / hoursPerYear
+	}
+
+	return math.Round(total*10) / 10
+}
+
+var legacyDurationRe = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(years?|yrs?|y|months?|mos?|m)?\b`)
+
+// ParseLegacyExperience converts a legacy "string or number" experience value
+// (e.g. 3, "2.5", "3 years", "18 months", "fresher") into years.
+// The second return value is false when no duration could be recognised.
+func ParseLegacyExperience(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, val >= 0
+	case float32:
+		return float64(val), val >= 0
+	case int:
+		return float64(val), val >= 0
+	case int32:
+		return float64(val), val >= 0
+	case int64:
+		return float64(val), val >= 0
+	case string:
+		s := strings.TrimSpace(val)
+		if s == "" {
+			return 0, false
+		}
+		lower := strings.ToLower(s)
+		if lower == "fresher" || lower == "none" || lower == "nil" {
+			return 0, true
+		}
+		m := legacyDurationRe.FindStringSubmatch(s)
+		if m == nil {
+			return 0, false
+		}
+		n, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, false
+		}
+		if strings.HasPrefix(strings.ToLower(m[2]), "m") {
+			n = n / 12
+		}
+		return math.Round(n*10) / 10, true
+	}
+	return 0, false
+}
+
+// FormatLegacyGrade converts a legacy "string or number" marks value into a grade string.
+func FormatLegacyGrade(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case int32:
+		return strconv.FormatInt(int64(val), 10)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case int:
+		return strconv.Itoa(val)
+	}
+	return ""
+}
+
+// LegacyPositions builds a work history from a legacy experience value.
+// Unparseable values are kept as a description so no information is lost.
+func LegacyPositions(v interface{}) []models.Position {
+	raw := strings.TrimSpace(FormatLegacyGrade(v))
+	if raw == "" {
+		return nil
+	}
+	years, ok := ParseLegacyExperience(v)
+	if ok && years == 0 {
+		return nil
+	}
+	return []models.Position{{Title: "Experience", Years: years, Description: raw}}
+}
+
+// LegacyDegrees builds an education history from legacy education and marks values.
+func LegacyDegrees(education interface{}, tenthMarks interface{}, twelfthMarks interface{}) []models.Degree {
+	var degrees []models.Degree
+	if ed, ok := education.(string); ok && strings.TrimSpace(ed) != "" {
+		degrees = append(degrees, models.Degree{Degree: strings.TrimSpace(ed)})
+	}
+	if grade := FormatLegacyGrade(twelfthMarks); grade != "" {
+		degrees = append(degrees, models.Degree{Degree: "Class 12", Grade: grade})
+	}
+	if grade := FormatLegacyGrade(tenthMarks); grade != "" {
+		degrees = append(degrees, models.Degree{Degree: "Class 10", Grade: grade})
+	}
+	return degrees
+}
+
+// UpsertDegrees merges degree marks into an education history. An entry with
+// the same degree name (e.g. "Class 10") only has its grade updated, keeping
+// the institution and years; other degrees are appended.
+func UpsertDegrees(history []models.Degree, degrees ...models.Degree) []models.Degree {
+	for _, d := range degrees {
+		updated := false
+		for i := range history {
+			if strings.EqualFold(strings.TrimSpace(history[i].Degree), d.Degree) {
+				history[i].Grade = d.Grade
+				updated = true
+				break
+			}
+		}
+		if !updated {
+			history = append(history, d)
+		}
+	}
+	return history
+}
@@ -0,0 +1,74 @@
+import React from 'react';
+
+/**
+ * ProfileHistory Components
+ *
+ * Render a seeker's structured education (Degree) and work history (Position) lists.
+ */
+
+const yearRange = (start, end) => {
+  if (!start && !end) return '';
+  return `${start || '?'} – ${end || 'Present'}`;
+};
+
+const formatDate = (value) => {
+  if (!value) return '';
+  const d = new Date(value);
+  return isNaN(d) ? '' : d.toLocaleDateString(undefined, { year: 'numeric', month: 'short' });
+};
+
+/**
+ * EducationList Component
+ *
+ * @param {Array} degrees - Degree entries: institution, degree, field, grade, start_year, end_year
+ */
+export function EducationList({ degrees }) {
+  if (!Array.isArray(degrees) || degrees.length === 0) {
+    return <p className="text-white/40 text-sm italic">Not provided</p>;
+  }
+  return (
+    <div className="space-y-3">
+      {degrees.map((d, idx) => (
+        <div key={idx}>
+          <p className="text-white font-medium">
+            {[d.degree, d.field].filter(Boolean).join(' in ') || 'Education'}
+          </p>
+          {d.institution && <p className="text-sm text-white/70">{d.institution}</p>}
+          <p className="text-xs text-white/50">
+            {[yearRange(d.start_year, d.end_year), d.grade && `Grade: ${d.grade}`].filter(Boolean).join(' · ')}
+          </p>
+        </div>
+      ))}
+    </div>
+  );
+}
+
+/**
+ * ExperienceList Component
+ *
+ * @param {Array} positions - Position entries: company, title, start_date, end_date, years, description
+ */
+export function ExperienceList({ positions }) {
+  if (!Array.isArray(positions) || positions.length === 0) {
+    return <p className="text-white/40 text-sm italic">Not provided</p>;
+  }
+  return (
+    <div className="space-y-3">
+      {positions.map((p, idx) => {
+        const dates = p.start_date ? `${formatDate(p.start_date)} – ${formatDate(p.end_date) || 'Present'}` : '';
+        const years = p.years ? `${p.years} yr${p.years === 1 ? '' : 's'}` : '';
+        return (
+          <div key={idx}>
+            <p className="text-white font-medium">
+              {[p.title, p.company].filter(Boolean).join(' at ') || 'Experience'}
+            </p>
+            {(dates || years) && (
+              <p className="text-xs text-white/50">{[dates, years].filter(Boolean).join(' · ')}</p>
+            )}
+            {p.description && <p className="text-sm text-white/70 whitespace-pre-wrap">{p.description}</p>}
+          </div>
+        );
+      })}
+    </div>
+  );
+}
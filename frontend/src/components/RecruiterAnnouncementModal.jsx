@@ -24,8 +24,12 @@ export default function RecruiterAnnouncementModal({ isOpen, onClose, onAnnounce
 
     setSending(true);
     try {
-      await createRecruiterAnnouncement(token, message);
-      toast.success('Announcement sent successfully to all recruiters');
+      const created = await createRecruiterAnnouncement(token, message);
+      if (created?.status === 'REJECTED') {
+        toast.error(created.moderation?.reason || 'Announcement was rejected');
+        return;
+      }
+      toast.success('Announcement submitted for review');
       setMessage('');
       onClose();
       // Notify parent to refresh announcements
@@ -5,6 +5,7 @@ import { getUserProfilePublic } from '../services/api.js';
 import { toast } from 'sonner';
 import AdminSendMessageModal from './AdminSendMessageModal.jsx';
 import PremiumName from './PremiumName.jsx';
+import { EducationList, ExperienceList } from './ProfileHistory.jsx';
 
 /**
  * RecruiterSeekerProfile
@@ -202,32 +203,7 @@ export default function RecruiterSeekerProfile({ seeker, onClose }) {
           {user.role === 'seeker' && (
             <div className="glass rounded-2xl p-6 space-y-4">
               <h3 className="text-xl font-semibold mb-4">Education Details</h3>
-              <div className="grid md:grid-cols-2 gap-4">
-                <div>
-                  <label className="text-xs text-white/60 mb-1 block">Education / Degree</label>
-                  {user.education ? (
-                    <p className="text-white font-medium">{user.education}</p>
-                  ) : (
-                    <p className="text-white/40 text-sm italic">Not provided</p>
-                  )}
-                </div>
-                <div>
-                  <label className="text-xs text-white/60 mb-1 block">10th Marks</label>
-                  {user.tenth_marks ? (
-                    <p className="text-white font-medium">{user.tenth_marks}{typeof user.tenth_marks === 'number' ? '%' : ''}</p>
-                  ) : (
-                    <p className="text-white/40 text-sm italic">Not provided</p>
-                  )}
-                </div>
-                <div>
-                  <label className="text-xs text-white/60 mb-1 block">12th Marks</label>
-                  {user.twelfth_marks ? (
-                    <p className="text-white font-medium">{user.twelfth_marks}{typeof user.twelfth_marks === 'number' ? '%' : ''}</p>
-                  ) : (
-                    <p className="text-white/40 text-sm italic">Not provided</p>
-                  )}
-                </div>
-              </div>
+              <EducationList degrees={user.education} />
             </div>
           )}
 
@@ -235,14 +211,7 @@ export default function RecruiterSeekerProfile({ seeker, onClose }) {
           {user.role === 'seeker' && (
             <div className="glass rounded-2xl p-6 space-y-4">
               <h3 className="text-xl font-semibold mb-4">Experience</h3>
-              <div>
-                <label className="text-xs text-white/60 mb-1 block">Experience</label>
-                {user.experience ? (
-                  <p className="text-white font-medium">{user.experience}</p>
-                ) : (
-                  <p className="text-white/40 text-sm italic">Not provided</p>
-                )}
-              </div>
+              <ExperienceList positions={user.experience} />
             </div>
           )}
 
@@ -7,6 +7,7 @@ import { toast } from 'sonner';
 import PremiumName from '../../components/PremiumName.jsx';
 import AdminSendMessageModal from '../../components/AdminSendMessageModal.jsx';
 import { getScoreProps } from '../../utils/scoreColor.js';
+import { EducationList, ExperienceList } from '../../components/ProfileHistory.jsx';
 
 /**
  * JobApplicantsPage
@@ -285,37 +286,18 @@ export default function JobApplicantsPage() {
             )}
 
             {/* Education */}
-            {(viewingProfile.education || viewingProfile.tenth_marks || viewingProfile.twelfth_marks) && (
+            {viewingProfile.education?.length > 0 && (
               <div className="glass rounded-xl p-4 space-y-3">
                 <h3 className="text-lg font-semibold mb-2">Education Details</h3>
-                {viewingProfile.education && (
-                  <div>
-                    <label className="text-xs text-white/60 mb-1 block">Education</label>
-                    <p className="text-white font-medium">{viewingProfile.education}</p>
-                  </div>
-                )}
-                <div className="grid grid-cols-2 gap-3">
-                  {viewingProfile.tenth_marks && (
-                    <div>
-                      <label className="text-xs text-white/60 mb-1 block">10th Marks</label>
-                      <p className="text-white font-medium">{viewingProfile.tenth_marks}%</p>
-                    </div>
-                  )}
-                  {viewingProfile.twelfth_marks && (
-                    <div>
-                      <label className="text-xs text-white/60 mb-1 block">12th Marks</label>
-                      <p className="text-white font-medium">{viewingProfile.twelfth_marks}%</p>
-                    </div>
-                  )}
-                </div>
+                <EducationList degrees={viewingProfile.education} />
               </div>
             )}
 
             {/* Experience */}
-            {viewingProfile.experience && (
+            {viewingProfile.experience?.length > 0 && (
               <div className="glass rounded-xl p-4">
                 <h3 className="text-lg font-semibold mb-2">Experience</h3>
-                <p className="text-white font-medium">{viewingProfile.experience}</p>
+                <ExperienceList positions={viewingProfile.experience} />
               </div>
             )}
 
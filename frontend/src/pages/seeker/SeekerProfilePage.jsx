@@ -24,10 +24,8 @@ export default function SeekerProfilePage() {
     wallet_address: '',
     phone_number: '',
     summary: '',
-    education: '',
-    tenth_marks: '',
-    twelfth_marks: '',
-    experience: '',
+    education: [],
+    experience: [],
     is_active: true
   });
   const [uploading, setUploading] = useState(false);
@@ -38,6 +36,35 @@ export default function SeekerProfilePage() {
 
   const normalizeSkill = (s) => s.trim();
   const hasSkill = (list, skill) => list.some((v) => v.toLowerCase() === skill.toLowerCase());
+  // Education and experience are edited as lists of Degree / Position entries.
+  // Position dates are edited as "YYYY-MM" and sent as RFC 3339 timestamps.
+  const emptyDegree = { institution: '', degree: '', field: '', grade: '', start_year: '', end_year: '' };
+  const emptyPosition = { company: '', title: '', start_date: '', end_date: '', years: '', description: '' };
+  const fromPosition = (p) => ({
+    ...emptyPosition,
+    ...p,
+    start_date: p.start_date ? p.start_date.slice(0, 7) : '',
+    end_date: p.end_date ? p.end_date.slice(0, 7) : '',
+    years: p.years || '',
+    description: p.description || '',
+  });
+  const toDegree = (d) => ({
+    ...d,
+    start_year: d.start_year ? Number(d.start_year) : 0,
+    end_year: d.end_year ? Number(d.end_year) : 0,
+  });
+  const toPosition = (p) => ({
+    ...p,
+    start_date: p.start_date ? `${p.start_date}-01T00:00:00Z` : null,
+    end_date: p.end_date ? `${p.end_date}-01T00:00:00Z` : null,
+    years: p.years ? Number(p.years) : 0,
+  });
+  const updateEntry = (field, idx, key, value) =>
+    setProfile((prev) => ({ ...prev, [field]: prev[field].map((e, i) => (i === idx ? { ...e, [key]: value } : e)) }));
+  const addEntry = (field, entry) => setProfile((prev) => ({ ...prev, [field]: [...prev[field], { ...entry }] }));
+  const removeEntry = (field, idx) => setProfile((prev) => ({ ...prev, [field]: prev[field].filter((_, i) => i !== idx) }));
+  const inputClass = 'w-full p-3 rounded-lg bg-white/10 border border-white/20';
+
   const parseSkills = (raw) => (Array.isArray(raw) ? raw : (raw || '').split(',').map((s) => s.trim()).filter(Boolean));
 
   useEffect(() => {
@@ -52,10 +79,8 @@ export default function SeekerProfilePage() {
           wallet_address: p.wallet_address || '',
           phone_number: p.phone_number || '',
           summary: p.summary || '',
-          education: p.education || '',
-          tenth_marks: p.tenth_marks || '',
-          twelfth_marks: p.twelfth_marks || '',
-          experience: p.experience || '',
+          education: Array.isArray(p.education) ? p.education : [],
+          experience: Array.isArray(p.experience) ? p.experience.map(fromPosition) : [],
           is_active: p.is_active !== undefined ? p.is_active : true,
         });
         setIsPremium(p.is_premium || false);
@@ -85,10 +110,8 @@ export default function SeekerProfilePage() {
         wallet_address: profile.wallet_address,
         phone_number: profile.phone_number || '',
         summary: profile.summary || '',
-        education: profile.education || '',
-        tenth_marks: profile.tenth_marks || null,
-        twelfth_marks: profile.twelfth_marks || null,
-        experience: profile.experience || null,
+        education: profile.education.map(toDegree),
+        experience: profile.experience.map(toPosition),
         is_active: profile.is_active,
       };
       await updateProfile(token, payload);
@@ -240,51 +263,41 @@ export default function SeekerProfilePage() {
         {/* SECTION 3: EDUCATION DETAILS */}
         <div className="glass rounded-2xl p-6 space-y-4">
           <h3 className="text-xl font-semibold mb-4">Education Details</h3>
-          <div className="grid gap-3">
-            <div>
-              <label className="text-sm text-white/70 mb-1 block">Education</label>
-              <input 
-                className="w-full p-3 rounded-lg bg-white/10 border border-white/20" 
-                placeholder="e.g., B.Tech Computer Science, MCA, etc." 
-                value={profile.education} 
-                onChange={(e) => setProfile({ ...profile, education: e.target.value })} 
-              />
+          {profile.education.map((d, idx) => (
+            <div key={idx} className="grid md:grid-cols-2 gap-3 border-b border-white/10 pb-4">
+              <input className={inputClass} placeholder="Degree (e.g., B.Tech, Class 12)" value={d.degree} onChange={(e) => updateEntry('education', idx, 'degree', e.target.value)} />
+              <input className={inputClass} placeholder="Field (e.g., Computer Science)" value={d.field} onChange={(e) => updateEntry('education', idx, 'field', e.target.value)} />
+              <input className={inputClass} placeholder="Institution" value={d.institution} onChange={(e) => updateEntry('education', idx, 'institution', e.target.value)} />
+              <input className={inputClass} placeholder="Grade (e.g., 8.5 CGPA, 85%)" value={d.grade} onChange={(e) => updateEntry('education', idx, 'grade', e.target.value)} />
+              <input className={inputClass} type="number" placeholder="Start year" value={d.start_year || ''} onChange={(e) => updateEntry('education', idx, 'start_year', e.target.value)} />
+              <input className={inputClass} type="number" placeholder="End year" value={d.end_year || ''} onChange={(e) => updateEntry('education', idx, 'end_year', e.target.value)} />
+              <button type="button" className="text-sm text-red-400 text-left" onClick={() => removeEntry('education', idx)}>Remove</button>
             </div>
-            <div className="grid grid-cols-2 gap-3">
-              <div>
-                <label className="text-sm text-white/70 mb-1 block">10th Marks (%)</label>
-                <input 
-                  className="w-full p-3 rounded-lg bg-white/10 border border-white/20" 
-                  placeholder="e.g., 85" 
-                  value={profile.tenth_marks} 
-                  onChange={(e) => setProfile({ ...profile, tenth_marks: e.target.value })} 
-                />
-              </div>
-              <div>
-                <label className="text-sm text-white/70 mb-1 block">12th Marks (%)</label>
-                <input 
-                  className="w-full p-3 rounded-lg bg-white/10 border border-white/20" 
-                  placeholder="e.g., 90" 
-                  value={profile.twelfth_marks} 
-                  onChange={(e) => setProfile({ ...profile, twelfth_marks: e.target.value })} 
-                />
-              </div>
-            </div>
-          </div>
+          ))}
+          <button type="button" className="px-4 py-2 rounded-lg bg-white/10 border border-white/20 text-sm hover:bg-white/20" onClick={() => addEntry('education', emptyDegree)}>+ Add education</button>
         </div>
 
         {/* SECTION 4: EXPERIENCE */}
         <div className="glass rounded-2xl p-6 space-y-4">
           <h3 className="text-xl font-semibold mb-4">Experience</h3>
-          <div>
-            <label className="text-sm text-white/70 mb-1 block">Experience</label>
-            <input 
-              className="w-full p-3 rounded-lg bg-white/10 border border-white/20" 
-              placeholder="e.g., 2 years, Fresh Graduate, 5+ years in software development" 
-              value={profile.experience} 
-              onChange={(e) => setProfile({ ...profile, experience: e.target.value })} 
-            />
-          </div>
+          {profile.experience.map((p, idx) => (
+            <div key={idx} className="grid md:grid-cols-2 gap-3 border-b border-white/10 pb-4">
+              <input className={inputClass} placeholder="Title" value={p.title} onChange={(e) => updateEntry('experience', idx, 'title', e.target.value)} />
+              <input className={inputClass} placeholder="Company" value={p.company} onChange={(e) => updateEntry('experience', idx, 'company', e.target.value)} />
+              <div>
+                <label className="text-sm text-white/70 mb-1 block">Start</label>
+                <input className={inputClass} type="month" value={p.start_date} onChange={(e) => updateEntry('experience', idx, 'start_date', e.target.value)} />
+              </div>
+              <div>
+                <label className="text-sm text-white/70 mb-1 block">End (leave empty if current)</label>
+                <input className={inputClass} type="month" value={p.end_date} onChange={(e) => updateEntry('experience', idx, 'end_date', e.target.value)} />
+              </div>
+              <input className={inputClass} type="number" step="0.1" min="0" placeholder="Years (if no dates)" value={p.years} onChange={(e) => updateEntry('experience', idx, 'years', e.target.value)} />
+              <textarea className={`${inputClass} md:col-span-2`} placeholder="Description" value={p.description} onChange={(e) => updateEntry('experience', idx, 'description', e.target.value)} />
+              <button type="button" className="text-sm text-red-400 text-left" onClick={() => removeEntry('experience', idx)}>Remove</button>
+            </div>
+          ))}
+          <button type="button" className="px-4 py-2 rounded-lg bg-white/10 border border-white/20 text-sm hover:bg-white/20" onClick={() => addEntry('experience', emptyPosition)}>+ Add experience</button>
         </div>
 
         {/* SECTION 5: SKILLS */}
@@ -69,21 +69,38 @@ export const listJobs = async (token, params = {}) => {
   }
 };
 
+const adminPage = async (token, path) => {
+  const { data } = await client.get(path, { params: { limit: 200 }, headers: authHeaders(token) });
+  return Array.isArray(data.data?.items) ? data.data.items : [];
+};
+
+// The dashboard endpoint returns aggregated metrics only, with snake_case keys
+// like the rest of the API (revenue.by_type, premium.conversion_rate,
+// top_recruiters[].recruiter_id); the list tabs load the first page of each
+// admin list endpoint. Monthly metrics cover at most ten years.
 export const adminDashboard = async (token) => {
+  const from = new Date();
+  from.setUTCFullYear(from.getUTCFullYear() - 10);
   try {
-    const { data } = await client.get('/admin/dashboard', { headers: authHeaders(token) });
-    const result = data.data || {};
+    const [metricsRes, payments, userList, jobList] = await Promise.all([
+      client.get('/admin/dashboard', { params: { from: from.toISOString().slice(0, 10), interval: 'month' }, headers: authHeaders(token) }),
+      adminPage(token, '/admin/payments'),
+      adminPage(token, '/admin/users'),
+      adminPage(token, '/admin/jobs'),
+    ]);
+    const metrics = metricsRes.data.data || {};
     return {
-      payments: Array.isArray(result.payments) ? result.payments : [],
-      total_payments_matic: result.total_payments_matic || 0,
-      users: result.users || 0,
-      jobs: result.jobs || 0,
-      user_list: Array.isArray(result.user_list) ? result.user_list : [],
-      job_list: Array.isArray(result.job_list) ? result.job_list : [],
+      metrics,
+      payments,
+      total_payments_matic: metrics.revenue?.total || 0,
+      users: metrics.users || 0,
+      jobs: metrics.jobs?.total || 0,
+      user_list: userList,
+      job_list: jobList,
     };
   } catch (err) {
     console.error('Failed to load admin dashboard', err);
-    return { payments: [], total_payments_matic: 0, users: 0, jobs: 0, user_list: [], job_list: [] };
+    return { metrics: null, payments: [], total_payments_matic: 0, users: 0, jobs: 0, user_list: [], job_list: [] };
   }
 };
 
@@ -146,15 +163,39 @@ export const getJobApplicants = async (token, jobId) => {
 };
 
 // Message APIs
-export const sendMessage = async (token, message, toUserId, toRole, jobId = null) => {
-  const payload = { message, toUserId, toRole };
+
+// messageBody sends fields as JSON, or as multipart form data when files are
+// attached.
+const messageBody = (fields, files = []) => {
+  if (!files.length) {
+    return fields;
+  }
+  const form = new FormData();
+  Object.entries(fields).forEach(([key, value]) => form.append(key, value));
+  files.forEach((file) => form.append('attachments', file));
+  return form;
+};
+
+export const sendMessage = async (token, message, toUserId, toRole, jobId = null, files = []) => {
+  const payload = { message, toRole };
+  if (toUserId) {
+    payload.toUserId = toUserId;
+  }
   if (jobId) {
     payload.jobId = jobId;
   }
-  const { data } = await client.post('/messages/send', payload, { headers: authHeaders(token) });
+  const { data } = await client.post('/messages/send', messageBody(payload, files), { headers: authHeaders(token) });
   return data.data;
 };
 
+export const downloadAttachment = async (token, messageId, attachmentId) => {
+  const { data } = await client.get(`/messages/${messageId}/attachments/${attachmentId}`, {
+    headers: authHeaders(token),
+    responseType: 'blob',
+  });
+  return data;
+};
+
 export const getAdminInbox = async (token) => {
   try {
     const { data } = await client.get('/admin/messages/inbox', { headers: authHeaders(token) });
@@ -176,6 +217,84 @@ export const markMessageAsRead = async (token, messageId) => {
   return data.data;
 };
 
+export const markAllMessagesRead = async (token) => {
+  const { data } = await client.put('/messages/read-all', {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const archiveMessage = async (token, messageId) => {
+  const { data } = await client.post(`/messages/${messageId}/archive`, {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const unarchiveMessage = async (token, messageId) => {
+  const { data } = await client.delete(`/messages/${messageId}/archive`, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const deleteMessage = async (token, messageId) => {
+  const { data } = await client.delete(`/messages/${messageId}`, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const reportMessage = async (token, messageId, reason) => {
+  const { data } = await client.post(`/messages/${messageId}/report`, { reason }, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const getMessageReports = async (token, status = 'OPEN') => {
+  const { data } = await client.get('/admin/messages/reports', { params: { status }, headers: authHeaders(token) });
+  return data.data;
+};
+
+export const reviewMessageReport = async (token, reportId, status, note = '') => {
+  const { data } = await client.put(`/admin/messages/reports/${reportId}`, { status, note }, { headers: authHeaders(token) });
+  return data.data;
+};
+
+// Admin support queue APIs
+export const getSupportThreads = async (token, { status = '', assignee = '', page = 1, limit = 20 } = {}) => {
+  const params = { page, limit };
+  if (status) params.status = status;
+  if (assignee) params.assignee = assignee;
+  const { data } = await client.get('/admin/support/threads', { params, headers: authHeaders(token) });
+  return data.data;
+};
+
+export const getSupportThread = async (token, threadId, before = null, limit = 30) => {
+  const params = { limit };
+  if (before) {
+    params.before = before;
+  }
+  const { data } = await client.get(`/admin/support/threads/${threadId}`, { params, headers: authHeaders(token) });
+  return data.data;
+};
+
+export const replyToSupportThread = async (token, threadId, message, files = []) => {
+  const { data } = await client.post(`/admin/support/threads/${threadId}/messages`, messageBody({ message }, files), { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const assignSupportThread = async (token, threadId, adminId = '') => {
+  const { data } = await client.put(`/admin/support/threads/${threadId}/assign`, { adminId }, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const setSupportThreadStatus = async (token, threadId, status) => {
+  const { data } = await client.put(`/admin/support/threads/${threadId}/status`, { status }, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const addSupportNote = async (token, threadId, note) => {
+  const { data } = await client.post(`/admin/support/threads/${threadId}/notes`, { note }, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const markSupportThreadRead = async (token, threadId) => {
+  const { data } = await client.put(`/admin/support/threads/${threadId}/read`, {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
 // Recruiter inbox APIs
 export const getRecruiterInbox = async (token) => {
   try {
@@ -210,17 +329,99 @@ export const getSeekerUnreadCount = async (token) => {
   return data.data || data;
 };
 
+// Conversation APIs
+export const getConversations = async (token, page = 1, limit = 20) => {
+  const { data } = await client.get('/conversations', { params: { page, limit }, headers: authHeaders(token) });
+  return data.data;
+};
+
+export const getConversationMessages = async (token, conversationId, before = null, limit = 30) => {
+  const params = { limit };
+  if (before) {
+    params.before = before;
+  }
+  const { data } = await client.get(`/conversations/${conversationId}/messages`, { params, headers: authHeaders(token) });
+  return data.data;
+};
+
+export const replyToConversation = async (token, conversationId, message, files = []) => {
+  const { data } = await client.post(`/conversations/${conversationId}/messages`, messageBody({ message }, files), { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const markConversationRead = async (token, conversationId) => {
+  const { data } = await client.put(`/conversations/${conversationId}/read`, {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
+// Notification APIs
+export const getNotifications = async (token, { unread = false, page = 1, limit = 20 } = {}) => {
+  const params = { page, limit };
+  if (unread) {
+    params.unread = true;
+  }
+  const { data } = await client.get('/notifications', { params, headers: authHeaders(token) });
+  return data.data;
+};
+
+export const markNotificationRead = async (token, notificationId) => {
+  const { data } = await client.put(`/notifications/${notificationId}/read`, {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const markAllNotificationsRead = async (token) => {
+  const { data } = await client.put('/notifications/read-all', {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const getNotificationPreferences = async (token) => {
+  const { data } = await client.get('/notifications/preferences', { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const updateNotificationPreferences = async (token, preferences) => {
+  const { data } = await client.put('/notifications/preferences', preferences, { headers: authHeaders(token) });
+  return data.data;
+};
+
 // Announcement APIs
-export const createAnnouncement = async (token, message) => {
-  const { data } = await client.post('/admin/announcements', { message }, { headers: authHeaders(token) });
+// options: { audience: { segment, skills, location }, publish_at, expires_at }
+export const createAnnouncement = async (token, message, options = {}) => {
+  const { data } = await client.post('/admin/announcements', { message, ...options }, { headers: authHeaders(token) });
   return data.data || data;
 };
 
+// Admins get every announcement with delivery progress; others get their feed.
 export const listAnnouncements = async (token) => {
   const { data } = await client.get('/announcements', { headers: authHeaders(token) });
   return data.data || data;
 };
 
+export const getAnnouncement = async (token, id) => {
+  const { data } = await client.get(`/admin/announcements/${id}`, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const cancelAnnouncement = async (token, id) => {
+  const { data } = await client.post(`/admin/announcements/${id}/cancel`, {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const retryAnnouncement = async (token, id) => {
+  const { data } = await client.post(`/admin/announcements/${id}/retry`, {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const markAnnouncementRead = async (token, id) => {
+  const { data } = await client.put(`/announcements/${id}/read`, {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const dismissAnnouncement = async (token, id) => {
+  const { data } = await client.put(`/announcements/${id}/dismiss`, {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
 // Get announcements for recruiters only
 export const getRecruiterAnnouncements = async (token) => {
   const { data } = await client.get('/recruiter/announcements', { headers: authHeaders(token) });
@@ -228,11 +429,87 @@ export const getRecruiterAnnouncements = async (token) => {
 };
 
 // Create announcement for recruiters (only visible to recruiters)
-export const createRecruiterAnnouncement = async (token, message) => {
-  const { data } = await client.post('/recruiter/announcements', { message }, { headers: authHeaders(token) });
+export const createRecruiterAnnouncement = async (token, message, options = {}) => {
+  const { data } = await client.post('/recruiter/announcements', { message, ...options }, { headers: authHeaders(token) });
   return data.data || data;
 };
 
+// The current recruiter's announcements with moderation status and reason.
+export const getMyRecruiterAnnouncements = async (token) => {
+  const { data } = await client.get('/recruiter/announcements/mine', { headers: authHeaders(token) });
+  return data.data;
+};
+
+// Admin moderation queue: announcements awaiting review.
+export const getPendingAnnouncements = async (token) => {
+  const { data } = await client.get('/admin/announcements', { params: { status: 'PENDING' }, headers: authHeaders(token) });
+  return data.data;
+};
+
+// decision is 'approve' or 'reject'; rejecting needs a reason.
+export const reviewAnnouncement = async (token, id, decision, reason = '') => {
+  const { data } = await client.put(`/admin/announcements/${id}/review`, { decision, reason }, { headers: authHeaders(token) });
+  return data.data;
+};
+
+// Social feed APIs
+export const getFeed = async (token, page = 1, limit = 20) => {
+  const { data } = await client.get('/posts', { params: { page, limit }, headers: authHeaders(token) });
+  return data.data;
+};
+
+export const createPost = async (token, { title, content, tags }) => {
+  const { data } = await client.post('/posts', { title, content, tags }, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const updatePost = async (token, id, { title, content, tags }) => {
+  const { data } = await client.put(`/posts/${id}`, { title, content, tags }, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const deletePost = async (token, id) => {
+  const { data } = await client.delete(`/posts/${id}`, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const likePost = async (token, id) => {
+  const { data } = await client.post(`/posts/${id}/like`, {}, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const unlikePost = async (token, id) => {
+  const { data } = await client.delete(`/posts/${id}/like`, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const getPostComments = async (token, id, page = 1, limit = 20) => {
+  const { data } = await client.get(`/posts/${id}/comments`, { params: { page, limit }, headers: authHeaders(token) });
+  return data.data;
+};
+
+export const addPostComment = async (token, id, content) => {
+  const { data } = await client.post(`/posts/${id}/comments`, { content }, { headers: authHeaders(token) });
+  return data.data;
+};
+
+export const deletePostComment = async (token, id, commentId) => {
+  const { data } = await client.delete(`/posts/${id}/comments/${commentId}`, { headers: authHeaders(token) });
+  return data.data;
+};
+
+// Admin feed moderation: status is '' for all posts or 'HIDDEN'.
+export const getAdminPosts = async (token, status = '', page = 1, limit = 20) => {
+  const { data } = await client.get('/admin/posts', { params: { status, page, limit }, headers: authHeaders(token) });
+  return data.data;
+};
+
+// Hide a post with a reason, or restore it with hidden = false.
+export const moderatePost = async (token, id, hidden, reason = '') => {
+  const { data } = await client.put(`/admin/posts/${id}/moderation`, { hidden, reason }, { headers: authHeaders(token) });
+  return data.data;
+};
+
 // Optional helper to call AI service directly for resume skill extraction.
 export const extractSkillsFromResume = async (base64Content) => {
   const aiBase = (import.meta.env.VITE_AI_URL || 'http://localhost:8000').replace(/\/+$/, ''); // Remove trailing slashes